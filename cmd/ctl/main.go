@@ -0,0 +1,341 @@
+// Command ctl approves and rejects Claude Control tasks from a terminal, for servers reached over SSH.
+//
+//	ctl [--server URL] list
+//	ctl [--server URL] approve <task-id>
+//	ctl [--server URL] reject <task-id> [--reason "..."]
+//	ctl [--server URL] watch [--interval 2s]
+//
+// The server URL defaults to CLAUDE_CONTROL_SERVER, then http://localhost:8080; include BASE_PATH if the
+// server uses one. ctl talks to the /api routes, so it only works while DASHBOARD_PASSWORD is unset.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	// defaultServer is used when neither --server nor CLAUDE_CONTROL_SERVER is set
+	defaultServer = "http://localhost:8080"
+
+	// defaultWatchInterval is how often watch polls for new tasks
+	defaultWatchInterval = 2 * time.Second
+
+	// maxDetailLength is the longest command or file path shown in a table row
+	maxDetailLength = 60
+)
+
+// errUsage reports a malformed command line; main exits with status 2 for it
+var errUsage = errors.New("usage: ctl [--server URL] list | approve <task-id> | reject <task-id> [--reason TEXT] | watch [--interval DURATION]")
+
+// task is the part of a task's JSON that ctl shows
+type task struct {
+	ID        string    `json:"id"`
+	HookType  string    `json:"hook_type"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	HookData  struct {
+		Data struct {
+			SessionID string `json:"session_id"`
+			ToolName  string `json:"tool_name"`
+			Message   string `json:"message"`
+			ToolInput *struct {
+				Command  string `json:"command"`
+				FilePath string `json:"file_path"`
+			} `json:"tool_input"`
+		} `json:"data"`
+	} `json:"hook_data"`
+}
+
+// detail summarises what the task is asking about
+func (t *task) detail() string {
+	data := t.HookData.Data
+	text := data.Message
+	if data.ToolName != "" {
+		text = data.ToolName
+		if data.ToolInput != nil && data.ToolInput.Command != "" {
+			text += ": " + data.ToolInput.Command
+		} else if data.ToolInput != nil && data.ToolInput.FilePath != "" {
+			text += ": " + data.ToolInput.FilePath
+		}
+	}
+
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > maxDetailLength {
+		text = string(runes[:maxDetailLength-3]) + "..."
+	}
+	return text
+}
+
+// apiResponse is the envelope every /api response shares
+type apiResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Tasks   []task `json:"tasks"`
+	Task    *task  `json:"task"`
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "ctl: %v\n", err)
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+// run parses the command line and runs the subcommand, writing its output to out
+func run(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("ctl", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	server := flags.String("server", envOrDefault("CLAUDE_CONTROL_SERVER", defaultServer), "Claude Control server URL")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w (%v)", errUsage, err)
+	}
+	if flags.NArg() == 0 {
+		return errUsage
+	}
+
+	c := newClient(*server)
+	command, commandArgs := flags.Arg(0), flags.Args()[1:]
+
+	switch command {
+	case "list":
+		tasks, err := c.pendingTasks(ctx)
+		if err != nil {
+			return err
+		}
+		if len(tasks) == 0 {
+			fmt.Fprintln(out, "No pending tasks")
+			return nil
+		}
+		return printTasks(out, tasks, true)
+
+	case "approve":
+		taskID, _, err := parseTaskCommand(command, commandArgs, false)
+		if err != nil {
+			return err
+		}
+		if err := c.takeAction(ctx, taskID, "approve", ""); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Approved %s\n", taskID)
+		return nil
+
+	case "reject":
+		taskID, reason, err := parseTaskCommand(command, commandArgs, true)
+		if err != nil {
+			return err
+		}
+		if err := c.takeAction(ctx, taskID, "reject", reason); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Rejected %s\n", taskID)
+		return nil
+
+	case "watch":
+		watchFlags := flag.NewFlagSet("watch", flag.ContinueOnError)
+		watchFlags.SetOutput(io.Discard)
+		interval := watchFlags.Duration("interval", defaultWatchInterval, "how often to poll")
+		if err := watchFlags.Parse(commandArgs); err != nil || *interval <= 0 {
+			return errUsage
+		}
+		return c.watch(ctx, out, *interval)
+	}
+
+	return fmt.Errorf("%w (unknown command %q)", errUsage, command)
+}
+
+// parseTaskCommand reads the task ID and, for reject, --reason from an approve or reject command line
+// The flag may come before or after the ID.
+func parseTaskCommand(command string, args []string, allowReason bool) (string, string, error) {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	var reason *string
+	if allowReason {
+		reason = flags.String("reason", "", "why the task was rejected")
+	}
+
+	var taskID string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		taskID, args = args[0], args[1:]
+	}
+	if err := flags.Parse(args); err != nil {
+		return "", "", fmt.Errorf("%w (%v)", errUsage, err)
+	}
+	rest := flags.Args()
+	if taskID == "" && len(rest) > 0 {
+		taskID, rest = rest[0], rest[1:]
+	}
+	if taskID == "" || len(rest) > 0 {
+		return "", "", fmt.Errorf("%w (%s needs exactly one task ID)", errUsage, command)
+	}
+
+	if reason == nil {
+		return taskID, "", nil
+	}
+	return taskID, *reason, nil
+}
+
+// printTasks writes tasks as an aligned table, with a header row if requested
+func printTasks(out io.Writer, tasks []task, header bool) error {
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if header {
+		fmt.Fprintln(table, "ID\tHOOK\tAGE\tSESSION\tDETAIL")
+	}
+	for _, t := range tasks {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n",
+			t.ID, t.HookType, time.Since(t.CreatedAt).Round(time.Second), shortSessionID(t.HookData.Data.SessionID), t.detail())
+	}
+	return table.Flush()
+}
+
+// shortSessionID keeps enough of a session ID to tell sessions apart in a table
+func shortSessionID(sessionID string) string {
+	if len(sessionID) > 8 {
+		return sessionID[:8]
+	}
+	return sessionID
+}
+
+// client calls the server's /api routes
+type client struct {
+	server     string
+	httpClient *http.Client
+}
+
+// newClient creates a client for the server at the given URL
+func newClient(server string) *client {
+	return &client{
+		server:     strings.TrimRight(server, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// pendingTasks lists the tasks waiting for a decision, newest first
+func (c *client) pendingTasks(ctx context.Context) ([]task, error) {
+	resp, err := c.do(ctx, "GET", "/api/tasks?status=pending", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	return resp.Tasks, nil
+}
+
+// takeAction approves or rejects a task, failing if it doesn't exist or is no longer pending
+func (c *client) takeAction(ctx context.Context, taskID, action, comment string) error {
+	resp, err := c.do(ctx, "GET", "/api/tasks/"+url.PathEscape(taskID), nil)
+	if err != nil {
+		return fmt.Errorf("task %s: %w", taskID, err)
+	}
+	if resp.Task == nil || resp.Task.Status != "pending" {
+		status := "unknown"
+		if resp.Task != nil {
+			status = resp.Task.Status
+		}
+		return fmt.Errorf("task %s is %s, not pending", taskID, status)
+	}
+
+	body := map[string]string{"action": action, "comment": comment}
+	if _, err := c.do(ctx, "POST", "/api/tasks/"+url.PathEscape(taskID)+"/action", body); err != nil {
+		return fmt.Errorf("failed to %s task %s: %w", action, taskID, err)
+	}
+	return nil
+}
+
+// watch prints the pending tasks, then each new one as it appears, until ctx is done
+func (c *client) watch(ctx context.Context, out io.Writer, interval time.Duration) error {
+	seen := make(map[string]bool)
+	header := true
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		tasks, err := c.pendingTasks(ctx)
+		if err != nil && ctx.Err() == nil {
+			// Keep watching through server restarts
+			fmt.Fprintf(os.Stderr, "ctl: %v\n", err)
+		}
+
+		var fresh []task
+		for i := len(tasks) - 1; i >= 0; i-- {
+			if !seen[tasks[i].ID] {
+				seen[tasks[i].ID] = true
+				fresh = append(fresh, tasks[i])
+			}
+		}
+		if len(fresh) > 0 {
+			if err := printTasks(out, fresh, header); err != nil {
+				return err
+			}
+			header = false
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// do sends a request with an optional JSON body and decodes the response, turning API errors into Go errors
+func (c *client) do(ctx context.Context, method, path string, body interface{}) (*apiResponse, error) {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = strings.NewReader(string(encoded))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.server+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("server returned status %d with an unreadable body: %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if result.Error == "" {
+			result.Error = http.StatusText(resp.StatusCode)
+		}
+		return nil, fmt.Errorf("server returned status %d: %s", resp.StatusCode, result.Error)
+	}
+	return &result, nil
+}
+
+// envOrDefault returns the environment variable, or defaultValue when it is unset
+func envOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
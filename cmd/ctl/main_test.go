@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeServer answers the task routes ctl uses from a map of task ID to status
+func fakeServer(t *testing.T, statuses map[string]string, actions *[]map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/api/tasks")
+
+		switch {
+		case r.Method == "GET" && path == "":
+			if r.URL.Query().Get("status") != "pending" {
+				t.Errorf("Expected list to ask for pending tasks, got %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"success":true,"tasks":[
+				{"id":"task-2","hook_type":"PreToolUse","status":"pending","hook_data":{"data":{"session_id":"abcdef123456","tool_name":"Bash","tool_input":{"command":"docker compose up"}}}},
+				{"id":"task-1","hook_type":"Notification","status":"pending","hook_data":{"data":{"message":"Claude needs your permission"}}}
+			],"count":2}`))
+
+		case r.Method == "GET":
+			status, ok := statuses[strings.TrimPrefix(path, "/")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"success":false,"error":"Task not found"}`))
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "task": map[string]string{"status": status}})
+
+		case r.Method == "POST" && strings.HasSuffix(path, "/action"):
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			*actions = append(*actions, body)
+			w.Write([]byte(`{"success":true}`))
+
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestRun_List(t *testing.T) {
+	server := fakeServer(t, nil, nil)
+	defer server.Close()
+
+	var out bytes.Buffer
+	if err := run(context.Background(), []string{"--server", server.URL, "list"}, &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{"ID", "task-2", "abcdef12", "Bash: docker compose up", "task-1", "Claude needs your permission"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %q in the table, got:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestRun_ApproveReject(t *testing.T) {
+	statuses := map[string]string{"pending-task": "pending", "approved-task": "approved"}
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectErr      bool
+		expectUsage    bool
+		expectedAction map[string]string
+	}{
+		{"approve", []string{"approve", "pending-task"}, false, false, map[string]string{"action": "approve", "comment": ""}},
+		{"reject with reason after ID", []string{"reject", "pending-task", "--reason", "wrong branch"}, false, false, map[string]string{"action": "reject", "comment": "wrong branch"}},
+		{"reject with reason before ID", []string{"reject", "--reason", "no", "pending-task"}, false, false, map[string]string{"action": "reject", "comment": "no"}},
+		{"unknown task", []string{"approve", "missing-task"}, true, false, nil},
+		{"already decided", []string{"reject", "approved-task"}, true, false, nil},
+		{"missing ID", []string{"approve"}, true, true, nil},
+		{"reason on approve", []string{"approve", "pending-task", "--reason", "ok"}, true, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actions []map[string]string
+			server := fakeServer(t, statuses, &actions)
+			defer server.Close()
+
+			var out bytes.Buffer
+			err := run(context.Background(), append([]string{"--server", server.URL}, tt.args...), &out)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("run() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if errors.Is(err, errUsage) != tt.expectUsage {
+				t.Errorf("Expected usage error %v, got %v", tt.expectUsage, err)
+			}
+
+			if tt.expectedAction == nil {
+				if len(actions) != 0 {
+					t.Errorf("Expected no action to be sent, got %v", actions)
+				}
+				return
+			}
+			if len(actions) != 1 || actions[0]["action"] != tt.expectedAction["action"] || actions[0]["comment"] != tt.expectedAction["comment"] {
+				t.Errorf("Expected action %v, got %v", tt.expectedAction, actions)
+			}
+		})
+	}
+}
+
+func TestRun_WatchPrintsEachTaskOnce(t *testing.T) {
+	server := fakeServer(t, nil, nil)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var out bytes.Buffer
+	if err := run(ctx, []string{"--server", server.URL, "watch", "--interval", "10ms"}, &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Oldest first, each once, under a single header
+	output := out.String()
+	if strings.Count(output, "task-1") != 1 || strings.Count(output, "task-2") != 1 || strings.Count(output, "DETAIL") != 1 {
+		t.Errorf("Expected one header and each task once, got:\n%s", output)
+	}
+	if strings.Index(output, "task-1") > strings.Index(output, "task-2") {
+		t.Errorf("Expected the older task first, got:\n%s", output)
+	}
+}
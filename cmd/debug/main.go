@@ -100,6 +100,8 @@ func main() {
     
     <h2>Other Endpoints:</h2>
     <div class="endpoint"><code>GET /health</code> - Health check</div>
+    <div class="endpoint"><code>GET /debug/configure</code> - List configured response templates</div>
+    <div class="endpoint"><code>POST /debug/configure</code> - Set the response returned for a hook type</div>
     
     <p>See the server logs for formatted webhook request details when endpoints are called.</p>
     <p>For usage instructions, see <code>DEBUG_WEBHOOK_USAGE.md</code></p>
@@ -0,0 +1,67 @@
+// Command decrypt-notification prints the plaintext of NTFY notifications encrypted with NTFY_ENCRYPTION_KEY.
+//
+// It reads the key from the first line of stdin and decrypts each following non-empty line, such as a
+// notification's title and message copied from the NTFY app, printing one plaintext line per input line:
+//
+//	printf '%s\n%s\n' "$NTFY_ENCRYPTION_KEY" "<encrypted message>" | go run ./cmd/decrypt-notification
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dan/claude-control/internal/adapters/ntfy"
+)
+
+// maxLineBytes is the longest encrypted message accepted on one line
+const maxLineBytes = 1024 * 1024
+
+func main() {
+	if err := run(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "decrypt-notification: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run decrypts every message after the key line of in and writes the plaintexts to out
+func run(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read key: %w", err)
+		}
+		return fmt.Errorf("expected the encryption key on the first line of stdin")
+	}
+	key := strings.TrimSpace(scanner.Text())
+	if key == "" {
+		return fmt.Errorf("encryption key is empty")
+	}
+	fmt.Fprintf(os.Stderr, "Using key fingerprint %s\n", ntfy.KeyFingerprint(key))
+
+	decrypted := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		plaintext, err := ntfy.DecryptMessage(key, line)
+		if err != nil {
+			return fmt.Errorf("message %d: %w", decrypted+1, err)
+		}
+		fmt.Fprintln(out, plaintext)
+		decrypted++
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read messages: %w", err)
+	}
+	if decrypted == 0 {
+		return fmt.Errorf("expected at least one encrypted message after the key")
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+// writeConfigFile writes a YAML config file into a temporary directory and returns its path
+func writeConfigFile(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfig_YAMLFile(t *testing.T) {
+	path := writeConfigFile(t, `
+server_port: "9090"
+database_url: postgresql://file@db/claude_control
+ntfy_topic: from-file
+ntfy_topic_from_cwd: true
+analyze_tool_output: false
+cors_allowed_origins:
+  - https://a.example.com
+  - https://b.example.com
+notification_retry:
+  max_retries: 5
+  initial_delay: 1s
+decision_buffer_size: 4
+hook_timeouts:
+  PreToolUse: 10m
+`)
+
+	// Environment variables win over the file
+	t.Setenv("NTFY_TOPIC", "from-env")
+	t.Setenv("TIMEOUT_STOP", "2m")
+	t.Setenv("BLOCKING_HOOK_TYPES", "PreToolUse, Stop")
+	t.Setenv("LAST_DECISION_WINS", "true")
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if config.ServerPort != "9090" || config.DatabaseURL != "postgresql://file@db/claude_control" {
+		t.Errorf("Expected server port and database URL from the file, got %q and %q", config.ServerPort, config.DatabaseURL)
+	}
+	if config.NTFYTopic != "from-env" {
+		t.Errorf("Expected NTFY_TOPIC to override the file, got %q", config.NTFYTopic)
+	}
+	if !config.NTFYTopicFromCWD || config.AnalyzeToolOutput {
+		t.Errorf("Expected booleans from the file, got ntfy_topic_from_cwd=%v analyze_tool_output=%v", config.NTFYTopicFromCWD, config.AnalyzeToolOutput)
+	}
+	if strings.Join(config.CORSAllowedOrigins, ",") != "https://a.example.com,https://b.example.com" {
+		t.Errorf("Expected CORS origins from the file, got %v", config.CORSAllowedOrigins)
+	}
+	if config.NotificationRetry.MaxRetries != 5 || config.NotificationRetry.InitialDelay != time.Second {
+		t.Errorf("Expected retry settings from the file, got %+v", config.NotificationRetry)
+	}
+	if config.NotificationRetry.MaxDelay != defaultConfig().NotificationRetry.MaxDelay {
+		t.Errorf("Expected keys missing from the file to keep their defaults, got max delay %v", config.NotificationRetry.MaxDelay)
+	}
+	if config.HookTimeouts[domain.HookTypePreToolUse] != 10*time.Minute || config.HookTimeouts[domain.HookTypeStop] != 2*time.Minute {
+		t.Errorf("Expected hook timeouts from both the file and the environment, got %v", config.HookTimeouts)
+	}
+	if expected := []domain.HookType{domain.HookTypePreToolUse, domain.HookTypeStop}; !reflect.DeepEqual(config.BlockingHookTypes, expected) {
+		t.Errorf("Expected blocking hook types %v from the environment, got %v", expected, config.BlockingHookTypes)
+	}
+	if config.DecisionBufferSize != 4 || !config.LastDecisionWins {
+		t.Errorf("Expected decision buffer size from the file and last-decision-wins from the environment, got %d and %v", config.DecisionBufferSize, config.LastDecisionWins)
+	}
+	if config.WebDomain != "localhost:8080" {
+		t.Errorf("Expected the default web domain, got %q", config.WebDomain)
+	}
+}
+
+func TestLoadConfig_ExampleFile(t *testing.T) {
+	config, err := LoadConfig(filepath.Join("..", "..", "config.example.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to load config.example.yaml: %v", err)
+	}
+
+	// The example documents the defaults, so loading it should change nothing
+	if expected := defaultConfig(); !reflect.DeepEqual(config, expected) {
+		t.Errorf("Expected config.example.yaml to match the defaults\ngot:      %+v\nexpected: %+v", config, expected)
+	}
+}
+
+func TestLoadConfig_BlockPreToolUse(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		env      map[string]string
+		expected []domain.HookType
+	}{
+		{
+			name:     "Unset follows the blocking hook types",
+			env:      map[string]string{"BLOCKING_HOOK_TYPES": "UserPromptSubmit"},
+			expected: []domain.HookType{domain.HookTypeUserPromptSubmit},
+		},
+		{
+			name:     "Enabled adds PreToolUse",
+			env:      map[string]string{"BLOCKING_HOOK_TYPES": "UserPromptSubmit", "BLOCK_PRE_TOOL_USE": "true"},
+			expected: []domain.HookType{domain.HookTypePreToolUse, domain.HookTypeUserPromptSubmit},
+		},
+		{
+			name:     "Disabled removes PreToolUse from the defaults",
+			env:      map[string]string{"BLOCK_PRE_TOOL_USE": "false"},
+			expected: []domain.HookType{domain.HookTypeUserPromptSubmit},
+		},
+		{
+			name:     "Enabled with 1",
+			env:      map[string]string{"BLOCKING_HOOK_TYPES": "UserPromptSubmit", "BLOCK_PRE_TOOL_USE": "1"},
+			expected: []domain.HookType{domain.HookTypePreToolUse, domain.HookTypeUserPromptSubmit},
+		},
+		{
+			name:     "Invalid value keeps the config file setting",
+			contents: "blocking_hook_types: [Stop]\nblock_pre_tool_use: true",
+			env:      map[string]string{"BLOCK_PRE_TOOL_USE": "yes"},
+			expected: []domain.HookType{domain.HookTypePreToolUse, domain.HookTypeStop},
+		},
+		{
+			name:     "Set in the config file",
+			contents: "blocking_hook_types: [Stop]\nblock_pre_tool_use: true",
+			expected: []domain.HookType{domain.HookTypePreToolUse, domain.HookTypeStop},
+		},
+		{
+			name:     "Already listed",
+			contents: "block_pre_tool_use: true",
+			expected: []domain.HookType{domain.HookTypePreToolUse, domain.HookTypeUserPromptSubmit},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			config, err := LoadConfig(writeConfigFile(t, tt.contents))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(config.BlockingHookTypes, tt.expected) {
+				t.Errorf("Expected blocking hook types %v, got %v", tt.expected, config.BlockingHookTypes)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		contents    string
+		env         map[string]string
+		expectedErr []string
+	}{
+		{
+			name:        "Empty database URL",
+			contents:    `database_url: ""`,
+			expectedErr: []string{"database_url"},
+		},
+		{
+			name:        "Backend credentials missing",
+			contents:    "notification_backend: email\nsmtp_host: smtp.example.com",
+			expectedErr: []string{"email_from (required for the email backend)", "email_to (required for the email backend)"},
+		},
+		{
+			name:        "Backend chosen by environment",
+			env:         map[string]string{"NOTIFICATION_BACKEND": "slack"},
+			expectedErr: []string{"slack_webhook_url"},
+		},
+		{
+			name:        "Forward URL without a scheme",
+			contents:    `forward_webhook_url: backup.example.com/webhook`,
+			expectedErr: []string{"forward_webhook_url must be an http or https URL"},
+		},
+		{
+			name:        "Unknown blocking hook type",
+			env:         map[string]string{"BLOCKING_HOOK_TYPES": "PreToolUse,PostToolUs"},
+			expectedErr: []string{`unknown hook type "PostToolUs"`},
+		},
+		{
+			name:        "Unknown database driver",
+			env:         map[string]string{"DATABASE_DRIVER": "mysql"},
+			expectedErr: []string{`database_driver must be postgres or sqlite, got "mysql"`},
+		},
+		{
+			name:        "SQLite with a PostgreSQL URL",
+			contents:    `database_driver: sqlite`,
+			expectedErr: []string{"database_url must be a file path for the sqlite driver"},
+		},
+		{
+			name:        "No Claude Code calls allowed",
+			contents:    `max_concurrent_claude_calls: 0`,
+			expectedErr: []string{"max_concurrent_claude_calls must be at least 1, got 0"},
+		},
+		{
+			name:        "Misspelt key",
+			contents:    `databse_url: postgresql://db`,
+			expectedErr: []string{"databse_url"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			_, err := LoadConfig(writeConfigFile(t, tt.contents))
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			for _, expected := range tt.expectedErr {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("Expected %q in error, got %v", expected, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfig_MissingFile(t *testing.T) {
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected an error for a config file that doesn't exist")
+	}
+}
@@ -9,9 +9,33 @@ CREATE TABLE IF NOT EXISTS tasks (
     created_at TIMESTAMP DEFAULT NOW(),
     updated_at TIMESTAMP DEFAULT NOW(),
     action_taken VARCHAR(50),
-    response_data JSONB
+    response_data JSONB,
+    snoozed_until TIMESTAMPTZ
 );
 
+-- Add columns introduced after the tasks table was first created
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS snoozed_until TIMESTAMPTZ;
+-- Session ID copied out of the hook data so lookups by session don't parse task_data
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS session_id VARCHAR(255) GENERATED ALWAYS AS (task_data->'data'->>'session_id') STORED;
+-- Task this one depends on, e.g. the tool call a subagent was started for; rejecting it cancels the child
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_task_id UUID REFERENCES tasks(id) ON DELETE SET NULL;
+
+-- Create task archive table (completed tasks moved out of tasks by the nightly archive job)
+CREATE TABLE IF NOT EXISTS tasks_archive (
+    id UUID PRIMARY KEY,
+    hook_type VARCHAR(50) NOT NULL,
+    task_data JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    action_taken VARCHAR(50),
+    response_data JSONB,
+    snoozed_until TIMESTAMPTZ,
+    archived_at TIMESTAMP DEFAULT NOW() NOT NULL
+);
+ALTER TABLE tasks_archive ADD COLUMN IF NOT EXISTS snoozed_until TIMESTAMPTZ;
+ALTER TABLE tasks_archive ADD COLUMN IF NOT EXISTS parent_task_id UUID;
+
 -- Create task history table
 CREATE TABLE IF NOT EXISTS task_history (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -21,12 +45,62 @@ CREATE TABLE IF NOT EXISTS task_history (
     created_at TIMESTAMP DEFAULT NOW()
 );
 
+-- Create sessions table (one row per Claude Code session)
+CREATE TABLE IF NOT EXISTS sessions (
+    id VARCHAR(255) PRIMARY KEY,
+    subagent_ids TEXT[] DEFAULT '{}' NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    last_activity_at TIMESTAMP,
+    last_hook_type VARCHAR(50)
+);
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS last_activity_at TIMESTAMP;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS last_hook_type VARCHAR(50);
+
+-- Create session events table (append-only log of hook events)
+CREATE TABLE IF NOT EXISTS session_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id VARCHAR(255) REFERENCES sessions(id) ON DELETE CASCADE,
+    hook_type VARCHAR(50) NOT NULL,
+    cwd TEXT,
+    transcript_path TEXT,
+    event_data JSONB,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Create server settings table (runtime settings toggled by operators)
+CREATE TABLE IF NOT EXISTS server_settings (
+    setting_name VARCHAR(100) PRIMARY KEY,
+    setting_value TEXT NOT NULL,
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Create pending decisions table (blocking webhooks waiting on the user; rows left after a restart were never answered)
+CREATE TABLE IF NOT EXISTS pending_decisions (
+    id SERIAL PRIMARY KEY,
+    task_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
 -- Create indexes for better performance
 CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status);
 CREATE INDEX IF NOT EXISTS idx_tasks_hook_type ON tasks(hook_type);
 CREATE INDEX IF NOT EXISTS idx_tasks_created_at ON tasks(created_at);
+CREATE INDEX IF NOT EXISTS idx_tasks_snoozed_until ON tasks(snoozed_until) WHERE snoozed_until IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_session_id ON tasks(session_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_task_id ON tasks(parent_task_id) WHERE parent_task_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_pending_session ON tasks((task_data->'data'->>'session_id'), created_at) WHERE status = 'pending';
+-- Trigram index so task searches (task_data::text ILIKE '%...%') don't scan every row
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS idx_tasks_task_data_search ON tasks USING GIN ((task_data::text) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_tasks_archive_created_at ON tasks_archive(created_at);
 CREATE INDEX IF NOT EXISTS idx_task_history_task_id ON task_history(task_id);
 CREATE INDEX IF NOT EXISTS idx_task_history_created_at ON task_history(created_at);
+CREATE INDEX IF NOT EXISTS idx_sessions_subagent_ids ON sessions USING GIN (subagent_ids);
+CREATE INDEX IF NOT EXISTS idx_session_events_session_id ON session_events(session_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_session_events_hook_type ON session_events(hook_type);
+CREATE INDEX IF NOT EXISTS idx_pending_decisions_task_id ON pending_decisions(task_id);
 
 -- Insert some sample data for testing (optional)
 -- INSERT INTO tasks (hook_type, task_data, status) VALUES 
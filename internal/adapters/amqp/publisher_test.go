@@ -0,0 +1,257 @@
+package amqp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/services"
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// publishedMessage is a message the fake channel accepted
+type publishedMessage struct {
+	exchange   string
+	routingKey string
+	message    amqp.Publishing
+}
+
+// fakeBroker hands out fake channels and records what is published on them
+type fakeBroker struct {
+	dials       int
+	dialErrs    []error // Returned by successive dials, then nil
+	publishErrs []error // Returned by successive publishes, then nil
+	exchanges   []string
+	published   []publishedMessage
+	closed      int
+}
+
+func (b *fakeBroker) dial(url string) (Channel, error) {
+	b.dials++
+	if len(b.dialErrs) > 0 {
+		err := b.dialErrs[0]
+		b.dialErrs = b.dialErrs[1:]
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &fakeChannel{broker: b}, nil
+}
+
+// fakeChannel publishes to its broker
+type fakeChannel struct {
+	broker *fakeBroker
+}
+
+func (c *fakeChannel) ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error {
+	if kind != amqp.ExchangeTopic || !durable {
+		return errors.New("expected a durable topic exchange")
+	}
+	c.broker.exchanges = append(c.broker.exchanges, name)
+	return nil
+}
+
+func (c *fakeChannel) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	if len(c.broker.publishErrs) > 0 {
+		err := c.broker.publishErrs[0]
+		c.broker.publishErrs = c.broker.publishErrs[1:]
+		if err != nil {
+			return err
+		}
+	}
+	c.broker.published = append(c.broker.published, publishedMessage{exchange: exchange, routingKey: key, message: msg})
+	return nil
+}
+
+func (c *fakeChannel) Close() error {
+	c.broker.closed++
+	return nil
+}
+
+// fakeEventSource hands the publisher a channel of events
+type fakeEventSource struct {
+	events       chan services.TaskEvent
+	unsubscribed bool
+}
+
+func (s *fakeEventSource) SubscribeTaskEvents() (<-chan services.TaskEvent, func()) {
+	return s.events, func() { s.unsubscribed = true }
+}
+
+// newTestPublisher connects to broker and records the waits between attempts instead of sleeping
+func newTestPublisher(config Config, broker *fakeBroker) (*Publisher, *[]time.Duration) {
+	var waits []time.Duration
+	publisher := NewPublisher(config)
+	publisher.dial = broker.dial
+	publisher.wait = func(ctx context.Context, delay time.Duration) error {
+		waits = append(waits, delay)
+		return ctx.Err()
+	}
+	return publisher, &waits
+}
+
+func newTestEvent(eventType services.TaskEventType, requestID string) services.TaskEvent {
+	return services.TaskEvent{
+		ID:        7,
+		UUID:      uuid.New(),
+		Type:      eventType,
+		Task:      &domain.Task{ID: uuid.New(), Status: domain.TaskStatusPending},
+		RequestID: requestID,
+	}
+}
+
+func TestPublisher_Publish(t *testing.T) {
+	tests := []struct {
+		name               string
+		config             Config
+		eventType          services.TaskEventType
+		expectedExchange   string
+		expectedRoutingKey string
+	}{
+		{"Defaults", Config{}, services.TaskEventCreated, DefaultExchange, "claude.task.created"},
+		{"Updated event", Config{}, services.TaskEventUpdated, DefaultExchange, "claude.task.updated"},
+		{"Configured exchange and prefix", Config{Exchange: "ops", RoutingKeyPrefix: "haiper.tasks"}, services.TaskEventDeleted, "ops", "haiper.tasks.deleted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := &fakeBroker{}
+			publisher, waits := newTestPublisher(tt.config, broker)
+			event := newTestEvent(tt.eventType, "req-123")
+
+			if err := publisher.Publish(context.Background(), event); err != nil {
+				t.Fatalf("Publish failed: %v", err)
+			}
+
+			if len(*waits) != 0 {
+				t.Errorf("Expected no reconnect waits, got %v", *waits)
+			}
+			if len(broker.exchanges) != 1 || broker.exchanges[0] != tt.expectedExchange {
+				t.Errorf("Expected exchange %s to be declared once, got %v", tt.expectedExchange, broker.exchanges)
+			}
+			if len(broker.published) != 1 {
+				t.Fatalf("Expected 1 published message, got %d", len(broker.published))
+			}
+
+			published := broker.published[0]
+			if published.exchange != tt.expectedExchange {
+				t.Errorf("Expected exchange %s, got %s", tt.expectedExchange, published.exchange)
+			}
+			if published.routingKey != tt.expectedRoutingKey {
+				t.Errorf("Expected routing key %s, got %s", tt.expectedRoutingKey, published.routingKey)
+			}
+
+			message := published.message
+			if message.MessageId != event.UUID.String() {
+				t.Errorf("Expected message ID %s, got %s", event.UUID, message.MessageId)
+			}
+			if message.CorrelationId != "req-123" {
+				t.Errorf("Expected correlation ID req-123, got %q", message.CorrelationId)
+			}
+			if message.DeliveryMode != amqp.Persistent {
+				t.Errorf("Expected persistent delivery, got mode %d", message.DeliveryMode)
+			}
+			if message.ContentType != "application/json" {
+				t.Errorf("Expected application/json, got %s", message.ContentType)
+			}
+
+			var decoded services.TaskEvent
+			if err := json.Unmarshal(message.Body, &decoded); err != nil {
+				t.Fatalf("Failed to decode message body: %v", err)
+			}
+			if decoded.UUID != event.UUID || decoded.Type != tt.eventType || decoded.Task.ID != event.Task.ID {
+				t.Errorf("Decoded event %+v does not match %+v", decoded, event)
+			}
+		})
+	}
+}
+
+func TestPublisher_PublishReconnects(t *testing.T) {
+	brokerDown := errors.New("connection refused")
+	channelClosed := errors.New("channel/connection is not open")
+
+	tests := []struct {
+		name          string
+		dialErrs      []error
+		publishErrs   []error
+		expectedDials int
+		expectedWaits []time.Duration
+	}{
+		{"Publish fails once", nil, []error{channelClosed}, 2, []time.Duration{InitialReconnectDelay}},
+		{"Broker down for a while", []error{nil, brokerDown, brokerDown}, []error{channelClosed}, 4,
+			[]time.Duration{InitialReconnectDelay, 2 * InitialReconnectDelay, 4 * InitialReconnectDelay}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := &fakeBroker{dialErrs: tt.dialErrs, publishErrs: tt.publishErrs}
+			publisher, waits := newTestPublisher(Config{}, broker)
+
+			if err := publisher.Publish(context.Background(), newTestEvent(services.TaskEventCreated, "")); err != nil {
+				t.Fatalf("Publish failed: %v", err)
+			}
+
+			if broker.dials != tt.expectedDials {
+				t.Errorf("Expected %d dials, got %d", tt.expectedDials, broker.dials)
+			}
+			if len(*waits) != len(tt.expectedWaits) {
+				t.Fatalf("Expected waits %v, got %v", tt.expectedWaits, *waits)
+			}
+			for i, wait := range tt.expectedWaits {
+				if (*waits)[i] != wait {
+					t.Errorf("Wait %d: expected %s, got %s", i, wait, (*waits)[i])
+				}
+			}
+			if len(broker.published) != 1 {
+				t.Errorf("Expected the event to be published once, got %d", len(broker.published))
+			}
+			if broker.closed != 1 {
+				t.Errorf("Expected the failed channel to be closed, got %d closes", broker.closed)
+			}
+		})
+	}
+}
+
+func TestPublisher_PublishGivesUpWhenCancelled(t *testing.T) {
+	broker := &fakeBroker{dialErrs: []error{errors.New("connection refused")}}
+	publisher, _ := newTestPublisher(Config{}, broker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := publisher.Publish(ctx, newTestEvent(services.TaskEventCreated, "")); err == nil {
+		t.Fatal("Expected an error once the context is cancelled")
+	}
+	if len(broker.published) != 0 {
+		t.Errorf("Expected nothing published, got %d", len(broker.published))
+	}
+}
+
+func TestPublisher_Run(t *testing.T) {
+	broker := &fakeBroker{}
+	publisher, _ := newTestPublisher(Config{}, broker)
+	source := &fakeEventSource{events: make(chan services.TaskEvent, 2)}
+
+	source.events <- newTestEvent(services.TaskEventCreated, "")
+	source.events <- newTestEvent(services.TaskEventUpdated, "")
+	close(source.events)
+
+	publisher.Run(context.Background(), source)
+
+	if len(broker.published) != 2 {
+		t.Fatalf("Expected 2 published messages, got %d", len(broker.published))
+	}
+	if broker.published[1].routingKey != "claude.task.updated" {
+		t.Errorf("Expected events in order, got %s second", broker.published[1].routingKey)
+	}
+	if !source.unsubscribed {
+		t.Error("Expected Run to unsubscribe when it returns")
+	}
+	if broker.closed != 1 {
+		t.Errorf("Expected Run to close its channel, got %d closes", broker.closed)
+	}
+}
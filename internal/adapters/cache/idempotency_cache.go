@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+)
+
+const (
+	// DefaultIdempotencyCacheSize is how many hook responses are kept before the least recently used is evicted
+	DefaultIdempotencyCacheSize = 1000
+
+	// DefaultIdempotencyTTL is how long a hook response can be replayed, long enough to cover a server restart
+	DefaultIdempotencyTTL = 10 * time.Minute
+)
+
+// Ensure IdempotencyCache satisfies the IdempotencyCache port
+var _ ports.IdempotencyCache = (*IdempotencyCache)(nil)
+
+// IdempotencyCache keeps hook responses in memory, bounded in both size and age
+// Entries are lost on restart, so only retries to the same instance are deduplicated.
+type IdempotencyCache struct {
+	responses *expirable.LRU[string, *domain.HookResponse]
+}
+
+// NewIdempotencyCache creates a cache holding up to size responses for ttl each
+func NewIdempotencyCache(size int, ttl time.Duration) *IdempotencyCache {
+	return &IdempotencyCache{
+		responses: expirable.NewLRU[string, *domain.HookResponse](size, nil, ttl),
+	}
+}
+
+// Get returns the response cached for key, if it hasn't expired
+func (c *IdempotencyCache) Get(key string) (*domain.HookResponse, bool) {
+	return c.responses.Get(key)
+}
+
+// Set caches the response sent for key
+func (c *IdempotencyCache) Set(key string, resp *domain.HookResponse) {
+	c.responses.Add(key, resp)
+}
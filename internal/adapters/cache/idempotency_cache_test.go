@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+func TestIdempotencyCache_GetSet(t *testing.T) {
+	c := NewIdempotencyCache(2, time.Minute)
+
+	if _, ok := c.Get("first"); ok {
+		t.Fatal("Expected a miss on an empty cache")
+	}
+
+	response := &domain.HookResponse{Continue: true, SuppressOutput: true}
+	c.Set("first", response)
+	if got, ok := c.Get("first"); !ok || got != response {
+		t.Errorf("Expected the cached response, got %+v (found %t)", got, ok)
+	}
+
+	// Adding past the size evicts the least recently used key
+	c.Set("second", &domain.HookResponse{Continue: true})
+	c.Get("first")
+	c.Set("third", &domain.HookResponse{Continue: false})
+	if _, ok := c.Get("second"); ok {
+		t.Error("Expected the least recently used key to be evicted")
+	}
+	if _, ok := c.Get("first"); !ok {
+		t.Error("Expected a recently used key to be kept")
+	}
+}
+
+func TestIdempotencyCache_Expiry(t *testing.T) {
+	c := NewIdempotencyCache(10, 20*time.Millisecond)
+	c.Set("key", &domain.HookResponse{Continue: true})
+
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := c.Get("key"); ok {
+		t.Error("Expected the response to expire")
+	}
+}
@@ -2,21 +2,37 @@ package claude
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os/exec"
+	"regexp"
 	"time"
+
+	"github.com/dan/claude-control/internal/core/ports"
 )
 
+// DefaultClaudeSessionNamePattern matches tmux sessions assumed to be running Claude Code
+const DefaultClaudeSessionNamePattern = "^claude"
+
+// listSessionsUnsupportedExitCode is the CLI's exit code for an unknown flag
+const listSessionsUnsupportedExitCode = 2
+
 // ClaudeCodeAdapter handles interaction with Claude Code CLI for specific webhook types
 type ClaudeCodeAdapter struct {
 	claudeBinaryPath string
 	defaultTimeout   time.Duration
+
+	// Optional fallback for GetSessionList when the CLI can't list sessions
+	tmuxController     ports.TMuxController
+	sessionNamePattern *regexp.Regexp
 }
 
 // ClaudeSession represents a Claude Code session that can receive input
 type ClaudeSession struct {
-	SessionID string
-	HookType  string
+	SessionID   string `json:"session_id,omitempty"`
+	HookType    string `json:"hook_type,omitempty"`
+	CWD         string `json:"cwd,omitempty"`
+	TMuxSession string `json:"tmux_session,omitempty"` // Set when the session was found through tmux
 }
 
 // ClaudeResponse represents the result of sending input to Claude Code
@@ -102,4 +118,65 @@ func (c *ClaudeCodeAdapter) SetTimeout(timeout time.Duration) {
 // GetTimeout returns the current timeout setting
 func (c *ClaudeCodeAdapter) GetTimeout() time.Duration {
 	return c.defaultTimeout
-}
\ No newline at end of file
+}
+
+// SetTMuxFallback configures GetSessionList to list tmux sessions whose names match namePattern
+// when the Claude Code CLI does not support --list-sessions
+func (c *ClaudeCodeAdapter) SetTMuxFallback(tmuxController ports.TMuxController, namePattern string) error {
+	if namePattern == "" {
+		namePattern = DefaultClaudeSessionNamePattern
+	}
+
+	pattern, err := regexp.Compile(namePattern)
+	if err != nil {
+		return fmt.Errorf("invalid Claude session name pattern %q: %w", namePattern, err)
+	}
+
+	c.tmuxController = tmuxController
+	c.sessionNamePattern = pattern
+	return nil
+}
+
+// GetSessionList enumerates running Claude Code sessions using `claude --list-sessions`
+// Falls back to matching tmux session names if the CLI rejects the flag and a fallback is configured.
+func (c *ClaudeCodeAdapter) GetSessionList(ctx context.Context) ([]ClaudeSession, error) {
+	cmdCtx, cancel := context.WithTimeout(ctx, c.defaultTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, c.claudeBinaryPath, "--list-sessions")
+	output, err := cmd.Output()
+	if err != nil {
+		if exitError, ok := err.(*exec.ExitError); ok && exitError.ExitCode() == listSessionsUnsupportedExitCode {
+			return c.getTMuxSessionList(ctx)
+		}
+		return nil, fmt.Errorf("failed to list Claude Code sessions: %w", err)
+	}
+
+	sessions := []ClaudeSession{}
+	if err := json.Unmarshal(output, &sessions); err != nil {
+		return nil, fmt.Errorf("failed to parse Claude Code session list: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// getTMuxSessionList lists tmux sessions whose names match the configured Claude session pattern
+func (c *ClaudeCodeAdapter) getTMuxSessionList(ctx context.Context) ([]ClaudeSession, error) {
+	if c.tmuxController == nil || c.sessionNamePattern == nil {
+		return nil, fmt.Errorf("Claude Code CLI does not support --list-sessions and no tmux fallback is configured")
+	}
+
+	tmuxSessions, err := c.tmuxController.ListSessions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tmux sessions: %w", err)
+	}
+
+	sessions := []ClaudeSession{}
+	for _, tmuxSession := range tmuxSessions {
+		if c.sessionNamePattern.MatchString(tmuxSession.Name) {
+			sessions = append(sessions, ClaudeSession{TMuxSession: tmuxSession.Name})
+		}
+	}
+
+	return sessions, nil
+}
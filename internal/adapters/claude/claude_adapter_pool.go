@@ -0,0 +1,89 @@
+package claude
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMaxConcurrentCalls is the default number of concurrent Claude Code CLI calls
+const DefaultMaxConcurrentCalls = 5
+
+// ClaudeCodeAdapterPool limits concurrent Claude Code CLI calls across sessions
+// Each pooled adapter doubles as a semaphore slot, so at most maxConcurrent calls run at once
+type ClaudeCodeAdapterPool struct {
+	adapters      chan *ClaudeCodeAdapter
+	maxConcurrent int
+	waiting       int64
+}
+
+// NewClaudeCodeAdapterPool creates a pool of Claude Code CLI adapters
+func NewClaudeCodeAdapterPool(claudeBinaryPath string, maxConcurrent int) *ClaudeCodeAdapterPool {
+	if maxConcurrent <= 0 {
+		maxConcurrent = DefaultMaxConcurrentCalls
+	}
+
+	pool := &ClaudeCodeAdapterPool{
+		adapters:      make(chan *ClaudeCodeAdapter, maxConcurrent),
+		maxConcurrent: maxConcurrent,
+	}
+
+	for i := 0; i < maxConcurrent; i++ {
+		pool.adapters <- NewClaudeCodeAdapter(claudeBinaryPath)
+	}
+
+	return pool
+}
+
+// SendInputToStopWebhook waits for a free adapter and sends user-defined input to a Claude Code session
+func (p *ClaudeCodeAdapterPool) SendInputToStopWebhook(ctx context.Context, sessionID, userInput string) (*ClaudeResponse, error) {
+	adapter, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer p.release(adapter)
+
+	return adapter.SendInputToStopWebhook(ctx, sessionID, userInput)
+}
+
+// WaitQueueDepth returns the number of calls currently waiting for a free adapter
+func (p *ClaudeCodeAdapterPool) WaitQueueDepth() int {
+	return int(atomic.LoadInt64(&p.waiting))
+}
+
+// MaxConcurrent returns the maximum number of concurrent Claude Code CLI calls
+func (p *ClaudeCodeAdapterPool) MaxConcurrent() int {
+	return p.maxConcurrent
+}
+
+// SetTimeout configures the timeout for all pooled adapters
+// It takes every adapter out of the pool first, so it blocks until in-flight calls have finished
+func (p *ClaudeCodeAdapterPool) SetTimeout(timeout time.Duration) {
+	held := make([]*ClaudeCodeAdapter, 0, p.maxConcurrent)
+	for i := 0; i < p.maxConcurrent; i++ {
+		held = append(held, <-p.adapters)
+	}
+
+	for _, adapter := range held {
+		adapter.SetTimeout(timeout)
+		p.adapters <- adapter
+	}
+}
+
+// acquire takes an adapter from the pool, blocking until one is free or the context is done
+func (p *ClaudeCodeAdapterPool) acquire(ctx context.Context) (*ClaudeCodeAdapter, error) {
+	atomic.AddInt64(&p.waiting, 1)
+	defer atomic.AddInt64(&p.waiting, -1)
+
+	select {
+	case adapter := <-p.adapters:
+		return adapter, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// release returns an adapter to the pool
+func (p *ClaudeCodeAdapterPool) release(adapter *ClaudeCodeAdapter) {
+	p.adapters <- adapter
+}
@@ -0,0 +1,124 @@
+package claude
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeFakeClaude writes a shell script that sleeps before echoing its arguments
+func writeFakeClaude(t *testing.T, sleep string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "claude")
+	script := fmt.Sprintf("#!/bin/sh\nsleep %s\necho \"$@\"\n", sleep)
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake claude binary: %v", err)
+	}
+	return path
+}
+
+func TestNewClaudeCodeAdapterPool(t *testing.T) {
+	pool := NewClaudeCodeAdapterPool("echo", 0)
+	if pool.MaxConcurrent() != DefaultMaxConcurrentCalls {
+		t.Errorf("Expected default max concurrent %d, got %d", DefaultMaxConcurrentCalls, pool.MaxConcurrent())
+	}
+
+	pool = NewClaudeCodeAdapterPool("echo", 3)
+	if pool.MaxConcurrent() != 3 {
+		t.Errorf("Expected max concurrent 3, got %d", pool.MaxConcurrent())
+	}
+}
+
+func TestClaudeCodeAdapterPool_ConcurrentCalls(t *testing.T) {
+	pool := NewClaudeCodeAdapterPool(writeFakeClaude(t, "0.05"), 3)
+	ctx := context.Background()
+
+	const calls = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, calls)
+
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sessionID := fmt.Sprintf("session-%d", i)
+			response, err := pool.SendInputToStopWebhook(ctx, sessionID, "continue")
+			if err != nil {
+				errs <- err
+				return
+			}
+			if expected := fmt.Sprintf("-r %s continue\n", sessionID); response.Output != expected {
+				errs <- fmt.Errorf("expected output %q, got %q", expected, response.Output)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Concurrent call failed: %v", err)
+	}
+
+	if depth := pool.WaitQueueDepth(); depth != 0 {
+		t.Errorf("Expected empty wait queue after all calls, got %d", depth)
+	}
+}
+
+func TestClaudeCodeAdapterPool_WaitQueueDepth(t *testing.T) {
+	pool := NewClaudeCodeAdapterPool(writeFakeClaude(t, "0.5"), 1)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pool.SendInputToStopWebhook(ctx, "test-session", "continue")
+		}()
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for pool.WaitQueueDepth() != 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if depth := pool.WaitQueueDepth(); depth != 1 {
+		t.Errorf("Expected one call waiting for a free adapter, got %d", depth)
+	}
+
+	wg.Wait()
+}
+
+func TestClaudeCodeAdapterPool_ContextCancelledWhileWaiting(t *testing.T) {
+	pool := NewClaudeCodeAdapterPool(writeFakeClaude(t, "0.5"), 1)
+
+	go pool.SendInputToStopWebhook(context.Background(), "busy-session", "continue")
+	for pool.WaitQueueDepth() != 0 || len(pool.adapters) != 0 {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := pool.SendInputToStopWebhook(ctx, "waiting-session", "continue"); err != context.DeadlineExceeded {
+		t.Errorf("Expected context deadline exceeded while waiting, got %v", err)
+	}
+}
+
+func TestClaudeCodeAdapterPool_SetTimeout(t *testing.T) {
+	pool := NewClaudeCodeAdapterPool("echo", 2)
+	pool.SetTimeout(45 * time.Second)
+
+	for i := 0; i < pool.MaxConcurrent(); i++ {
+		adapter := <-pool.adapters
+		if adapter.GetTimeout() != 45*time.Second {
+			t.Errorf("Expected pooled adapter timeout 45s, got %v", adapter.GetTimeout())
+		}
+		pool.adapters <- adapter
+	}
+}
@@ -0,0 +1,105 @@
+package claude
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dan/claude-control/internal/adapters/mock"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// writeFakeClaudeScript writes an executable stand-in for the claude binary
+func writeFakeClaudeScript(t *testing.T, script string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "claude")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatalf("Failed to write fake claude binary: %v", err)
+	}
+	return path
+}
+
+func TestClaudeCodeAdapter_GetSessionList_CLI(t *testing.T) {
+	binary := writeFakeClaudeScript(t, `echo '[{"session_id":"c3e0f54b-0df7-4aa2-8179-1ee1b8c17147","cwd":"/Users/dan/Software/haiper"}]'`+"\n")
+	adapter := NewClaudeCodeAdapter(binary)
+
+	sessions, err := adapter.GetSessionList(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(sessions) != 1 {
+		t.Fatalf("Expected 1 session, got %v", sessions)
+	}
+	if sessions[0].SessionID != "c3e0f54b-0df7-4aa2-8179-1ee1b8c17147" || sessions[0].CWD != "/Users/dan/Software/haiper" {
+		t.Errorf("Unexpected session: %+v", sessions[0])
+	}
+}
+
+func TestClaudeCodeAdapter_GetSessionList_InvalidOutput(t *testing.T) {
+	// echo prints its arguments, which is not a JSON session list
+	adapter := NewClaudeCodeAdapter("echo")
+
+	if _, err := adapter.GetSessionList(context.Background()); err == nil {
+		t.Error("Expected error for non-JSON output")
+	}
+}
+
+func TestClaudeCodeAdapter_GetSessionList_TMuxFallback(t *testing.T) {
+	binary := writeFakeClaudeScript(t, "echo 'error: unknown option --list-sessions' >&2\nexit 2\n")
+
+	tests := []struct {
+		name        string
+		pattern     string
+		expected    []string
+		expectError bool
+	}{
+		{name: "Default pattern", pattern: "", expected: []string{"claude-haiper", "claude-api"}},
+		{name: "Custom pattern", pattern: "-api$", expected: []string{"claude-api"}},
+		{name: "No fallback configured", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := NewClaudeCodeAdapter(binary)
+			if !tt.expectError {
+				controller := mock.NewMockTMuxController()
+				controller.SetResponse("ListSessions", []ports.TMuxSession{
+					{Name: "claude-haiper"}, {Name: "scratch"}, {Name: "claude-api"},
+				}, nil)
+				if err := adapter.SetTMuxFallback(controller, tt.pattern); err != nil {
+					t.Fatalf("Failed to configure fallback: %v", err)
+				}
+			}
+
+			sessions, err := adapter.GetSessionList(context.Background())
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error without a tmux fallback")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(sessions) != len(tt.expected) {
+				t.Fatalf("Expected sessions %v, got %+v", tt.expected, sessions)
+			}
+			for i, name := range tt.expected {
+				if sessions[i].TMuxSession != name {
+					t.Errorf("Expected tmux session %s, got %s", name, sessions[i].TMuxSession)
+				}
+			}
+		})
+	}
+}
+
+func TestClaudeCodeAdapter_SetTMuxFallback_InvalidPattern(t *testing.T) {
+	adapter := NewClaudeCodeAdapter("echo")
+	if err := adapter.SetTMuxFallback(mock.NewMockTMuxController(), "("); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
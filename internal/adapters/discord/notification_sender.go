@@ -0,0 +1,231 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// Discord's limits on embed text
+const (
+	maxTitleLength       = 256
+	maxDescriptionLength = 4096
+)
+
+// Discord component types and the link button style
+const (
+	componentActionRow = 1
+	componentButton    = 2
+	buttonStyleLink    = 5
+)
+
+// directActionAnchor is the id of the decision form on the task page
+const directActionAnchor = "#take-action"
+
+// Ensure NotificationSender satisfies the NotificationSender port
+var _ ports.NotificationSender = (*NotificationSender)(nil)
+var _ ports.NotificationRouter = (*NotificationSender)(nil)
+
+// Config holds configuration for the Discord notification sender
+type Config struct {
+	WebhookURL string `json:"-"` // Incoming webhook URL; it embeds the webhook's secret token
+}
+
+// message is a Discord webhook execute payload
+type message struct {
+	Embeds          []embed         `json:"embeds"`
+	Components      []component     `json:"components,omitempty"`
+	AllowedMentions allowedMentions `json:"allowed_mentions"`
+}
+
+// embed is a Discord rich embed
+type embed struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url,omitempty"`
+	Color       int    `json:"color"`
+	Timestamp   string `json:"timestamp,omitempty"`
+}
+
+// component is an action row or a button inside one
+type component struct {
+	Type       int         `json:"type"`
+	Components []component `json:"components,omitempty"`
+	Style      int         `json:"style,omitempty"`
+	Label      string      `json:"label,omitempty"`
+	URL        string      `json:"url,omitempty"`
+}
+
+// allowedMentions controls which mentions in the message ping anyone
+type allowedMentions struct {
+	Parse []string `json:"parse"`
+}
+
+// webhookInfo is the part of Discord's webhook metadata Verify checks
+type webhookInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// NotificationSender implements the NotificationSender port for Discord incoming webhooks
+type NotificationSender struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// NewNotificationSender creates a new Discord notification sender
+func NewNotificationSender(config Config) *NotificationSender {
+	return &NotificationSender{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Send posts the notification to the Discord channel as a colored embed with links to the task
+func (n *NotificationSender) Send(ctx context.Context, notification *domain.Notification) error {
+	webhookURL, err := withComponents(n.config.WebhookURL)
+	if err != nil {
+		return err
+	}
+
+	payloadBytes, err := json.Marshal(buildMessage(notification))
+	if err != nil {
+		return fmt.Errorf("failed to marshal Discord message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Discord message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("Discord webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	notification.MarkSent()
+
+	return nil
+}
+
+// Verify fetches the webhook's metadata, which Discord serves on a GET of the webhook URL, without posting anything
+func (n *NotificationSender) Verify(ctx context.Context) error {
+	if n.config.WebhookURL == "" {
+		return fmt.Errorf("Discord webhook URL is not set")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", n.config.WebhookURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Discord webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("Discord webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var info webhookInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil || info.ID == "" {
+		return fmt.Errorf("Discord webhook URL did not return webhook details")
+	}
+
+	return nil
+}
+
+// DestinationFor reports the Discord channel; the webhook URL is a secret so no topic is given
+func (n *NotificationSender) DestinationFor(notification *domain.Notification) ports.NotificationDestination {
+	return ports.NotificationDestination{Channel: "discord"}
+}
+
+// withComponents adds with_components=true to the webhook URL, which Discord requires before a webhook
+// that isn't owned by an application may send buttons
+func withComponents(webhookURL string) (string, error) {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid Discord webhook URL: %w", err)
+	}
+
+	query := parsed.Query()
+	query.Set("with_components", "true")
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
+
+// buildMessage lays out the notification as an embed colored by priority, followed by link buttons
+// to view the task and to jump straight to its decision form
+func buildMessage(notification *domain.Notification) message {
+	return message{
+		Embeds: []embed{{
+			Title:       truncate(notification.Title, maxTitleLength),
+			Description: truncate(description(notification), maxDescriptionLength),
+			URL:         notification.ActionURL,
+			Color:       notification.Priority.ToDiscordColor(),
+			Timestamp:   notification.CreatedAt.UTC().Format(time.RFC3339),
+		}},
+		Components: []component{{
+			Type: componentActionRow,
+			Components: []component{
+				linkButton("View Task", notification.ActionURL),
+				linkButton("Direct Action", notification.ActionURL+directActionAnchor),
+			},
+		}},
+		AllowedMentions: allowedMentions{Parse: []string{}},
+	}
+}
+
+// description is the notification message followed by the session ID and working directory, when known
+func description(notification *domain.Notification) string {
+	lines := []string{notification.Message}
+	if notification.SessionID != "" {
+		lines = append(lines, "", "**Session:** "+code(notification.SessionID))
+	}
+	if notification.SourceCWD != "" {
+		if notification.SessionID == "" {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "**Directory:** "+code(notification.SourceCWD))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// linkButton creates a button that opens url
+func linkButton(label, url string) component {
+	return component{Type: componentButton, Style: buttonStyleLink, Label: label, URL: url}
+}
+
+// code wraps s in an inline code span, dropping backticks that would end it early
+func code(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "") + "`"
+}
+
+// truncate shortens s to at most limit bytes without splitting a UTF-8 character
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	return strings.ToValidUTF8(s[:limit], "")
+}
@@ -0,0 +1,162 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+const testWebhookURL = "https://discord.com/api/webhooks/123/secret-token"
+
+// roundTripFunc mocks the sender's HTTP client
+type roundTripFunc func(req *http.Request) *http.Response
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
+
+// newTestSender returns a sender whose requests are answered by respond instead of Discord
+func newTestSender(respond roundTripFunc) *NotificationSender {
+	sender := NewNotificationSender(Config{WebhookURL: testWebhookURL})
+	sender.httpClient = &http.Client{Transport: respond}
+	return sender
+}
+
+func response(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}
+}
+
+func TestNotificationSender_Send(t *testing.T) {
+	var request *http.Request
+	var received message
+	sender := newTestSender(func(req *http.Request) *http.Response {
+		request = req
+		if err := json.NewDecoder(req.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode message: %v", err)
+		}
+		return response(http.StatusNoContent, "")
+	})
+
+	notification := domain.NewNotification(uuid.New(), domain.HookTypePreToolUse, "control.example.com", "/srv/haiper")
+	notification.SessionID = "abc123"
+	notification.Message = "Run rm -rf build @everyone"
+
+	if err := sender.Send(context.Background(), notification); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !notification.IsSent() {
+		t.Error("Expected notification to be marked sent")
+	}
+
+	if request.Method != http.MethodPost || request.URL.Query().Get("with_components") != "true" || !strings.HasPrefix(request.URL.String(), testWebhookURL) {
+		t.Errorf("Expected a POST to the webhook URL with components enabled, got %s %s", request.Method, request.URL)
+	}
+
+	if len(received.Embeds) != 1 {
+		t.Fatalf("Expected 1 embed, got %d", len(received.Embeds))
+	}
+	embed := received.Embeds[0]
+	if embed.Title != notification.Title || embed.URL != notification.ActionURL {
+		t.Errorf("Expected the notification title linking to the task, got %+v", embed)
+	}
+	expectedDescription := "Run rm -rf build @everyone\n\n**Session:** `abc123`\n**Directory:** `/srv/haiper`"
+	if embed.Description != expectedDescription {
+		t.Errorf("Expected description %q, got %q", expectedDescription, embed.Description)
+	}
+	if received.AllowedMentions.Parse == nil || len(received.AllowedMentions.Parse) != 0 {
+		t.Errorf("Expected mentions in the message not to ping anyone, got %+v", received.AllowedMentions)
+	}
+
+	if len(received.Components) != 1 || len(received.Components[0].Components) != 2 {
+		t.Fatalf("Expected one row of two buttons, got %+v", received.Components)
+	}
+	buttons := received.Components[0].Components
+	if buttons[0].Label != "View Task" || buttons[0].URL != notification.ActionURL || buttons[0].Style != buttonStyleLink {
+		t.Errorf("Unexpected View Task button: %+v", buttons[0])
+	}
+	if buttons[1].Label != "Direct Action" || buttons[1].URL != notification.ActionURL+"#take-action" {
+		t.Errorf("Unexpected Direct Action button: %+v", buttons[1])
+	}
+}
+
+func TestNotificationSender_SendPriorityColors(t *testing.T) {
+	tests := []struct {
+		priority domain.NotificationPriority
+		expected int
+	}{
+		{domain.PriorityUrgent, 0xE74C3C},
+		{domain.PriorityHigh, 0xF1C40F},
+		{domain.PriorityNormal, 0x3498DB},
+		{domain.PriorityLow, 0x95A5A6},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.priority), func(t *testing.T) {
+			var received message
+			sender := newTestSender(func(req *http.Request) *http.Response {
+				json.NewDecoder(req.Body).Decode(&received)
+				return response(http.StatusNoContent, "")
+			})
+
+			notification := domain.NewNotification(uuid.New(), domain.HookTypeNotification, "localhost:8080", "")
+			notification.Priority = tt.priority
+			if err := sender.Send(context.Background(), notification); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(received.Embeds) != 1 || received.Embeds[0].Color != tt.expected {
+				t.Errorf("Expected embed color %#x, got %+v", tt.expected, received.Embeds)
+			}
+		})
+	}
+}
+
+func TestNotificationSender_SendErrorStatus(t *testing.T) {
+	sender := newTestSender(func(req *http.Request) *http.Response {
+		return response(http.StatusNotFound, `{"message": "Unknown Webhook", "code": 10015}`)
+	})
+	notification := domain.NewNotification(uuid.New(), domain.HookTypeNotification, "localhost:8080", "")
+
+	err := sender.Send(context.Background(), notification)
+	if err == nil || !strings.Contains(err.Error(), "Unknown Webhook") {
+		t.Errorf("Expected an error naming Discord's answer, got %v", err)
+	}
+	if notification.IsSent() {
+		t.Error("Expected notification not to be marked sent")
+	}
+}
+
+func TestNotificationSender_Verify(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		expectErr bool
+	}{
+		{"Live webhook", http.StatusOK, `{"type": 1, "id": "123", "name": "Claude Control", "channel_id": "456"}`, false},
+		{"Deleted webhook", http.StatusNotFound, `{"message": "Unknown Webhook", "code": 10015}`, true},
+		{"Not a webhook", http.StatusOK, `<html></html>`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := newTestSender(func(req *http.Request) *http.Response {
+				if req.Method != http.MethodGet || req.URL.String() != testWebhookURL {
+					t.Errorf("Expected a GET of the webhook URL, got %s %s", req.Method, req.URL)
+				}
+				return response(tt.status, tt.body)
+			})
+
+			err := sender.Verify(context.Background())
+			if (err != nil) != tt.expectErr {
+				t.Errorf("Verify() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"html/template"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// DefaultPort is the SMTP submission port, which upgrades to TLS with STARTTLS
+const DefaultPort = "587"
+
+// implicitTLSPort is the SMTPS port, where TLS starts before the SMTP greeting
+const implicitTLSPort = "465"
+
+// dialTimeout bounds connecting to the SMTP server when the context has no deadline
+const dialTimeout = 30 * time.Second
+
+// Ensure NotificationSender satisfies the NotificationSender port
+var _ ports.NotificationSender = (*NotificationSender)(nil)
+var _ ports.NotificationRouter = (*NotificationSender)(nil)
+
+// Config holds configuration for the email notification sender
+type Config struct {
+	Host     string   `json:"host"`
+	Port     string   `json:"port,omitempty"` // Defaults to DefaultPort
+	Username string   `json:"-"`              // Optional - mail is sent without authenticating when empty
+	Password string   `json:"-"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+// NotificationSender implements the NotificationSender port for SMTP email
+type NotificationSender struct {
+	config Config
+}
+
+// NewNotificationSender creates a new email notification sender
+func NewNotificationSender(config Config) *NotificationSender {
+	if config.Port == "" {
+		config.Port = DefaultPort
+	}
+
+	return &NotificationSender{config: config}
+}
+
+// Send emails the notification as an HTML message linking to the task page
+func (n *NotificationSender) Send(ctx context.Context, notification *domain.Notification) error {
+	message, err := buildMessage(n.config, notification)
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+
+	client, err := n.connect(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.Mail(n.config.From); err != nil {
+		return fmt.Errorf("SMTP server rejected sender %s: %w", n.config.From, err)
+	}
+	for _, recipient := range n.config.To {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("SMTP server rejected recipient %s: %w", recipient, err)
+		}
+	}
+
+	data, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP server refused message data: %w", err)
+	}
+	if _, err := data.Write(message); err != nil {
+		data.Close()
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := data.Close(); err != nil {
+		return fmt.Errorf("SMTP server rejected email: %w", err)
+	}
+
+	notification.MarkSent()
+
+	client.Quit()
+	return nil
+}
+
+// Verify connects and authenticates to the SMTP server, then hangs up without sending anything
+func (n *NotificationSender) Verify(ctx context.Context) error {
+	if n.config.Host == "" || n.config.From == "" || len(n.config.To) == 0 {
+		return fmt.Errorf("SMTP host, sender and at least one recipient must be set")
+	}
+
+	client, err := n.connect(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to verify SMTP server: %w", err)
+	}
+	defer client.Close()
+
+	return client.Quit()
+}
+
+// DestinationFor reports the email channel; recipients are personal addresses so no topic is given
+func (n *NotificationSender) DestinationFor(notification *domain.Notification) ports.NotificationDestination {
+	return ports.NotificationDestination{Channel: "email"}
+}
+
+// connect dials the SMTP server, upgrades to TLS where possible and authenticates if a username is set
+// The connection's deadline follows ctx, since net/smtp has no context support of its own.
+func (n *NotificationSender) connect(ctx context.Context) (*smtp.Client, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(dialTimeout)
+	}
+
+	address := net.JoinHostPort(n.config.Host, n.config.Port)
+	dialer := &net.Dialer{Deadline: deadline}
+	tlsConfig := &tls.Config{ServerName: n.config.Host}
+
+	var conn net.Conn
+	var err error
+	if n.config.Port == implicitTLSPort {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, n.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to start SMTP session with %s: %w", address, err)
+	}
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to start TLS with %s: %w", address, err)
+		}
+	}
+
+	if n.config.Username != "" {
+		auth := smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)
+		if err := client.Auth(auth); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to authenticate as %s: %w", n.config.Username, err)
+		}
+	}
+
+	return client, nil
+}
+
+// messageTemplate is the HTML body of a notification email
+var messageTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<h2>{{.Title}}</h2>
+<p style="white-space: pre-wrap;">{{.Message}}</p>
+<table style="color: #555;">
+{{- if .SessionID}}
+<tr><td>Session</td><td><code>{{.SessionID}}</code></td></tr>
+{{- end}}
+{{- if .SourceCWD}}
+<tr><td>Directory</td><td><code>{{.SourceCWD}}</code></td></tr>
+{{- end}}
+{{- if .HookType}}
+<tr><td>Hook</td><td>{{.HookType}}</td></tr>
+{{- end}}
+</table>
+<p>
+{{- if .HookType.IsBlocking}}
+<a href="{{.ActionURL}}" style="background: #2e7d32; color: #fff; padding: 8px 16px; text-decoration: none;">Approve</a>
+<a href="{{.ActionURL}}" style="background: #c62828; color: #fff; padding: 8px 16px; text-decoration: none;">Reject</a>
+{{- else}}
+<a href="{{.ActionURL}}">Open Task</a>
+{{- end}}
+</p>
+</body>
+</html>
+`))
+
+// buildMessage writes the headers and HTML body of the email for a notification
+// Approve and Reject both open the task page, where the decision is made, since a link in an email
+// can't be trusted to carry a decision by itself.
+func buildMessage(config Config, notification *domain.Notification) ([]byte, error) {
+	var message bytes.Buffer
+	headers := []struct{ name, value string }{
+		{"From", config.From},
+		{"To", strings.Join(config.To, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", strings.Join(strings.Fields(notification.Title), " "))},
+		{"Date", notification.CreatedAt.Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/html; charset=UTF-8"},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&message, "%s: %s\r\n", header.name, header.value)
+	}
+	message.WriteString("\r\n")
+
+	if err := messageTemplate.Execute(&message, notification); err != nil {
+		return nil, err
+	}
+	return message.Bytes(), nil
+}
@@ -0,0 +1,197 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// fakeSMTPServer speaks just enough SMTP to accept one session at a time and record what it was sent
+type fakeSMTPServer struct {
+	listener net.Listener
+	password string
+
+	mutex      sync.Mutex
+	auth       string
+	recipients []string
+	data       string
+	done       chan struct{}
+}
+
+func newFakeSMTPServer(t *testing.T, password string) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := &fakeSMTPServer{listener: listener, password: password, done: make(chan struct{})}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (s *fakeSMTPServer) port() string {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	return port
+}
+
+func (s *fakeSMTPServer) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	defer close(s.done)
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 localhost ESMTP")
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimSpace(line)
+
+		s.mutex.Lock()
+		switch verb := strings.ToUpper(strings.Fields(command + " ")[0]); verb {
+		case "EHLO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			s.auth = strings.TrimPrefix(command, "AUTH PLAIN ")
+			decoded, _ := base64.StdEncoding.DecodeString(s.auth)
+			if strings.HasSuffix(string(decoded), "\x00"+s.password) {
+				reply("235 Authenticated")
+			} else {
+				reply("535 Authentication failed")
+			}
+		case "MAIL":
+			reply("250 OK")
+		case "RCPT":
+			s.recipients = append(s.recipients, command)
+			reply("250 OK")
+		case "DATA":
+			reply("354 Go ahead")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil || dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.data = data.String()
+			reply("250 Queued")
+		case "QUIT":
+			reply("221 Bye")
+			s.mutex.Unlock()
+			return
+		default:
+			reply("502 Not implemented")
+		}
+		s.mutex.Unlock()
+	}
+}
+
+func TestNotificationSender_Send(t *testing.T) {
+	server := newFakeSMTPServer(t, "secret")
+	sender := NewNotificationSender(Config{
+		Host:     "127.0.0.1",
+		Port:     server.port(),
+		Username: "alerts",
+		Password: "secret",
+		From:     "claude-control@example.com",
+		To:       []string{"dan@example.com", "ops@example.com"},
+	})
+
+	notification := domain.NewNotification(uuid.New(), domain.HookTypePreToolUse, "control.example.com", "/srv/haiper")
+	notification.SessionID = "abc123"
+	notification.Message = "Run rm -rf <build> & friends"
+
+	if err := sender.Send(context.Background(), notification); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !notification.IsSent() {
+		t.Error("Expected notification to be marked sent")
+	}
+	<-server.done
+
+	if len(server.recipients) != 2 {
+		t.Errorf("Expected both recipients, got %v", server.recipients)
+	}
+	for _, expected := range []string{
+		"Content-Type: text/html; charset=UTF-8",
+		"To: dan@example.com, ops@example.com",
+		"Run rm -rf &lt;build&gt; &amp; friends",
+		"<code>abc123</code>",
+		"<code>/srv/haiper</code>",
+		`<a href="` + notification.ActionURL + `"`,
+		">Approve</a>",
+		">Reject</a>",
+	} {
+		if !strings.Contains(server.data, expected) {
+			t.Errorf("Expected %q in the email, got:\n%s", expected, server.data)
+		}
+	}
+}
+
+func TestBuildMessage_NonBlockingHook(t *testing.T) {
+	notification := domain.NewNotification(uuid.New(), domain.HookTypeStop, "localhost:8080", "")
+	notification.Title = "Claude finished\r\nBcc: someone@example.com"
+
+	message, err := buildMessage(Config{From: "a@example.com", To: []string{"b@example.com"}}, notification)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.Contains(string(message), ">Open Task</a>") || strings.Contains(string(message), "Approve") {
+		t.Error("Expected a single Open Task link for a hook that doesn't wait on a decision")
+	}
+	headers, _, _ := strings.Cut(string(message), "\r\n\r\n")
+	if strings.Contains(headers, "\r\nBcc:") || !strings.Contains(headers, "Subject: Claude finished Bcc: someone@example.com\r\n") {
+		t.Errorf("Expected line breaks in the title to be flattened rather than start a new header, got:\n%s", headers)
+	}
+	if strings.Contains(string(message), "Directory") {
+		t.Error("Expected the directory row to be left out when the working directory is unknown")
+	}
+}
+
+func TestNotificationSender_Verify(t *testing.T) {
+	tests := []struct {
+		name      string
+		password  string
+		config    func(port string) Config
+		expectErr bool
+	}{
+		{"Valid login", "secret", func(port string) Config {
+			return Config{Host: "127.0.0.1", Port: port, Username: "alerts", Password: "secret", From: "a@example.com", To: []string{"b@example.com"}}
+		}, false},
+		{"Wrong password", "secret", func(port string) Config {
+			return Config{Host: "127.0.0.1", Port: port, Username: "alerts", Password: "guess", From: "a@example.com", To: []string{"b@example.com"}}
+		}, true},
+		{"Missing recipient", "secret", func(port string) Config {
+			return Config{Host: "127.0.0.1", Port: port, From: "a@example.com"}
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeSMTPServer(t, tt.password)
+			err := NewNotificationSender(tt.config(server.port())).Verify(context.Background())
+			if (err != nil) != tt.expectErr {
+				t.Errorf("Verify() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if server.data != "" {
+				t.Error("Expected Verify not to send anything")
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// activityChartWidth is the length of the bar for the busiest hour
+const activityChartWidth = 50
+
+// renderActivityChart draws one ASCII bar per hour from since to now, scaled to the busiest hour
+// Hours missing from buckets are drawn empty so gaps in activity stay visible.
+func renderActivityChart(buckets []ports.HourlyBucket, since, now time.Time) string {
+	byHour := make(map[int64]ports.HourlyBucket, len(buckets))
+	maxEvents := 0
+	for _, bucket := range buckets {
+		byHour[bucket.Hour.Unix()] = bucket
+		if bucket.EventCount > maxEvents {
+			maxEvents = bucket.EventCount
+		}
+	}
+
+	var chart strings.Builder
+	for hour := since.UTC().Truncate(time.Hour); !hour.After(now); hour = hour.Add(time.Hour) {
+		bucket := byHour[hour.Unix()]
+
+		barLength := 0
+		if maxEvents > 0 {
+			barLength = (bucket.EventCount*activityChartWidth + maxEvents - 1) / maxEvents
+		}
+
+		fmt.Fprintf(&chart, "%s | %-*s %d events, %d actions\n",
+			hour.Format("2006-01-02 15:04"), activityChartWidth, strings.Repeat("#", barLength),
+			bucket.EventCount, bucket.ActionsTaken)
+	}
+
+	return chart.String()
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+func TestRenderActivityChart(t *testing.T) {
+	since := time.Date(2025, 8, 1, 9, 30, 0, 0, time.UTC)
+	now := time.Date(2025, 8, 1, 12, 15, 0, 0, time.UTC)
+	buckets := []ports.HourlyBucket{
+		{Hour: time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC), EventCount: 10, ActionsTaken: 4},
+		{Hour: time.Date(2025, 8, 1, 11, 0, 0, 0, time.UTC), EventCount: 1, ActionsTaken: 0},
+	}
+
+	lines := strings.Split(strings.TrimSuffix(renderActivityChart(buckets, since, now), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected one line per hour from 09:00 to 12:00, got %d:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+
+	tests := []struct {
+		line      string
+		prefix    string
+		barLength int
+		suffix    string
+	}{
+		{lines[0], "2025-08-01 09:00 | ", activityChartWidth, "10 events, 4 actions"},
+		{lines[1], "2025-08-01 10:00 | ", 0, "0 events, 0 actions"},
+		{lines[2], "2025-08-01 11:00 | ", 5, "1 events, 0 actions"},
+		{lines[3], "2025-08-01 12:00 | ", 0, "0 events, 0 actions"},
+	}
+
+	for _, tt := range tests {
+		if !strings.HasPrefix(tt.line, tt.prefix) || !strings.HasSuffix(tt.line, tt.suffix) {
+			t.Errorf("Unexpected line %q", tt.line)
+		}
+		if got := strings.Count(tt.line, "#"); got != tt.barLength {
+			t.Errorf("Expected bar of %d in %q, got %d", tt.barLength, tt.line, got)
+		}
+	}
+}
+
+func TestRenderActivityChart_NoActivity(t *testing.T) {
+	since := time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC)
+	chart := renderActivityChart(nil, since, since.Add(time.Hour))
+
+	if strings.Contains(chart, "#") {
+		t.Errorf("Expected empty bars without activity, got:\n%s", chart)
+	}
+	if strings.Count(chart, "\n") != 2 {
+		t.Errorf("Expected 2 hours, got:\n%s", chart)
+	}
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/gorilla/mux"
+)
+
+// AdminKeyHeader is the request header carrying the admin API key
+const AdminKeyHeader = "X-Admin-Key"
+
+// AdminHandler handles operator-only endpoints for server maintenance
+type AdminHandler struct {
+	settings ports.ServerSettingsService
+	adminKey string
+}
+
+// NewAdminHandler creates a new admin handler
+// The admin endpoints reject every request when adminKey is empty.
+func NewAdminHandler(settings ports.ServerSettingsService, adminKey string) *AdminHandler {
+	return &AdminHandler{
+		settings: settings,
+		adminKey: adminKey,
+	}
+}
+
+// RegisterRoutes registers admin routes with the router
+func (h *AdminHandler) RegisterRoutes(router *mux.Router) {
+	admin := router.PathPrefix("/api/admin").Subrouter()
+	admin.Use(h.requireAdminKey)
+
+	admin.HandleFunc("/hooks/disable", h.handleDisableHooks).Methods("POST")
+	admin.HandleFunc("/hooks/enable", h.handleEnableHooks).Methods("POST")
+}
+
+// requireAdminKey rejects requests that don't carry the configured admin key
+func (h *AdminHandler) requireAdminKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.adminKey == "" {
+			h.respondWithError(w, http.StatusServiceUnavailable, "Admin API is not configured")
+			return
+		}
+
+		providedKey := r.Header.Get(AdminKeyHeader)
+		if subtle.ConstantTimeCompare([]byte(providedKey), []byte(h.adminKey)) != 1 {
+			h.respondWithError(w, http.StatusUnauthorized, "Invalid admin key")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleDisableHooks turns on the hooks-disabled maintenance mode
+func (h *AdminHandler) handleDisableHooks(w http.ResponseWriter, r *http.Request) {
+	h.setHooksDisabled(w, r, true)
+}
+
+// handleEnableHooks turns off the hooks-disabled maintenance mode
+func (h *AdminHandler) handleEnableHooks(w http.ResponseWriter, r *http.Request) {
+	h.setHooksDisabled(w, r, false)
+}
+
+// setHooksDisabled persists the hooks-disabled state and reports the result
+func (h *AdminHandler) setHooksDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	if err := h.settings.SetHooksDisabled(r.Context(), disabled); err != nil {
+		log.Printf("Failed to set hooks disabled to %t: %v", disabled, err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to update hooks setting")
+		return
+	}
+
+	if disabled {
+		log.Printf("Hook processing disabled - all hooks will be auto-approved")
+	} else {
+		log.Printf("Hook processing enabled")
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":        true,
+		"hooks_disabled": disabled,
+	})
+}
+
+// respondWithError sends an error response
+func (h *AdminHandler) respondWithError(w http.ResponseWriter, statusCode int, message string) {
+	h.respondWithJSON(w, statusCode, map[string]interface{}{
+		"success": false,
+		"error":   message,
+	})
+}
+
+// respondWithJSON sends a JSON response
+func (h *AdminHandler) respondWithJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
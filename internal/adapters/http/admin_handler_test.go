@@ -0,0 +1,88 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// fakeServerSettings keeps the hooks-disabled state in memory
+type fakeServerSettings struct {
+	hooksDisabled bool
+}
+
+func (f *fakeServerSettings) HooksDisabled() bool {
+	return f.hooksDisabled
+}
+
+func (f *fakeServerSettings) SetHooksDisabled(ctx context.Context, disabled bool) error {
+	f.hooksDisabled = disabled
+	return nil
+}
+
+func TestAdminHandler_ToggleHooks(t *testing.T) {
+	settings := &fakeServerSettings{}
+	router := mux.NewRouter()
+	NewAdminHandler(settings, "secret").RegisterRoutes(router)
+
+	steps := []struct {
+		path             string
+		expectedDisabled bool
+	}{
+		{path: "/api/admin/hooks/disable", expectedDisabled: true},
+		{path: "/api/admin/hooks/disable", expectedDisabled: true},
+		{path: "/api/admin/hooks/enable", expectedDisabled: false},
+	}
+
+	for _, step := range steps {
+		req := httptest.NewRequest(http.MethodPost, step.path, nil)
+		req.Header.Set(AdminKeyHeader, "secret")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", step.path, rec.Code)
+		}
+		if settings.hooksDisabled != step.expectedDisabled {
+			t.Errorf("%s: expected hooks disabled %t, got %t", step.path, step.expectedDisabled, settings.hooksDisabled)
+		}
+	}
+}
+
+func TestAdminHandler_RequiresAdminKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		adminKey       string
+		providedKey    string
+		expectedStatus int
+	}{
+		{name: "Missing key", adminKey: "secret", providedKey: "", expectedStatus: http.StatusUnauthorized},
+		{name: "Wrong key", adminKey: "secret", providedKey: "guess", expectedStatus: http.StatusUnauthorized},
+		{name: "Admin API not configured", adminKey: "", providedKey: "", expectedStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := &fakeServerSettings{}
+			router := mux.NewRouter()
+			NewAdminHandler(settings, tt.adminKey).RegisterRoutes(router)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/hooks/disable", nil)
+			if tt.providedKey != "" {
+				req.Header.Set(AdminKeyHeader, tt.providedKey)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if settings.hooksDisabled {
+				t.Error("Expected hooks to stay enabled for an unauthorized request")
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// basePathContextKey stores the base path a request was received under
+type basePathContextKey struct{}
+
+// NormalizeBasePath cleans a configured base path into "/prefix" form, or "" when serving from the root
+// The result can be prepended directly to absolute paths like "/dashboard".
+func NormalizeBasePath(basePath string) string {
+	trimmed := strings.Trim(strings.TrimSpace(basePath), "/")
+	if trimmed == "" {
+		return ""
+	}
+	return "/" + trimmed
+}
+
+// MountAtBasePath returns the router that routes should be registered on to be served under basePath
+// For a non-root base path, "/" and the bare prefix redirect to the dashboard, and the prefix is trimmed
+// from request paths after routing so path-based middleware keeps seeing app-relative paths.
+// Call it before adding any other middleware to router.
+func MountAtBasePath(router *mux.Router, basePath string) *mux.Router {
+	if basePath == "" {
+		return router
+	}
+
+	redirectToDashboard := func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, basePath+"/dashboard", http.StatusSeeOther)
+	}
+	router.HandleFunc("/", redirectToDashboard).Methods("GET")
+	router.HandleFunc(basePath, redirectToDashboard).Methods("GET")
+
+	subrouter := router.PathPrefix(basePath).Subrouter()
+	router.Use(trimBasePath(basePath))
+	return subrouter
+}
+
+// trimBasePath records basePath for building redirects and strips it from requests under it
+func trimBasePath(basePath string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(context.WithValue(r.Context(), basePathContextKey{}, basePath))
+
+			trimmedPath, ok := strings.CutPrefix(r.URL.Path, basePath)
+			if ok && (trimmedPath == "" || strings.HasPrefix(trimmedPath, "/")) {
+				if trimmedPath == "" {
+					trimmedPath = "/"
+				}
+				url := *r.URL
+				url.Path = trimmedPath
+				url.RawPath = ""
+				r.URL = &url
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// basePathFromRequest returns the base path the request was received under, or "" at the root
+func basePathFromRequest(r *http.Request) string {
+	basePath, _ := r.Context().Value(basePathContextKey{}).(string)
+	return basePath
+}
@@ -0,0 +1,135 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func TestNormalizeBasePath(t *testing.T) {
+	tests := []struct {
+		basePath string
+		expected string
+	}{
+		{"", ""},
+		{"/", ""},
+		{"/claude-control", "/claude-control"},
+		{"claude-control", "/claude-control"},
+		{"/claude-control/", "/claude-control"},
+		{" /tools/claude/ ", "/tools/claude"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.basePath, func(t *testing.T) {
+			if got := NormalizeBasePath(tt.basePath); got != tt.expected {
+				t.Errorf("NormalizeBasePath(%q) = %q, expected %q", tt.basePath, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestMountAtBasePath verifies routes and path-based middleware work under a non-trivial base path
+func TestMountAtBasePath(t *testing.T) {
+	const basePath = "/tools/claude-control"
+
+	var seenPath, seenTaskID string
+	var blocking bool
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		seenPath = r.URL.Path
+		seenTaskID = mux.Vars(r)["taskId"]
+		deadline, _ := r.Context().Deadline()
+		blocking = time.Until(deadline) > time.Second
+		w.WriteHeader(http.StatusOK)
+	}
+
+	rootRouter := mux.NewRouter()
+	router := MountAtBasePath(rootRouter, basePath)
+	rootRouter.Use(TimeoutMiddleware(HandlerTimeouts{Blocking: time.Minute, NonBlocking: time.Second}))
+	router.HandleFunc("/dashboard", handler).Methods("GET")
+	router.HandleFunc("/task/{taskId}", handler).Methods("GET")
+	router.HandleFunc("/webhook/{hookType}", handler).Methods("POST")
+
+	tests := []struct {
+		name             string
+		method           string
+		path             string
+		expectedStatus   int
+		expectedPath     string
+		expectedTaskID   string
+		expectedBlocking bool
+		expectedLocation string
+	}{
+		{name: "Dashboard", method: "GET", path: basePath + "/dashboard", expectedStatus: http.StatusOK, expectedPath: "/dashboard"},
+		{name: "Route variables", method: "GET", path: basePath + "/task/abc123", expectedStatus: http.StatusOK, expectedPath: "/task/abc123", expectedTaskID: "abc123"},
+		{name: "Blocking webhook", method: "POST", path: basePath + "/webhook/PreToolUse", expectedStatus: http.StatusOK, expectedPath: "/webhook/PreToolUse", expectedBlocking: true},
+		{name: "Unprefixed route", method: "GET", path: "/dashboard", expectedStatus: http.StatusNotFound},
+		{name: "Root redirects", method: "GET", path: "/", expectedStatus: http.StatusSeeOther, expectedLocation: basePath + "/dashboard"},
+		{name: "Bare prefix redirects", method: "GET", path: basePath, expectedStatus: http.StatusSeeOther, expectedLocation: basePath + "/dashboard"},
+		{name: "Similar prefix", method: "GET", path: basePath + "-old/dashboard", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seenPath, seenTaskID, blocking = "", "", false
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+			rootRouter.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if location := rec.Header().Get("Location"); location != tt.expectedLocation {
+				t.Errorf("Expected Location %q, got %q", tt.expectedLocation, location)
+			}
+			if seenPath != tt.expectedPath {
+				t.Errorf("Expected handler to see path %q, got %q", tt.expectedPath, seenPath)
+			}
+			if seenTaskID != tt.expectedTaskID {
+				t.Errorf("Expected taskId %q, got %q", tt.expectedTaskID, seenTaskID)
+			}
+			if blocking != tt.expectedBlocking {
+				t.Errorf("Expected blocking=%t, got %t", tt.expectedBlocking, blocking)
+			}
+		})
+	}
+}
+
+// TestMountAtBasePath_Root verifies the router is used as-is without a base path
+func TestMountAtBasePath_Root(t *testing.T) {
+	router := mux.NewRouter()
+	if MountAtBasePath(router, "") != router {
+		t.Error("Expected routes to be registered on the root router")
+	}
+}
+
+// TestMountAtBasePath_Redirects verifies login redirects and preloaded assets carry the base path
+func TestMountAtBasePath_Redirects(t *testing.T) {
+	const basePath = "/claude-control"
+
+	rootRouter := mux.NewRouter()
+	router := MountAtBasePath(rootRouter, basePath)
+	rootRouter.Use(PreloadMiddleware(map[string][]string{"/dashboard": {"/static/app.js"}}))
+	rootRouter.Use(DashboardAuth(NewDashboardCookieStore("secret")))
+	router.HandleFunc("/dashboard", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods("GET")
+
+	req := httptest.NewRequestWithContext(context.Background(), "GET", basePath+"/dashboard", nil)
+	rec := httptest.NewRecorder()
+	rootRouter.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("Expected login redirect, got status %d", rec.Code)
+	}
+	if location := rec.Header().Get("Location"); location != basePath+"/login" {
+		t.Errorf("Expected redirect to %s/login, got %q", basePath, location)
+	}
+	if link := rec.Header().Get("Link"); link != "</claude-control/static/app.js>; rel=preload; as=script" {
+		t.Errorf("Expected prefixed preload link, got %q", link)
+	}
+}
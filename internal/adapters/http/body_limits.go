@@ -0,0 +1,57 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+const (
+	// DefaultPostToolUseMaxBytes leaves room for tool output such as the contents of a file that was read
+	DefaultPostToolUseMaxBytes = 4 << 20
+
+	// DefaultWebhookMaxBytes bounds every other webhook body
+	DefaultWebhookMaxBytes = 64 << 10
+)
+
+// BodySizeConfig limits the size of webhook request bodies by hook type
+type BodySizeConfig struct {
+	PostToolUseMaxBytes int64 `json:"post_tool_use_max_bytes" yaml:"post_tool_use_max_bytes"`
+	DefaultMaxBytes     int64 `json:"default_max_bytes" yaml:"default_max_bytes"` // Every other hook type and the receipt route
+}
+
+// DefaultBodySizeConfig returns 4MB for PostToolUse and 64KB for everything else
+func DefaultBodySizeConfig() BodySizeConfig {
+	return BodySizeConfig{
+		PostToolUseMaxBytes: DefaultPostToolUseMaxBytes,
+		DefaultMaxBytes:     DefaultWebhookMaxBytes,
+	}
+}
+
+// limitFor returns the body size limit for a hook type; zero or negative limits fall back to the defaults
+func (c BodySizeConfig) limitFor(hookType domain.HookType) int64 {
+	if hookType == domain.HookTypePostToolUse {
+		if c.PostToolUseMaxBytes > 0 {
+			return c.PostToolUseMaxBytes
+		}
+		return DefaultPostToolUseMaxBytes
+	}
+	if c.DefaultMaxBytes > 0 {
+		return c.DefaultMaxBytes
+	}
+	return DefaultWebhookMaxBytes
+}
+
+// payloadTooLargeResponse is the body of a 413 response
+type payloadTooLargeResponse struct {
+	Error string `json:"error"`
+	Limit int64  `json:"limit"`
+}
+
+// respondWithPayloadTooLarge tells the caller its body went over limit bytes
+func respondWithPayloadTooLarge(w http.ResponseWriter, limit int64) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusRequestEntityTooLarge)
+	json.NewEncoder(w).Encode(payloadTooLargeResponse{Error: "payload too large", Limit: limit})
+}
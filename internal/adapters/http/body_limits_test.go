@@ -0,0 +1,90 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/gorilla/mux"
+)
+
+func TestBodySizeConfig_LimitFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   BodySizeConfig
+		hookType domain.HookType
+		expected int64
+	}{
+		{"PostToolUse default", DefaultBodySizeConfig(), domain.HookTypePostToolUse, 4 << 20},
+		{"PreToolUse default", DefaultBodySizeConfig(), domain.HookTypePreToolUse, 64 << 10},
+		{"Unknown hook type", DefaultBodySizeConfig(), "", 64 << 10},
+		{"Configured PostToolUse", BodySizeConfig{PostToolUseMaxBytes: 8 << 20}, domain.HookTypePostToolUse, 8 << 20},
+		{"Configured default", BodySizeConfig{DefaultMaxBytes: 1 << 20}, domain.HookTypeStop, 1 << 20},
+		{"Unset config", BodySizeConfig{}, domain.HookTypePostToolUse, DefaultPostToolUseMaxBytes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.limitFor(tt.hookType); got != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWebhookHandler_BodySizeLimits(t *testing.T) {
+	limits := BodySizeConfig{PostToolUseMaxBytes: 1024, DefaultMaxBytes: 256}
+	padding := func(n int) string {
+		return `{"session_id": "abc123", "padding": "` + strings.Repeat("x", n) + `"}`
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		secret         string
+		expectedStatus int
+		expectedLimit  int64
+	}{
+		{"PostToolUse within its limit", "/webhook/PostToolUse", padding(512), "", http.StatusOK, 0},
+		{"PostToolUse over its limit", "/webhook/PostToolUse", padding(2048), "", http.StatusRequestEntityTooLarge, 1024},
+		{"Alias uses the hook type's limit", "/webhook/post_tool_use", padding(512), "", http.StatusOK, 0},
+		{"PreToolUse over the default limit", "/webhook/PreToolUse", padding(512), "", http.StatusRequestEntityTooLarge, 256},
+		{"Over the limit with signatures on", "/webhook/PreToolUse", padding(512), "webhook-secret", http.StatusRequestEntityTooLarge, 256},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewWebhookHandler(&recordingSessionService{})
+			handler.SetBodySizeLimits(limits)
+			handler.SetWebhookSecret(tt.secret)
+			router := mux.NewRouter()
+			handler.RegisterRoutes(router)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			if tt.secret != "" {
+				req.Header.Set(WebhookSignatureHeader, GenerateWebhookSignature([]byte(tt.secret), []byte(tt.body)))
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusRequestEntityTooLarge {
+				return
+			}
+
+			var response payloadTooLargeResponse
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Error != "payload too large" || response.Limit != tt.expectedLimit {
+				t.Errorf("Expected payload too large with limit %d, got %+v", tt.expectedLimit, response)
+			}
+		})
+	}
+}
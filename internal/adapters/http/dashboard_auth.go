@@ -0,0 +1,131 @@
+package http
+
+import (
+	"crypto/sha256"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/securecookie"
+)
+
+// DashboardSessionCookieName is the cookie holding a signed dashboard login
+const DashboardSessionCookieName = "session"
+
+// DashboardSessionMaxAge is how long a dashboard login lasts
+const DashboardSessionMaxAge = 7 * 24 * time.Hour
+
+// dashboardSession is the value stored in the signed session cookie
+type dashboardSession struct {
+	Authenticated bool  `json:"authenticated"`
+	IssuedAt      int64 `json:"issued_at"`
+}
+
+// NewDashboardCookieStore creates the signing and encryption keys for dashboard session cookies
+// Keys are derived from secret so logins survive restarts; an empty secret uses random keys.
+func NewDashboardCookieStore(secret string) *securecookie.SecureCookie {
+	hashKey := deriveDashboardKey(secret, "session-hash")
+	blockKey := deriveDashboardKey(secret, "session-block")
+
+	cookieStore := securecookie.New(hashKey, blockKey)
+	cookieStore.MaxAge(int(DashboardSessionMaxAge.Seconds()))
+	cookieStore.SetSerializer(securecookie.JSONEncoder{})
+	return cookieStore
+}
+
+// DashboardCSRFKey returns the key used to sign the login form's CSRF token
+func DashboardCSRFKey(secret string) []byte {
+	return deriveDashboardKey(secret, "csrf")
+}
+
+// deriveDashboardKey derives a 32-byte key for one purpose from the configured secret
+func deriveDashboardKey(secret, purpose string) []byte {
+	if secret == "" {
+		return securecookie.GenerateRandomKey(32)
+	}
+	key := sha256.Sum256([]byte(purpose + ":" + secret))
+	return key[:]
+}
+
+// DashboardAuth requires a valid dashboard session cookie on every route except the login page,
+// health checks and Claude Code webhooks. Pages redirect to /login; API routes get 401.
+// Requests with a Bearer token on the routes APIKeyMiddleware guards are left for it to check, so it must
+// be installed after DashboardAuth whenever DashboardAuth is. Quick actions carrying an approval token are
+// likewise left to ApprovalTokenMiddleware on their route.
+func DashboardAuth(cookieStore *securecookie.SecureCookie) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isDashboardAuthExempt(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if hasDashboardSession(r, cookieStore) {
+				next.ServeHTTP(w, r.WithContext(withVerifiedDashboardSession(r.Context())))
+				return
+			}
+			if isAPIKeyProtected(r.URL.Path) && bearerToken(r) != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if isQuickActionRequest(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if strings.HasPrefix(r.URL.Path, "/api/") {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"success":false,"error":"Login required"}`))
+				return
+			}
+
+			http.Redirect(w, r, basePathFromRequest(r)+"/login", http.StatusSeeOther)
+		})
+	}
+}
+
+// isDashboardAuthExempt returns true for routes that must work without a dashboard login
+// Admin routes are guarded by their own key so scripts don't need a session.
+func isDashboardAuthExempt(path string) bool {
+	return path == "/login" ||
+		path == "/health" || strings.HasPrefix(path, "/health/") ||
+		strings.HasPrefix(path, "/webhook/") ||
+		strings.HasPrefix(path, "/debug/webhook/") ||
+		strings.HasPrefix(path, "/api/admin/")
+}
+
+// hasDashboardSession checks the request for a valid, unexpired session cookie
+func hasDashboardSession(r *http.Request, cookieStore *securecookie.SecureCookie) bool {
+	cookie, err := r.Cookie(DashboardSessionCookieName)
+	if err != nil {
+		return false
+	}
+
+	var session dashboardSession
+	if err := cookieStore.Decode(DashboardSessionCookieName, cookie.Value, &session); err != nil {
+		return false
+	}
+	return session.Authenticated
+}
+
+// setDashboardSessionCookie logs the browser in by setting a signed session cookie
+func setDashboardSessionCookie(w http.ResponseWriter, cookieStore *securecookie.SecureCookie, secure bool) error {
+	encoded, err := cookieStore.Encode(DashboardSessionCookieName, dashboardSession{
+		Authenticated: true,
+		IssuedAt:      time.Now().Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     DashboardSessionCookieName,
+		Value:    encoded,
+		Path:     "/",
+		MaxAge:   int(DashboardSessionMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
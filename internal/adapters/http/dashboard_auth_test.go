@@ -0,0 +1,119 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDashboardAuth(t *testing.T) {
+	cookieStore := NewDashboardCookieStore("test-secret")
+	handler := DashboardAuth(cookieStore)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// Log in once to get a valid session cookie
+	loginRec := httptest.NewRecorder()
+	if err := setDashboardSessionCookie(loginRec, cookieStore, false); err != nil {
+		t.Fatalf("Failed to create session cookie: %v", err)
+	}
+	validCookie := loginRec.Result().Cookies()[0]
+
+	// A cookie signed with a different secret must not be accepted
+	otherRec := httptest.NewRecorder()
+	if err := setDashboardSessionCookie(otherRec, NewDashboardCookieStore("other-secret"), false); err != nil {
+		t.Fatalf("Failed to create session cookie: %v", err)
+	}
+	foreignCookie := otherRec.Result().Cookies()[0]
+
+	tests := []struct {
+		name             string
+		path             string
+		cookie           *http.Cookie
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{name: "Dashboard redirects to login", path: "/dashboard", expectedStatus: http.StatusSeeOther, expectedLocation: "/login"},
+		{name: "Task page redirects to login", path: "/task/123", expectedStatus: http.StatusSeeOther, expectedLocation: "/login"},
+		{name: "API returns unauthorized", path: "/api/tasks", expectedStatus: http.StatusUnauthorized},
+		{name: "Tampered cookie redirects to login", path: "/dashboard", cookie: &http.Cookie{Name: DashboardSessionCookieName, Value: "forged"}, expectedStatus: http.StatusSeeOther, expectedLocation: "/login"},
+		{name: "Cookie from another secret redirects to login", path: "/dashboard", cookie: foreignCookie, expectedStatus: http.StatusSeeOther, expectedLocation: "/login"},
+		{name: "Valid session reaches dashboard", path: "/dashboard", cookie: validCookie, expectedStatus: http.StatusOK},
+		{name: "Valid session reaches API", path: "/api/tasks", cookie: validCookie, expectedStatus: http.StatusOK},
+		{name: "Login page is exempt", path: "/login", expectedStatus: http.StatusOK},
+		{name: "Health check is exempt", path: "/health", expectedStatus: http.StatusOK},
+		{name: "Decision health is exempt", path: "/health/decisions", expectedStatus: http.StatusOK},
+		{name: "Webhooks are exempt", path: "/webhook/PreToolUse", expectedStatus: http.StatusOK},
+		{name: "Admin routes are exempt", path: "/api/admin/hooks/disable", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if location := rec.Header().Get("Location"); location != tt.expectedLocation {
+				t.Errorf("Expected redirect to %q, got %q", tt.expectedLocation, location)
+			}
+		})
+	}
+}
+
+func TestDashboardAuth_WithAPIKeys(t *testing.T) {
+	cookieStore := NewDashboardCookieStore("test-secret")
+	handler := DashboardAuth(cookieStore)(APIKeyMiddleware([]string{"script-key"}, APIKeyRateLimit{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	loginRec := httptest.NewRecorder()
+	if err := setDashboardSessionCookie(loginRec, cookieStore, false); err != nil {
+		t.Fatalf("Failed to create session cookie: %v", err)
+	}
+	validCookie := loginRec.Result().Cookies()[0]
+
+	tests := []struct {
+		name           string
+		path           string
+		cookie         *http.Cookie
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "Logged-in browser needs no key", path: "/task/123", cookie: validCookie, expectedStatus: http.StatusOK},
+		{name: "Script needs no login", path: "/api/tasks", authorization: "Bearer script-key", expectedStatus: http.StatusOK},
+		{name: "Wrong key without login", path: "/api/tasks", authorization: "Bearer guess", expectedStatus: http.StatusUnauthorized},
+		{name: "A key doesn't open dashboard pages", path: "/dashboard", authorization: "Bearer script-key", expectedStatus: http.StatusSeeOther},
+		{name: "Neither", path: "/api/tasks", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+
+	// Quick actions carry their own signed token, which the route checks
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/tasks/6f1c1b9e-2f44-4a8e-9d57-3b1f7e0c2a10/action?action=approve&token=1.ab", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected a quick action to be left to its token check, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,215 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+const (
+	// DefaultMaxDiffFileBytes is the largest file the diff view reads from disk
+	DefaultMaxDiffFileBytes = 1 << 20
+
+	// diffContextLines is how many unchanged lines are shown around each change
+	diffContextLines = 3
+)
+
+// ErrNoFileEdit is returned when a task isn't a PreToolUse hook for the Edit or Write tool
+var ErrNoFileEdit = errors.New("task is not an Edit or Write tool call")
+
+// DiffRenderer shows what an Edit or Write tool call would do to the file on disk as a unified diff
+type DiffRenderer struct {
+	maxFileBytes int64
+}
+
+// NewDiffRenderer creates a renderer that reads files of at most maxFileBytes
+func NewDiffRenderer(maxFileBytes int64) *DiffRenderer {
+	if maxFileBytes <= 0 {
+		maxFileBytes = DefaultMaxDiffFileBytes
+	}
+	return &DiffRenderer{maxFileBytes: maxFileBytes}
+}
+
+// Render returns the unified diff between the file as it is now and as the tool call would leave it
+// Relative paths are resolved against the hook's working directory. Once the tool has run, the file
+// already holds the new content, so the diff is only meaningful while the task is pending.
+func (r *DiffRenderer) Render(hookData *domain.HookData) (string, error) {
+	data, ok := hookData.Data.(*domain.PreToolUseHookData)
+	if !ok || data.ToolInput == nil || data.ToolInput.FilePath == "" || (data.ToolName != "Edit" && data.ToolName != "Write") {
+		return "", ErrNoFileEdit
+	}
+
+	path := data.ToolInput.FilePath
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(data.CWD, path)
+	}
+
+	oldContent, err := r.readFile(path)
+	if err != nil && !(errors.Is(err, os.ErrNotExist) && data.ToolName == "Write") {
+		return "", err
+	}
+
+	newContent := data.ToolInput.Content
+	if data.ToolName == "Edit" {
+		if !strings.Contains(oldContent, data.ToolInput.OldString) {
+			return "", fmt.Errorf("%s no longer contains the text being replaced", path)
+		}
+		count := 1
+		if data.ToolInput.ReplaceAll {
+			count = -1
+		}
+		newContent = strings.Replace(oldContent, data.ToolInput.OldString, data.ToolInput.NewString, count)
+	}
+
+	return unifiedDiff(path, oldContent, newContent), nil
+}
+
+// readFile reads a file, refusing ones larger than the renderer's limit
+func (r *DiffRenderer) readFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, r.maxFileBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if int64(len(content)) > r.maxFileBytes {
+		return "", fmt.Errorf("%s is larger than %d bytes", path, r.maxFileBytes)
+	}
+	return string(content), nil
+}
+
+// diffEntry is one line of a line-level diff, with how many old and new lines came before it
+type diffEntry struct {
+	operation diffmatchpatch.Operation
+	text      string
+	oldLine   int
+	newLine   int
+}
+
+// unifiedDiff formats the changes between two versions of a file like diff -u, or returns "" if there are none
+func unifiedDiff(path, oldContent, newContent string) string {
+	if oldContent == newContent {
+		return ""
+	}
+
+	dmp := diffmatchpatch.New()
+	oldChars, newChars, lines := dmp.DiffLinesToChars(oldContent, newContent)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(oldChars, newChars, false), lines)
+
+	var entries []diffEntry
+	oldLine, newLine := 0, 0
+	for _, diff := range diffs {
+		for _, line := range strings.SplitAfter(diff.Text, "\n") {
+			if line == "" {
+				continue
+			}
+			entries = append(entries, diffEntry{operation: diff.Type, text: strings.TrimSuffix(line, "\n"), oldLine: oldLine, newLine: newLine})
+			if diff.Type != diffmatchpatch.DiffInsert {
+				oldLine++
+			}
+			if diff.Type != diffmatchpatch.DiffDelete {
+				newLine++
+			}
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", path, path)
+	for _, hunk := range diffHunks(entries) {
+		writeHunk(&out, entries[hunk[0]:hunk[1]])
+	}
+	return out.String()
+}
+
+// diffHunks groups the changed entries into [start, end) ranges padded with context lines,
+// merging changes whose context would overlap
+func diffHunks(entries []diffEntry) [][2]int {
+	var hunks [][2]int
+	for i, entry := range entries {
+		if entry.operation == diffmatchpatch.DiffEqual {
+			continue
+		}
+
+		start, end := max(0, i-diffContextLines), min(len(entries), i+diffContextLines+1)
+		if last := len(hunks) - 1; last >= 0 && start <= hunks[last][1] {
+			hunks[last][1] = end
+			continue
+		}
+		hunks = append(hunks, [2]int{start, end})
+	}
+	return hunks
+}
+
+// writeHunk writes one hunk's header and lines
+func writeHunk(out *strings.Builder, hunk []diffEntry) {
+	oldCount, newCount := 0, 0
+	for _, entry := range hunk {
+		if entry.operation != diffmatchpatch.DiffInsert {
+			oldCount++
+		}
+		if entry.operation != diffmatchpatch.DiffDelete {
+			newCount++
+		}
+	}
+
+	// Ranges start at line 1, except an empty range names the line before it
+	oldStart, newStart := hunk[0].oldLine, hunk[0].newLine
+	if oldCount > 0 {
+		oldStart++
+	}
+	if newCount > 0 {
+		newStart++
+	}
+	fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+
+	for _, entry := range hunk {
+		prefix := " "
+		switch entry.operation {
+		case diffmatchpatch.DiffInsert:
+			prefix = "+"
+		case diffmatchpatch.DiffDelete:
+			prefix = "-"
+		}
+		out.WriteString(prefix + entry.text + "\n")
+	}
+}
+
+// diffLineView is a line of a unified diff with the CSS class the task page colours it by
+type diffLineView struct {
+	Class string
+	Text  string
+}
+
+// newDiffLineViews splits a unified diff into lines classed as file headers, hunk headers, additions or deletions
+func newDiffLineViews(diff string) []diffLineView {
+	if diff == "" {
+		return nil
+	}
+
+	var views []diffLineView
+	for i, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		class := "diff-context"
+		switch {
+		case i < 2:
+			class = "diff-file"
+		case strings.HasPrefix(line, "@@"):
+			class = "diff-hunk"
+		case strings.HasPrefix(line, "+"):
+			class = "diff-add"
+		case strings.HasPrefix(line, "-"):
+			class = "diff-delete"
+		}
+		views = append(views, diffLineView{Class: class, Text: line})
+	}
+	return views
+}
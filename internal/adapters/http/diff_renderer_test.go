@@ -0,0 +1,187 @@
+package http
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	oldContent := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello\")\n}\n"
+	newContent := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello, world\")\n}\n"
+
+	expected := strings.Join([]string{
+		"--- /srv/app/main.go",
+		"+++ /srv/app/main.go",
+		"@@ -3,5 +3,5 @@",
+		" import \"fmt\"",
+		" ",
+		" func main() {",
+		"-\tfmt.Println(\"hello\")",
+		"+\tfmt.Println(\"hello, world\")",
+		" }",
+		"",
+	}, "\n")
+
+	if diff := unifiedDiff("/srv/app/main.go", oldContent, newContent); diff != expected {
+		t.Errorf("Unexpected diff:\n%s\nexpected:\n%s", diff, expected)
+	}
+}
+
+func TestUnifiedDiff_Hunks(t *testing.T) {
+	var oldLines []string
+	for i := 1; i <= 20; i++ {
+		oldLines = append(oldLines, strings.Repeat("x", i))
+	}
+	newLines := append([]string{}, oldLines...)
+	newLines[1] = "changed near the top"
+	newLines[17] = "changed near the bottom"
+
+	diff := unifiedDiff("f", strings.Join(oldLines, "\n")+"\n", strings.Join(newLines, "\n")+"\n")
+	for _, header := range []string{"@@ -1,5 +1,5 @@", "@@ -15,6 +15,6 @@"} {
+		if !strings.Contains(diff, header+"\n") {
+			t.Errorf("Expected hunk %q in diff:\n%s", header, diff)
+		}
+	}
+
+	if diff := unifiedDiff("f", "", "first line\n"); !strings.Contains(diff, "@@ -0,0 +1,1 @@\n+first line\n") {
+		t.Errorf("Expected a new file to diff against an empty range, got:\n%s", diff)
+	}
+	if diff := unifiedDiff("f", "same\n", "same\n"); diff != "" {
+		t.Errorf("Expected no diff for identical content, got %q", diff)
+	}
+}
+
+func TestDiffRenderer_Render(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("port: 8080\nhost: localhost\nport_alt: 8080\n"), 0600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	hookData := func(toolName string, input *domain.ToolInput) *domain.HookData {
+		return &domain.HookData{
+			Type: domain.HookTypePreToolUse,
+			Data: &domain.PreToolUseHookData{
+				BaseHookData: domain.BaseHookData{SessionID: "abc123", CWD: dir},
+				ToolName:     toolName,
+				ToolInput:    input,
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		hookData    *domain.HookData
+		expected    []string
+		expectedErr error
+	}{
+		{
+			name:     "Edit with a relative path",
+			hookData: hookData("Edit", &domain.ToolInput{FilePath: "config.yaml", OldString: "8080", NewString: "9090"}),
+			expected: []string{"--- " + filepath.Join(dir, "config.yaml"), "-port: 8080", "+port: 9090", " port_alt: 8080"},
+		},
+		{
+			name:     "Edit replacing every match",
+			hookData: hookData("Edit", &domain.ToolInput{FilePath: filepath.Join(dir, "config.yaml"), OldString: "8080", NewString: "9090", ReplaceAll: true}),
+			expected: []string{"+port: 9090", "+port_alt: 9090"},
+		},
+		{
+			name:     "Write over an existing file",
+			hookData: hookData("Write", &domain.ToolInput{FilePath: "config.yaml", Content: "port: 8080\n"}),
+			expected: []string{" port: 8080", "-host: localhost", "-port_alt: 8080"},
+		},
+		{
+			name:     "Write creating a file",
+			hookData: hookData("Write", &domain.ToolInput{FilePath: "new.txt", Content: "hello\n"}),
+			expected: []string{"@@ -0,0 +1,1 @@", "+hello"},
+		},
+		{
+			name:        "Bash is not a file edit",
+			hookData:    hookData("Bash", &domain.ToolInput{Command: "cat config.yaml"}),
+			expectedErr: ErrNoFileEdit,
+		},
+		{
+			name:        "PostToolUse is not diffed",
+			hookData:    &domain.HookData{Type: domain.HookTypePostToolUse, Data: &domain.PostToolUseHookData{ToolName: "Edit"}},
+			expectedErr: ErrNoFileEdit,
+		},
+	}
+
+	renderer := NewDiffRenderer(DefaultMaxDiffFileBytes)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := renderer.Render(tt.hookData)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			lines := strings.Split(diff, "\n")
+			for _, expected := range tt.expected {
+				found := false
+				for _, line := range lines {
+					found = found || line == expected
+				}
+				if !found {
+					t.Errorf("Expected line %q in diff:\n%s", expected, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffRenderer_RenderErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "big.txt"), []byte(strings.Repeat("a", 100)), 0600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    *domain.ToolInput
+		maxBytes int64
+	}{
+		{"Edit of a missing file", &domain.ToolInput{FilePath: "missing.txt", OldString: "a", NewString: "b"}, DefaultMaxDiffFileBytes},
+		{"Edit already applied", &domain.ToolInput{FilePath: "big.txt", OldString: "b", NewString: "c"}, DefaultMaxDiffFileBytes},
+		{"File over the size limit", &domain.ToolInput{FilePath: "big.txt", OldString: "a", NewString: "b"}, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hookData := &domain.HookData{
+				Type: domain.HookTypePreToolUse,
+				Data: &domain.PreToolUseHookData{BaseHookData: domain.BaseHookData{CWD: dir}, ToolName: "Edit", ToolInput: tt.input},
+			}
+			if _, err := NewDiffRenderer(tt.maxBytes).Render(hookData); err == nil || errors.Is(err, ErrNoFileEdit) {
+				t.Errorf("Expected an error reading the file, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewDiffLineViews(t *testing.T) {
+	views := newDiffLineViews("--- f\n+++ f\n@@ -1,2 +1,2 @@\n same\n--- removed dashes\n+added\n")
+
+	expected := []string{"diff-file", "diff-file", "diff-hunk", "diff-context", "diff-delete", "diff-add"}
+	if len(views) != len(expected) {
+		t.Fatalf("Expected %d lines, got %+v", len(expected), views)
+	}
+	for i, class := range expected {
+		if views[i].Class != class {
+			t.Errorf("Line %d %q: expected class %s, got %s", i, views[i].Text, class, views[i].Class)
+		}
+	}
+
+	if views := newDiffLineViews(""); views != nil {
+		t.Errorf("Expected no lines for an empty diff, got %+v", views)
+	}
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/services"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// EventStreamKeepaliveInterval is how often an idle event stream gets a comment line
+	// so proxies don't close it
+	EventStreamKeepaliveInterval = 30 * time.Second
+
+	// eventStreamWriteTimeout bounds how long sending one event to a client may take
+	eventStreamWriteTimeout = 10 * time.Second
+)
+
+// TaskEventResumer publishes task events and replays the recent ones a reconnecting client missed
+type TaskEventResumer interface {
+	ResumeTaskEvents(lastEventID uint64) ([]services.TaskEvent, <-chan services.TaskEvent, func())
+}
+
+// EventStreamHandler pushes task events to clients as Server-Sent Events
+// It's a one-way alternative to WebSocketHandler that only needs EventSource in the browser.
+type EventStreamHandler struct {
+	events            TaskEventResumer
+	keepaliveInterval time.Duration
+}
+
+// NewEventStreamHandler creates a handler streaming the resumer's task events
+func NewEventStreamHandler(events TaskEventResumer) *EventStreamHandler {
+	return &EventStreamHandler{
+		events:            events,
+		keepaliveInterval: EventStreamKeepaliveInterval,
+	}
+}
+
+// RegisterRoutes registers the task event stream with the router
+func (h *EventStreamHandler) RegisterRoutes(router *mux.Router) {
+	router.Handle("/api/events", h).Methods("GET")
+}
+
+// ServeHTTP streams task events until the client disconnects, starting after the Last-Event-ID header if set
+func (h *EventStreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var lastEventID uint64
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		var err error
+		lastEventID, err = strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			h.respondWithError(w, http.StatusBadRequest, "Invalid Last-Event-ID")
+			return
+		}
+	}
+
+	controller := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // Stop nginx holding events back when serving under a sub-path
+	w.WriteHeader(http.StatusOK)
+	if err := controller.Flush(); err != nil {
+		log.Printf("Failed to start task event stream: %v", err)
+		return
+	}
+
+	missed, events, unsubscribe := h.events.ResumeTaskEvents(lastEventID)
+	defer unsubscribe()
+
+	for _, event := range missed {
+		if err := h.writeEvent(w, controller, event); err != nil {
+			log.Printf("Closing task event stream for %s: %v", r.RemoteAddr, err)
+			return
+		}
+	}
+
+	keepalive := time.NewTicker(h.keepaliveInterval)
+	defer keepalive.Stop()
+
+	for {
+		var err error
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			err = h.writeEvent(w, controller, event)
+		case <-keepalive.C:
+			err = h.write(w, controller, ": keepalive\n\n")
+		case <-r.Context().Done():
+			return
+		}
+		if err != nil {
+			log.Printf("Closing task event stream for %s: %v", r.RemoteAddr, err)
+			return
+		}
+	}
+}
+
+// writeEvent sends one task event with its sequence number as the event ID
+func (h *EventStreamHandler) writeEvent(w http.ResponseWriter, controller *http.ResponseController, event services.TaskEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode task event %d: %w", event.ID, err)
+	}
+	return h.write(w, controller, fmt.Sprintf("id: %d\ndata: %s\n\n", event.ID, data))
+}
+
+// write sends text to the client straight away rather than leaving it in a buffer
+func (h *EventStreamHandler) write(w http.ResponseWriter, controller *http.ResponseController, text string) error {
+	// Not every ResponseWriter supports deadlines; the stream still works without one
+	controller.SetWriteDeadline(time.Now().Add(eventStreamWriteTimeout))
+	if _, err := fmt.Fprint(w, text); err != nil {
+		return err
+	}
+	return controller.Flush()
+}
+
+// respondWithError sends an error response
+func (h *EventStreamHandler) respondWithError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": false,
+		"error":   message,
+	})
+}
@@ -0,0 +1,163 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/services"
+	"github.com/gorilla/mux"
+)
+
+// historyResumer serves task events from an in-memory history and broadcaster, like TaskService does
+type historyResumer struct {
+	broadcaster *services.EventBroadcaster[services.TaskEvent]
+	history     *services.TaskEventHistory
+	mutex       sync.Mutex
+}
+
+func newHistoryResumer() *historyResumer {
+	return &historyResumer{
+		broadcaster: services.NewEventBroadcaster[services.TaskEvent](services.DefaultEventSubscriberBuffer),
+		history:     services.NewTaskEventHistory(services.DefaultTaskEventHistorySize),
+	}
+}
+
+func (r *historyResumer) ResumeTaskEvents(lastEventID uint64) ([]services.TaskEvent, <-chan services.TaskEvent, func()) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	events, unsubscribe := r.broadcaster.Subscribe()
+	return r.history.Since(lastEventID), events, unsubscribe
+}
+
+func (r *historyResumer) publish(eventType services.TaskEventType) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.broadcaster.Publish(r.history.Append(services.TaskEvent{Type: eventType}))
+}
+
+// openEventStream connects to the handler's event stream, sending lastEventID if set
+func openEventStream(t *testing.T, handler *EventStreamHandler, lastEventID string) *bufio.Reader {
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/events", nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open event stream: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected a 200 event stream, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	return bufio.NewReader(resp.Body)
+}
+
+// readEvent reads lines up to the next blank line, skipping comments
+func readEvent(t *testing.T, reader *bufio.Reader) []string {
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read event: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if len(lines) > 0 {
+				return lines
+			}
+			continue
+		}
+		if !strings.HasPrefix(line, ":") {
+			lines = append(lines, line)
+		}
+	}
+}
+
+// waitForStreamSubscribers waits until the resumer has n subscribers
+func waitForStreamSubscribers(t *testing.T, resumer *historyResumer, n int) {
+	deadline := time.Now().Add(2 * time.Second)
+	for resumer.broadcaster.Subscribers() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d subscribers, got %d", n, resumer.broadcaster.Subscribers())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestEventStreamHandler_StreamsTaskEvents(t *testing.T) {
+	resumer := newHistoryResumer()
+	reader := openEventStream(t, NewEventStreamHandler(resumer), "")
+	waitForStreamSubscribers(t, resumer, 1)
+
+	resumer.publish(services.TaskEventCreated)
+	resumer.publish(services.TaskEventUpdated)
+
+	expected := [][]string{
+		{"id: 1", `data: {"id":1,"uuid":"00000000-0000-0000-0000-000000000000","type":"created","task":null}`},
+		{"id: 2", `data: {"id":2,"uuid":"00000000-0000-0000-0000-000000000000","type":"updated","task":null}`},
+	}
+	for _, want := range expected {
+		got := readEvent(t, reader)
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("Expected event %q, got %q", want, got)
+		}
+	}
+}
+
+func TestEventStreamHandler_ResumesAfterLastEventID(t *testing.T) {
+	resumer := newHistoryResumer()
+	for i := 0; i < 3; i++ {
+		resumer.publish(services.TaskEventUpdated)
+	}
+
+	reader := openEventStream(t, NewEventStreamHandler(resumer), "1")
+	for _, want := range []string{"id: 2", "id: 3"} {
+		if got := readEvent(t, reader); got[0] != want {
+			t.Errorf("Expected missed event %q, got %q", want, got[0])
+		}
+	}
+
+	resumer.publish(services.TaskEventCreated)
+	if got := readEvent(t, reader); got[0] != "id: 4" {
+		t.Errorf("Expected live event %q, got %q", "id: 4", got[0])
+	}
+}
+
+func TestEventStreamHandler_Keepalive(t *testing.T) {
+	handler := NewEventStreamHandler(newHistoryResumer())
+	handler.keepaliveInterval = 10 * time.Millisecond
+	reader := openEventStream(t, handler, "")
+
+	line, err := reader.ReadString('\n')
+	if err != nil || line != ": keepalive\n" {
+		t.Errorf("Expected a keepalive comment, got %q (%v)", line, err)
+	}
+}
+
+func TestEventStreamHandler_InvalidLastEventID(t *testing.T) {
+	router := mux.NewRouter()
+	NewEventStreamHandler(newHistoryResumer()).RegisterRoutes(router)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+}
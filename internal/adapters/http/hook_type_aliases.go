@@ -0,0 +1,66 @@
+package http
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+// legacyHookTypeKeys are the body keys older Claude Code versions used instead of hook_event_name
+var legacyHookTypeKeys = []string{"hook_type", "hookType", "event", "event_type"}
+
+// DefaultHookTypeAliases returns the spellings older Claude Code versions used for each hook type
+// Keys are lowercase; lookups lowercase the name first, so "preToolUse" and "PRE_TOOL_USE" match too.
+func DefaultHookTypeAliases() map[string]domain.HookType {
+	aliases := make(map[string]domain.HookType)
+	for _, hookType := range domain.AllHookTypes() {
+		words := splitHookTypeWords(hookType.String())
+		aliases[strings.Join(words, "_")] = hookType
+		aliases[strings.Join(words, "-")] = hookType
+		aliases[strings.Join(words, "")] = hookType
+	}
+	return aliases
+}
+
+// splitHookTypeWords splits a PascalCase hook type such as PreToolUse into lowercase words
+func splitHookTypeWords(name string) []string {
+	var words []string
+	start := 0
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			words = append(words, strings.ToLower(name[start:i]))
+			start = i
+		}
+	}
+	return append(words, strings.ToLower(name[start:]))
+}
+
+// resolveHookType maps a hook name to its hook type, falling back to the alias map
+// The second result is true when the name was an alias rather than the canonical hook type.
+func (h *WebhookHandler) resolveHookType(name string) (domain.HookType, bool, error) {
+	hookType, err := domain.ParseHookType(name)
+	if err == nil {
+		return hookType, false, nil
+	}
+
+	trimmed := strings.TrimSpace(name)
+	if alias, ok := h.HookTypeAliases[trimmed]; ok {
+		return alias, true, nil
+	}
+	if alias, ok := h.HookTypeAliases[strings.ToLower(trimmed)]; ok {
+		return alias, true, nil
+	}
+	return "", false, err
+}
+
+// hookEventName finds the hook name in a webhook body, checking the legacy keys when hook_event_name is missing
+// It returns the key the name was found under, or an empty key when the body names no hook at all.
+func hookEventName(rawData map[string]interface{}) (string, string) {
+	for _, key := range append([]string{"hook_event_name"}, legacyHookTypeKeys...) {
+		if name, _ := rawData[key].(string); name != "" {
+			return key, name
+		}
+	}
+	return "", ""
+}
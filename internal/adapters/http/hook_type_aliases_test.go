@@ -0,0 +1,177 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/gorilla/mux"
+)
+
+// recordingSessionService keeps the events the webhook handler appends
+type recordingSessionService struct {
+	ports.SessionService
+	events []*domain.SessionEvent
+}
+
+func (s *recordingSessionService) AppendEvent(ctx context.Context, sessionID string, event *domain.SessionEvent) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestDefaultHookTypeAliases(t *testing.T) {
+	tests := []struct {
+		alias    string
+		expected domain.HookType
+	}{
+		{"pre_tool_use", domain.HookTypePreToolUse},
+		{"pre-tool-use", domain.HookTypePreToolUse},
+		{"pretooluse", domain.HookTypePreToolUse},
+		{"post_tool_use", domain.HookTypePostToolUse},
+		{"post-tool-use", domain.HookTypePostToolUse},
+		{"posttooluse", domain.HookTypePostToolUse},
+		{"notification", domain.HookTypeNotification},
+		{"user_prompt_submit", domain.HookTypeUserPromptSubmit},
+		{"user-prompt-submit", domain.HookTypeUserPromptSubmit},
+		{"userpromptsubmit", domain.HookTypeUserPromptSubmit},
+		{"stop", domain.HookTypeStop},
+		{"subagent_stop", domain.HookTypeSubagentStop},
+		{"subagent-stop", domain.HookTypeSubagentStop},
+		{"subagentstop", domain.HookTypeSubagentStop},
+		{"pre_compact", domain.HookTypePreCompact},
+		{"pre-compact", domain.HookTypePreCompact},
+		{"precompact", domain.HookTypePreCompact},
+	}
+
+	aliases := DefaultHookTypeAliases()
+	if len(aliases) != len(tests) {
+		t.Errorf("Expected %d default aliases, got %d", len(tests), len(aliases))
+	}
+
+	handler := NewWebhookHandler(nil)
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			if got := aliases[tt.alias]; got != tt.expected {
+				t.Errorf("Expected alias %q to map to %s, got %q", tt.alias, tt.expected, got)
+			}
+
+			hookType, aliased, err := handler.resolveHookType(strings.ToUpper(tt.alias))
+			if err != nil || !aliased || hookType != tt.expected {
+				t.Errorf("Expected %q to resolve to %s as an alias, got %q (aliased=%t, err=%v)",
+					strings.ToUpper(tt.alias), tt.expected, hookType, aliased, err)
+			}
+		})
+	}
+}
+
+func TestResolveHookType(t *testing.T) {
+	handler := NewWebhookHandler(nil)
+	handler.HookTypeAliases["tool"] = domain.HookTypePreToolUse
+
+	tests := []struct {
+		name            string
+		input           string
+		expected        domain.HookType
+		expectedAliased bool
+		expectError     bool
+	}{
+		{"Canonical name", "PreToolUse", domain.HookTypePreToolUse, false, false},
+		{"camelCase", "preToolUse", domain.HookTypePreToolUse, true, false},
+		{"Custom alias", "tool", domain.HookTypePreToolUse, true, false},
+		{"Unknown", "before_tool", "", false, true},
+		{"Empty", "", "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hookType, aliased, err := handler.resolveHookType(tt.input)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Expected error %t, got %v", tt.expectError, err)
+			}
+			if hookType != tt.expected || aliased != tt.expectedAliased {
+				t.Errorf("Expected %q (aliased=%t), got %q (aliased=%t)", tt.expected, tt.expectedAliased, hookType, aliased)
+			}
+		})
+	}
+}
+
+func TestWebhookHandler_HookTypeAliasInURL(t *testing.T) {
+	for alias, expected := range DefaultHookTypeAliases() {
+		t.Run(alias, func(t *testing.T) {
+			sessionService := &recordingSessionService{}
+			router := mux.NewRouter()
+			NewWebhookHandler(sessionService).RegisterRoutes(router)
+
+			body := `{"session_id": "abc123", "hook_event_name": "` + expected.String() + `"}`
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook/"+alias, strings.NewReader(body)))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if len(sessionService.events) != 1 || sessionService.events[0].HookType != expected {
+				t.Errorf("Expected one %s event, got %+v", expected, sessionService.events)
+			}
+		})
+	}
+}
+
+func TestWebhookHandler_LegacyHookEventKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"hook_type", `{"session_id": "abc123", "hook_type": "PreToolUse"}`, "PreToolUse"},
+		{"hookType", `{"session_id": "abc123", "hookType": "pre_tool_use"}`, "PreToolUse"},
+		{"event", `{"session_id": "abc123", "event": "pre-tool-use"}`, "PreToolUse"},
+		{"event_type", `{"session_id": "abc123", "event_type": "PRE_TOOL_USE"}`, "PreToolUse"},
+		{"Aliased hook_event_name", `{"session_id": "abc123", "hook_event_name": "pre_tool_use"}`, "PreToolUse"},
+		{"hook_event_name wins", `{"session_id": "abc123", "hook_event_name": "PreToolUse", "event": "stop"}`, "PreToolUse"},
+		{"Unknown legacy value left alone", `{"session_id": "abc123", "event": "tool_call"}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionService := &recordingSessionService{}
+			router := mux.NewRouter()
+			NewWebhookHandler(sessionService).RegisterRoutes(router)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook/PreToolUse", strings.NewReader(tt.body)))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if len(sessionService.events) != 1 {
+				t.Fatalf("Expected one event, got %d", len(sessionService.events))
+			}
+
+			eventData, _ := sessionService.events[0].EventData.(map[string]interface{})
+			hookEventName, _ := eventData["hook_event_name"].(string)
+			if hookEventName != tt.expected {
+				t.Errorf("Expected hook_event_name %q, got %q", tt.expected, hookEventName)
+			}
+		})
+	}
+}
+
+func TestWebhookHandler_UnknownHookTypeAlias(t *testing.T) {
+	router := mux.NewRouter()
+	NewWebhookHandler(nil).RegisterRoutes(router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook/before_tool", strings.NewReader(`{}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown alias, got %d", rec.Code)
+	}
+
+	var response map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil || response["error"] != "Unknown hook type" {
+		t.Errorf("Expected an unknown hook type error, got %v (%v)", response, err)
+	}
+}
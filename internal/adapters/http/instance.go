@@ -0,0 +1,80 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// RequestIDHeader is the response header carrying the ID generated for each request
+const RequestIDHeader = "X-Request-ID"
+
+// InstanceIDHeader is the response header naming the server instance that handled the request
+const InstanceIDHeader = "X-Instance-ID"
+
+// fallbackInstanceID is used when the hostname can't be read
+const fallbackInstanceID = "claude-control"
+
+// InstanceInfo describes the running server instance
+type InstanceInfo struct {
+	InstanceID string    `json:"instance_id"`
+	StartTime  time.Time `json:"start_time"`
+	Version    string    `json:"version"`
+}
+
+// DefaultInstanceID returns the hostname, which is unique per container in most deployments
+func DefaultInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return fallbackInstanceID
+	}
+	return hostname
+}
+
+// RequestIDMiddleware gives every request an ID of the form <instanceID>-<uuid> so logs and
+// responses from different replicas can be told apart. Both IDs are sent as response headers.
+func RequestIDMiddleware(instanceID string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := instanceID + "-" + uuid.New().String()
+
+			w.Header().Set(RequestIDHeader, requestID)
+			w.Header().Set(InstanceIDHeader, instanceID)
+
+			next.ServeHTTP(w, r.WithContext(domain.WithRequestID(r.Context(), requestID)))
+		})
+	}
+}
+
+// RequestIDFromContext returns the request ID set by RequestIDMiddleware, or "" outside a request
+func RequestIDFromContext(ctx context.Context) string {
+	return domain.RequestIDFromContext(ctx)
+}
+
+// InstanceHandler reports which server instance is answering
+type InstanceHandler struct {
+	info InstanceInfo
+}
+
+// NewInstanceHandler creates a new instance handler
+func NewInstanceHandler(info InstanceInfo) *InstanceHandler {
+	return &InstanceHandler{info: info}
+}
+
+// RegisterRoutes registers the instance debug route with the router
+func (h *InstanceHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/api/debug/instance", h.handleGetInstance).Methods("GET")
+}
+
+// handleGetInstance returns the instance ID, start time and version
+func (h *InstanceHandler) handleGetInstance(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(h.info)
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var contextRequestID string
+	router := mux.NewRouter()
+	router.Use(RequestIDMiddleware("replica-2"))
+	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		contextRequestID = RequestIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+
+		requestID := rec.Header().Get(RequestIDHeader)
+		if !strings.HasPrefix(requestID, "replica-2-") {
+			t.Errorf("Expected %s to start with the instance ID, got %q", RequestIDHeader, requestID)
+		}
+		if len(requestID) != len("replica-2-")+36 {
+			t.Errorf("Expected the instance ID followed by a UUID, got %q", requestID)
+		}
+		if seen[requestID] {
+			t.Errorf("Request ID %q was reused", requestID)
+		}
+		seen[requestID] = true
+
+		if got := rec.Header().Get(InstanceIDHeader); got != "replica-2" {
+			t.Errorf("Expected %s replica-2, got %q", InstanceIDHeader, got)
+		}
+		if contextRequestID != requestID {
+			t.Errorf("Expected request ID %q in the context, got %q", requestID, contextRequestID)
+		}
+	}
+}
+
+func TestInstanceHandler(t *testing.T) {
+	startTime := time.Date(2025, 8, 1, 9, 30, 0, 0, time.UTC)
+	router := mux.NewRouter()
+	NewInstanceHandler(InstanceInfo{InstanceID: "replica-2", StartTime: startTime, Version: "1.4.0"}).RegisterRoutes(router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/debug/instance", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var body map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	expected := map[string]string{
+		"instance_id": "replica-2",
+		"start_time":  "2025-08-01T09:30:00Z",
+		"version":     "1.4.0",
+	}
+	for key, value := range expected {
+		if body[key] != value {
+			t.Errorf("Expected %s %q, got %q", key, value, body[key])
+		}
+	}
+}
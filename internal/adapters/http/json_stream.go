@@ -0,0 +1,78 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+// streamJSONList writes {"success":true,"<key>":[...],"count":N}, encoding one item at a time
+// Encoding the whole response at once buffers every item's JSON before the first byte is written,
+// which for thousands of tasks means megabytes of garbage per request.
+func streamJSONList[T any](w io.Writer, key string, items []T) error {
+	return streamJSONPage(w, key, items, nil)
+}
+
+// streamJSONPage writes a list like streamJSONList, followed by "pagination" when it isn't nil
+func streamJSONPage[T any](w io.Writer, key string, items []T, pagination *domain.Pagination) error {
+	encodedKey, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, `{"success":true,%s:[`, encodedKey); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	for i, item := range items {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		if err := encoder.Encode(item); err != nil {
+			return fmt.Errorf("failed to encode item %d: %w", i, err)
+		}
+	}
+
+	if _, err := fmt.Fprintf(w, "],\"count\":%d", len(items)); err != nil {
+		return err
+	}
+	if pagination != nil {
+		encodedPagination, err := json.Marshal(pagination)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, ",\"pagination\":%s", encodedPagination); err != nil {
+			return err
+		}
+	}
+
+	_, err = io.WriteString(w, "}\n")
+	return err
+}
+
+// respondWithJSONList sends a successful list response without buffering the whole body
+// An encoding failure part way through can only be logged, since the status has already been sent.
+func respondWithJSONList[T any](w http.ResponseWriter, key string, items []T) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := streamJSONList(w, key, items); err != nil {
+		log.Printf("Failed to stream JSON %s response: %v", key, err)
+	}
+}
+
+// respondWithJSONPage sends one page of a list like respondWithJSONList, with where it sits in the full list
+func respondWithJSONPage[T any](w http.ResponseWriter, key string, items []T, pagination domain.Pagination) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := streamJSONPage(w, key, items, &pagination); err != nil {
+		log.Printf("Failed to stream JSON %s response: %v", key, err)
+	}
+}
@@ -0,0 +1,162 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// streamedTask has the shape of a task as /api/tasks returns it
+type streamedTask struct {
+	ID        uuid.UUID        `json:"id"`
+	HookType  domain.HookType  `json:"hook_type"`
+	HookData  *domain.HookData `json:"hook_data"`
+	Status    string           `json:"status"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+func newStreamedTasks(n int) []*streamedTask {
+	tasks := make([]*streamedTask, n)
+	for i := range tasks {
+		tasks[i] = &streamedTask{
+			ID:       uuid.New(),
+			HookType: domain.HookTypePreToolUse,
+			HookData: &domain.HookData{
+				Type: domain.HookTypePreToolUse,
+				Data: &domain.PreToolUseHookData{
+					BaseHookData: domain.BaseHookData{SessionID: "c3e0f54b-0df7-4aa2-8179-1ee1b8c17147", CWD: "/srv/haiper"},
+					ToolName:     "Bash",
+					ToolInput:    &domain.ToolInput{Command: fmt.Sprintf("go test ./... -run Test%d", i)},
+				},
+			},
+			Status:    "pending",
+			CreatedAt: time.Date(2025, 8, 1, 12, 0, i%60, 0, time.UTC),
+		}
+	}
+	return tasks
+}
+
+func TestStreamJSONList(t *testing.T) {
+	for _, n := range []int{0, 1, 3} {
+		t.Run(fmt.Sprintf("%d tasks", n), func(t *testing.T) {
+			tasks := newStreamedTasks(n)
+
+			var body bytes.Buffer
+			if err := streamJSONList(&body, "tasks", tasks); err != nil {
+				t.Fatalf("Failed to stream tasks: %v", err)
+			}
+
+			var response struct {
+				Success bool              `json:"success"`
+				Tasks   []json.RawMessage `json:"tasks"`
+				Count   int               `json:"count"`
+			}
+			if err := json.Unmarshal(body.Bytes(), &response); err != nil {
+				t.Fatalf("Response is not valid JSON: %v\n%s", err, body.String())
+			}
+			if !response.Success {
+				t.Error("Expected success to be true")
+			}
+			if response.Count != n || len(response.Tasks) != n {
+				t.Errorf("Expected count %d and %d tasks, got count %d and %d tasks", n, n, response.Count, len(response.Tasks))
+			}
+			if response.Tasks == nil {
+				t.Error("Expected an empty array rather than null")
+			}
+
+			for i, raw := range response.Tasks {
+				var decoded streamedTask
+				if err := json.Unmarshal(raw, &decoded); err != nil {
+					t.Fatalf("Task %d is not valid JSON: %v", i, err)
+				}
+				if decoded.ID != tasks[i].ID || decoded.HookData.GetCommand() != tasks[i].HookData.GetCommand() {
+					t.Errorf("Task %d did not round trip: %+v", i, decoded)
+				}
+			}
+		})
+	}
+}
+
+func TestStreamJSONPage(t *testing.T) {
+	tasks := newStreamedTasks(2)
+
+	var body bytes.Buffer
+	if err := streamJSONPage(&body, "tasks", tasks, &domain.Pagination{Total: 5, Limit: 2, Offset: 0, HasMore: true}); err != nil {
+		t.Fatalf("Failed to stream tasks: %v", err)
+	}
+
+	var response struct {
+		Success    bool               `json:"success"`
+		Tasks      []json.RawMessage  `json:"tasks"`
+		Count      int                `json:"count"`
+		Pagination *domain.Pagination `json:"pagination"`
+	}
+	if err := json.Unmarshal(body.Bytes(), &response); err != nil {
+		t.Fatalf("Response is not valid JSON: %v\n%s", err, body.String())
+	}
+	if !response.Success || response.Count != 2 || len(response.Tasks) != 2 {
+		t.Errorf("Expected a successful response with 2 tasks, got %s", body.String())
+	}
+	expected := domain.Pagination{Total: 5, Limit: 2, Offset: 0, HasMore: true}
+	if response.Pagination == nil || *response.Pagination != expected {
+		t.Errorf("Expected pagination %+v, got %+v", expected, response.Pagination)
+	}
+
+	body.Reset()
+	if err := streamJSONList(&body, "tasks", tasks); err != nil {
+		t.Fatalf("Failed to stream tasks: %v", err)
+	}
+	if bytes.Contains(body.Bytes(), []byte("pagination")) {
+		t.Errorf("Expected no pagination in a plain list, got %s", body.String())
+	}
+}
+
+// largestWriteRecorder discards output but remembers the biggest single write, i.e. the largest buffer flushed
+type largestWriteRecorder struct {
+	largest int
+}
+
+func (r *largestWriteRecorder) Write(p []byte) (int, error) {
+	if len(p) > r.largest {
+		r.largest = len(p)
+	}
+	return len(p), nil
+}
+
+// BenchmarkListTasksResponse compares streaming 10,000 tasks with encoding the whole response at once
+// encoding/json pools its buffers, so B/op barely differs; largest-write-B shows the buffer each approach
+// needs, which is the whole body when buffered and about one task when streamed.
+func BenchmarkListTasksResponse(b *testing.B) {
+	tasks := newStreamedTasks(10000)
+
+	b.Run("buffered", func(b *testing.B) {
+		b.ReportAllocs()
+		recorder := &largestWriteRecorder{}
+		for i := 0; i < b.N; i++ {
+			if err := json.NewEncoder(recorder).Encode(map[string]interface{}{
+				"success": true,
+				"tasks":   tasks,
+				"count":   len(tasks),
+			}); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(recorder.largest), "largest-write-B")
+	})
+
+	b.Run("streamed", func(b *testing.B) {
+		b.ReportAllocs()
+		recorder := &largestWriteRecorder{}
+		for i := 0; i < b.N; i++ {
+			if err := streamJSONList(recorder, "tasks", tasks); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(recorder.largest), "largest-write-B")
+	})
+}
@@ -0,0 +1,177 @@
+package http
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/dan/claude-control/internal/core/services"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsNamespace prefixes every metric name
+const metricsNamespace = "claude_control"
+
+// metricsGaugeTimeout bounds the task count query made when a gauge is scraped
+const metricsGaugeTimeout = 5 * time.Second
+
+// MetricsSource reports the current task state sampled by the gauges on each scrape
+type MetricsSource interface {
+	GetTaskCounts(ctx context.Context) (*services.TaskCounts, error)
+	GetActiveDecisions() int
+	GetReceiptStats() services.ReceiptStats
+}
+
+// AdapterQueue reports how many Claude Code CLI calls are waiting for a free adapter
+type AdapterQueue interface {
+	WaitQueueDepth() int
+}
+
+// Ensure MetricsHandler records what TaskService reports
+var _ ports.TaskMetrics = (*MetricsHandler)(nil)
+
+// MetricsHandler records task and decision statistics and serves them in Prometheus text format
+// It uses its own registry rather than the global one so only these metrics are exposed.
+type MetricsHandler struct {
+	registry         *prometheus.Registry
+	registerer       prometheus.Registerer // Registers into registry, labelling every series with the instance ID
+	webhooks         *prometheus.CounterVec
+	decisions        *prometheus.CounterVec
+	decisionTimeouts prometheus.Counter
+	decisionDuration *prometheus.HistogramVec
+	sessions         prometheus.Gauge
+}
+
+// NewMetricsHandler creates the metrics, sampling pending tasks and decision channels from source
+// Every series carries an instance_id label so replicas scraped into one Prometheus can be told apart.
+func NewMetricsHandler(source MetricsSource, instanceID string) *MetricsHandler {
+	registry := prometheus.NewRegistry()
+	h := &MetricsHandler{
+		registry:   registry,
+		registerer: prometheus.WrapRegistererWith(prometheus.Labels{"instance_id": instanceID}, registry),
+		webhooks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "webhooks_total",
+			Help:      "Hooks received that created a task.",
+		}, []string{"hook_type"}),
+		decisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "decisions_total",
+			Help:      "User actions taken on tasks.",
+		}, []string{"hook_type", "action"}),
+		decisionTimeouts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "decision_timeouts_total",
+			Help:      "Blocking hooks that gave up waiting for a decision.",
+		}),
+		decisionDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "decision_duration_seconds",
+			Help:      "How long blocking hooks waited for the user's decision.",
+			// Decisions take from seconds to the 5 minute blocking timeout
+			Buckets: []float64{1, 5, 10, 30, 60, 120, 180, 240, 300},
+		}, []string{"hook_type"}),
+		sessions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "concurrent_sessions",
+			Help:      "Sessions with a blocking hook waiting on the user.",
+		}),
+	}
+
+	h.registerer.MustRegister(
+		h.webhooks,
+		h.decisions,
+		h.decisionTimeouts,
+		h.decisionDuration,
+		h.sessions,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "pending_tasks",
+			Help:      "Tasks waiting for a user decision.",
+		}, func() float64 {
+			return pendingTaskCount(source)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "active_decision_channels",
+			Help:      "Blocking hooks currently waiting on a decision channel.",
+		}, func() float64 {
+			return float64(source.GetActiveDecisions())
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "receipts_total",
+			Help:      "Hook responses Claude Code acknowledged with a receipt.",
+		}, func() float64 {
+			return float64(source.GetReceiptStats().ReceiptsTotal)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "unacknowledged_total",
+			Help:      "Hook responses whose receipt was not acknowledged in time.",
+		}, func() float64 {
+			return float64(source.GetReceiptStats().UnacknowledgedTotal)
+		}),
+	)
+
+	return h
+}
+
+// RegisterRoutes registers the metrics endpoint with the router
+// Use a router of its own, served on the metrics port, so the endpoint isn't exposed with the dashboard.
+func (h *MetricsHandler) RegisterRoutes(router *mux.Router) {
+	router.Handle("/metrics", promhttp.HandlerFor(h.registry, promhttp.HandlerOpts{})).Methods("GET")
+}
+
+// WatchClaudeAdapterQueue samples the Claude Code adapter pool's wait queue on each scrape
+func (h *MetricsHandler) WatchClaudeAdapterQueue(queue AdapterQueue) {
+	h.registerer.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "claude_adapter_queue_depth",
+		Help:      "Claude Code CLI calls waiting for a free adapter.",
+	}, func() float64 {
+		return float64(queue.WaitQueueDepth())
+	}))
+}
+
+// WebhookReceived counts a hook that created a task
+func (h *MetricsHandler) WebhookReceived(hookType domain.HookType) {
+	h.webhooks.WithLabelValues(hookType.String()).Inc()
+}
+
+// DecisionMade counts a user action on a task
+func (h *MetricsHandler) DecisionMade(hookType domain.HookType, action domain.ActionType) {
+	h.decisions.WithLabelValues(hookType.String(), string(action)).Inc()
+}
+
+// DecisionWaited records how long a blocking hook waited for the user's decision
+func (h *MetricsHandler) DecisionWaited(hookType domain.HookType, waited time.Duration) {
+	h.decisionDuration.WithLabelValues(hookType.String()).Observe(waited.Seconds())
+}
+
+// DecisionTimedOut counts a blocking hook that gave up waiting for a decision
+func (h *MetricsHandler) DecisionTimedOut(hookType domain.HookType) {
+	h.decisionTimeouts.Inc()
+}
+
+// ConcurrentSessions records how many sessions have a blocking hook waiting on the user
+func (h *MetricsHandler) ConcurrentSessions(count int) {
+	h.sessions.Set(float64(count))
+}
+
+// pendingTaskCount returns the number of pending tasks, or 0 if they can't be counted
+func pendingTaskCount(source MetricsSource) float64 {
+	ctx, cancel := context.WithTimeout(context.Background(), metricsGaugeTimeout)
+	defer cancel()
+
+	counts, err := source.GetTaskCounts(ctx)
+	if err != nil {
+		log.Printf("Warning: failed to count pending tasks for metrics: %v", err)
+		return 0
+	}
+	return float64(counts.Status(domain.TaskStatusPending))
+}
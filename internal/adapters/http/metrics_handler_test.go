@@ -0,0 +1,114 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/services"
+	"github.com/gorilla/mux"
+)
+
+// staticMetricsSource reports fixed task state
+type staticMetricsSource struct {
+	counts          *services.TaskCounts
+	err             error
+	activeDecisions int
+	receipts        services.ReceiptStats
+}
+
+func (s staticMetricsSource) GetTaskCounts(ctx context.Context) (*services.TaskCounts, error) {
+	return s.counts, s.err
+}
+
+func (s staticMetricsSource) GetActiveDecisions() int {
+	return s.activeDecisions
+}
+
+func (s staticMetricsSource) GetReceiptStats() services.ReceiptStats {
+	return s.receipts
+}
+
+// scrapeMetrics returns the metrics endpoint's response body
+func scrapeMetrics(t *testing.T, handler *MetricsHandler) string {
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	return rec.Body.String()
+}
+
+func TestMetricsHandler(t *testing.T) {
+	handler := NewMetricsHandler(staticMetricsSource{
+		counts:          &services.TaskCounts{ByStatus: map[domain.TaskStatus]int{domain.TaskStatusPending: 3}},
+		activeDecisions: 2,
+		receipts:        services.ReceiptStats{ReceiptsTotal: 5, UnacknowledgedTotal: 1},
+	}, "test")
+
+	handler.WebhookReceived(domain.HookTypePreToolUse)
+	handler.WebhookReceived(domain.HookTypePreToolUse)
+	handler.WebhookReceived(domain.HookTypeStop)
+	handler.DecisionMade(domain.HookTypePreToolUse, domain.ActionTypeApprove)
+	handler.DecisionWaited(domain.HookTypePreToolUse, 7*time.Second)
+	handler.DecisionTimedOut(domain.HookTypePreToolUse)
+	handler.ConcurrentSessions(3)
+
+	body := scrapeMetrics(t, handler)
+	expected := []string{
+		`claude_control_webhooks_total{hook_type="PreToolUse",instance_id="test"} 2`,
+		`claude_control_webhooks_total{hook_type="Stop",instance_id="test"} 1`,
+		`claude_control_decisions_total{action="approve",hook_type="PreToolUse",instance_id="test"} 1`,
+		`claude_control_decision_timeouts_total{instance_id="test"} 1`,
+		`claude_control_decision_duration_seconds_bucket{hook_type="PreToolUse",instance_id="test",le="5"} 0`,
+		`claude_control_decision_duration_seconds_bucket{hook_type="PreToolUse",instance_id="test",le="10"} 1`,
+		`claude_control_decision_duration_seconds_sum{hook_type="PreToolUse",instance_id="test"} 7`,
+		`claude_control_pending_tasks{instance_id="test"} 3`,
+		`claude_control_active_decision_channels{instance_id="test"} 2`,
+		`claude_control_concurrent_sessions{instance_id="test"} 3`,
+		`# TYPE claude_control_receipts_total counter`,
+		`claude_control_receipts_total{instance_id="test"} 5`,
+		`# TYPE claude_control_unacknowledged_total gauge`,
+		`claude_control_unacknowledged_total{instance_id="test"} 1`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("Expected metrics to contain %q", line)
+		}
+	}
+}
+
+func TestMetricsHandler_PendingTasksUnavailable(t *testing.T) {
+	handler := NewMetricsHandler(staticMetricsSource{err: errors.New("database unavailable")}, "test")
+
+	if body := scrapeMetrics(t, handler); !strings.Contains(body, `claude_control_pending_tasks{instance_id="test"} 0`+"\n") {
+		t.Errorf("Expected pending tasks to read 0 when counts fail, got:\n%s", body)
+	}
+}
+
+// queueDepth reports a fixed wait queue depth
+type queueDepth int
+
+func (d queueDepth) WaitQueueDepth() int {
+	return int(d)
+}
+
+func TestMetricsHandler_ClaudeAdapterQueue(t *testing.T) {
+	handler := NewMetricsHandler(staticMetricsSource{counts: &services.TaskCounts{}}, "test")
+	if body := scrapeMetrics(t, handler); strings.Contains(body, "claude_control_claude_adapter_queue_depth") {
+		t.Error("Expected no queue depth gauge before a pool is watched")
+	}
+
+	handler.WatchClaudeAdapterQueue(queueDepth(4))
+	if body := scrapeMetrics(t, handler); !strings.Contains(body, `claude_control_claude_adapter_queue_depth{instance_id="test"} 4`+"\n") {
+		t.Errorf("Expected the pool's queue depth, got:\n%s", body)
+	}
+}
@@ -0,0 +1,649 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"golang.org/x/time/rate"
+)
+
+// WebhookSignatureHeader carries the HMAC-SHA256 signature of a webhook request body
+const WebhookSignatureHeader = "X-Claude-Signature"
+
+// webhookSignaturePrefix names the hash algorithm in the signature header
+const webhookSignaturePrefix = "sha256="
+
+// GenerateWebhookSignature returns the X-Claude-Signature header value for body, sha256=<hex>
+func GenerateWebhookSignature(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// HMACVerificationMiddleware rejects requests whose X-Claude-Signature header isn't the HMAC-SHA256
+// of the body under secret. An empty secret turns verification off.
+func HMACVerificationMiddleware(secret []byte) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		if len(secret) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					respondWithPayloadTooLarge(w, maxBytesErr.Limit)
+					return
+				}
+				respondWithSignatureError(w, http.StatusBadRequest, "Failed to read request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			if !validWebhookSignature(secret, body, r.Header.Get(WebhookSignatureHeader)) {
+				respondWithSignatureError(w, http.StatusUnauthorized, "Invalid webhook signature")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// validWebhookSignature compares the header's signature with the body's in constant time
+func validWebhookSignature(secret, body []byte, header string) bool {
+	if !strings.HasPrefix(header, webhookSignaturePrefix) {
+		return false
+	}
+	signature, err := hex.DecodeString(strings.TrimPrefix(header, webhookSignaturePrefix))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(signature, mac.Sum(nil))
+}
+
+// respondWithSignatureError sends a JSON error for a request that failed verification
+func respondWithSignatureError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write([]byte(`{"success":false,"error":"` + message + `"}`))
+}
+
+// ApprovalTokenMiddleware only lets requests through whose ?token= was signed with secret for the
+// route's task and ?action=, answering 401 otherwise. It guards the quick action route notification
+// buttons call without a login or API key. Unlike webhook signatures, an empty secret rejects every request.
+func ApprovalTokenMiddleware(secret string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			taskID, err := uuid.Parse(mux.Vars(r)["taskId"])
+			if err != nil {
+				respondWithSignatureError(w, http.StatusBadRequest, "Invalid task ID")
+				return
+			}
+
+			query := r.URL.Query()
+			err = domain.VerifyApprovalToken(query.Get("token"), taskID, domain.ActionType(query.Get("action")), secret)
+			if errors.Is(err, domain.ErrApprovalTokenExpired) {
+				respondWithSignatureError(w, http.StatusUnauthorized, "Approval token expired")
+				return
+			}
+			if err != nil {
+				respondWithSignatureError(w, http.StatusUnauthorized, "Invalid approval token")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isQuickActionRequest returns true for a POST to a task's action route carrying an approval token
+// DashboardAuth and APIKeyMiddleware leave these to ApprovalTokenMiddleware.
+func isQuickActionRequest(r *http.Request) bool {
+	if r.Method != http.MethodPost || r.URL.Query().Get("token") == "" {
+		return false
+	}
+	taskID, found := strings.CutPrefix(r.URL.Path, "/api/tasks/")
+	if !found {
+		return false
+	}
+	taskID, found = strings.CutSuffix(taskID, "/action")
+	return found && taskID != "" && !strings.Contains(taskID, "/")
+}
+
+// apiKeyAuthScheme is the Authorization header scheme API keys are sent with
+const apiKeyAuthScheme = "Bearer"
+
+// APIKeyRateLimit throttles each API key APIKeyMiddleware accepts with its own token bucket
+type APIKeyRateLimit struct {
+	PerMinute int    // Requests each key may make per minute, also the burst; 0 turns the limit off
+	AdminKey  string // Requests carrying it in X-Admin-Key are never limited
+}
+
+// APIKeyRateLimitIdleTimeout is how long a key's rate limiter is kept after its last request
+const APIKeyRateLimitIdleTimeout = 10 * time.Minute
+
+// APIKeyMiddleware requires an "Authorization: Bearer <key>" header carrying one of keys on /api/* and /task/* routes
+// Webhooks (signed with HMAC instead), health checks, the dashboard and quick actions (signed with an approval
+// token) are not checked, and requests DashboardAuth let in with a session cookie pass without a key. Keys are compared in constant time; with no keys configured
+// only those logged-in requests get through. With limit.PerMinute set, each key gets X-RateLimit-* headers and
+// a 429 with Retry-After once it runs out; a goroutine drops the limiters of keys idle for APIKeyRateLimitIdleTimeout.
+func APIKeyMiddleware(keys []string, limit APIKeyRateLimit) mux.MiddlewareFunc {
+	var limiters *sync.Map // API key to *clientLimiter
+	if limit.PerMinute > 0 {
+		limiters = &sync.Map{}
+		go evictIdleLimiters(limiters, APIKeyRateLimitIdleTimeout)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isAPIKeyProtected(r.URL.Path) || hasVerifiedDashboardSession(r.Context()) || isQuickActionRequest(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token := bearerToken(r)
+			if !validAPIKey(keys, token) {
+				w.Header().Set("WWW-Authenticate", apiKeyAuthScheme)
+				respondWithSignatureError(w, http.StatusUnauthorized, "Invalid or missing API key")
+				return
+			}
+			if limiters != nil && !isAdminRequest(r, limit.AdminKey) && !allowAPIKeyRequest(w, limiters, token, limit.PerMinute) {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isAdminRequest returns true for a request carrying adminKey in the X-Admin-Key header
+func isAdminRequest(r *http.Request, adminKey string) bool {
+	return adminKey != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(AdminKeyHeader)), []byte(adminKey)) == 1
+}
+
+// allowAPIKeyRequest takes a token from key's bucket and sets the X-RateLimit-* headers, answering 429 and
+// returning false when the bucket is empty
+func allowAPIKeyRequest(w http.ResponseWriter, limiters *sync.Map, key string, perMinute int) bool {
+	value, ok := limiters.Load(key)
+	if !ok {
+		value, _ = limiters.LoadOrStore(key, &clientLimiter{limiter: rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), perMinute)})
+	}
+	client := value.(*clientLimiter)
+	now := time.Now()
+	client.lastSeen.Store(now.UnixNano())
+
+	reservation := client.limiter.ReserveN(now, 1)
+	delay := reservation.DelayFrom(now)
+	if delay > 0 {
+		reservation.CancelAt(now)
+	}
+
+	// Remaining is what is left after this request; Reset is how long until the bucket is full again
+	tokens := client.limiter.TokensAt(now)
+	header := w.Header()
+	header.Set("X-RateLimit-Limit", strconv.Itoa(perMinute))
+	header.Set("X-RateLimit-Remaining", strconv.Itoa(int(math.Max(0, math.Floor(tokens)))))
+	header.Set("X-RateLimit-Reset", strconv.Itoa(int(math.Ceil((float64(perMinute)-tokens)*60/float64(perMinute)))))
+
+	if delay > 0 {
+		header.Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+		header.Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"success":false,"error":"Too many requests"}`))
+		return false
+	}
+	return true
+}
+
+// isAPIKeyProtected returns true for the routes APIKeyMiddleware checks
+func isAPIKeyProtected(path string) bool {
+	return strings.HasPrefix(path, "/api/") || strings.HasPrefix(path, "/task/")
+}
+
+// bearerToken returns the token of a Bearer Authorization header, or "" if there is none
+func bearerToken(r *http.Request) string {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, apiKeyAuthScheme) {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// validAPIKey compares token against every key without stopping at a match, so timing reveals nothing
+func validAPIKey(keys []string, token string) bool {
+	if token == "" {
+		return false
+	}
+	valid := 0
+	for _, key := range keys {
+		valid |= subtle.ConstantTimeCompare([]byte(token), []byte(key))
+	}
+	return valid == 1
+}
+
+// dashboardSessionContextKey marks a request DashboardAuth let in with a valid session cookie
+type dashboardSessionContextKey struct{}
+
+// withVerifiedDashboardSession records that the request's session cookie was checked and is valid
+func withVerifiedDashboardSession(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dashboardSessionContextKey{}, true)
+}
+
+// hasVerifiedDashboardSession reports whether DashboardAuth let the request in with a session cookie
+func hasVerifiedDashboardSession(ctx context.Context) bool {
+	verified, _ := ctx.Value(dashboardSessionContextKey{}).(bool)
+	return verified
+}
+
+// Default webhook rate limit per client IP
+const (
+	DefaultRateLimitRPS   = 100
+	DefaultRateLimitBurst = 20
+)
+
+// RateLimitIdleTimeout is how long a client's rate limiter is kept after its last request
+const RateLimitIdleTimeout = 5 * time.Minute
+
+// clientLimiter is one client's token bucket
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen atomic.Int64 // Unix nanoseconds
+}
+
+// RateLimitMiddleware answers 429 with a Retry-After header once a client IP sends more than rps requests
+// per second, allowing bursts of up to burst. Clients are told apart by the connection's remote address;
+// forwarded headers are ignored because any client can set them, so behind a reverse proxy every request
+// shares the proxy's limit. A goroutine drops the limiters of clients idle for RateLimitIdleTimeout.
+func RateLimitMiddleware(rps float64, burst int) mux.MiddlewareFunc {
+	var limiters sync.Map // Client IP to *clientLimiter
+	go evictIdleLimiters(&limiters, RateLimitIdleTimeout)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := clientIP(r)
+			value, ok := limiters.Load(ip)
+			if !ok {
+				value, _ = limiters.LoadOrStore(ip, &clientLimiter{limiter: rate.NewLimiter(rate.Limit(rps), burst)})
+			}
+			client := value.(*clientLimiter)
+			client.lastSeen.Store(time.Now().UnixNano())
+
+			reservation := client.limiter.Reserve()
+			if delay := reservation.Delay(); delay > 0 {
+				reservation.Cancel()
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(`{"success":false,"error":"Too many requests"}`))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// evictIdleLimiters periodically removes limiters that haven't been used for idleTimeout
+func evictIdleLimiters(limiters *sync.Map, idleTimeout time.Duration) {
+	ticker := time.NewTicker(idleTimeout)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		evictLimitersIdleSince(limiters, time.Now().Add(-idleTimeout))
+	}
+}
+
+// evictLimitersIdleSince removes limiters last used before cutoff
+func evictLimitersIdleSince(limiters *sync.Map, cutoff time.Time) {
+	limiters.Range(func(key, value interface{}) bool {
+		if value.(*clientLimiter).lastSeen.Load() < cutoff.UnixNano() {
+			limiters.Delete(key)
+		}
+		return true
+	})
+}
+
+// clientIP returns the IP address of the connection a request came in on
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ForwardedByHeader marks a webhook copy sent on by ForwardingMiddleware, so the receiver can tell it apart
+const ForwardedByHeader = "X-Forwarded-By"
+
+// forwardedByValue is the ForwardedByHeader value on forwarded webhooks
+const forwardedByValue = "claude-control"
+
+// ForwardTimeout bounds each forwarded webhook, so a slow secondary URL can't pile up requests
+const ForwardTimeout = 5 * time.Second
+
+// ForwardingMiddleware POSTs a copy of each request to forwardURL with the original headers plus X-Forwarded-By
+// The body is teed as the handler reads it and sent in the background once fully read; failures are only logged.
+func ForwardingMiddleware(forwardURL string) mux.MiddlewareFunc {
+	client := &http.Client{Timeout: ForwardTimeout}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := r.Header.Clone()
+			path := r.URL.Path
+			body := &forwardingBody{Closer: r.Body}
+			body.Reader = io.TeeReader(r.Body, &body.copy)
+			body.send = func() {
+				go forwardWebhook(client, forwardURL, path, header, body.copy.Bytes())
+			}
+			r.Body = body
+
+			next.ServeHTTP(w, r)
+
+			// Handlers like json.Decoder stop at the end of the value without reading EOF
+			io.Copy(io.Discard, body)
+		})
+	}
+}
+
+// forwardingBody tees a request body into copy and calls send once, when it reaches EOF
+type forwardingBody struct {
+	io.Reader
+	io.Closer
+	copy bytes.Buffer
+	send func()
+	once sync.Once
+}
+
+// Read reads from the teed body and sends the copy on at EOF
+func (b *forwardingBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if err == io.EOF {
+		b.once.Do(b.send)
+	}
+	return n, err
+}
+
+// forwardWebhook POSTs a webhook body to forwardURL, logging rather than returning any failure
+func forwardWebhook(client *http.Client, forwardURL, path string, header http.Header, body []byte) {
+	req, err := http.NewRequest(http.MethodPost, forwardURL, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("Warning: failed to forward webhook %s: %v", path, err)
+		return
+	}
+	req.Header = header
+	req.Header.Set(ForwardedByHeader, forwardedByValue)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Printf("Warning: failed to forward webhook %s: %v", path, err)
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		log.Printf("Warning: forwarded webhook %s got status %d from %s", path, resp.StatusCode, forwardURL)
+	}
+}
+
+// DefaultCORSMaxAge is how long browsers may cache a preflight response
+const DefaultCORSMaxAge = 10 * time.Minute
+
+const (
+	// corsAllowedMethods are the methods cross-origin callers may use
+	corsAllowedMethods = "GET, POST, OPTIONS"
+
+	// corsAllowedHeaders are the request headers cross-origin callers may send
+	corsAllowedHeaders = "Content-Type, Authorization, Last-Event-ID, " + WebhookSignatureHeader
+
+	// corsExposedHeaders are the response headers cross-origin callers may read
+	corsExposedHeaders = "X-Request-ID, X-Instance-ID"
+)
+
+// CORSMiddleware lets browser pages from allowedOrigins call the server, answering preflight requests itself
+// A "*" entry allows any origin. Credentials are never allowed, so cross-origin calls don't carry the
+// dashboard login cookie. With no origins configured the middleware does nothing.
+// It must wrap the router rather than be added with Use, because mux only runs middleware for matched
+// routes and most routes don't accept OPTIONS.
+func CORSMiddleware(allowedOrigins []string, maxAge time.Duration) mux.MiddlewareFunc {
+	allowAny := false
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			allowAny = true
+		}
+		allowed[normalizeOrigin(origin)] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		if len(allowed) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := w.Header()
+			header.Add("Vary", "Origin")
+			switch {
+			case allowAny:
+				header.Set("Access-Control-Allow-Origin", "*")
+			case allowed[normalizeOrigin(origin)]:
+				header.Set("Access-Control-Allow-Origin", origin)
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				header.Set("Access-Control-Allow-Methods", corsAllowedMethods)
+				header.Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+				header.Set("Access-Control-Max-Age", strconv.Itoa(int(maxAge.Seconds())))
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			header.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// normalizeOrigin makes configured and requested origins comparable: lower case, no trailing slash
+func normalizeOrigin(origin string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+}
+
+// maxLoggedBodyBytes is how much of a webhook body LoggingMiddleware keeps to find the session ID
+// Claude Code sends session_id first, so large tool output further on doesn't matter.
+const maxLoggedBodyBytes = 64 * 1024
+
+// requestLogEntry is one line of the request log
+type requestLogEntry struct {
+	Time       string `json:"time"`
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Status     int    `json:"status"`
+	DurationMS int64  `json:"duration_ms"`
+	RequestID  string `json:"request_id"`
+	InstanceID string `json:"instance_id"`
+	SessionID  string `json:"session_id,omitempty"`
+}
+
+// LoggingMiddleware writes a JSON line to out for every request once it completes, with the request ID
+// set by RequestIDMiddleware, the instance ID and, for webhooks, the Claude Code session ID read from the body
+// as the handler consumes it
+func LoggingMiddleware(out io.Writer, instanceID string) mux.MiddlewareFunc {
+	var mutex sync.Mutex
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			var body *limitedBuffer
+			if r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/webhook/") && r.Body != nil {
+				body = &limitedBuffer{limit: maxLoggedBodyBytes}
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.TeeReader(r.Body, body), r.Body}
+			}
+
+			recorder := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+
+			entry := requestLogEntry{
+				Time:       start.UTC().Format(time.RFC3339Nano),
+				Method:     r.Method,
+				Path:       r.URL.Path,
+				Status:     recorder.statusCode(),
+				DurationMS: time.Since(start).Milliseconds(),
+				RequestID:  RequestIDFromContext(r.Context()),
+				InstanceID: instanceID,
+			}
+			if body != nil {
+				entry.SessionID = sessionIDFromBody(body.Bytes())
+			}
+
+			line, err := json.Marshal(entry)
+			if err != nil {
+				return
+			}
+			mutex.Lock()
+			out.Write(append(line, '\n'))
+			mutex.Unlock()
+		})
+	}
+}
+
+// sessionIDFromBody returns the top-level session_id of a JSON object, or "" if there is none
+// The body may be cut short, so it is scanned token by token and only needs to be valid up to session_id.
+func sessionIDFromBody(body []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return ""
+	}
+
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		if key == "session_id" {
+			var sessionID string
+			if err := decoder.Decode(&sessionID); err != nil {
+				return ""
+			}
+			return sessionID
+		}
+
+		var skipped json.RawMessage
+		if err := decoder.Decode(&skipped); err != nil {
+			return ""
+		}
+	}
+	return ""
+}
+
+// limitedBuffer keeps the first limit bytes written to it and quietly drops the rest
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+// Write stores what fits under the limit but always reports the whole write, so a TeeReader never fails
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		if len(p) > room {
+			b.Buffer.Write(p[:room])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// statusRecorder remembers the status code a handler sent
+// It unwraps for http.ResponseController and passes flushes and hijacks through, so event streams and
+// WebSockets keep working behind it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code and sends it
+func (r *statusRecorder) WriteHeader(statusCode int) {
+	if r.status == 0 {
+		r.status = statusCode
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write sends body bytes, implying 200 OK if no status was set
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+// Flush sends buffered data to the client
+func (r *statusRecorder) Flush() {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+// Hijack hands the connection to the handler, as WebSocket upgrades do
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err == nil && r.status == 0 {
+		r.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// statusCode returns the recorded status, 200 if the handler wrote nothing
+func (r *statusRecorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
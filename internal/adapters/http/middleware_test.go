@@ -0,0 +1,589 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+func TestGenerateWebhookSignature(t *testing.T) {
+	// Known HMAC-SHA256 of "The quick brown fox jumps over the lazy dog" keyed with "key"
+	expected := "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	if got := GenerateWebhookSignature([]byte("key"), []byte("The quick brown fox jumps over the lazy dog")); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestHMACVerificationMiddleware(t *testing.T) {
+	secret := []byte("webhook-secret")
+	body := `{"session_id": "abc123", "hook_event_name": "Stop"}`
+
+	tests := []struct {
+		name           string
+		secret         []byte
+		signature      string
+		expectedStatus int
+	}{
+		{"Valid signature", secret, GenerateWebhookSignature(secret, []byte(body)), http.StatusOK},
+		{"Missing signature", secret, "", http.StatusUnauthorized},
+		{"Wrong secret", secret, GenerateWebhookSignature([]byte("guess"), []byte(body)), http.StatusUnauthorized},
+		{"Signature of another body", secret, GenerateWebhookSignature(secret, []byte(`{}`)), http.StatusUnauthorized},
+		{"Missing prefix", secret, strings.TrimPrefix(GenerateWebhookSignature(secret, []byte(body)), "sha256="), http.StatusUnauthorized},
+		{"Not hex", secret, "sha256=not-hex", http.StatusUnauthorized},
+		{"Verification disabled", nil, "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received string
+			router := mux.NewRouter()
+			router.Use(HMACVerificationMiddleware(tt.secret))
+			router.HandleFunc("/webhook/Stop", func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				received = string(data)
+			}).Methods("POST")
+
+			req := httptest.NewRequest(http.MethodPost, "/webhook/Stop", strings.NewReader(body))
+			if tt.signature != "" {
+				req.Header.Set(WebhookSignatureHeader, tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus == http.StatusOK && received != body {
+				t.Errorf("Expected the handler to read the original body, got %q", received)
+			}
+		})
+	}
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	keys := []string{"first-key", "second-key"}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		authorization  string
+		expectedStatus int
+	}{
+		{"Verify with a valid key", http.MethodPost, "/api/auth/verify", "Bearer first-key", http.StatusOK},
+		{"Any configured key works", http.MethodGet, "/api/tasks", "Bearer second-key", http.StatusOK},
+		{"Scheme is case-insensitive", http.MethodGet, "/api/tasks", "bearer first-key", http.StatusOK},
+		{"Task page with a key", http.MethodGet, "/task/123", "Bearer first-key", http.StatusOK},
+		{"Verify without a key", http.MethodPost, "/api/auth/verify", "", http.StatusUnauthorized},
+		{"Unknown key", http.MethodGet, "/api/tasks", "Bearer guess", http.StatusUnauthorized},
+		{"Key prefix", http.MethodGet, "/api/tasks", "Bearer first", http.StatusUnauthorized},
+		{"Basic auth", http.MethodGet, "/api/tasks", "Basic Zmlyc3Qta2V5", http.StatusUnauthorized},
+		{"Task page without a key", http.MethodGet, "/task/123", "", http.StatusUnauthorized},
+		{"Webhooks are exempt", http.MethodPost, "/webhook/PreToolUse", "", http.StatusOK},
+		{"Health check is exempt", http.MethodGet, "/health", "", http.StatusOK},
+		{"Dashboard is exempt", http.MethodGet, "/", "", http.StatusOK},
+	}
+
+	router := mux.NewRouter()
+	router.Use(APIKeyMiddleware(keys, APIKeyRateLimit{}))
+	router.HandleFunc("/api/auth/verify", (&WebHandler{}).handleVerifyAPIKey).Methods("POST")
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	for _, path := range []string{"/api/tasks", "/task/{taskId}", "/webhook/{hookType}", "/health", "/"} {
+		router.HandleFunc(path, ok)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Errorf("Expected a Bearer challenge, got %q", rec.Header().Get("WWW-Authenticate"))
+			}
+			if tt.path == "/api/auth/verify" && tt.expectedStatus == http.StatusOK && !strings.Contains(rec.Body.String(), `"valid":true`) {
+				t.Errorf("Expected the verify endpoint to report the key valid, got %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAPIKeyMiddleware_RateLimit(t *testing.T) {
+	handler := APIKeyMiddleware([]string{"script-key", "phone-key"}, APIKeyRateLimit{PerMinute: 3, AdminKey: "admin-key"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(key string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+		req.Header.Set("Authorization", "Bearer "+key)
+		for name, value := range header {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// A key may make its per-minute allowance at once, counting down as it goes
+	for i, remaining := range []string{"2", "1", "0"} {
+		rec := send("script-key", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Request %d: expected status 200 within the limit, got %d", i+1, rec.Code)
+		}
+		if rec.Header().Get("X-RateLimit-Limit") != "3" || rec.Header().Get("X-RateLimit-Remaining") != remaining {
+			t.Errorf("Request %d: expected limit 3 with %s remaining, got %q and %q", i+1, remaining,
+				rec.Header().Get("X-RateLimit-Limit"), rec.Header().Get("X-RateLimit-Remaining"))
+		}
+	}
+
+	// Then it waits for the next token, one every 20 seconds, and the full bucket a minute from now
+	rec := send("script-key", nil)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429 once the limit is used up, got %d", rec.Code)
+	}
+	expectedHeaders := map[string]string{"Retry-After": "20", "X-RateLimit-Limit": "3", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "60"}
+	for name, expected := range expectedHeaders {
+		if got := rec.Header().Get(name); got != expected {
+			t.Errorf("Expected %s %s, got %q", name, expected, got)
+		}
+	}
+
+	// Other keys have their own bucket, and admin requests aren't limited at all
+	if rec := send("phone-key", nil); rec.Code != http.StatusOK {
+		t.Errorf("Expected another key to be allowed, got %d", rec.Code)
+	}
+	for i := 0; i < 5; i++ {
+		rec := send("script-key", map[string]string{AdminKeyHeader: "admin-key"})
+		if rec.Code != http.StatusOK || rec.Header().Get("X-RateLimit-Limit") != "" {
+			t.Fatalf("Admin request %d: expected an unlimited 200, got %d with limit %q", i+1, rec.Code, rec.Header().Get("X-RateLimit-Limit"))
+		}
+	}
+	if rec := send("script-key", map[string]string{AdminKeyHeader: "guess"}); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected a wrong admin key to stay limited, got %d", rec.Code)
+	}
+}
+
+func TestApprovalTokenMiddleware(t *testing.T) {
+	secret := "quick-action-secret"
+	taskID := uuid.New()
+	approveToken := domain.GenerateApprovalToken(taskID, domain.ActionTypeApprove, secret, time.Minute)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedRoute  string
+	}{
+		{"Signed approval needs no key", "/api/tasks/" + taskID.String() + "/action?action=approve&token=" + approveToken, http.StatusOK, "quick"},
+		{"Signed reject", "/api/tasks/" + taskID.String() + "/action?action=reject&token=" + domain.GenerateApprovalToken(taskID, domain.ActionTypeReject, secret, time.Minute), http.StatusOK, "quick"},
+		{"Token for another action", "/api/tasks/" + taskID.String() + "/action?action=reject&token=" + approveToken, http.StatusUnauthorized, ""},
+		{"Token for another task", "/api/tasks/" + uuid.New().String() + "/action?action=approve&token=" + approveToken, http.StatusUnauthorized, ""},
+		{"Token signed with another secret", "/api/tasks/" + taskID.String() + "/action?action=approve&token=" + domain.GenerateApprovalToken(taskID, domain.ActionTypeApprove, "guess", time.Minute), http.StatusUnauthorized, ""},
+		{"Expired token", "/api/tasks/" + taskID.String() + "/action?action=approve&token=" + domain.GenerateApprovalToken(taskID, domain.ActionTypeApprove, secret, -time.Minute), http.StatusUnauthorized, ""},
+		{"Invalid task ID", "/api/tasks/not-a-task/action?action=approve&token=" + approveToken, http.StatusBadRequest, ""},
+		{"No token still needs a key", "/api/tasks/" + taskID.String() + "/action", http.StatusUnauthorized, ""},
+		{"A token doesn't open other routes", "/api/tasks/bulk-action?token=" + approveToken, http.StatusUnauthorized, ""},
+	}
+
+	router := mux.NewRouter()
+	router.Use(APIKeyMiddleware([]string{"script-key"}, APIKeyRateLimit{}))
+	route := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(name)) }
+	}
+	router.Handle("/api/tasks/{taskId}/action", ApprovalTokenMiddleware(secret)(route("quick"))).Methods("POST").Queries("token", "{token}")
+	router.HandleFunc("/api/tasks/bulk-action", route("bulk")).Methods("POST")
+	router.HandleFunc("/api/tasks/{taskId}/action", route("json")).Methods("POST")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.path, nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedRoute != "" && rec.Body.String() != tt.expectedRoute {
+				t.Errorf("Expected the %s route, got %s", tt.expectedRoute, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWebhookHandler_WebhookSecret(t *testing.T) {
+	secret := "webhook-secret"
+	body := `{"session_id": "abc123", "hook_event_name": "Stop"}`
+
+	tests := []struct {
+		name           string
+		path           string
+		signature      string
+		expectedStatus int
+	}{
+		{"Signed webhook", "/webhook/Stop", GenerateWebhookSignature([]byte(secret), []byte(body)), http.StatusOK},
+		{"Unsigned webhook", "/webhook/Stop", "", http.StatusUnauthorized},
+		{"Unsigned receipt", "/webhook/receipt", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionService := &recordingSessionService{}
+			handler := NewWebhookHandler(sessionService)
+			handler.SetWebhookSecret(secret)
+			router := mux.NewRouter()
+			handler.RegisterRoutes(router)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(body))
+			if tt.signature != "" {
+				req.Header.Set(WebhookSignatureHeader, tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus == http.StatusUnauthorized && len(sessionService.events) != 0 {
+				t.Errorf("Expected no events from a rejected webhook, got %d", len(sessionService.events))
+			}
+		})
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	handler := RateLimitMiddleware(1, 2)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/webhook/Stop", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The burst is allowed, then the next request is refused until a token refills
+	for i := 0; i < 2; i++ {
+		if rec := send("192.0.2.1:5000"); rec.Code != http.StatusOK {
+			t.Fatalf("Request %d: expected status 200 within the burst, got %d", i+1, rec.Code)
+		}
+	}
+	rec := send("192.0.2.1:5001")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429 after the burst, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") != "1" {
+		t.Errorf("Expected Retry-After 1, got %q", rec.Header().Get("Retry-After"))
+	}
+
+	// Other clients have their own limit
+	if rec := send("198.51.100.7:5000"); rec.Code != http.StatusOK {
+		t.Errorf("Expected another client to be allowed, got %d", rec.Code)
+	}
+}
+
+func TestEvictLimitersIdleSince(t *testing.T) {
+	var limiters sync.Map
+	idle, active := &clientLimiter{}, &clientLimiter{}
+	idle.lastSeen.Store(time.Now().Add(-10 * time.Minute).UnixNano())
+	active.lastSeen.Store(time.Now().UnixNano())
+	limiters.Store("192.0.2.1", idle)
+	limiters.Store("192.0.2.2", active)
+
+	evictLimitersIdleSince(&limiters, time.Now().Add(-RateLimitIdleTimeout))
+
+	if _, ok := limiters.Load("192.0.2.1"); ok {
+		t.Error("Expected the idle client's limiter to be evicted")
+	}
+	if _, ok := limiters.Load("192.0.2.2"); !ok {
+		t.Error("Expected the active client's limiter to be kept")
+	}
+}
+
+func TestWebhookHandler_RateLimit(t *testing.T) {
+	sessionService := &recordingSessionService{}
+	handler := NewWebhookHandler(sessionService)
+	handler.SetRateLimit(RateLimitMiddleware(1, 1))
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	codes := []int{}
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/webhook/Stop", strings.NewReader(`{"session_id": "abc123"}`))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		codes = append(codes, rec.Code)
+	}
+
+	if codes[0] != http.StatusOK || codes[1] != http.StatusTooManyRequests {
+		t.Errorf("Expected statuses [200 429], got %v", codes)
+	}
+	if len(sessionService.events) != 1 {
+		t.Errorf("Expected only the allowed webhook to be recorded, got %d events", len(sessionService.events))
+	}
+}
+
+func TestForwardingMiddleware(t *testing.T) {
+	type forwarded struct {
+		header http.Header
+		body   string
+	}
+	received := make(chan forwarded, 1)
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- forwarded{header: r.Header, body: string(body)}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer secondary.Close()
+
+	// The handler decodes one JSON value and never reads EOF, as the webhook handler does
+	handler := ForwardingMiddleware(secondary.URL)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil || data["session_id"] != "abc123" {
+			t.Errorf("Expected the handler to read the original body, got %v (%v)", data, err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	body := `{"session_id": "abc123", "hook_event_name": "Stop"}`
+	req := httptest.NewRequest(http.MethodPost, "/webhook/Stop", strings.NewReader(body))
+	req.Header.Set(WebhookSignatureHeader, "sha256=abc")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected the secondary's failure not to affect the response, got status %d", rec.Code)
+	}
+
+	select {
+	case got := <-received:
+		if got.body != body {
+			t.Errorf("Expected the forwarded body %q, got %q", body, got.body)
+		}
+		if got.header.Get(ForwardedByHeader) != "claude-control" {
+			t.Errorf("Expected %s: claude-control, got %q", ForwardedByHeader, got.header.Get(ForwardedByHeader))
+		}
+		if got.header.Get(WebhookSignatureHeader) != "sha256=abc" {
+			t.Errorf("Expected the original headers to be forwarded, got %v", got.header)
+		}
+	case <-time.After(ForwardTimeout):
+		t.Fatal("Expected the webhook to be forwarded")
+	}
+}
+
+func TestWebhookHandler_Forwarding(t *testing.T) {
+	received := make(chan string, 2)
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- string(body)
+	}))
+	defer secondary.Close()
+
+	sessionService := &recordingSessionService{}
+	handler := NewWebhookHandler(sessionService)
+	handler.SetBodySizeLimits(BodySizeConfig{DefaultMaxBytes: 64})
+	handler.SetForwarding(ForwardingMiddleware(secondary.URL))
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	// An oversized body is refused and not forwarded; the accepted one is
+	for _, body := range []string{`{"session_id": "` + strings.Repeat("x", 100) + `"}`, `{"session_id": "abc123"}`} {
+		req := httptest.NewRequest(http.MethodPost, "/webhook/Stop", strings.NewReader(body))
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	select {
+	case body := <-received:
+		if body != `{"session_id": "abc123"}` {
+			t.Errorf("Expected only the accepted webhook to be forwarded, got %q", body)
+		}
+	case <-time.After(ForwardTimeout):
+		t.Fatal("Expected the accepted webhook to be forwarded")
+	}
+	if len(sessionService.events) != 1 {
+		t.Errorf("Expected one recorded event, got %d", len(sessionService.events))
+	}
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	allowed := []string{"https://ops.example.com/", "http://localhost:3000"}
+
+	tests := []struct {
+		name           string
+		allowedOrigins []string
+		method         string
+		origin         string
+		preflight      bool
+		expectedStatus int
+		expectedOrigin string
+	}{
+		{"Allowed origin", allowed, http.MethodGet, "http://localhost:3000", false, http.StatusOK, "http://localhost:3000"},
+		{"Allowed origin with different case", allowed, http.MethodGet, "https://OPS.example.com", false, http.StatusOK, "https://OPS.example.com"},
+		{"Unlisted origin", allowed, http.MethodGet, "https://evil.example.com", false, http.StatusOK, ""},
+		{"Same-origin request", allowed, http.MethodGet, "", false, http.StatusOK, ""},
+		{"Wildcard", []string{"*"}, http.MethodGet, "https://anywhere.example.com", false, http.StatusOK, "*"},
+		{"Preflight", allowed, http.MethodOptions, "http://localhost:3000", true, http.StatusNoContent, "http://localhost:3000"},
+		{"Preflight from unlisted origin", allowed, http.MethodOptions, "https://evil.example.com", true, http.StatusMethodNotAllowed, ""},
+		{"Disabled", nil, http.MethodGet, "http://localhost:3000", false, http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := mux.NewRouter()
+			router.HandleFunc("/api/tasks", func(w http.ResponseWriter, r *http.Request) {}).Methods("GET", "POST")
+			handler := CORSMiddleware(tt.allowedOrigins, 5*time.Minute)(router)
+
+			req := httptest.NewRequest(tt.method, "/api/tasks", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", "POST")
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.expectedOrigin {
+				t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", tt.expectedOrigin, got)
+			}
+			if rec.Header().Get("Access-Control-Allow-Credentials") != "" {
+				t.Error("Expected credentials never to be allowed")
+			}
+
+			if tt.expectedStatus == http.StatusNoContent {
+				if got := rec.Header().Get("Access-Control-Allow-Methods"); got != "GET, POST, OPTIONS" {
+					t.Errorf("Expected allowed methods GET, POST, OPTIONS, got %q", got)
+				}
+				if got := rec.Header().Get("Access-Control-Max-Age"); got != "300" {
+					t.Errorf("Expected max age 300, got %q", got)
+				}
+				if !strings.Contains(rec.Header().Get("Access-Control-Allow-Headers"), WebhookSignatureHeader) {
+					t.Errorf("Expected %s to be an allowed header", WebhookSignatureHeader)
+				}
+			}
+		})
+	}
+}
+
+func TestSessionIDFromBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"First key", `{"session_id": "abc123", "hook_event_name": "Stop"}`, "abc123"},
+		{"After nested values", `{"tool_input": {"session_id": "nested"}, "tags": [1, 2], "session_id": "abc123"}`, "abc123"},
+		{"Cut short after session_id", `{"session_id": "abc123", "tool_response": {"stdout": "lots of outp`, "abc123"},
+		{"Cut short before session_id", `{"tool_response": {"stdout": "lots of outp`, ""},
+		{"Missing", `{"hook_event_name": "Stop"}`, ""},
+		{"Not a string", `{"session_id": 42}`, ""},
+		{"Not an object", `["session_id", "abc123"]`, ""},
+		{"Empty", ``, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sessionIDFromBody([]byte(tt.body)); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	var logged bytes.Buffer
+	var handlerBody string
+	router := mux.NewRouter()
+	router.Use(RequestIDMiddleware("test"))
+	router.Use(LoggingMiddleware(&logged, "test"))
+	router.HandleFunc("/webhook/{hookType}", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		handlerBody = string(data)
+		w.WriteHeader(http.StatusAccepted)
+	}).Methods("POST")
+	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}).Methods("GET")
+
+	body := `{"session_id": "abc123", "hook_event_name": "Stop"}`
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/webhook/Stop", strings.NewReader(body)))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	if handlerBody != body {
+		t.Errorf("Expected the handler to read the whole body, got %q", handlerBody)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logged.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d: %s", len(lines), logged.String())
+	}
+
+	var webhook, health map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &webhook); err != nil {
+		t.Fatalf("Expected a JSON log line, got %q: %v", lines[0], err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &health); err != nil {
+		t.Fatalf("Expected a JSON log line, got %q: %v", lines[1], err)
+	}
+
+	if webhook["method"] != "POST" || webhook["path"] != "/webhook/Stop" || webhook["status"] != float64(http.StatusAccepted) {
+		t.Errorf("Unexpected webhook log line: %v", webhook)
+	}
+	if webhook["session_id"] != "abc123" {
+		t.Errorf("Expected session_id abc123, got %v", webhook["session_id"])
+	}
+	if webhook["request_id"] != rec.Header().Get(RequestIDHeader) {
+		t.Errorf("Expected request_id %s, got %v", rec.Header().Get(RequestIDHeader), webhook["request_id"])
+	}
+	if webhook["instance_id"] != "test" || health["instance_id"] != "test" {
+		t.Errorf("Expected instance_id test on every line, got %v and %v", webhook["instance_id"], health["instance_id"])
+	}
+	if _, ok := webhook["duration_ms"]; !ok {
+		t.Error("Expected a duration_ms field")
+	}
+	if _, err := time.Parse(time.RFC3339Nano, webhook["time"].(string)); err != nil {
+		t.Errorf("Expected an RFC 3339 time, got %v", webhook["time"])
+	}
+
+	if health["status"] != float64(http.StatusOK) {
+		t.Errorf("Expected an implicit 200 to be logged, got %v", health["status"])
+	}
+	if _, ok := health["session_id"]; ok {
+		t.Errorf("Expected no session_id outside webhooks, got %v", health["session_id"])
+	}
+}
+
+func TestStatusRecorder_KeepsResponseController(t *testing.T) {
+	rec := httptest.NewRecorder()
+	recorder := &statusRecorder{ResponseWriter: rec}
+
+	if err := http.NewResponseController(recorder).Flush(); err != nil {
+		t.Fatalf("Expected flushes to reach the wrapped writer, got %v", err)
+	}
+	if !rec.Flushed {
+		t.Error("Expected the wrapped writer to be flushed")
+	}
+	if recorder.statusCode() != http.StatusOK {
+		t.Errorf("Expected a flush to imply 200, got %d", recorder.statusCode())
+	}
+}
@@ -0,0 +1,507 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dan/claude-control/internal/adapters/claude"
+	"github.com/dan/claude-control/internal/adapters/transcript"
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/dan/claude-control/internal/core/services"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification the served document follows
+const OpenAPIVersion = "3.0.3"
+
+// openAPIPathParam matches a mux path variable, with or without a pattern, e.g. {taskId} or {id:[0-9]+}
+var openAPIPathParam = regexp.MustCompile(`\{([^}:]+)(?::[^}]*)?\}`)
+
+// apiOperationDoc describes what the handler behind one route accepts and returns
+// Request and response shapes are given as sample values whose types are reflected into schemas.
+type apiOperationDoc struct {
+	summary  string
+	query    []string
+	request  interface{}
+	response map[string]interface{} // Fields of the JSON response besides "success"
+	body     interface{}            // The whole response, for handlers that don't wrap it in an object
+	stream   bool                   // The response is a text/event-stream
+	export   bool                   // The response is a task export file, CSV or JSON Lines
+	text     bool                   // The response is plain text
+}
+
+// taskFilterQuery lists the query parameters parseTaskFilter reads
+var taskFilterQuery = []string{"status", "hook_type", "search", "created_after", "created_before", "session_id", "limit", "offset"}
+
+// taskIDsRequest is the body of the bulk task endpoints
+type taskIDsRequest struct {
+	TaskIDs []uuid.UUID `json:"task_ids"`
+}
+
+// apiOperationDocs documents the /api routes by "METHOD /path"; a route missing here is still listed,
+// just without schemas
+var apiOperationDocs = map[string]apiOperationDoc{
+	"GET /api/tasks": {
+		summary:  "List tasks",
+		query:    taskFilterQuery,
+		response: map[string]interface{}{"tasks": []*domain.Task{}, "count": 0, "pagination": domain.Pagination{}},
+	},
+	"GET /api/tasks/archived": {
+		summary:  "List archived tasks",
+		query:    taskFilterQuery,
+		response: map[string]interface{}{"tasks": []*domain.Task{}, "count": 0},
+	},
+	"GET /api/tasks/export": {
+		summary: "Download tasks as CSV or JSON Lines",
+		query:   append([]string{"format", "since"}, taskFilterQuery...),
+		export:  true,
+	},
+	"POST /api/tasks/bulk-action": {
+		summary: "Take the same action on several tasks",
+		request: struct {
+			taskIDsRequest
+			Action   domain.ActionType      `json:"action"`
+			Response map[string]interface{} `json:"response"`
+		}{},
+		response: map[string]interface{}{"action": domain.ActionType(""), "succeeded": []uuid.UUID{}, "failed": []services.BulkActionError{}},
+	},
+	"POST /api/tasks/bulk-delete": {
+		summary:  "Delete several finished tasks",
+		request:  taskIDsRequest{},
+		response: map[string]interface{}{"deleted": []uuid.UUID{}, "failed": []services.BulkActionError{}},
+	},
+	"GET /api/tasks/{taskId}": {
+		summary:  "Get a task with its history",
+		response: map[string]interface{}{"task": &domain.Task{}, "history": []*domain.TaskHistory{}},
+	},
+	"PATCH /api/tasks/{taskId}": {
+		summary: "Set the command to run instead of the original once approved",
+		request: struct {
+			ModifiedCommand string `json:"modified_command"`
+		}{},
+		response: map[string]interface{}{"task_id": uuid.UUID{}, "modified_command": ""},
+	},
+	"DELETE /api/tasks/{taskId}": {
+		summary:  "Delete a finished task",
+		response: map[string]interface{}{"task_id": uuid.UUID{}},
+	},
+	"POST /api/tasks/{taskId}/action": {
+		summary: "Take an action on a task; notification buttons send ?action= and a signed ?token= instead of a body",
+		query:   []string{"action", "token"},
+		request: struct {
+			Action   domain.ActionType      `json:"action"`
+			Comment  string                 `json:"comment"`
+			Response map[string]interface{} `json:"response"`
+		}{},
+		response: map[string]interface{}{"message": ""},
+	},
+	"GET /api/tasks/{taskId}/stream": {
+		summary:  "Wait for a task to change",
+		query:    []string{"timeout"},
+		response: map[string]interface{}{"status": "", "task": &domain.Task{}},
+	},
+	"GET /api/tasks/{taskId}/comments": {
+		summary:  "List the comments left on a task's decisions",
+		response: map[string]interface{}{"task_id": uuid.UUID{}, "comments": []services.TaskComment{}, "count": 0},
+	},
+	"GET /api/tasks/{taskId}/transcript": {
+		summary:  "Get the last messages of the task's session transcript",
+		response: map[string]interface{}{"task_id": uuid.UUID{}, "entries": []transcript.TranscriptEntry{}, "count": 0},
+	},
+	"POST /api/tasks/{taskId}/snooze": {
+		summary:  "Silence a pending task's notifications",
+		query:    []string{"duration"},
+		response: map[string]interface{}{"task_id": uuid.UUID{}, "snoozed_until": time.Time{}},
+	},
+	"DELETE /api/tasks/{taskId}/snooze": {
+		summary:  "Resume a task's notifications",
+		response: map[string]interface{}{"task_id": uuid.UUID{}},
+	},
+	"POST /api/tasks/{taskId}/notify": {
+		summary:  "Re-send a pending task's notification",
+		response: map[string]interface{}{"sent": true, "channel": "", "topic": ""},
+	},
+	"GET /api/tasks/{taskId}/diff": {
+		summary: "Get the unified diff an Edit or Write task would make to the file on disk",
+		text:    true,
+	},
+	"GET /api/tasks/{taskId}/diff/{otherTaskId}": {
+		summary:  "Compare two tasks",
+		response: map[string]interface{}{"task_id": uuid.UUID{}, "other_task_id": uuid.UUID{}, "diff": []domain.FieldDiff{}},
+	},
+	"GET /api/transcripts/{taskId}": {
+		summary:  "Get the transcript backup path of a PreCompact task",
+		response: map[string]interface{}{"task_id": uuid.UUID{}, "backup_path": ""},
+	},
+	"GET /api/sessions": {
+		summary:  "List recent sessions, or with subagent_id get the subagent's parent session",
+		query:    []string{"limit", "subagent_id"},
+		response: map[string]interface{}{"sessions": []sessionView{}, "session": sessionView{}},
+	},
+	"GET /api/sessions/{sessionID}/pending": {
+		summary:  "List a session's pending tasks",
+		response: map[string]interface{}{"session_id": "", "tasks": []*domain.Task{}, "count": 0},
+	},
+	"GET /api/sessions/{sessionID}/summary": {
+		summary:  "Summarise a session's tasks and decision times",
+		response: map[string]interface{}{"summary": ports.SessionSummary{}, "text": ""},
+	},
+	"GET /api/claude/sessions": {
+		summary:  "List running Claude Code sessions",
+		response: map[string]interface{}{"sessions": []claude.ClaudeSession{}, "count": 0},
+	},
+	"GET /api/tmux/sessions/{name}/scrollback": {
+		summary:  "Capture a tmux session's scrollback",
+		query:    []string{"lines"},
+		response: map[string]interface{}{"session": "", "lines": 0, "scrollback": ""},
+	},
+	"DELETE /api/tmux/sessions/{name}": {
+		summary:  "Kill a tmux session",
+		response: map[string]interface{}{"message": ""},
+	},
+	"POST /api/auth/verify": {
+		summary:  "Check that the request's API key is valid",
+		response: map[string]interface{}{"valid": true},
+	},
+	"GET /api/stats": {
+		summary:  "Get task totals and decision durations",
+		query:    []string{"since"},
+		response: map[string]interface{}{"since": time.Time{}, "stats": &ports.TaskStats{}},
+	},
+	"GET /api/stats/tools": {
+		summary:  "Get per-tool call and decision counts",
+		query:    []string{"since"},
+		response: map[string]interface{}{"since": time.Time{}, "tools": []ports.ToolUsageStat{}, "count": 0},
+	},
+	"GET /api/stats/counts": {
+		summary:  "Count tasks by status and hook type",
+		response: map[string]interface{}{"by_status": map[string]int{}, "by_hook_type": map[domain.HookType]int{}},
+	},
+	"GET /api/stats/receipts": {
+		summary:  "Count issued and unacknowledged hook response receipts",
+		response: map[string]interface{}{"receipts": services.ReceiptStats{}},
+	},
+	"GET /api/stats/concurrent-sessions": {
+		summary:  "Count sessions with a pending task",
+		response: map[string]interface{}{"claude_control_concurrent_sessions": 0, "window": ""},
+	},
+	"GET /api/stats/activity": {
+		summary:  "Count task history events per hour",
+		query:    []string{"since", "format"},
+		response: map[string]interface{}{"since": time.Time{}, "buckets": []ports.HourlyBucket{}, "count": 0},
+	},
+	"GET /api/stats/compact": {
+		summary:  "Count PreCompact hooks by trigger and session",
+		query:    []string{"since"},
+		response: map[string]interface{}{"since": time.Time{}, "compacts": &ports.CompactStats{}},
+	},
+	"GET /api/events": {
+		summary: "Stream task events as server-sent events",
+		stream:  true,
+	},
+	"GET /api/debug/instance": {
+		summary: "Get the instance answering the request",
+		body:    InstanceInfo{},
+	},
+	"POST /api/admin/hooks/disable": {
+		summary:  "Auto-approve every hook until re-enabled",
+		response: map[string]interface{}{"hooks_disabled": true},
+	},
+	"POST /api/admin/hooks/enable": {
+		summary:  "Resume normal hook processing",
+		response: map[string]interface{}{"hooks_disabled": false},
+	},
+}
+
+// OpenAPIHandler serves the API description and a Swagger UI page for it
+type OpenAPIHandler struct {
+	spec []byte
+}
+
+// NewOpenAPIHandler describes every /api route registered on router so far
+// Call it after the other handlers have registered their routes; the spec is built once, at startup, so it
+// always matches the routes of the running binary. basePath is the prefix the routes are served under.
+func NewOpenAPIHandler(router *mux.Router, basePath, version string) (*OpenAPIHandler, error) {
+	spec, err := buildOpenAPISpec(router, basePath, version)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI spec: %w", err)
+	}
+	return &OpenAPIHandler{spec: encoded}, nil
+}
+
+// RegisterRoutes registers the spec and documentation routes with the router
+func (h *OpenAPIHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/openapi.json", h.handleSpec).Methods("GET")
+	router.HandleFunc("/docs", h.handleDocs).Methods("GET")
+}
+
+// handleSpec returns the OpenAPI document
+func (h *OpenAPIHandler) handleSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(h.spec)
+}
+
+// handleDocs renders Swagger UI for the OpenAPI document
+// The Swagger UI assets come from a CDN, so the page needs internet access in the browser.
+func (h *OpenAPIHandler) handleDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, swaggerUIPage)
+}
+
+// swaggerUIPage loads the spec relative to /docs, so it works under any base path
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <title>Claude Control API</title>
+    <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+    <div id="swagger-ui"></div>
+    <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+    <script>
+        SwaggerUIBundle({ url: 'openapi.json', dom_id: '#swagger-ui' });
+    </script>
+</body>
+</html>
+`
+
+// buildOpenAPISpec walks the router's routes and describes those under /api
+func buildOpenAPISpec(router *mux.Router, basePath, version string) (*openapi3.T, error) {
+	spec := &openapi3.T{
+		OpenAPI: OpenAPIVersion,
+		Info:    &openapi3.Info{Title: "Claude Control API", Version: version},
+		Paths:   make(openapi3.Paths),
+	}
+	if basePath != "" {
+		spec.AddServer(&openapi3.Server{URL: basePath})
+	}
+	schemas := newOpenAPISchemas()
+
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		// Path prefixes for subrouters have no methods; their routes are visited separately
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+
+		path := openAPIPathParam.ReplaceAllString(strings.TrimPrefix(template, basePath), "{$1}")
+		if !strings.HasPrefix(path, "/api/") {
+			return nil
+		}
+
+		for _, method := range methods {
+			spec.AddOperation(path, method, schemas.operation(method, path))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk routes: %w", err)
+	}
+
+	spec.Components.Schemas = schemas.components
+	return spec, nil
+}
+
+// openAPISchemas reflects Go types into schemas, collecting named structs as reusable components
+type openAPISchemas struct {
+	components openapi3.Schemas
+}
+
+func newOpenAPISchemas() *openAPISchemas {
+	return &openAPISchemas{components: make(openapi3.Schemas)}
+}
+
+// operation describes one route, using its entry in apiOperationDocs when there is one
+func (s *openAPISchemas) operation(method, path string) *openapi3.Operation {
+	doc := apiOperationDocs[method+" "+path]
+	success := s.responseSchema(doc.response)
+	if doc.body != nil {
+		success = s.schemaFor(reflect.TypeOf(doc.body))
+	}
+
+	op := openapi3.NewOperation()
+	op.OperationID = openAPIOperationID(method, path)
+	op.Summary = doc.summary
+	op.Responses = openapi3.Responses{
+		"200":     {Value: openapi3.NewResponse().WithDescription("Success").WithJSONSchemaRef(success)},
+		"default": {Value: openapi3.NewResponse().WithDescription("Error").WithJSONSchemaRef(s.responseSchema(map[string]interface{}{"error": ""}))},
+	}
+
+	if doc.stream {
+		op.Responses["200"].Value = openapi3.NewResponse().WithDescription("Task events").
+			WithContent(openapi3.NewContentWithSchema(openapi3.NewStringSchema(), []string{"text/event-stream"}))
+	}
+
+	if doc.export {
+		op.Responses["200"].Value = openapi3.NewResponse().WithDescription("Tasks, newest first; JSON exports have one object per line").
+			WithContent(openapi3.Content{
+				"text/csv":         openapi3.NewMediaType().WithSchema(openapi3.NewStringSchema()),
+				"application/json": openapi3.NewMediaType().WithSchemaRef(s.schemaFor(reflect.TypeOf(taskExportRow{}))),
+			})
+	}
+
+	if doc.text {
+		op.Responses["200"].Value = openapi3.NewResponse().WithDescription("Success").
+			WithContent(openapi3.NewContentWithSchema(openapi3.NewStringSchema(), []string{"text/plain"}))
+	}
+
+	for _, match := range openAPIPathParam.FindAllStringSubmatch(path, -1) {
+		schema := openapi3.NewStringSchema()
+		if strings.HasSuffix(strings.ToLower(match[1]), "taskid") {
+			schema = openapi3.NewUUIDSchema()
+		}
+		op.AddParameter(openapi3.NewPathParameter(match[1]).WithSchema(schema))
+	}
+	for _, name := range doc.query {
+		op.AddParameter(openapi3.NewQueryParameter(name).WithSchema(openapi3.NewStringSchema()))
+	}
+
+	if doc.request != nil {
+		op.RequestBody = &openapi3.RequestBodyRef{
+			Value: openapi3.NewRequestBody().WithRequired(true).WithJSONSchemaRef(s.schemaFor(reflect.TypeOf(doc.request))),
+		}
+	}
+	return op
+}
+
+// responseSchema describes a response object with a success flag and the given fields
+func (s *openAPISchemas) responseSchema(fields map[string]interface{}) *openapi3.SchemaRef {
+	schema := openapi3.NewObjectSchema().WithProperty("success", openapi3.NewBoolSchema())
+	for name, sample := range fields {
+		schema.WithPropertyRef(name, s.schemaFor(reflect.TypeOf(sample)))
+	}
+	return schema.NewRef()
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	uuidType     = reflect.TypeOf(uuid.UUID{})
+)
+
+// schemaFor describes how a value of type t encodes as JSON
+func (s *openAPISchemas) schemaFor(t reflect.Type) *openapi3.SchemaRef {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return openapi3.NewDateTimeSchema().NewRef()
+	case durationType:
+		return openapi3.NewInt64Schema().NewRef()
+	case uuidType:
+		return openapi3.NewUUIDSchema().NewRef()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return openapi3.NewBoolSchema().NewRef()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return openapi3.NewIntegerSchema().NewRef()
+	case reflect.Float32, reflect.Float64:
+		return openapi3.NewFloat64Schema().NewRef()
+	case reflect.String:
+		return openapi3.NewStringSchema().NewRef()
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return openapi3.NewBytesSchema().NewRef()
+		}
+		schema := openapi3.NewArraySchema()
+		schema.Items = s.schemaFor(t.Elem())
+		return schema.NewRef()
+	case reflect.Map:
+		schema := openapi3.NewObjectSchema()
+		schema.AdditionalProperties = s.schemaFor(t.Elem())
+		return schema.NewRef()
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t).NewRef()
+		}
+		name := openAPIComponentName(t)
+		if _, ok := s.components[name]; !ok {
+			// Register before filling in so self-referencing types end in a $ref instead of recursing
+			s.components[name] = openapi3.NewSchemaRef("", openapi3.NewSchema())
+			*s.components[name].Value = *s.structSchema(t)
+		}
+		return openapi3.NewSchemaRef("#/components/schemas/"+name, s.components[name].Value)
+	}
+
+	// Interfaces can hold anything
+	return openapi3.NewSchema().NewRef()
+}
+
+// structSchema describes a struct's JSON fields, flattening embedded structs as encoding/json does
+func (s *openAPISchemas) structSchema(t reflect.Type) *openapi3.Schema {
+	schema := openapi3.NewObjectSchema()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			for embeddedName, embedded := range s.structSchema(fieldType).Properties {
+				if _, ok := schema.Properties[embeddedName]; !ok {
+					schema.WithPropertyRef(embeddedName, embedded)
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		schema.WithPropertyRef(name, s.schemaFor(field.Type))
+	}
+	return schema
+}
+
+// openAPIComponentName names a struct's schema after its type, capitalised so unexported views read like the rest
+func openAPIComponentName(t reflect.Type) string {
+	name := []rune(t.Name())
+	name[0] = unicode.ToUpper(name[0])
+	return string(name)
+}
+
+// openAPIOperationID turns a method and path into an identifier such as getApiTasksTaskId
+func openAPIOperationID(method, path string) string {
+	var id strings.Builder
+	id.WriteString(strings.ToLower(method))
+	for _, word := range strings.FieldsFunc(path, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		id.WriteString(string(runes))
+	}
+	return id.String()
+}
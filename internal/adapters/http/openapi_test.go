@@ -0,0 +1,98 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/mux"
+)
+
+// TestOpenAPISpecValid builds the spec from the real route registrations and checks it is a usable document
+func TestOpenAPISpecValid(t *testing.T) {
+	const basePath = "/tools/claude-control"
+
+	rootRouter := mux.NewRouter()
+	router := MountAtBasePath(rootRouter, basePath)
+	(&WebHandler{}).RegisterRoutes(router)
+	NewAdminHandler(nil, "").RegisterRoutes(router)
+	NewInstanceHandler(InstanceInfo{}).RegisterRoutes(router)
+	NewEventStreamHandler(nil).RegisterRoutes(router)
+
+	handler, err := NewOpenAPIHandler(router, basePath, "test")
+	if err != nil {
+		t.Fatalf("Failed to build OpenAPI spec: %v", err)
+	}
+	handler.RegisterRoutes(router)
+
+	rr := httptest.NewRecorder()
+	rootRouter.ServeHTTP(rr, httptest.NewRequest("GET", basePath+"/openapi.json", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rr.Code)
+	}
+
+	// Loading resolves every $ref, so a reference to a missing component fails here
+	spec, err := openapi3.NewLoader().LoadFromData(rr.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	if err := spec.Validate(context.Background()); err != nil {
+		t.Fatalf("Spec is not a valid OpenAPI document: %v", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.0.") {
+		t.Errorf("Expected an OpenAPI 3.0 document, got %q", spec.OpenAPI)
+	}
+	if len(spec.Servers) != 1 || spec.Servers[0].URL != basePath {
+		t.Errorf("Expected the base path as the server URL, got %+v", spec.Servers)
+	}
+
+	for _, expected := range []struct{ path, method string }{
+		{"/api/tasks", "GET"},
+		{"/api/tasks/export", "GET"},
+		{"/api/tasks/{taskId}", "GET"},
+		{"/api/tasks/{taskId}", "DELETE"},
+		{"/api/tasks/{taskId}/action", "POST"},
+		{"/api/admin/hooks/disable", "POST"},
+		{"/api/debug/instance", "GET"},
+	} {
+		if item := spec.Paths.Find(expected.path); item == nil || item.GetOperation(expected.method) == nil {
+			t.Errorf("Expected %s %s in the spec", expected.method, expected.path)
+		}
+	}
+	for path := range spec.Paths {
+		if !strings.HasPrefix(path, "/api/") {
+			t.Errorf("Expected only /api routes, got %s", path)
+		}
+	}
+
+	operationIDs := make(map[string]string)
+	for path, item := range spec.Paths {
+		for method, op := range item.Operations() {
+			if other, ok := operationIDs[op.OperationID]; ok {
+				t.Errorf("%s %s reuses operation ID %s from %s", method, path, op.OperationID, other)
+			}
+			operationIDs[op.OperationID] = method + " " + path
+		}
+	}
+}
+
+func TestOpenAPIHandler_Docs(t *testing.T) {
+	handler, err := NewOpenAPIHandler(mux.NewRouter(), "", "test")
+	if err != nil {
+		t.Fatalf("Failed to build OpenAPI spec: %v", err)
+	}
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/docs", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), "url: 'openapi.json'") {
+		t.Error("Expected Swagger UI to load the spec relative to the docs page")
+	}
+}
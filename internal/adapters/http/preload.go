@@ -0,0 +1,59 @@
+package http
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// DefaultPreloadAssets maps dashboard pages to the static assets they load
+var DefaultPreloadAssets = map[string][]string{
+	"/":          {"/static/app.js"},
+	"/dashboard": {"/static/app.js"},
+}
+
+// PreloadMiddleware advertises the static assets a page needs before the page itself is sent
+// Over HTTP/2 the assets are pushed; otherwise, or if the push fails, Link preload headers are added.
+func PreloadMiddleware(assets map[string][]string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				for _, asset := range assets[r.URL.Path] {
+					preloadAsset(w, r, basePathFromRequest(r)+asset)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// preloadAsset pushes a single asset, falling back to a Link header
+func preloadAsset(w http.ResponseWriter, r *http.Request, asset string) {
+	if pusher, ok := w.(http.Pusher); ok && r.TLS != nil {
+		err := pusher.Push(asset, nil)
+		if err == nil {
+			return
+		}
+		if err != http.ErrNotSupported {
+			log.Printf("Warning: Failed to push %s: %v", asset, err)
+		}
+	}
+
+	w.Header().Add("Link", preloadLink(asset))
+}
+
+// preloadLink formats a Link preload header value for an asset
+func preloadLink(asset string) string {
+	link := fmt.Sprintf("<%s>; rel=preload", asset)
+	switch strings.ToLower(path.Ext(asset)) {
+	case ".js":
+		link += "; as=script"
+	case ".css":
+		link += "; as=style"
+	}
+	return link
+}
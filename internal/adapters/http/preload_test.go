@@ -0,0 +1,52 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// TestPreloadMiddleware verifies Link preload headers are only added to pages with known assets
+func TestPreloadMiddleware(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(PreloadMiddleware(DefaultPreloadAssets))
+	page := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html></html>"))
+	}
+	router.HandleFunc("/dashboard", page).Methods("GET")
+	router.HandleFunc("/api/tasks", page).Methods("GET")
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedLink string
+	}{
+		{
+			name:         "Dashboard preloads app script",
+			path:         "/dashboard",
+			expectedLink: "</static/app.js>; rel=preload; as=script",
+		},
+		{
+			name:         "API route has no preload",
+			path:         "/api/tasks",
+			expectedLink: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+			}
+			if link := rr.Header().Get("Link"); link != tt.expectedLink {
+				t.Errorf("Expected Link header %q, got %q", tt.expectedLink, link)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+// sessionTaskGroup is one session's tasks summarised for the session-grouped dashboard
+type sessionTaskGroup struct {
+	SessionID     string
+	CWD           string
+	TaskCount     int
+	FirstHookType domain.HookType
+	LastHookType  domain.HookType
+	LastActivity  time.Time
+	Tasks         []*domain.Task
+}
+
+// newSessionTaskGroups summarises each session's tasks, most recently active session first
+// Tasks within a group are expected oldest first, as GetTasksGroupedBySession returns them.
+func newSessionTaskGroups(groups map[string][]*domain.Task) []sessionTaskGroup {
+	views := make([]sessionTaskGroup, 0, len(groups))
+	for sessionID, tasks := range groups {
+		if len(tasks) == 0 {
+			continue
+		}
+
+		first, last := tasks[0], tasks[len(tasks)-1]
+		view := sessionTaskGroup{
+			SessionID:     sessionID,
+			TaskCount:     len(tasks),
+			FirstHookType: first.HookType,
+			LastHookType:  last.HookType,
+			LastActivity:  last.CreatedAt,
+			Tasks:         tasks,
+		}
+		// Use the most recent working directory in case the session changed directory
+		for i := len(tasks) - 1; i >= 0 && view.CWD == ""; i-- {
+			if tasks[i].HookData != nil {
+				view.CWD = tasks[i].HookData.GetCWD()
+			}
+		}
+		views = append(views, view)
+	}
+
+	sort.Slice(views, func(i, j int) bool {
+		if !views[i].LastActivity.Equal(views[j].LastActivity) {
+			return views[i].LastActivity.After(views[j].LastActivity)
+		}
+		return views[i].SessionID < views[j].SessionID
+	})
+	return views
+}
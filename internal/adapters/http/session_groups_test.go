@@ -0,0 +1,52 @@
+package http
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+func TestNewSessionTaskGroups(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	newTask := func(hookType domain.HookType, cwd string, createdAt time.Time) *domain.Task {
+		return &domain.Task{
+			HookType:  hookType,
+			CreatedAt: createdAt,
+			HookData:  &domain.HookData{Type: hookType, Data: &domain.StopHookData{BaseHookData: domain.BaseHookData{CWD: cwd}}},
+		}
+	}
+
+	groups := newSessionTaskGroups(map[string][]*domain.Task{
+		"c3e0f54b-5b1a-4c2e-9d7f-0a1b2c3d4e5f": {
+			newTask(domain.HookTypeUserPromptSubmit, "/srv/app", now.Add(-time.Hour)),
+			newTask(domain.HookTypePreToolUse, "/srv/app/web", now.Add(-30*time.Minute)),
+			newTask(domain.HookTypeStop, "", now.Add(-20*time.Minute)),
+		},
+		"9a1d": {
+			newTask(domain.HookTypeNotification, "/home/dev", now.Add(-5*time.Minute)),
+		},
+		"empty": {},
+	})
+
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 groups with tasks, got %d", len(groups))
+	}
+	if groups[0].SessionID != "9a1d" {
+		t.Errorf("Expected the most recently active session first, got %s", groups[0].SessionID)
+	}
+
+	busy := groups[1]
+	if busy.TaskCount != 3 {
+		t.Errorf("Expected 3 tasks, got %d", busy.TaskCount)
+	}
+	if busy.FirstHookType != domain.HookTypeUserPromptSubmit || busy.LastHookType != domain.HookTypeStop {
+		t.Errorf("Expected UserPromptSubmit to Stop, got %s to %s", busy.FirstHookType, busy.LastHookType)
+	}
+	if busy.CWD != "/srv/app/web" {
+		t.Errorf("Expected the most recent known working directory, got %q", busy.CWD)
+	}
+	if !busy.LastActivity.Equal(now.Add(-20 * time.Minute)) {
+		t.Errorf("Expected last activity at the newest task, got %v", busy.LastActivity)
+	}
+}
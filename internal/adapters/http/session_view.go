@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+// DefaultSessionListLimit is how many sessions GET /api/sessions and the dashboard show
+const DefaultSessionListLimit = 50
+
+// MaxSessionListLimit is the most sessions GET /api/sessions returns
+const MaxSessionListLimit = 500
+
+// sessionView is a session with its liveness worked out for display
+type sessionView struct {
+	*domain.Session
+	Active      bool                `json:"active"`
+	State       domain.SessionState `json:"state"`
+	IdleSeconds int64               `json:"idle_seconds"`
+}
+
+// newSessionViews derives each session's liveness and puts active sessions first, most recent first within each group
+func newSessionViews(sessions []*domain.Session) []sessionView {
+	views := make([]sessionView, 0, len(sessions))
+	for _, session := range sessions {
+		views = append(views, sessionView{
+			Session:     session,
+			Active:      session.IsActive(domain.DefaultSessionIdleThreshold),
+			State:       session.Classify(),
+			IdleSeconds: int64(session.IdleSince().Seconds()),
+		})
+	}
+
+	sort.SliceStable(views, func(i, j int) bool {
+		if views[i].Active != views[j].Active {
+			return views[i].Active
+		}
+		return views[i].LastActivityAt.After(views[j].LastActivityAt)
+	})
+	return views
+}
+
+// parseSessionListLimit reads the limit query parameter, defaulting to DefaultSessionListLimit
+func parseSessionListLimit(r *http.Request) int {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		return DefaultSessionListLimit
+	}
+	if limit > MaxSessionListLimit {
+		return MaxSessionListLimit
+	}
+	return limit
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+func TestNewSessionViews(t *testing.T) {
+	now := time.Now()
+	sessions := []*domain.Session{
+		{ID: "idle", LastActivityAt: now.Add(-2 * time.Hour), LastHookType: domain.HookTypePostToolUse},
+		{ID: "active-older", LastActivityAt: now.Add(-10 * time.Minute), LastHookType: domain.HookTypePreToolUse},
+		{ID: "stopped-long-ago", LastActivityAt: now.Add(-3 * time.Hour), LastHookType: domain.HookTypeStop},
+		{ID: "active-newest", LastActivityAt: now.Add(-time.Minute), LastHookType: domain.HookTypeStop},
+	}
+
+	views := newSessionViews(sessions)
+
+	expected := []struct {
+		id     string
+		active bool
+		state  domain.SessionState
+	}{
+		{"active-newest", true, domain.SessionStateStopped},
+		{"active-older", true, domain.SessionStateActive},
+		{"idle", false, domain.SessionStateIdle},
+		{"stopped-long-ago", false, domain.SessionStateStopped},
+	}
+	if len(views) != len(expected) {
+		t.Fatalf("Expected %d views, got %d", len(expected), len(views))
+	}
+	for i, want := range expected {
+		if views[i].ID != want.id || views[i].Active != want.active || views[i].State != want.state {
+			t.Errorf("View %d: expected %s (active %v, %s), got %s (active %v, %s)",
+				i, want.id, want.active, want.state, views[i].ID, views[i].Active, views[i].State)
+		}
+	}
+
+	if idle := views[2].IdleSeconds; idle < 7199 || idle > 7201 {
+		t.Errorf("Expected the idle session to be idle for about 7200s, got %d", idle)
+	}
+}
+
+func TestSessionView_JSON(t *testing.T) {
+	view := newSessionViews([]*domain.Session{{ID: "c3e0f54b", LastActivityAt: time.Now()}})[0]
+
+	data, err := json.Marshal(view)
+	if err != nil {
+		t.Fatalf("Failed to marshal session view: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal session view: %v", err)
+	}
+	if decoded["id"] != "c3e0f54b" || decoded["active"] != true || decoded["state"] != "active" {
+		t.Errorf("Expected session fields alongside the derived ones, got %v", decoded)
+	}
+}
+
+func TestParseSessionListLimit(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected int
+	}{
+		{"", DefaultSessionListLimit},
+		{"limit=10", 10},
+		{"limit=0", DefaultSessionListLimit},
+		{"limit=-5", DefaultSessionListLimit},
+		{"limit=abc", DefaultSessionListLimit},
+		{"limit=100000", MaxSessionListLimit},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/api/sessions?"+tt.query, nil)
+		if got := parseSessionListLimit(r); got != tt.expected {
+			t.Errorf("parseSessionListLimit(%q) = %d, expected %d", tt.query, got, tt.expected)
+		}
+	}
+}
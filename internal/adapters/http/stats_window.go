@@ -0,0 +1,48 @@
+package http
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultStatsWindow is the reporting window used when a tool stats request has no since parameter
+const DefaultStatsWindow = 7 * 24 * time.Hour
+
+// DefaultActivityWindow is the reporting window used when an activity request has no since parameter
+const DefaultActivityWindow = 24 * time.Hour
+
+// DefaultTaskStatsWindow is the reporting window used when a task stats request has no since parameter
+const DefaultTaskStatsWindow = 24 * time.Hour
+
+// MaxStatsWindow is the longest reporting window a stats request may ask for
+const MaxStatsWindow = 365 * 24 * time.Hour
+
+// parseStatsWindow parses a since parameter such as "7d", "12h" or "30m" into a reporting window
+// Go durations have no day unit, so a "d" suffix is handled here. An empty parameter uses defaultWindow.
+func parseStatsWindow(since string, defaultWindow time.Duration) (time.Duration, error) {
+	if since == "" {
+		return defaultWindow, nil
+	}
+
+	var window time.Duration
+	if days, ok := strings.CutSuffix(since, "d"); ok {
+		count, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid since window %q", since)
+		}
+		window = time.Duration(count) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(since)
+		if err != nil {
+			return 0, fmt.Errorf("invalid since window %q", since)
+		}
+		window = parsed
+	}
+
+	if window <= 0 || window > MaxStatsWindow {
+		return 0, fmt.Errorf("since window must be between 0 and %s", MaxStatsWindow)
+	}
+	return window, nil
+}
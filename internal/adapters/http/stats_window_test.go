@@ -0,0 +1,45 @@
+package http
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseStatsWindow verifies day suffixes and Go durations are both accepted
+func TestParseStatsWindow(t *testing.T) {
+	tests := []struct {
+		name      string
+		since     string
+		expected  time.Duration
+		expectErr bool
+	}{
+		{"Empty uses default", "", DefaultStatsWindow, false},
+		{"Days", "7d", 7 * 24 * time.Hour, false},
+		{"Hours", "12h", 12 * time.Hour, false},
+		{"Minutes", "30m", 30 * time.Minute, false},
+		{"Max window", "365d", MaxStatsWindow, false},
+		{"Over max window", "366d", 0, true},
+		{"Zero days", "0d", 0, true},
+		{"Negative duration", "-1h", 0, true},
+		{"Fractional days", "1.5d", 0, true},
+		{"Garbage", "week", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := parseStatsWindow(tt.since, DefaultStatsWindow)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("Expected error for %q, got window %s", tt.since, window)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if window != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, window)
+			}
+		})
+	}
+}
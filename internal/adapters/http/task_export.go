@@ -0,0 +1,171 @@
+package http
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+const (
+	// DefaultExportMaxRows is the most tasks one export returns, so a forgotten filter can't download the whole database
+	DefaultExportMaxRows = 10000
+
+	// exportPageSize is how many tasks an export reads from the repository before flushing them to the client
+	exportPageSize = 500
+)
+
+// taskExportColumns is the CSV header, in the order taskExportRow.csvRecord writes the fields
+var taskExportColumns = []string{"id", "hook_type", "session_id", "tool_name", "command", "status", "action_taken", "created_at", "decision_duration_ms"}
+
+// taskExportFormat is a file format tasks can be exported in
+type taskExportFormat struct {
+	contentType string
+	extension   string
+	newWriter   func(w io.Writer, flush func() error) (taskExportWriter, error)
+}
+
+// taskExportFormats are the formats GET /api/tasks/export accepts, by ?format= value
+var taskExportFormats = map[string]taskExportFormat{
+	"csv":  {contentType: "text/csv; charset=utf-8", extension: "csv", newWriter: newCSVTaskExportWriter},
+	"json": {contentType: "application/json", extension: "jsonl", newWriter: newJSONLinesTaskExportWriter},
+}
+
+// taskExportRow is one task as it appears in an export
+type taskExportRow struct {
+	ID                 string    `json:"id"`
+	HookType           string    `json:"hook_type"`
+	SessionID          string    `json:"session_id"`
+	ToolName           string    `json:"tool_name"`
+	Command            string    `json:"command"`
+	Status             string    `json:"status"`
+	ActionTaken        string    `json:"action_taken,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+	DecisionDurationMs *int64    `json:"decision_duration_ms,omitempty"` // Nil until an action is taken
+}
+
+// newTaskExportRow flattens a task into the fields auditors need
+// The decision duration is the time from creation to the last update, matching the task stats.
+func newTaskExportRow(task *domain.Task) taskExportRow {
+	row := taskExportRow{
+		ID:        task.ID.String(),
+		HookType:  task.HookType.String(),
+		Status:    task.Status.String(),
+		CreatedAt: task.CreatedAt.UTC(),
+	}
+	if task.HookData != nil {
+		row.SessionID = task.HookData.GetSessionID()
+		row.ToolName = task.HookData.GetToolName()
+		row.Command = task.HookData.GetCommand()
+	}
+	if task.ActionTaken != nil {
+		row.ActionTaken = task.ActionTaken.String()
+		durationMs := task.UpdatedAt.Sub(task.CreatedAt).Milliseconds()
+		row.DecisionDurationMs = &durationMs
+	}
+	return row
+}
+
+// csvRecord returns the row's fields in taskExportColumns order, leaving the duration empty for undecided tasks
+func (r taskExportRow) csvRecord() []string {
+	duration := ""
+	if r.DecisionDurationMs != nil {
+		duration = strconv.FormatInt(*r.DecisionDurationMs, 10)
+	}
+	return []string{r.ID, r.HookType, r.SessionID, r.ToolName, r.Command, r.Status, r.ActionTaken, r.CreatedAt.Format(time.RFC3339), duration}
+}
+
+// taskExportWriter writes exported tasks in one file format
+type taskExportWriter interface {
+	Write(row taskExportRow) error
+	Flush() error // Sends everything written so far on to the client
+}
+
+// csvTaskExportWriter writes tasks as CSV with a header row
+type csvTaskExportWriter struct {
+	csv   *csv.Writer
+	flush func() error
+}
+
+// newCSVTaskExportWriter creates a CSV writer and writes the header row
+func newCSVTaskExportWriter(w io.Writer, flush func() error) (taskExportWriter, error) {
+	writer := &csvTaskExportWriter{csv: csv.NewWriter(w), flush: flush}
+	if err := writer.csv.Write(taskExportColumns); err != nil {
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	return writer, nil
+}
+
+func (c *csvTaskExportWriter) Write(row taskExportRow) error {
+	return c.csv.Write(row.csvRecord())
+}
+
+func (c *csvTaskExportWriter) Flush() error {
+	c.csv.Flush()
+	if err := c.csv.Error(); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+// jsonLinesTaskExportWriter writes tasks as JSON Lines, one object per line
+type jsonLinesTaskExportWriter struct {
+	encoder *json.Encoder
+	flush   func() error
+}
+
+// newJSONLinesTaskExportWriter creates a JSON Lines writer
+func newJSONLinesTaskExportWriter(w io.Writer, flush func() error) (taskExportWriter, error) {
+	return &jsonLinesTaskExportWriter{encoder: json.NewEncoder(w), flush: flush}, nil
+}
+
+func (j *jsonLinesTaskExportWriter) Write(row taskExportRow) error {
+	return j.encoder.Encode(row)
+}
+
+func (j *jsonLinesTaskExportWriter) Flush() error {
+	return j.flush()
+}
+
+// exportTasks writes the tasks matching filter that were created at or after since, newest first, up to maxRows
+// Tasks are listed a page at a time and flushed after each page, so no more than one page is held in memory.
+// It returns how many tasks were written, which on error is how far the export got.
+func exportTasks(ctx context.Context, list func(context.Context, ports.TaskFilter) ([]*domain.Task, error), filter ports.TaskFilter, since time.Time, maxRows int, writer taskExportWriter) (int, error) {
+	filter.SortBy, filter.SortOrder = "created_at", "desc"
+
+	written := 0
+	for written < maxRows {
+		filter.Limit = min(exportPageSize, maxRows-written)
+		filter.Offset = written
+
+		tasks, err := list(ctx, filter)
+		if err != nil {
+			return written, fmt.Errorf("failed to list tasks: %w", err)
+		}
+
+		for _, task := range tasks {
+			// Newest first, so the first task before the window ends the export
+			if task.CreatedAt.Before(since) {
+				return written, writer.Flush()
+			}
+			if err := writer.Write(newTaskExportRow(task)); err != nil {
+				return written, fmt.Errorf("failed to write task %s: %w", task.ID, err)
+			}
+			written++
+		}
+
+		if err := writer.Flush(); err != nil {
+			return written, fmt.Errorf("failed to flush export: %w", err)
+		}
+		if len(tasks) < filter.Limit {
+			break
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,176 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// exportTestTasks returns count tasks created an hour apart, newest first, the way the repository lists them
+func exportTestTasks(count int, now time.Time) []*domain.Task {
+	tasks := make([]*domain.Task, count)
+	for i := range tasks {
+		tasks[i] = &domain.Task{
+			ID:        uuid.New(),
+			HookType:  domain.HookTypeNotification,
+			Status:    domain.TaskStatusPending,
+			CreatedAt: now.Add(-time.Duration(i) * time.Hour),
+			UpdatedAt: now.Add(-time.Duration(i) * time.Hour),
+		}
+	}
+	return tasks
+}
+
+// pagedLister serves tasks by the filter's offset and limit, counting the pages asked for
+func pagedLister(tasks []*domain.Task, pages *int) func(context.Context, ports.TaskFilter) ([]*domain.Task, error) {
+	return func(ctx context.Context, filter ports.TaskFilter) ([]*domain.Task, error) {
+		*pages++
+		start := min(filter.Offset, len(tasks))
+		end := min(start+filter.Limit, len(tasks))
+		return tasks[start:end], nil
+	}
+}
+
+func TestNewTaskExportRow(t *testing.T) {
+	created := time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)
+	approved := domain.ActionTypeApprove
+	task := &domain.Task{
+		ID:       uuid.MustParse("6f1c1b9e-2f44-4a8e-9d57-3b1f7e0c2a10"),
+		HookType: domain.HookTypePreToolUse,
+		HookData: &domain.HookData{
+			Type: domain.HookTypePreToolUse,
+			Data: &domain.PreToolUseHookData{
+				BaseHookData: domain.BaseHookData{SessionID: "abc123"},
+				ToolName:     "Bash",
+				ToolInput:    &domain.ToolInput{Command: `echo "a, b"`},
+			},
+		},
+		Status:      domain.TaskStatusApproved,
+		ActionTaken: &approved,
+		CreatedAt:   created,
+		UpdatedAt:   created.Add(12400 * time.Millisecond),
+	}
+
+	var out bytes.Buffer
+	writer, err := newCSVTaskExportWriter(&out, func() error { return nil })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	writer.Write(newTaskExportRow(task))
+	writer.Write(newTaskExportRow(&domain.Task{ID: task.ID, HookType: domain.HookTypeStop, Status: domain.TaskStatusPending, CreatedAt: created}))
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("Export is not valid CSV: %v", err)
+	}
+	expected := [][]string{
+		taskExportColumns,
+		{task.ID.String(), "PreToolUse", "abc123", "Bash", `echo "a, b"`, "approved", "approve", "2024-01-15T09:30:00Z", "12400"},
+		{task.ID.String(), "Stop", "", "", "", "pending", "", "2024-01-15T09:30:00Z", ""},
+	}
+	if fmt.Sprint(records) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected CSV records:\n%q\nexpected:\n%q", records, expected)
+	}
+}
+
+func TestExportTasks(t *testing.T) {
+	now := time.Now()
+	tasks := exportTestTasks(1200, now)
+
+	tests := []struct {
+		name          string
+		since         time.Time
+		maxRows       int
+		expectedRows  int
+		expectedPages int
+	}{
+		{"Everything under the limit", time.Time{}, 10000, 1200, 3},
+		{"Stops at the row limit", time.Time{}, 700, 700, 2},
+		{"Stops at the since window", now.Add(-99*time.Hour - time.Minute), 10000, 100, 1},
+		{"Window ending on a page boundary", now.Add(-499*time.Hour - time.Minute), 10000, 500, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			var pages, flushes int
+			writer, _ := newJSONLinesTaskExportWriter(&out, func() error { flushes++; return nil })
+
+			written, err := exportTasks(context.Background(), pagedLister(tasks, &pages), ports.TaskFilter{}, tt.since, tt.maxRows, writer)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			if written != tt.expectedRows || len(lines) != tt.expectedRows {
+				t.Fatalf("Expected %d rows, wrote %d with %d lines", tt.expectedRows, written, len(lines))
+			}
+			if pages != tt.expectedPages || flushes != tt.expectedPages {
+				t.Errorf("Expected %d pages each flushed, got %d pages and %d flushes", tt.expectedPages, pages, flushes)
+			}
+
+			var last taskExportRow
+			if err := json.Unmarshal([]byte(lines[len(lines)-1]), &last); err != nil {
+				t.Fatalf("Line is not valid JSON: %v", err)
+			}
+			if last.ID != tasks[tt.expectedRows-1].ID.String() {
+				t.Errorf("Expected the rows newest first, last was %s", last.ID)
+			}
+		})
+	}
+}
+
+func TestExportTasks_ListError(t *testing.T) {
+	tasks := exportTestTasks(600, time.Now())
+	list := func(ctx context.Context, filter ports.TaskFilter) ([]*domain.Task, error) {
+		if filter.Offset > 0 {
+			return nil, errors.New("connection reset")
+		}
+		return tasks[:filter.Limit], nil
+	}
+
+	writer, _ := newCSVTaskExportWriter(&bytes.Buffer{}, func() error { return nil })
+	written, err := exportTasks(context.Background(), list, ports.TaskFilter{}, time.Time{}, 10000, writer)
+	if err == nil || written != exportPageSize {
+		t.Errorf("Expected the error after the first page, got %d rows and %v", written, err)
+	}
+}
+
+func TestHandleExportTasks_InvalidParameters(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"Unknown format", "format=xml"},
+		{"Invalid since", "since=yesterday"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			(&WebHandler{}).handleExportTasks(rr, httptest.NewRequest("GET", "/api/tasks/export?"+tt.query, nil))
+
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", rr.Code)
+			}
+			if rr.Header().Get("Content-Disposition") != "" {
+				t.Error("Expected no attachment for a rejected export")
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package http
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/services"
+)
+
+// NewWebHandlerWithEmbedFS creates a web handler whose templates are compiled into the binary
+// Pass templates.Files so the server runs without a templates/ directory next to it.
+func NewWebHandlerWithEmbedFS(templateFS embed.FS, taskService *services.TaskService, webhookHandler *WebhookHandler) *WebHandler {
+	return newWebHandler(taskService, webhookHandler, templateFS, false)
+}
+
+// parseTemplates parses the handler's page templates
+func (h *WebHandler) parseTemplates() (*template.Template, error) {
+	return parsePageTemplates(h.templateFS, func() string { return h.basePath })
+}
+
+// parsePageTemplates parses every .html template in templateFS with the functions pages rely on
+func parsePageTemplates(templateFS fs.FS, basePath func() string) (*template.Template, error) {
+	return template.New("").Funcs(template.FuncMap{
+		"basePath": basePath,
+		"age":      templateAge,
+		"overdue":  func(task *domain.Task) bool { return task.IsOverdue(domain.DefaultTaskOverdueThreshold) },
+	}).ParseFS(templateFS, "*.html")
+}
+
+// templateAge describes how long ago a task was created, or a time passed, for the age template function
+func templateAge(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case *domain.Task:
+		return value.AgeString(), nil
+	case time.Time:
+		return domain.FormatAge(time.Since(value)), nil
+	default:
+		return "", fmt.Errorf("age: unsupported value %T", value)
+	}
+}
+
+// executeTemplate renders a page template, re-parsing the templates first in development
+func (h *WebHandler) executeTemplate(w io.Writer, name string, data interface{}) error {
+	templates := h.templates
+	if h.reloadTemplates {
+		reloaded, err := h.parseTemplates()
+		if err != nil {
+			return fmt.Errorf("failed to reload templates: %w", err)
+		}
+		templates = reloaded
+	}
+	return templates.ExecuteTemplate(w, name, data)
+}
@@ -0,0 +1,129 @@
+package http
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/adapters/transcript"
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/dan/claude-control/internal/core/services"
+	"github.com/dan/claude-control/templates"
+	"github.com/google/uuid"
+)
+
+func TestEmbeddedTemplatesRender(t *testing.T) {
+	pageTemplates, err := parsePageTemplates(templates.Files, func() string { return "/control" })
+	if err != nil {
+		t.Fatalf("Failed to parse embedded templates: %v", err)
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name string
+		data interface{}
+	}{
+		{"login.html", map[string]interface{}{
+			"Title": "Log in",
+			"Error": "Incorrect password",
+		}},
+		{"dashboard.html", map[string]interface{}{
+			"Title": "Dashboard",
+			"PendingTasks": []*domain.Task{
+				{ID: uuid.New(), HookType: domain.HookTypePreToolUse, Status: domain.TaskStatusPending, CreatedAt: now.Add(-2 * time.Hour)},
+				{ID: uuid.New(), HookType: domain.HookTypePreToolUse, Status: domain.TaskStatusPending, CreatedAt: now.Add(-45 * time.Second)},
+			},
+			"RecentTasks": []interface{}{},
+			"Sessions": newSessionViews([]*domain.Session{
+				{ID: "c3e0f54b-5b1a", LastActivityAt: now, LastHookType: domain.HookTypePreToolUse, EventCount: 12},
+				{ID: "9a1d7e20-77c4", LastActivityAt: now.Add(-2 * time.Hour), LastHookType: domain.HookTypeStop},
+			}),
+			"Counts": &services.TaskCounts{
+				ByStatus: map[domain.TaskStatus]int{domain.TaskStatusPending: 2, domain.TaskStatusApproved: 14},
+			},
+			"ToolStats": []ports.ToolUsageStat{{ToolName: "Bash", CallCount: 4, ApprovalCount: 3, RejectionCount: 1}},
+			"Compacts": &ports.CompactStats{
+				TotalCompacts: 3, AutoCompacts: 2, ManualCompacts: 1,
+				BySession: map[string]int64{"c3e0f54b-5b1a": 3}, AvgTranscriptLines: 1840,
+			},
+			"SessionID": "c3e0f54b-5b1a",
+			"Summary": &ports.SessionSummary{
+				SessionID: "c3e0f54b-5b1a", TotalTasks: 5, PendingTasks: 2, ApprovedTasks: 2, RejectedTasks: 1,
+				FirstSeen: now.Add(-time.Hour), LastSeen: now, AvgDecisionDurationMs: 12400,
+			},
+		}},
+		{"tmux.html", map[string]interface{}{
+			"Title":       "tmux",
+			"TMuxEnabled": true,
+			"Sessions":    []ports.TMuxSession{{Name: "claude-main", Windows: 2, LastUsed: "2025-08-01 09:30"}},
+		}},
+		{"sessions.html", map[string]interface{}{
+			"Title": "Tasks by Session",
+			"Groups": newSessionTaskGroups(map[string][]*domain.Task{
+				"c3e0f54b-5b1a": {{ID: uuid.New(), HookType: domain.HookTypeStop, Status: domain.TaskStatusPending, CreatedAt: now}},
+			}),
+		}},
+		{"task-detail.html", map[string]interface{}{
+			"Title": "Task",
+			"Task": map[string]interface{}{
+				"ID":           uuid.New(),
+				"HookType":     "Stop",
+				"Status":       "pending",
+				"CreatedAt":    now,
+				"UpdatedAt":    now,
+				"IsActionable": true,
+			},
+			"Comments":    []services.TaskComment{{Comment: "Only touches the build cache", Action: domain.ActionTypeApprove, CreatedAt: now}},
+			"UnifiedDiff": newDiffLineViews(unifiedDiff("/srv/app/Makefile", "build:\n\tgo build\n", "build:\n\tgo build ./...\n")),
+			"Transcript": []transcript.TranscriptEntry{
+				{Role: "user", Content: "Clear the build cache", Timestamp: "2025-08-01T09:00:00Z"},
+				{Role: "assistant", Content: "Running rm -rf build/cache"},
+			},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rendered bytes.Buffer
+			if err := pageTemplates.ExecuteTemplate(&rendered, tt.name, tt.data); err != nil {
+				t.Fatalf("Failed to render %s: %v", tt.name, err)
+			}
+			if !strings.Contains(rendered.String(), "/control/") {
+				t.Errorf("Expected %s links to use the base path", tt.name)
+			}
+		})
+	}
+}
+
+func TestDashboardHighlightsOverduePendingTasks(t *testing.T) {
+	pageTemplates, err := parsePageTemplates(templates.Files, func() string { return "" })
+	if err != nil {
+		t.Fatalf("Failed to parse embedded templates: %v", err)
+	}
+
+	stale := domain.NewTask(&domain.HookData{Type: domain.HookTypePreToolUse})
+	stale.CreatedAt = time.Now().Add(-2 * time.Hour)
+	fresh := domain.NewTask(&domain.HookData{Type: domain.HookTypePreToolUse})
+	fresh.CreatedAt = time.Now().Add(-45 * time.Second)
+
+	var rendered bytes.Buffer
+	err = pageTemplates.ExecuteTemplate(&rendered, "dashboard.html", map[string]interface{}{
+		"Title":        "Dashboard",
+		"PendingTasks": []*domain.Task{stale, fresh},
+	})
+	if err != nil {
+		t.Fatalf("Failed to render dashboard: %v", err)
+	}
+
+	page := rendered.String()
+	if strings.Count(page, `class="task-item pending overdue"`) != 1 {
+		t.Error("Expected exactly the 2-hour-old pending task to be highlighted as overdue")
+	}
+	for _, age := range []string{"Created 2 hours ago", "Created just now"} {
+		if !strings.Contains(page, age) {
+			t.Errorf("Expected dashboard to show %q", age)
+		}
+	}
+}
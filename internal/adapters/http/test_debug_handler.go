@@ -0,0 +1,258 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/gorilla/mux"
+)
+
+// debugKeyFields are the payload fields pulled out into the key fields section of the debug log
+var debugKeyFields = []string{"hook_event_name", "session_id", "tool_name", "cwd", "message", "prompt", "transcript_path"}
+
+// debugRedactedHeaders are headers whose values are not written to the debug log
+var debugRedactedHeaders = []string{"Authorization", AdminKeyHeader, WebhookSignatureHeader}
+
+// debugLogRule is the border of a debug log entry
+var debugLogRule = strings.Repeat("═", 94)
+
+// TestDebugHandler logs Claude Code webhook requests in full and answers them without any processing
+// Stub responses can be set per hook type so integration tests can simulate the server blocking or stopping a hook.
+type TestDebugHandler struct {
+	mu        sync.RWMutex
+	templates map[domain.HookType]*domain.HookResponse
+}
+
+// debugResponseTemplate is one configured template as sent to and listed by /debug/configure
+type debugResponseTemplate struct {
+	HookType domain.HookType      `json:"hook_type"`
+	Response *domain.HookResponse `json:"response"`
+}
+
+// NewTestDebugHandler creates a debug handler that returns the default response for every hook
+func NewTestDebugHandler() *TestDebugHandler {
+	return &TestDebugHandler{
+		templates: make(map[domain.HookType]*domain.HookResponse),
+	}
+}
+
+// SetResponseTemplate sets the response returned for a hook type; a nil response restores the default
+func (h *TestDebugHandler) SetResponseTemplate(hookType domain.HookType, response *domain.HookResponse) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if response == nil {
+		delete(h.templates, hookType)
+		return
+	}
+	template := *response
+	h.templates[hookType] = &template
+}
+
+// ResponseTemplate returns the response configured for a hook type, if any
+func (h *TestDebugHandler) ResponseTemplate(hookType domain.HookType) (*domain.HookResponse, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	template, ok := h.templates[hookType]
+	return template, ok
+}
+
+// listResponseTemplates returns every configured template, ordered by hook type
+func (h *TestDebugHandler) listResponseTemplates() []debugResponseTemplate {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	templates := make([]debugResponseTemplate, 0, len(h.templates))
+	for hookType, response := range h.templates {
+		templates = append(templates, debugResponseTemplate{HookType: hookType, Response: response})
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].HookType < templates[j].HookType
+	})
+	return templates
+}
+
+// RegisterRoutes registers the debug webhook and template configuration routes with the router
+// When the full server registers its webhook routes first they take precedence over the debug /webhook/ routes.
+func (h *TestDebugHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/webhook/{hookType}", h.handleDebugWebhook).Methods("POST")
+	router.HandleFunc("/debug/webhook/{hookType}", h.handleDebugWebhook).Methods("POST")
+	router.HandleFunc("/debug/configure", h.handleListTemplates).Methods("GET")
+	router.HandleFunc("/debug/configure", h.handleConfigureTemplate).Methods("POST")
+}
+
+// handleListTemplates lists every configured response template
+func (h *TestDebugHandler) handleListTemplates(w http.ResponseWriter, r *http.Request) {
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"templates": h.listResponseTemplates(),
+	})
+}
+
+// handleConfigureTemplate sets the response template for one hook type
+// Sending a null response clears the template so the hook type gets the default response again.
+func (h *TestDebugHandler) handleConfigureTemplate(w http.ResponseWriter, r *http.Request) {
+	var request debugResponseTemplate
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	hookType, err := domain.ParseHookType(string(request.HookType))
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if request.Response != nil {
+		if err := request.Response.Validate(); err != nil {
+			h.respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	h.SetResponseTemplate(hookType, request.Response)
+	if request.Response == nil {
+		log.Printf("Debug response template cleared for %s", hookType)
+	} else {
+		log.Printf("Debug response template set for %s: %s", hookType, request.Response)
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":   true,
+		"hook_type": hookType,
+		"response":  request.Response,
+	})
+}
+
+// handleDebugWebhook logs a webhook request and answers it from the configured template or the default response
+// A template with Continue false is returned with 400 so the calling hook sees a blocking failure.
+func (h *TestDebugHandler) handleDebugWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Failed to read request body")
+		return
+	}
+	logDebugWebhook(r, mux.Vars(r)["hookType"], body)
+
+	if template, ok := h.ResponseTemplate(debugRequestHookType(r.URL.Path, body)); ok {
+		statusCode := http.StatusOK
+		if !template.Continue {
+			statusCode = http.StatusBadRequest
+		}
+		h.respondWithJSON(w, statusCode, template)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"continue":        true,
+		"suppress_output": true,
+		"debug":           true,
+		"message":         "Request logged successfully",
+	})
+}
+
+// logDebugWebhook logs a request in the boxed format shown in DEBUG_WEBHOOK_USAGE.md
+func logDebugWebhook(r *http.Request, hookName string, body []byte) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n╔%s\n", debugLogRule)
+	fmt.Fprintf(&b, "║ 🚀 CLAUDE CODE WEBHOOK DEBUG - %s\n", time.Now().Format("2006-01-02 15:04:05.000"))
+	fmt.Fprintf(&b, "║ Hook Type: %s\n", hookName)
+	fmt.Fprintf(&b, "╠%s\n", debugLogRule)
+	fmt.Fprintf(&b, "║ HTTP Method: %s\n", r.Method)
+	fmt.Fprintf(&b, "║ URL Path: %s\n", r.URL.Path)
+	fmt.Fprintf(&b, "║ Remote Address: %s\n", r.RemoteAddr)
+
+	b.WriteString("║\n║ 📋 HTTP HEADERS:\n")
+	names := make([]string, 0, len(r.Header))
+	for name := range r.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := strings.Join(r.Header.Values(name), ", ")
+		for _, redacted := range debugRedactedHeaders {
+			if strings.EqualFold(name, redacted) {
+				value = "[redacted]"
+			}
+		}
+		fmt.Fprintf(&b, "║   %s: %s\n", name, value)
+	}
+
+	b.WriteString("║\n║ 📦 REQUEST BODY:\n")
+	var payload map[string]interface{}
+	if len(body) == 0 {
+		b.WriteString("║   (empty)\n")
+	} else if err := json.Unmarshal(body, &payload); err != nil {
+		fmt.Fprintf(&b, "║   Invalid JSON (%v):\n", err)
+		fmt.Fprintf(&b, "║   %s\n", truncateString(string(body), 2000))
+	} else {
+		pretty, _ := json.MarshalIndent(payload, "║   ", "  ")
+		fmt.Fprintf(&b, "║   JSON Data:\n║   %s\n", pretty)
+
+		b.WriteString("║\n║ 🔍 KEY CLAUDE CODE FIELDS:\n")
+		for _, key := range debugKeyFields {
+			if value, ok := payload[key]; ok {
+				fmt.Fprintf(&b, "║   %s: %v\n", key, value)
+			}
+		}
+		if toolInput, ok := payload["tool_input"].(map[string]interface{}); ok {
+			for _, key := range []string{"command", "description", "file_path"} {
+				if value, ok := toolInput[key]; ok {
+					fmt.Fprintf(&b, "║   %s: %v\n", key, value)
+				}
+			}
+		}
+	}
+
+	b.WriteString("║\n║ 📊 REQUEST STATS:\n")
+	fmt.Fprintf(&b, "║   Body Size: %d bytes\n", len(body))
+	fmt.Fprintf(&b, "║   Content-Type: %s\n", r.Header.Get("Content-Type"))
+	fmt.Fprintf(&b, "║   User-Agent: %s\n", r.UserAgent())
+	fmt.Fprintf(&b, "╚%s", debugLogRule)
+	log.Print(b.String())
+}
+
+// debugRequestHookType works out which hook a debug request is for
+// Claude Code names the hook in hook_event_name; the URL is only a fallback since the named routes use kebab-case.
+func debugRequestHookType(urlPath string, body []byte) domain.HookType {
+	var payload struct {
+		HookEventName string `json:"hook_event_name"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if hookType, err := domain.ParseHookType(payload.HookEventName); err == nil {
+			return hookType
+		}
+	}
+
+	hookType, err := domain.ParseHookType(path.Base(urlPath))
+	if err != nil {
+		return ""
+	}
+	return hookType
+}
+
+// respondWithError sends an error response
+func (h *TestDebugHandler) respondWithError(w http.ResponseWriter, statusCode int, message string) {
+	h.respondWithJSON(w, statusCode, map[string]interface{}{
+		"success": false,
+		"error":   message,
+	})
+}
+
+// respondWithJSON sends a JSON response
+func (h *TestDebugHandler) respondWithJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		log.Printf("Failed to encode debug response: %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/gorilla/mux"
+)
+
+// newDebugRouter registers a debug handler's routes on a new router
+func newDebugRouter(handler *TestDebugHandler) *mux.Router {
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+	return router
+}
+
+func TestDebugConfigureBlockingResponse(t *testing.T) {
+	router := newDebugRouter(NewTestDebugHandler())
+
+	configure := `{"hook_type": "PreToolUse", "response": {"continue": false, "stopReason": "Blocked by test"}}`
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("POST", "/debug/configure", strings.NewReader(configure)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected configure to return 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	tests := []struct {
+		name           string
+		endpoint       string
+		payload        string
+		expectedStatus int
+		expectedDebug  bool
+	}{
+		{
+			name:           "Configured hook is blocked",
+			endpoint:       "/webhook/pre-tool-use",
+			payload:        `{"hook_event_name": "PreToolUse", "tool_name": "Bash"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Legacy debug path is blocked",
+			endpoint:       "/debug/webhook/pre-tool-use",
+			payload:        `{"hook_event_name": "PreToolUse", "tool_name": "Bash"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Hook type taken from the URL",
+			endpoint:       "/webhook/PreToolUse",
+			payload:        `{"tool_name": "Bash"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Other hook types keep the default",
+			endpoint:       "/webhook/stop",
+			payload:        `{"hook_event_name": "Stop"}`,
+			expectedStatus: http.StatusOK,
+			expectedDebug:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest("POST", tt.endpoint, strings.NewReader(tt.payload)))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+
+			var response map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Response is not valid JSON: %v", err)
+			}
+			if _, isDebug := response["debug"]; isDebug != tt.expectedDebug {
+				t.Errorf("Expected debug response %t, got %v", tt.expectedDebug, response)
+			}
+			if !tt.expectedDebug && response["stopReason"] != "Blocked by test" {
+				t.Errorf("Expected the configured stop reason, got %v", response["stopReason"])
+			}
+		})
+	}
+}
+
+func TestDebugConfigureList(t *testing.T) {
+	handler := NewTestDebugHandler()
+	handler.SetResponseTemplate(domain.HookTypeStop, &domain.HookResponse{Continue: true, SuppressOutput: true})
+	handler.SetResponseTemplate(domain.HookTypePreToolUse, &domain.HookResponse{Continue: false, StopReason: "No"})
+	router := newDebugRouter(handler)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/configure", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+
+	var response struct {
+		Templates []debugResponseTemplate `json:"templates"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Response is not valid JSON: %v", err)
+	}
+	if len(response.Templates) != 2 {
+		t.Fatalf("Expected 2 templates, got %d", len(response.Templates))
+	}
+	if response.Templates[0].HookType != domain.HookTypePreToolUse || response.Templates[0].Response.StopReason != "No" {
+		t.Errorf("Expected PreToolUse template first, got %+v", response.Templates[0])
+	}
+	if response.Templates[1].HookType != domain.HookTypeStop || !response.Templates[1].Response.SuppressOutput {
+		t.Errorf("Expected Stop template second, got %+v", response.Templates[1])
+	}
+
+	// A null response clears the template
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("POST", "/debug/configure", strings.NewReader(`{"hook_type": "Stop", "response": null}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected clearing a template to return 200, got %d", rec.Code)
+	}
+	if _, ok := handler.ResponseTemplate(domain.HookTypeStop); ok {
+		t.Error("Expected the Stop template to be cleared")
+	}
+}
+
+func TestDebugConfigureValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+	}{
+		{"Invalid JSON", `{"hook_type":`},
+		{"Unknown hook type", `{"hook_type": "NotAHook", "response": {"continue": true}}`},
+		{"Modified command on a blocking response", `{"hook_type": "PreToolUse", "response": {"continue": false, "modified_command": "ls"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewTestDebugHandler()
+			router := newDebugRouter(handler)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest("POST", "/debug/configure", strings.NewReader(tt.payload)))
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400, got %d", rec.Code)
+			}
+			if len(handler.listResponseTemplates()) != 0 {
+				t.Error("Expected no template to be configured")
+			}
+		})
+	}
+}
+
+func TestDebugWebhookLogsRequest(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	router := newDebugRouter(NewTestDebugHandler())
+	payload := `{"hook_event_name": "PreToolUse", "session_id": "abc-123", "tool_input": {"command": "ls -la"}}`
+	req := httptest.NewRequest("POST", "/debug/webhook/pre-tool-use", strings.NewReader(payload))
+	req.Header.Set("Authorization", "Bearer secret-key")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	var response map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Response is not valid JSON: %v", err)
+	}
+	if response["continue"] != true || response["suppress_output"] != true || response["message"] != "Request logged successfully" {
+		t.Errorf("Expected the default debug response, got %v", response)
+	}
+
+	output := logged.String()
+	for _, expected := range []string{"Hook Type: pre-tool-use", "session_id: abc-123", "command: ls -la", "Authorization: [redacted]"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected the log to contain %q, got:\n%s", expected, output)
+		}
+	}
+	if strings.Contains(output, "secret-key") {
+		t.Error("Expected the Authorization header to be redacted")
+	}
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/gorilla/mux"
+	"golang.org/x/net/http/httpguts"
+)
+
+const (
+	// DefaultBlockingHandlerTimeout covers the 5 minute decision wait plus time to respond
+	DefaultBlockingHandlerTimeout = 5*time.Minute + 30*time.Second
+
+	// DefaultNonBlockingHandlerTimeout applies to every request that does not wait for a user decision
+	DefaultNonBlockingHandlerTimeout = 10 * time.Second
+)
+
+// HandlerTimeouts configures per-request deadlines for blocking and non-blocking routes
+type HandlerTimeouts struct {
+	Blocking    time.Duration // Webhooks that wait for a user decision (PreToolUse, UserPromptSubmit) and task long polls
+	NonBlocking time.Duration // All other routes, including health checks
+
+	// BlockingHooks lists the hook types whose webhooks wait for a decision; HookType.IsBlocking decides when nil
+	BlockingHooks []domain.HookType
+}
+
+// isBlockingHook reports whether webhooks of the hook type get the blocking timeout
+func (t HandlerTimeouts) isBlockingHook(hookType domain.HookType) bool {
+	if t.BlockingHooks == nil {
+		return hookType.IsBlocking()
+	}
+	return slices.Contains(t.BlockingHooks, hookType)
+}
+
+// TimeoutMiddleware bounds each request with a deadline chosen by whether it blocks on a user decision
+// The deadline is set on the request context, so services waiting on a decision see it too.
+// WebSocket upgrades, the event stream and task exports are passed through untouched: they are long-lived,
+// and TimeoutHandler can neither hijack nor flush the connection.
+func TimeoutMiddleware(timeouts HandlerTimeouts) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		blocking := http.TimeoutHandler(next, timeouts.Blocking, `{"error":"Request timed out"}`)
+		nonBlocking := http.TimeoutHandler(next, timeouts.NonBlocking, `{"error":"Request timed out"}`)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isWebSocketUpgrade(r) || isStreamingRequest(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if isBlockingRequest(r, timeouts) {
+				blocking.ServeHTTP(w, r)
+				return
+			}
+			nonBlocking.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isBlockingRequest reports whether the request waits on the user: a blocking webhook or a task long poll
+func isBlockingRequest(r *http.Request, timeouts HandlerTimeouts) bool {
+	if strings.HasPrefix(r.URL.Path, "/api/tasks/") && strings.HasSuffix(r.URL.Path, "/stream") {
+		return true
+	}
+	if !strings.HasPrefix(r.URL.Path, "/webhook/") {
+		return false
+	}
+
+	hookType, err := domain.ParseHookType(mux.Vars(r)["hookType"])
+	if err != nil {
+		return false
+	}
+	return timeouts.isBlockingHook(hookType)
+}
+
+// isWebSocketUpgrade returns true for requests asking to switch to the WebSocket protocol
+func isWebSocketUpgrade(r *http.Request) bool {
+	return httpguts.HeaderValuesContainsToken(r.Header["Connection"], "upgrade") &&
+		httpguts.HeaderValuesContainsToken(r.Header["Upgrade"], "websocket")
+}
+
+// isStreamingRequest returns true for requests to the Server-Sent Events task stream or a task export
+func isStreamingRequest(r *http.Request) bool {
+	return r.URL.Path == "/api/events" || r.URL.Path == "/api/tasks/export"
+}
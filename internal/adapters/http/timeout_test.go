@@ -0,0 +1,157 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/gorilla/mux"
+)
+
+// TestTimeoutMiddleware_DeadlinePropagation verifies each route group gets its own request deadline
+func TestTimeoutMiddleware_DeadlinePropagation(t *testing.T) {
+	timeouts := HandlerTimeouts{Blocking: time.Minute, NonBlocking: time.Second}
+
+	var remaining time.Duration
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok := r.Context().Deadline()
+		if !ok {
+			t.Error("Expected request context to have a deadline")
+			return
+		}
+		remaining = time.Until(deadline)
+		w.WriteHeader(http.StatusOK)
+	}
+
+	router := mux.NewRouter()
+	router.Use(TimeoutMiddleware(timeouts))
+	router.HandleFunc("/webhook/{hookType}", handler).Methods("POST")
+	router.HandleFunc("/health", handler).Methods("GET")
+	router.HandleFunc("/api/tasks/{taskId}/stream", handler).Methods("GET")
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		expected time.Duration
+	}{
+		{"Blocking PreToolUse webhook", "POST", "/webhook/PreToolUse", timeouts.Blocking},
+		{"Blocking UserPromptSubmit webhook", "POST", "/webhook/UserPromptSubmit", timeouts.Blocking},
+		{"Non-blocking Stop webhook", "POST", "/webhook/Stop", timeouts.NonBlocking},
+		{"Unknown hook type", "POST", "/webhook/unknown", timeouts.NonBlocking},
+		{"Health check", "GET", "/health", timeouts.NonBlocking},
+		{"Task long poll", "GET", "/api/tasks/7d0b4a1e-3c1f-4a8e-9b61-1f0f3c2a9d11/stream", timeouts.Blocking},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remaining = 0
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+			}
+			if remaining > tt.expected || remaining < tt.expected-100*time.Millisecond {
+				t.Errorf("Expected deadline about %v away, got %v", tt.expected, remaining)
+			}
+		})
+	}
+}
+
+// TestTimeoutMiddleware_ConfiguredBlockingHooks verifies configured blocking hook types replace the defaults
+func TestTimeoutMiddleware_ConfiguredBlockingHooks(t *testing.T) {
+	timeouts := HandlerTimeouts{Blocking: time.Minute, NonBlocking: time.Second, BlockingHooks: []domain.HookType{domain.HookTypeStop}}
+
+	var remaining time.Duration
+	router := mux.NewRouter()
+	router.Use(TimeoutMiddleware(timeouts))
+	router.HandleFunc("/webhook/{hookType}", func(w http.ResponseWriter, r *http.Request) {
+		deadline, _ := r.Context().Deadline()
+		remaining = time.Until(deadline)
+	}).Methods("POST")
+
+	for hookType, expected := range map[string]time.Duration{"Stop": timeouts.Blocking, "PreToolUse": timeouts.NonBlocking} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/webhook/"+hookType, nil))
+		if remaining > expected || remaining < expected-100*time.Millisecond {
+			t.Errorf("Expected %s deadline about %v away, got %v", hookType, expected, remaining)
+		}
+	}
+}
+
+// TestTimeoutMiddleware_SlowHandler verifies a handler exceeding its deadline gets a 503
+func TestTimeoutMiddleware_SlowHandler(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(TimeoutMiddleware(HandlerTimeouts{Blocking: time.Minute, NonBlocking: 20 * time.Millisecond}))
+	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}).Methods("GET")
+
+	req := httptest.NewRequest("GET", "/health", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, rr.Code)
+	}
+}
+
+// TestTimeoutMiddleware_WebSocketUpgrade verifies WebSocket upgrades skip the deadline so they can hijack the connection
+func TestTimeoutMiddleware_WebSocketUpgrade(t *testing.T) {
+	tests := []struct {
+		name         string
+		connection   string
+		upgrade      string
+		expectBypass bool
+	}{
+		{"WebSocket upgrade", "Upgrade", "websocket", true},
+		{"Mixed case tokens", "keep-alive, UPGRADE", "WebSocket", true},
+		{"Plain request", "keep-alive", "", false},
+		{"Other protocol", "Upgrade", "h2c", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hasDeadline bool
+			router := mux.NewRouter()
+			router.Use(TimeoutMiddleware(HandlerTimeouts{Blocking: time.Minute, NonBlocking: time.Second}))
+			router.HandleFunc("/ws/tasks", func(w http.ResponseWriter, r *http.Request) {
+				_, hasDeadline = r.Context().Deadline()
+			}).Methods("GET")
+
+			req := httptest.NewRequest("GET", "/ws/tasks", nil)
+			req.Header.Set("Connection", tt.connection)
+			if tt.upgrade != "" {
+				req.Header.Set("Upgrade", tt.upgrade)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if hasDeadline == tt.expectBypass {
+				t.Errorf("Expected bypass %t, got deadline %t", tt.expectBypass, hasDeadline)
+			}
+		})
+	}
+}
+
+// TestTimeoutMiddleware_Streaming verifies the event stream and task exports skip the deadline so they can stay open and flush
+func TestTimeoutMiddleware_Streaming(t *testing.T) {
+	for _, path := range []string{"/api/events", "/api/tasks/export"} {
+		t.Run(path, func(t *testing.T) {
+			var hasDeadline, canFlush bool
+			router := mux.NewRouter()
+			router.Use(TimeoutMiddleware(HandlerTimeouts{Blocking: time.Minute, NonBlocking: time.Second}))
+			router.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+				_, hasDeadline = r.Context().Deadline()
+				canFlush = http.NewResponseController(w).Flush() == nil
+			}).Methods("GET")
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+			if hasDeadline || !canFlush {
+				t.Errorf("Expected no deadline and a flushable writer, got deadline %t and flushable %t", hasDeadline, canFlush)
+			}
+		})
+	}
+}
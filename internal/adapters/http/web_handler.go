@@ -1,58 +1,309 @@
 package http
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
+	"io/fs"
 	"log"
+	"math/rand"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/dan/claude-control/internal/adapters/claude"
+	"github.com/dan/claude-control/internal/adapters/transcript"
 	"github.com/dan/claude-control/internal/core/domain"
 	"github.com/dan/claude-control/internal/core/ports"
 	"github.com/dan/claude-control/internal/core/services"
+	"github.com/dan/claude-control/static"
 	"github.com/google/uuid"
+	"github.com/gorilla/csrf"
 	"github.com/gorilla/mux"
+	"github.com/gorilla/securecookie"
 )
 
+const (
+	// defaultLongPollTimeout is how long /api/tasks/{taskId}/stream waits when no timeout is given
+	defaultLongPollTimeout = 30 * time.Second
+
+	// maxLongPollTimeout keeps long polls inside the blocking request deadline
+	maxLongPollTimeout = 5 * time.Minute
+
+	// longPollStagger is the maximum random delay before a long poll first loads its task
+	longPollStagger = 250 * time.Millisecond
+
+	// TranscriptTailEntries is how many recent transcript messages the task page and API show
+	TranscriptTailEntries = 20
+)
+
+// StopInputSender sends the user's guidance to a stopped Claude Code session
+// ClaudeCodeAdapter sends it directly; ClaudeCodeAdapterPool limits how many sessions are sent to at once.
+type StopInputSender interface {
+	SendInputToStopWebhook(ctx context.Context, sessionID, userInput string) (*claude.ClaudeResponse, error)
+}
+
 // WebHandler handles web interface requests
 type WebHandler struct {
 	taskService     *services.TaskService
 	webhookHandler  *WebhookHandler
+	tmuxController  ports.TMuxController         // Optional - tmux views are disabled when nil
+	claudeAdapter   *claude.ClaudeCodeAdapter    // Optional - Claude session listing is disabled when nil
+	stopInput       StopInputSender              // Optional - sending Stop input is disabled when nil
+	settings        ports.ServerSettingsService  // Optional - used to show the hooks-disabled banner
+	transcripts     *transcript.TranscriptReader // Optional - transcript reading is disabled when nil
+	diffs           *DiffRenderer                // Optional - the file diff view is disabled when nil
+	exportMaxRows   int                          // Most tasks one GET /api/tasks/export returns
 	templates       *template.Template
+	templateFS      fs.FS
+	reloadTemplates bool   // Re-parse templateFS on every render, for editing templates in development
+	basePath        string // Prefix for links in rendered pages, "" when served from the root
+
+	// Dashboard login - the login page just redirects to the dashboard when no password is set
+	dashboardPassword string
+	cookieStore       *securecookie.SecureCookie
+	csrfProtect       func(http.Handler) http.Handler
+	secureCookies     bool
+
+	// Notification quick actions - every approval token is rejected when no secret is set
+	quickActionSecret string
 }
 
-// NewWebHandler creates a new web handler
+// NewWebHandler creates a web handler that reads templates from the templates/ directory
+// Templates are re-read on every render so edits show up without a restart; use it for development.
 func NewWebHandler(taskService *services.TaskService, webhookHandler *WebhookHandler) *WebHandler {
-	return &WebHandler{
-		taskService:    taskService,
-		webhookHandler: webhookHandler,
-		templates:      template.Must(template.New("").ParseGlob("templates/*.html")),
+	return newWebHandler(taskService, webhookHandler, os.DirFS("templates"), true)
+}
+
+// newWebHandler creates a web handler whose templates are parsed from templateFS
+func newWebHandler(taskService *services.TaskService, webhookHandler *WebhookHandler, templateFS fs.FS, reloadTemplates bool) *WebHandler {
+	h := &WebHandler{
+		taskService:     taskService,
+		webhookHandler:  webhookHandler,
+		templateFS:      templateFS,
+		reloadTemplates: reloadTemplates,
+		exportMaxRows:   DefaultExportMaxRows,
+	}
+	h.templates = template.Must(h.parseTemplates())
+	return h
+}
+
+// SetBasePath prefixes links in rendered pages with the base path the routes are mounted under
+// Call it before SetDashboardLogin so the CSRF cookie is scoped to the prefixed login page.
+func (h *WebHandler) SetBasePath(basePath string) {
+	h.basePath = basePath
+}
+
+// SetTMuxController enables the tmux session views using the given controller
+func (h *WebHandler) SetTMuxController(tmuxController ports.TMuxController) {
+	h.tmuxController = tmuxController
+}
+
+// SetClaudeAdapter enables listing running Claude Code sessions using the given adapter
+func (h *WebHandler) SetClaudeAdapter(claudeAdapter *claude.ClaudeCodeAdapter) {
+	h.claudeAdapter = claudeAdapter
+}
+
+// SetStopInputSender enables sending guidance to stopped Claude Code sessions using the given sender
+func (h *WebHandler) SetStopInputSender(sender StopInputSender) {
+	h.stopInput = sender
+}
+
+// SetServerSettings lets the dashboard show server-wide settings such as the hooks-disabled mode
+func (h *WebHandler) SetServerSettings(settings ports.ServerSettingsService) {
+	h.settings = settings
+}
+
+// SetTranscriptReader enables showing recent transcript messages next to a task
+// Transcripts hold the whole conversation, so this is opt-in.
+func (h *WebHandler) SetTranscriptReader(reader *transcript.TranscriptReader) {
+	h.transcripts = reader
+}
+
+// SetDiffRenderer enables showing what Edit and Write tool calls would change in the file on disk
+// Diffs are read from the server's filesystem, so this is opt-in.
+func (h *WebHandler) SetDiffRenderer(renderer *DiffRenderer) {
+	h.diffs = renderer
+}
+
+// SetExportMaxRows limits how many tasks one export returns; values below 1 keep DefaultExportMaxRows
+func (h *WebHandler) SetExportMaxRows(maxRows int) {
+	if maxRows > 0 {
+		h.exportMaxRows = maxRows
+	}
+}
+
+// SetQuickActionSecret sets the secret notification approve and reject links are signed with
+// Call it before RegisterRoutes, which hands the secret to the quick action route.
+func (h *WebHandler) SetQuickActionSecret(secret string) {
+	h.quickActionSecret = secret
+}
+
+// SetDashboardLogin enables the password login page
+// The session cookie is signed with cookieStore and the login form is CSRF-protected with csrfKey.
+func (h *WebHandler) SetDashboardLogin(password string, cookieStore *securecookie.SecureCookie, csrfKey []byte, secureCookies bool) {
+	h.dashboardPassword = password
+	h.cookieStore = cookieStore
+	h.secureCookies = secureCookies
+
+	protect := csrf.Protect(csrfKey, csrf.Path(h.basePath+"/login"), csrf.Secure(secureCookies))
+	h.csrfProtect = func(next http.Handler) http.Handler {
+		csrfHandler := protect(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Strict Referer checks only apply to requests that actually arrived over TLS
+			if r.TLS == nil {
+				r = csrf.PlaintextHTTPRequest(r)
+			}
+			csrfHandler.ServeHTTP(w, r)
+		})
 	}
 }
 
 // RegisterRoutes registers web interface routes with the router
 func (h *WebHandler) RegisterRoutes(router *mux.Router) {
+	// Login routes
+	router.Handle("/login", h.withCSRF(h.handleLoginPage)).Methods("GET")
+	router.Handle("/login", h.withCSRF(h.handleLogin)).Methods("POST")
+
 	// Web interface routes
 	router.HandleFunc("/", h.handleDashboard).Methods("GET")
 	router.HandleFunc("/dashboard", h.handleDashboard).Methods("GET")
+	router.HandleFunc("/dashboard/tmux", h.handleTmuxDashboard).Methods("GET")
+	router.HandleFunc("/dashboard/sessions", h.handleSessionDashboard).Methods("GET")
 	router.HandleFunc("/task/{taskId}", h.handleTaskDetail).Methods("GET")
 	router.HandleFunc("/task/{taskId}/action", h.handleTaskAction).Methods("POST")
 	router.HandleFunc("/task/{taskId}/stop-input", h.handleStopInput).Methods("POST")
+	router.HandleFunc("/static/{file}", h.handleStatic).Methods("GET")
 	
 	// API routes
 	router.HandleFunc("/api/tasks", h.handleListTasks).Methods("GET")
+	router.HandleFunc("/api/tasks/archived", h.handleListArchivedTasks).Methods("GET")
+	router.HandleFunc("/api/tasks/bulk-action", h.handleBulkTaskAction).Methods("POST")
+	router.HandleFunc("/api/tasks/bulk-delete", h.handleBulkDeleteTasks).Methods("POST")
+	router.HandleFunc("/api/tasks/export", h.handleExportTasks).Methods("GET")
 	router.HandleFunc("/api/tasks/{taskId}", h.handleGetTask).Methods("GET")
+	router.HandleFunc("/api/tasks/{taskId}", h.handleModifyTaskCommand).Methods("PATCH")
+	router.HandleFunc("/api/tasks/{taskId}", h.handleDeleteTask).Methods("DELETE")
+	// Quick actions from notification buttons carry a signed token instead of a JSON body, so they match first
+	router.Handle("/api/tasks/{taskId}/action", ApprovalTokenMiddleware(h.quickActionSecret)(http.HandlerFunc(h.handleQuickAction))).
+		Methods("POST").Queries("token", "{token}")
 	router.HandleFunc("/api/tasks/{taskId}/action", h.handleTaskActionAPI).Methods("POST")
+	router.HandleFunc("/api/tasks/{taskId}/stream", h.handleTaskStream).Methods("GET")
+	router.HandleFunc("/api/tasks/{taskId}/comments", h.handleListTaskComments).Methods("GET")
+	router.HandleFunc("/api/tasks/{taskId}/transcript", h.handleTaskTranscript).Methods("GET")
+	router.HandleFunc("/api/tasks/{taskId}/snooze", h.handleSnoozeTask).Methods("POST")
+	router.HandleFunc("/api/tasks/{taskId}/snooze", h.handleUnsnoozeTask).Methods("DELETE")
+	router.HandleFunc("/api/tasks/{taskId}/notify", h.handleRenotifyTask).Methods("POST")
+	router.HandleFunc("/api/transcripts/{taskId}", h.handleGetTranscriptBackup).Methods("GET")
+	router.HandleFunc("/api/tasks/{taskId}/diff", h.handleTaskFileDiff).Methods("GET")
+	router.HandleFunc("/api/tasks/{taskId}/diff/{otherTaskId}", h.handleTaskDiff).Methods("GET")
+	router.HandleFunc("/api/sessions/{sessionID}/pending", h.handleListSessionPendingTasks).Methods("GET")
+	router.HandleFunc("/api/sessions/{sessionID}/summary", h.handleGetSessionSummary).Methods("GET")
+	router.HandleFunc("/api/tmux/sessions/{name}", h.handleKillTmuxSession).Methods("DELETE")
+	router.HandleFunc("/api/tmux/sessions/{name}/scrollback", h.handleTmuxScrollback).Methods("GET")
+	router.HandleFunc("/api/claude/sessions", h.handleListClaudeSessions).Methods("GET")
+	router.HandleFunc("/api/webhook/config", h.handleWebhookConfig).Methods("GET")
+	router.HandleFunc("/api/auth/verify", h.handleVerifyAPIKey).Methods("POST")
+	router.HandleFunc("/api/stats", h.handleTaskStats).Methods("GET")
+	router.HandleFunc("/api/stats/tools", h.handleToolUsageStats).Methods("GET")
+	router.HandleFunc("/api/stats/counts", h.handleTaskCounts).Methods("GET")
+	router.HandleFunc("/api/stats/receipts", h.handleReceiptStats).Methods("GET")
+	router.HandleFunc("/api/stats/concurrent-sessions", h.handleConcurrentSessions).Methods("GET")
+	router.HandleFunc("/api/stats/activity", h.handleActivityStats).Methods("GET")
+	router.HandleFunc("/api/stats/compact", h.handleCompactStats).Methods("GET")
+
+	// Live task updates
+	NewWebSocketHandler(h.taskService).RegisterRoutes(router)
+	NewEventStreamHandler(h.taskService).RegisterRoutes(router)
 	
 	// Health check
 	router.HandleFunc("/health", h.handleHealthCheck).Methods("GET")
+	router.HandleFunc("/health/decisions", h.handleDecisionHealth).Methods("GET")
+}
+
+// withCSRF wraps a login handler with CSRF protection once the dashboard login is enabled
+func (h *WebHandler) withCSRF(handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.csrfProtect == nil {
+			handler(w, r)
+			return
+		}
+		h.csrfProtect(handler).ServeHTTP(w, r)
+	})
+}
+
+// handleLoginPage shows the dashboard login form
+func (h *WebHandler) handleLoginPage(w http.ResponseWriter, r *http.Request) {
+	if h.cookieStore == nil {
+		http.Redirect(w, r, basePathFromRequest(r)+"/dashboard", http.StatusSeeOther)
+		return
+	}
+
+	h.renderLogin(w, r, http.StatusOK, "")
+}
+
+// handleLogin checks the dashboard password and starts a session
+func (h *WebHandler) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if h.cookieStore == nil {
+		http.Redirect(w, r, basePathFromRequest(r)+"/dashboard", http.StatusSeeOther)
+		return
+	}
+
+	password := r.FormValue("password")
+	if subtle.ConstantTimeCompare([]byte(password), []byte(h.dashboardPassword)) != 1 {
+		log.Printf("Failed dashboard login from %s", r.RemoteAddr)
+		h.renderLogin(w, r, http.StatusUnauthorized, "Incorrect password")
+		return
+	}
+
+	if err := setDashboardSessionCookie(w, h.cookieStore, h.secureCookies); err != nil {
+		log.Printf("Failed to create dashboard session: %v", err)
+		http.Error(w, "Failed to log in", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, basePathFromRequest(r)+"/dashboard", http.StatusSeeOther)
+}
+
+// renderLogin renders the login page with an optional error message
+func (h *WebHandler) renderLogin(w http.ResponseWriter, r *http.Request, statusCode int, errorMessage string) {
+	data := struct {
+		Title     string
+		Error     string
+		CSRFField template.HTML
+	}{
+		Title:     "Claude Control Login",
+		Error:     errorMessage,
+		CSRFField: csrf.TemplateField(r),
+	}
+
+	w.WriteHeader(statusCode)
+	if err := h.executeTemplate(w, "login.html", data); err != nil {
+		log.Printf("Failed to render login template: %v", err)
+	}
+}
+
+// handleStatic serves embedded dashboard assets
+func (h *WebHandler) handleStatic(w http.ResponseWriter, r *http.Request) {
+	http.ServeFileFS(w, r, static.Files, mux.Vars(r)["file"])
 }
 
 // handleDashboard shows the main dashboard with pending tasks
 func (h *WebHandler) handleDashboard(w http.ResponseWriter, r *http.Request) {
-	// Get pending tasks
-	pendingTasks, err := h.taskService.GetPendingTasks(r.Context())
+	// Get pending tasks, optionally only those raised by one session
+	sessionID := r.URL.Query().Get("session_id")
+	var pendingTasks []*domain.Task
+	var err error
+	if sessionID != "" {
+		pendingTasks, err = h.taskService.GetPendingTasksForSession(r.Context(), sessionID)
+	} else {
+		pendingTasks, err = h.taskService.GetPendingTasks(r.Context())
+	}
 	if err != nil {
 		log.Printf("Failed to get pending tasks: %v", err)
 		http.Error(w, "Failed to load dashboard", http.StatusInternalServerError)
@@ -72,22 +323,128 @@ func (h *WebHandler) handleDashboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Tool usage is informational, so the dashboard still renders without it
+	toolStats, err := h.taskService.GetToolUsageStats(r.Context(), DefaultStatsWindow)
+	if err != nil {
+		log.Printf("Warning: failed to get tool usage stats: %v", err)
+	}
+
+	// Compaction frequency is informational as well
+	compacts, err := h.taskService.GetCompactStats(r.Context(), time.Now().Add(-DefaultStatsWindow))
+	if err != nil {
+		log.Printf("Warning: failed to get compact stats: %v", err)
+	}
+
+	// Badge counts are informational as well; the header hides them when they can't be loaded
+	counts, err := h.taskService.GetTaskCounts(r.Context())
+	if err != nil {
+		log.Printf("Warning: failed to get task counts: %v", err)
+	}
+
+	// The summary card is informational as well, and a session with no tasks yet simply has none
+	var sessionSummary *ports.SessionSummary
+	if sessionID != "" {
+		sessionSummary, err = h.taskService.GetSessionSummary(r.Context(), sessionID)
+		if err != nil && !errors.Is(err, services.ErrSessionNotFound) {
+			log.Printf("Warning: failed to get summary for session %s: %v", sessionID, err)
+		}
+	}
+
+	// Sessions are informational too; the webhook handler owns the session service
+	var sessions []sessionView
+	if h.webhookHandler != nil && h.webhookHandler.sessionService != nil {
+		recentSessions, err := h.webhookHandler.sessionService.ListSessions(r.Context(), DefaultSessionListLimit)
+		if err != nil {
+			log.Printf("Warning: failed to list sessions: %v", err)
+		}
+		sessions = newSessionViews(recentSessions)
+	}
+
 	data := struct {
-		PendingTasks []*domain.Task
-		RecentTasks  []*domain.Task
-		Title        string
+		PendingTasks  []*domain.Task
+		RecentTasks   []*domain.Task
+		Sessions      []sessionView
+		Counts        *services.TaskCounts
+		ToolStats     []ports.ToolUsageStat
+		Compacts      *ports.CompactStats
+		Title         string
+		HooksDisabled bool
+		SessionID     string
+		Summary       *ports.SessionSummary
 	}{
-		PendingTasks: pendingTasks,
-		RecentTasks:  recentTasks,
-		Title:        "Claude Control Dashboard",
+		PendingTasks:  pendingTasks,
+		RecentTasks:   recentTasks,
+		Sessions:      sessions,
+		Counts:        counts,
+		ToolStats:     toolStats,
+		Compacts:      compacts,
+		Title:         "Claude Control Dashboard",
+		HooksDisabled: h.settings != nil && h.settings.HooksDisabled(),
+		SessionID:     sessionID,
+		Summary:       sessionSummary,
 	}
 
-	if err := h.templates.ExecuteTemplate(w, "dashboard.html", data); err != nil {
+	if err := h.executeTemplate(w, "dashboard.html", data); err != nil {
 		log.Printf("Failed to render dashboard template: %v", err)
 		http.Error(w, "Failed to render page", http.StatusInternalServerError)
 	}
 }
 
+// handleTmuxDashboard shows the status of the tmux sessions running Claude Code
+func (h *WebHandler) handleTmuxDashboard(w http.ResponseWriter, r *http.Request) {
+	var sessions []ports.TMuxSession
+	if h.tmuxController != nil {
+		var err error
+		sessions, err = h.tmuxController.ListSessions(r.Context())
+		if err != nil {
+			log.Printf("Failed to list tmux sessions: %v", err)
+			http.Error(w, "Failed to load tmux sessions", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	data := struct {
+		Sessions         []ports.TMuxSession
+		PendingDecisions int
+		TMuxEnabled      bool
+		Title            string
+	}{
+		Sessions:         sessions,
+		PendingDecisions: h.taskService.GetActiveDecisions(),
+		TMuxEnabled:      h.tmuxController != nil,
+		Title:            "tmux Sessions",
+	}
+
+	if err := h.executeTemplate(w, "tmux.html", data); err != nil {
+		log.Printf("Failed to render tmux dashboard template: %v", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
+// handleSessionDashboard shows tasks grouped by the Claude Code session that raised them
+// Accepts the same status, hook_type, limit and offset parameters as /api/tasks.
+func (h *WebHandler) handleSessionDashboard(w http.ResponseWriter, r *http.Request) {
+	groups, err := h.taskService.GetTasksGroupedBySession(r.Context(), parseTaskFilter(r))
+	if err != nil {
+		log.Printf("Failed to get tasks by session: %v", err)
+		http.Error(w, "Failed to load sessions", http.StatusInternalServerError)
+		return
+	}
+
+	data := struct {
+		Groups []sessionTaskGroup
+		Title  string
+	}{
+		Groups: newSessionTaskGroups(groups),
+		Title:  "Tasks by Session",
+	}
+
+	if err := h.executeTemplate(w, "sessions.html", data); err != nil {
+		log.Printf("Failed to render sessions template: %v", err)
+		http.Error(w, "Failed to render page", http.StatusInternalServerError)
+	}
+}
+
 // handleTaskDetail shows detailed view of a specific task
 func (h *WebHandler) handleTaskDetail(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -107,17 +464,62 @@ func (h *WebHandler) handleTaskDetail(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Optionally compare against another task via ?compare=<taskId>
+	var compareTask *domain.Task
+	var diffs []domain.FieldDiff
+	if compareIDStr := r.URL.Query().Get("compare"); compareIDStr != "" {
+		compareTask, diffs, err = h.diffTasks(r.Context(), taskID, compareIDStr)
+		if err != nil {
+			log.Printf("Failed to diff task %s against %s: %v", taskID, compareIDStr, err)
+			if errors.Is(err, services.ErrTaskNotFound) {
+				http.Error(w, "Task not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Failed to compare tasks", http.StatusBadRequest)
+			}
+			return
+		}
+	}
+
 	data := struct {
-		Task    *domain.Task
-		History []*domain.TaskHistory
-		Title   string
+		Task        *domain.Task
+		History     []*domain.TaskHistory
+		Comments    []services.TaskComment
+		CompareTask *domain.Task
+		Diffs       []domain.FieldDiff
+		FileDiff    *domain.FileDiff
+		UnifiedDiff []diffLineView
+		Transcript  []transcript.TranscriptEntry
+		Title       string
 	}{
-		Task:    task,
-		History: history,
-		Title:   fmt.Sprintf("Task %s", taskID.String()[:8]),
+		Task:        task,
+		History:     history,
+		Comments:    services.CommentsFromHistory(history),
+		CompareTask: compareTask,
+		Diffs:       diffs,
+		Title:       fmt.Sprintf("Task %s", taskID.String()[:8]),
+	}
+	if task.HookData != nil {
+		data.FileDiff = task.HookData.FileDiff()
+	}
+
+	// The file diff is context too; most tasks aren't file edits at all
+	if h.diffs != nil && task.HookData != nil {
+		diff, err := h.diffs.Render(task.HookData)
+		if err != nil && !errors.Is(err, ErrNoFileEdit) {
+			log.Printf("Warning: failed to render file diff for task %s: %v", taskID, err)
+		}
+		data.UnifiedDiff = newDiffLineViews(diff)
 	}
 
-	if err := h.templates.ExecuteTemplate(w, "task-detail.html", data); err != nil {
+	// The transcript is context only, so the page still renders without it
+	if h.transcripts != nil && task.HookData.GetTranscriptPath() != "" {
+		data.Transcript, err = h.transcripts.ReadLast(task.HookData.GetTranscriptPath(), TranscriptTailEntries)
+		if err != nil {
+			log.Printf("Warning: failed to read transcript for task %s: %v", taskID, err)
+		}
+	}
+
+	if err := h.executeTemplate(w, "task-detail.html", data); err != nil {
 		log.Printf("Failed to render task detail template: %v", err)
 		http.Error(w, "Failed to render page", http.StatusInternalServerError)
 	}
@@ -146,6 +548,11 @@ func (h *WebHandler) handleTaskAction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := services.ValidateTaskComment(r.FormValue("comment")); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	action := domain.ActionType(actionStr)
 	responseData := map[string]interface{}{
 		"user_agent": r.Header.Get("User-Agent"),
@@ -153,6 +560,23 @@ func (h *WebHandler) handleTaskAction(w http.ResponseWriter, r *http.Request) {
 		"comment":    r.FormValue("comment"),
 	}
 
+	// Optional command substitution when approving a tool call (experimental)
+	if modifiedCommand := r.FormValue("modified_command"); modifiedCommand != "" && action == domain.ActionTypeApprove {
+		if err := domain.ValidateModifiedCommand(modifiedCommand); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		responseData["modified_command"] = modifiedCommand
+	}
+
+	// Take the action (update task in database) before waking the blocking webhook,
+	// so the waiting handler can read response data such as a modified command
+	if err := h.taskService.TakeAction(r.Context(), taskID, action, responseData); err != nil {
+		log.Printf("Failed to take action %s on task %s: %v", action, taskID, err)
+		http.Error(w, "Failed to process action", http.StatusInternalServerError)
+		return
+	}
+
 	// Check if this task has a pending decision (blocking webhook waiting)
 	if h.taskService.HasPendingDecision(taskID) {
 		// Send decision to waiting webhook handler
@@ -164,15 +588,8 @@ func (h *WebHandler) handleTaskAction(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Take the action (update task in database)
-	if err := h.taskService.TakeAction(r.Context(), taskID, action, responseData); err != nil {
-		log.Printf("Failed to take action %s on task %s: %v", action, taskID, err)
-		http.Error(w, "Failed to process action", http.StatusInternalServerError)
-		return
-	}
-
 	// Redirect back to task detail page
-	http.Redirect(w, r, fmt.Sprintf("/task/%s", taskID.String()), http.StatusSeeOther)
+	http.Redirect(w, r, fmt.Sprintf("%s/task/%s", basePathFromRequest(r), taskID.String()), http.StatusSeeOther)
 }
 
 // handleStopInput processes user input for Stop webhook tasks
@@ -226,13 +643,20 @@ func (h *WebHandler) handleStopInput(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.stopInput == nil {
+		http.Error(w, "Claude Code integration is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
 	// Update the webhook handler's stop input with user guidance
-	h.webhookHandler.SetStopInput(guidance)
+	if h.webhookHandler != nil {
+		h.webhookHandler.SetStopInput(guidance)
+	}
 
 	// Send the guidance to Claude Code via the CLI adapter
 	log.Printf("Sending user guidance '%s' to Claude Code session %s", guidance, sessionID)
 	
-	claudeResponse, err := h.webhookHandler.claudeAdapter.SendInputToStopWebhook(r.Context(), sessionID, guidance)
+	claudeResponse, err := h.stopInput.SendInputToStopWebhook(r.Context(), sessionID, guidance)
 	if err != nil {
 		log.Printf("Failed to send guidance to Claude Code: %v", err)
 		http.Error(w, "Failed to send guidance to Claude Code", http.StatusInternalServerError)
@@ -271,39 +695,12 @@ func (h *WebHandler) handleStopInput(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Redirect back to task detail page
-	http.Redirect(w, r, fmt.Sprintf("/task/%s", taskID.String()), http.StatusSeeOther)
+	http.Redirect(w, r, fmt.Sprintf("%s/task/%s", basePathFromRequest(r), taskID.String()), http.StatusSeeOther)
 }
 
-// handleListTasks returns tasks as JSON (API endpoint)
+// handleListTasks returns a page of tasks as JSON, with the total matching the filter for paging (API endpoint)
 func (h *WebHandler) handleListTasks(w http.ResponseWriter, r *http.Request) {
-	// Parse query parameters
-	filter := ports.TaskFilter{}
-	
-	if status := r.URL.Query().Get("status"); status != "" {
-		taskStatus := domain.TaskStatus(status)
-		if taskStatus.IsValid() {
-			filter.Status = &taskStatus
-		}
-	}
-	
-	if hookType := r.URL.Query().Get("hook_type"); hookType != "" {
-		if parsedHookType, err := domain.ParseHookType(hookType); err == nil {
-			filter.HookType = &parsedHookType
-		}
-	}
-	
-	if limit := r.URL.Query().Get("limit"); limit != "" {
-		if parsedLimit, err := strconv.Atoi(limit); err == nil && parsedLimit > 0 {
-			filter.Limit = parsedLimit
-		}
-	}
-	
-	if offset := r.URL.Query().Get("offset"); offset != "" {
-		if parsedOffset, err := strconv.Atoi(offset); err == nil && parsedOffset >= 0 {
-			filter.Offset = parsedOffset
-		}
-	}
-
+	filter := parseTaskFilter(r)
 	tasks, err := h.taskService.ListTasks(r.Context(), filter)
 	if err != nil {
 		log.Printf("Failed to list tasks: %v", err)
@@ -311,110 +708,1061 @@ func (h *WebHandler) handleListTasks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
-		"success": true,
-		"tasks":   tasks,
-		"count":   len(tasks),
-	})
-}
-
-// handleGetTask returns a specific task as JSON (API endpoint)
-func (h *WebHandler) handleGetTask(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	taskIDStr := vars["taskId"]
-	
-	taskID, err := uuid.Parse(taskIDStr)
+	total, err := h.taskService.CountTasks(r.Context(), filter)
 	if err != nil {
-		h.respondWithError(w, http.StatusBadRequest, "Invalid task ID")
+		log.Printf("Failed to count tasks: %v", err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to list tasks")
 		return
 	}
 
-	task, history, err := h.taskService.GetTaskWithHistory(r.Context(), taskID)
+	respondWithJSONPage(w, "tasks", tasks, domain.NewPagination(total, filter.Limit, filter.Offset, len(tasks)))
+}
+
+// handleListArchivedTasks returns archived tasks as JSON, filtered like /api/tasks (API endpoint)
+func (h *WebHandler) handleListArchivedTasks(w http.ResponseWriter, r *http.Request) {
+	tasks, err := h.taskService.ListArchivedTasks(r.Context(), parseTaskFilter(r))
 	if err != nil {
-		log.Printf("Failed to get task %s: %v", taskID, err)
-		h.respondWithError(w, http.StatusNotFound, "Task not found")
+		log.Printf("Failed to list archived tasks: %v", err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to list archived tasks")
 		return
 	}
 
-	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
-		"success": true,
-		"task":    task,
-		"history": history,
-	})
+	respondWithJSONList(w, "tasks", tasks)
 }
 
-// handleTaskActionAPI processes user actions on tasks via API
-func (h *WebHandler) handleTaskActionAPI(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	taskIDStr := vars["taskId"]
-	
-	taskID, err := uuid.Parse(taskIDStr)
+// handleExportTasks streams tasks as CSV or JSON Lines, filtered like /api/tasks and by a window such as ?since=7d (API endpoint)
+// Tasks are written newest first and the file is downloaded as an attachment; a ?limit= below the export limit lowers it.
+func (h *WebHandler) handleExportTasks(w http.ResponseWriter, r *http.Request) {
+	formatName := r.URL.Query().Get("format")
+	if formatName == "" {
+		formatName = "csv"
+	}
+	format, ok := taskExportFormats[formatName]
+	if !ok {
+		h.respondWithError(w, http.StatusBadRequest, "format must be csv or json")
+		return
+	}
+
+	window, err := parseStatsWindow(r.URL.Query().Get("since"), 0)
 	if err != nil {
-		h.respondWithError(w, http.StatusBadRequest, "Invalid task ID")
+		h.respondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	var since time.Time
+	if window > 0 {
+		since = time.Now().Add(-window)
+	}
 
-	// Parse JSON payload
-	var payload struct {
-		Action   string                 `json:"action"`
-		Response map[string]interface{} `json:"response"`
+	filter := parseTaskFilter(r)
+	maxRows := h.exportMaxRows
+	if filter.Limit > 0 && filter.Limit < maxRows {
+		maxRows = filter.Limit
 	}
-	
-	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		h.respondWithError(w, http.StatusBadRequest, "Invalid JSON payload")
+
+	controller := http.NewResponseController(w)
+	filename := fmt.Sprintf("tasks-%s.%s", time.Now().Format("2006-01-02"), format.extension)
+	w.Header().Set("Content-Type", format.contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.Header().Set("X-Export-Max-Rows", strconv.Itoa(maxRows))
+	w.WriteHeader(http.StatusOK)
+
+	// The status has been sent, so a failure part way through can only be logged; the client sees a short file
+	writer, err := format.newWriter(w, controller.Flush)
+	if err != nil {
+		log.Printf("Failed to start task export: %v", err)
 		return
 	}
+	written, err := exportTasks(r.Context(), h.taskService.ListTasks, filter, since, maxRows, writer)
+	if err != nil {
+		log.Printf("Failed to export tasks after %d rows: %v", written, err)
+	}
+}
 
-	if payload.Action == "" {
-		h.respondWithError(w, http.StatusBadRequest, "Action is required")
+// handleListSessionPendingTasks returns the pending tasks for one Claude Code session (API endpoint)
+func (h *WebHandler) handleListSessionPendingTasks(w http.ResponseWriter, r *http.Request) {
+	sessionID := mux.Vars(r)["sessionID"]
+
+	tasks, err := h.taskService.GetPendingTasksForSession(r.Context(), sessionID)
+	if err != nil {
+		log.Printf("Failed to list pending tasks for session %s: %v", sessionID, err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to list pending tasks")
 		return
 	}
 
-	action := domain.ActionType(payload.Action)
-	responseData := payload.Response
-	if responseData == nil {
-		responseData = make(map[string]interface{})
-	}
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":    true,
+		"session_id": sessionID,
+		"tasks":      tasks,
+		"count":      len(tasks),
+	})
+}
 
-	// Add metadata
-	responseData["user_agent"] = r.Header.Get("User-Agent")
-	responseData["api_request"] = true
+// handleGetSessionSummary returns task counts and the average decision time for one Claude Code session (API endpoint)
+func (h *WebHandler) handleGetSessionSummary(w http.ResponseWriter, r *http.Request) {
+	sessionID := mux.Vars(r)["sessionID"]
 
-	// Check if this task has a pending decision (blocking webhook waiting)
-	if h.taskService.HasPendingDecision(taskID) {
-		// Send decision to waiting webhook handler
-		success := h.taskService.SendDecisionToTask(taskID, action)
-		if success {
-			log.Printf("Sent decision %s to blocking webhook for task %s via API", action, taskID.String()[:8])
-		} else {
-			log.Printf("Warning: Failed to send decision to blocking webhook for task %s via API", taskID.String()[:8])
+	summary, err := h.taskService.GetSessionSummary(r.Context(), sessionID)
+	if err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			h.respondWithError(w, http.StatusNotFound, "Session not found")
+			return
 		}
-	}
-
-	// Take the action (update task in database)
-	if err := h.taskService.TakeAction(r.Context(), taskID, action, responseData); err != nil {
-		log.Printf("Failed to take action %s on task %s: %v", action, taskID, err)
-		h.respondWithError(w, http.StatusInternalServerError, "Failed to process action")
+		log.Printf("Failed to get summary for session %s: %v", sessionID, err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to get session summary")
 		return
 	}
 
 	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
 		"success": true,
-		"message": fmt.Sprintf("Action %s processed successfully", action),
+		"summary": summary,
+		"text":    summary.String(),
 	})
 }
 
-// handleHealthCheck returns server health status
-func (h *WebHandler) handleHealthCheck(w http.ResponseWriter, r *http.Request) {
+// handleTaskStats returns task totals, breakdowns and decision durations over a window such as ?since=24h (API endpoint)
+func (h *WebHandler) handleTaskStats(w http.ResponseWriter, r *http.Request) {
+	window, err := parseStatsWindow(r.URL.Query().Get("since"), DefaultTaskStatsWindow)
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	since := time.Now().Add(-window)
+	stats, err := h.taskService.GetStats(r.Context(), since)
+	if err != nil {
+		log.Printf("Failed to get task stats: %v", err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to get task stats")
+		return
+	}
+
 	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
 		"success": true,
-		"status":  "healthy",
-		"service": "claude-control-server",
+		"since":   since,
+		"stats":   stats,
 	})
 }
 
-// respondWithError sends an error response
-func (h *WebHandler) respondWithError(w http.ResponseWriter, statusCode int, message string) {
+// handleToolUsageStats returns per-tool call and decision counts over a window such as ?since=7d (API endpoint)
+func (h *WebHandler) handleToolUsageStats(w http.ResponseWriter, r *http.Request) {
+	window, err := parseStatsWindow(r.URL.Query().Get("since"), DefaultStatsWindow)
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stats, err := h.taskService.GetToolUsageStats(r.Context(), window)
+	if err != nil {
+		log.Printf("Failed to get tool usage stats: %v", err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to get tool usage stats")
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"since":   time.Now().Add(-window),
+		"tools":   stats,
+		"count":   len(stats),
+	})
+}
+
+// handleTaskCounts returns how many tasks are in each status and were raised by each hook type (API endpoint)
+func (h *WebHandler) handleTaskCounts(w http.ResponseWriter, r *http.Request) {
+	counts, err := h.taskService.GetTaskCounts(r.Context())
+	if err != nil {
+		log.Printf("Failed to get task counts: %v", err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to get task counts")
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":      true,
+		"by_status":    counts.ByStatus,
+		"by_hook_type": counts.ByHookType,
+	})
+}
+
+// handleReceiptStats returns how many hook responses Claude Code has and hasn't acknowledged (API endpoint)
+func (h *WebHandler) handleReceiptStats(w http.ResponseWriter, r *http.Request) {
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":  true,
+		"receipts": h.taskService.GetReceiptStats(),
+	})
+}
+
+// handleConcurrentSessions returns how many sessions currently have a recent pending task (API endpoint)
+func (h *WebHandler) handleConcurrentSessions(w http.ResponseWriter, r *http.Request) {
+	count, err := h.taskService.GetConcurrentSessionCount(r.Context())
+	if err != nil {
+		log.Printf("Failed to count concurrent sessions: %v", err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to count concurrent sessions")
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":                            true,
+		"claude_control_concurrent_sessions": count,
+		"window":                             services.ConcurrentSessionWindow.String(),
+	})
+}
+
+// handleActivityStats returns hourly task history activity over a window such as ?since=24h (API endpoint)
+// ?format=text renders the timeline as an ASCII bar chart instead of JSON.
+func (h *WebHandler) handleActivityStats(w http.ResponseWriter, r *http.Request) {
+	window, err := parseStatsWindow(r.URL.Query().Get("since"), DefaultActivityWindow)
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	buckets, err := h.taskService.GetHourlyActivity(r.Context(), window)
+	if err != nil {
+		log.Printf("Failed to get hourly activity: %v", err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to get activity")
+		return
+	}
+
+	now := time.Now()
+	since := now.Add(-window)
+	if r.URL.Query().Get("format") == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(renderActivityChart(buckets, since, now)))
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"since":   since,
+		"buckets": buckets,
+		"count":   len(buckets),
+	})
+}
+
+// handleCompactStats returns how often sessions were compacted over a window such as ?since=7d (API endpoint)
+func (h *WebHandler) handleCompactStats(w http.ResponseWriter, r *http.Request) {
+	window, err := parseStatsWindow(r.URL.Query().Get("since"), DefaultStatsWindow)
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	since := time.Now().Add(-window)
+	stats, err := h.taskService.GetCompactStats(r.Context(), since)
+	if err != nil {
+		log.Printf("Failed to get compact stats: %v", err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to get compact stats")
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":  true,
+		"since":    since,
+		"compacts": stats,
+	})
+}
+
+// parseTaskFilter builds a task filter from the status, hook_type, search, created_after, created_before,
+// session_id, limit and offset query parameters. Times are RFC 3339; invalid values are ignored like bad statuses.
+func parseTaskFilter(r *http.Request) ports.TaskFilter {
+	filter := ports.TaskFilter{}
+	
+	if status := r.URL.Query().Get("status"); status != "" {
+		taskStatus := domain.TaskStatus(status)
+		if taskStatus.IsValid() {
+			filter.Status = &taskStatus
+		}
+	}
+	
+	if hookType := r.URL.Query().Get("hook_type"); hookType != "" {
+		if parsedHookType, err := domain.ParseHookType(hookType); err == nil {
+			filter.HookType = &parsedHookType
+		}
+	}
+
+	if search := strings.TrimSpace(r.URL.Query().Get("search")); search != "" {
+		filter.SearchQuery = &search
+	}
+
+	if createdAfter, err := time.Parse(time.RFC3339, r.URL.Query().Get("created_after")); err == nil {
+		filter.CreatedAfter = &createdAfter
+	}
+
+	if createdBefore, err := time.Parse(time.RFC3339, r.URL.Query().Get("created_before")); err == nil {
+		filter.CreatedBefore = &createdBefore
+	}
+
+	if sessionID := r.URL.Query().Get("session_id"); sessionID != "" {
+		filter.SessionID = &sessionID
+	}
+	
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		if parsedLimit, err := strconv.Atoi(limit); err == nil && parsedLimit > 0 {
+			filter.Limit = parsedLimit
+		}
+	}
+	
+	if offset := r.URL.Query().Get("offset"); offset != "" {
+		if parsedOffset, err := strconv.Atoi(offset); err == nil && parsedOffset >= 0 {
+			filter.Offset = parsedOffset
+		}
+	}
+
+	return filter
+}
+
+// handleGetTask returns a specific task as JSON (API endpoint)
+func (h *WebHandler) handleGetTask(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	taskIDStr := vars["taskId"]
+	
+	taskID, err := uuid.Parse(taskIDStr)
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	task, history, err := h.taskService.GetTaskWithHistory(r.Context(), taskID)
+	if err != nil {
+		log.Printf("Failed to get task %s: %v", taskID, err)
+		h.respondWithError(w, http.StatusNotFound, "Task not found")
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"task":    task,
+		"history": history,
+	})
+}
+
+// handleTaskStream long-polls until the task changes status, the timeout elapses or the client disconnects (API endpoint)
+func (h *WebHandler) handleTaskStream(w http.ResponseWriter, r *http.Request) {
+	taskID, err := uuid.Parse(mux.Vars(r)["taskId"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	timeout := defaultLongPollTimeout
+	if timeoutStr := r.URL.Query().Get("timeout"); timeoutStr != "" {
+		timeout, err = time.ParseDuration(timeoutStr)
+		if err != nil || timeout <= 0 {
+			h.respondWithError(w, http.StatusBadRequest, "Invalid timeout duration")
+			return
+		}
+		if timeout > maxLongPollTimeout {
+			timeout = maxLongPollTimeout
+		}
+	}
+
+	// Stagger the first lookup so clients reconnecting together after a restart don't hit the database at once
+	select {
+	case <-time.After(time.Duration(rand.Int63n(int64(longPollStagger)))):
+	case <-r.Context().Done():
+		return
+	}
+
+	// Watch before loading the task so an update between the two isn't missed
+	updated := h.taskService.WatchTask(taskID)
+
+	task, err := h.taskService.GetTask(r.Context(), taskID)
+	if err != nil {
+		log.Printf("Failed to get task %s: %v", taskID, err)
+		h.respondWithError(w, http.StatusNotFound, "Task not found")
+		return
+	}
+
+	if task.IsActionable() {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		select {
+		case <-updated:
+			task, err = h.taskService.GetTask(r.Context(), taskID)
+			if err != nil {
+				log.Printf("Failed to reload task %s: %v", taskID, err)
+				h.respondWithError(w, http.StatusInternalServerError, "Failed to load task")
+				return
+			}
+		case <-timer.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"status":  task.Status,
+		"task":    task,
+	})
+}
+
+// handleTaskDiff returns the fields that changed between two tasks' hook data (API endpoint)
+func (h *WebHandler) handleTaskDiff(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	taskID, err := uuid.Parse(vars["taskId"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	otherTask, diffs, err := h.diffTasks(r.Context(), taskID, vars["otherTaskId"])
+	if err != nil {
+		log.Printf("Failed to diff task %s against %s: %v", taskID, vars["otherTaskId"], err)
+		if errors.Is(err, services.ErrTaskNotFound) {
+			h.respondWithError(w, http.StatusNotFound, "Task not found")
+		} else {
+			h.respondWithError(w, http.StatusBadRequest, "Failed to compare tasks")
+		}
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":       true,
+		"task_id":       taskID,
+		"other_task_id": otherTask.ID,
+		"diff":          diffs,
+	})
+}
+
+// diffTasks loads two tasks and diffs the first task's hook data against the second's
+// Either task missing is reported as services.ErrTaskNotFound.
+func (h *WebHandler) diffTasks(ctx context.Context, taskID uuid.UUID, otherTaskIDStr string) (*domain.Task, []domain.FieldDiff, error) {
+	otherTaskID, err := uuid.Parse(otherTaskIDStr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid task ID %q: %w", otherTaskIDStr, err)
+	}
+
+	task, err := h.taskService.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s: %v", services.ErrTaskNotFound, taskID, err)
+	}
+
+	otherTask, err := h.taskService.GetTask(ctx, otherTaskID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s: %v", services.ErrTaskNotFound, otherTaskID, err)
+	}
+
+	diffs, err := domain.DiffHookData(task.HookData, otherTask.HookData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return otherTask, diffs, nil
+}
+
+// handleSnoozeTask silences a task's notifications for ?duration= (default 10m) (API endpoint)
+func (h *WebHandler) handleSnoozeTask(w http.ResponseWriter, r *http.Request) {
+	taskID, err := uuid.Parse(mux.Vars(r)["taskId"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	duration := services.DefaultSnoozeDuration
+	if durationStr := r.URL.Query().Get("duration"); durationStr != "" {
+		duration, err = time.ParseDuration(durationStr)
+		if err != nil {
+			h.respondWithError(w, http.StatusBadRequest, "Invalid duration")
+			return
+		}
+	}
+
+	task, err := h.taskService.SnoozeTask(r.Context(), taskID, duration)
+	if err != nil {
+		h.respondWithSnoozeError(w, taskID, err)
+		return
+	}
+
+	log.Printf("Snoozed task %s until %s", taskID, task.SnoozedUntil.Format(time.RFC3339))
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":       true,
+		"task_id":       taskID,
+		"snoozed_until": task.SnoozedUntil,
+	})
+}
+
+// handleUnsnoozeTask resumes a snoozed task's notifications (API endpoint)
+func (h *WebHandler) handleUnsnoozeTask(w http.ResponseWriter, r *http.Request) {
+	taskID, err := uuid.Parse(mux.Vars(r)["taskId"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	if _, err := h.taskService.UnsnoozeTask(r.Context(), taskID); err != nil {
+		h.respondWithSnoozeError(w, taskID, err)
+		return
+	}
+
+	log.Printf("Unsnoozed task %s", taskID)
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"task_id": taskID,
+	})
+}
+
+// respondWithSnoozeError maps snooze failures to HTTP status codes
+func (h *WebHandler) respondWithSnoozeError(w http.ResponseWriter, taskID uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidSnoozeDuration):
+		h.respondWithError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrTaskNotActionable):
+		h.respondWithError(w, http.StatusConflict, "Only pending tasks can be snoozed")
+	default:
+		log.Printf("Failed to update snooze for task %s: %v", taskID, err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to update snooze")
+	}
+}
+
+// handleRenotifyTask re-sends a pending task's notification, at most once a minute per task (API endpoint)
+func (h *WebHandler) handleRenotifyTask(w http.ResponseWriter, r *http.Request) {
+	taskID, err := uuid.Parse(mux.Vars(r)["taskId"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	destination, err := h.taskService.RenotifyTask(r.Context(), taskID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRenotifyRateLimited):
+			w.Header().Set("Retry-After", strconv.Itoa(int(services.RenotifyInterval.Seconds())))
+			h.respondWithError(w, http.StatusTooManyRequests, "Notification was re-sent less than a minute ago")
+		case errors.Is(err, services.ErrTaskNotActionable):
+			h.respondWithError(w, http.StatusConflict, "Only pending tasks can be re-notified")
+		default:
+			log.Printf("Failed to re-send notification for task %s: %v", taskID, err)
+			h.respondWithError(w, http.StatusInternalServerError, "Failed to re-send notification")
+		}
+		return
+	}
+
+	log.Printf("Re-sent notification for task %s via %s", taskID, destination.Channel)
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"sent":    true,
+		"channel": destination.Channel,
+		"topic":   destination.Topic,
+	})
+}
+
+// handleGetTranscriptBackup returns where a PreCompact task's transcript was backed up (API endpoint)
+func (h *WebHandler) handleGetTranscriptBackup(w http.ResponseWriter, r *http.Request) {
+	taskID, err := uuid.Parse(mux.Vars(r)["taskId"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	backupPath, err := h.taskService.GetTranscriptBackupPath(r.Context(), taskID)
+	if err != nil {
+		if errors.Is(err, services.ErrTranscriptBackupNotFound) {
+			h.respondWithError(w, http.StatusNotFound, "No transcript backup for this task")
+			return
+		}
+		log.Printf("Failed to get transcript backup for task %s: %v", taskID, err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to get transcript backup")
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":     true,
+		"task_id":     taskID,
+		"backup_path": backupPath,
+	})
+}
+
+// handleTaskFileDiff returns what an Edit or Write task would change in the file on disk as a plain text unified diff (API endpoint)
+func (h *WebHandler) handleTaskFileDiff(w http.ResponseWriter, r *http.Request) {
+	if h.diffs == nil {
+		h.respondWithError(w, http.StatusNotFound, "Diff view is disabled")
+		return
+	}
+
+	taskID, err := uuid.Parse(mux.Vars(r)["taskId"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	task, err := h.taskService.GetTask(r.Context(), taskID)
+	if err != nil {
+		h.respondWithError(w, http.StatusNotFound, "Task not found")
+		return
+	}
+
+	diff, err := h.diffs.Render(task.HookData)
+	if errors.Is(err, ErrNoFileEdit) {
+		h.respondWithError(w, http.StatusNotFound, "Task is not an Edit or Write tool call")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to render file diff for task %s: %v", taskID, err)
+		h.respondWithError(w, http.StatusNotFound, "Diff could not be rendered")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(diff))
+}
+
+// handleTaskTranscript returns the last messages of the transcript of the session that raised a task (API endpoint)
+func (h *WebHandler) handleTaskTranscript(w http.ResponseWriter, r *http.Request) {
+	if h.transcripts == nil {
+		h.respondWithError(w, http.StatusNotFound, "Transcript reading is disabled")
+		return
+	}
+
+	taskID, err := uuid.Parse(mux.Vars(r)["taskId"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	task, err := h.taskService.GetTask(r.Context(), taskID)
+	if err != nil {
+		h.respondWithError(w, http.StatusNotFound, "Task not found")
+		return
+	}
+
+	transcriptPath := task.HookData.GetTranscriptPath()
+	if transcriptPath == "" {
+		h.respondWithError(w, http.StatusNotFound, "No transcript for this task")
+		return
+	}
+
+	entries, err := h.transcripts.ReadLast(transcriptPath, TranscriptTailEntries)
+	if err != nil {
+		log.Printf("Failed to read transcript for task %s: %v", taskID, err)
+		h.respondWithError(w, http.StatusNotFound, "Transcript could not be read")
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"task_id": taskID,
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
+
+// handleModifyTaskCommand sets the command to run instead of the original once the task is approved (API endpoint)
+// An empty modified_command clears a previously set override.
+func (h *WebHandler) handleModifyTaskCommand(w http.ResponseWriter, r *http.Request) {
+	taskID, err := uuid.Parse(mux.Vars(r)["taskId"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	var payload struct {
+		ModifiedCommand *string `json:"modified_command"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+	if payload.ModifiedCommand == nil {
+		h.respondWithError(w, http.StatusBadRequest, "modified_command is required")
+		return
+	}
+
+	if _, err := h.taskService.SetModifiedCommand(r.Context(), taskID, *payload.ModifiedCommand); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidModifiedCommand), errors.Is(err, services.ErrCommandNotModifiable):
+			h.respondWithError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrTaskNotActionable):
+			h.respondWithError(w, http.StatusConflict, "Only pending tasks can be modified")
+		default:
+			log.Printf("Failed to modify command for task %s: %v", taskID, err)
+			h.respondWithError(w, http.StatusInternalServerError, "Failed to modify command")
+		}
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":          true,
+		"task_id":          taskID,
+		"modified_command": *payload.ModifiedCommand,
+	})
+}
+
+// handleTaskActionAPI processes user actions on tasks via API
+func (h *WebHandler) handleTaskActionAPI(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	taskIDStr := vars["taskId"]
+	
+	taskID, err := uuid.Parse(taskIDStr)
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	// Parse JSON payload
+	var payload struct {
+		Action   string                 `json:"action"`
+		Comment  string                 `json:"comment"`
+		Response map[string]interface{} `json:"response"`
+	}
+	
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	if payload.Action == "" {
+		h.respondWithError(w, http.StatusBadRequest, "Action is required")
+		return
+	}
+
+	if err := services.ValidateTaskComment(payload.Comment); err != nil {
+		h.respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	action := domain.ActionType(payload.Action)
+	responseData := payload.Response
+	if responseData == nil {
+		responseData = make(map[string]interface{})
+	}
+	if payload.Comment != "" {
+		responseData["comment"] = payload.Comment
+	}
+
+	// Validate optional command substitution (experimental); the blocking webhook builds its
+	// response from the stored command, so it has to pass the same checks as the hook response
+	if modifiedCommand, ok := responseData["modified_command"].(string); ok && modifiedCommand != "" {
+		candidate := &domain.HookResponse{Continue: action == domain.ActionTypeApprove, ModifiedCommand: modifiedCommand}
+		if err := candidate.Validate(); err != nil {
+			h.respondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	// Add metadata
+	responseData["user_agent"] = r.Header.Get("User-Agent")
+	responseData["api_request"] = true
+
+	// Take the action (update task in database) before waking the blocking webhook,
+	// so the waiting handler can read response data such as a modified command
+	if err := h.taskService.TakeAction(r.Context(), taskID, action, responseData); err != nil {
+		log.Printf("Failed to take action %s on task %s: %v", action, taskID, err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to process action")
+		return
+	}
+
+	// Check if this task has a pending decision (blocking webhook waiting)
+	if h.taskService.HasPendingDecision(taskID) {
+		// Send decision to waiting webhook handler
+		success := h.taskService.SendDecisionToTask(taskID, action)
+		if success {
+			log.Printf("Sent decision %s to blocking webhook for task %s via API", action, taskID.String()[:8])
+		} else {
+			log.Printf("Warning: Failed to send decision to blocking webhook for task %s via API", taskID.String()[:8])
+		}
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Action %s processed successfully", action),
+	})
+}
+
+// handleQuickAction approves or rejects a task from a notification button (API endpoint)
+// ApprovalTokenMiddleware has already checked the token was signed for this task and ?action=.
+func (h *WebHandler) handleQuickAction(w http.ResponseWriter, r *http.Request) {
+	taskID, err := uuid.Parse(mux.Vars(r)["taskId"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	action := domain.ActionType(r.URL.Query().Get("action"))
+	if action != domain.ActionTypeApprove && action != domain.ActionTypeReject {
+		h.respondWithError(w, http.StatusBadRequest, "Quick actions can only approve or reject")
+		return
+	}
+
+	responseData := map[string]interface{}{
+		"user_agent":   r.Header.Get("User-Agent"),
+		"quick_action": true,
+	}
+	if err := h.taskService.TakeAction(r.Context(), taskID, action, responseData); err != nil {
+		switch {
+		case errors.Is(err, services.ErrTaskNotFound):
+			h.respondWithError(w, http.StatusNotFound, "Task not found")
+		case errors.Is(err, services.ErrTaskNotActionable):
+			h.respondWithError(w, http.StatusConflict, "Task has already been decided")
+		default:
+			log.Printf("Failed to take quick action %s on task %s: %v", action, taskID, err)
+			h.respondWithError(w, http.StatusInternalServerError, "Failed to process action")
+		}
+		return
+	}
+
+	if h.taskService.HasPendingDecision(taskID) {
+		if h.taskService.SendDecisionToTask(taskID, action) {
+			log.Printf("Sent decision %s to blocking webhook for task %s via quick action", action, taskID.String()[:8])
+		} else {
+			log.Printf("Warning: Failed to send decision to blocking webhook for task %s via quick action", taskID.String()[:8])
+		}
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Action %s processed successfully", action),
+	})
+}
+
+// handleListTaskComments returns the comments left on a task's decisions, oldest first
+func (h *WebHandler) handleListTaskComments(w http.ResponseWriter, r *http.Request) {
+	taskID, err := uuid.Parse(mux.Vars(r)["taskId"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	comments, err := h.taskService.GetTaskComments(r.Context(), taskID)
+	if err != nil {
+		log.Printf("Failed to get comments for task %s: %v", taskID, err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to get comments")
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"task_id":  taskID,
+		"comments": comments,
+		"count":    len(comments),
+	})
+}
+
+// handleBulkTaskAction takes one action on several tasks, reporting which succeeded and which failed
+func (h *WebHandler) handleBulkTaskAction(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		TaskIDs  []string               `json:"task_ids"`
+		Action   string                 `json:"action"`
+		Response map[string]interface{} `json:"response"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	if payload.Action == "" {
+		h.respondWithError(w, http.StatusBadRequest, "Action is required")
+		return
+	}
+
+	taskIDs := make([]uuid.UUID, 0, len(payload.TaskIDs))
+	for _, taskIDStr := range payload.TaskIDs {
+		taskID, err := uuid.Parse(taskIDStr)
+		if err != nil {
+			h.respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Invalid task ID: %s", taskIDStr))
+			return
+		}
+		taskIDs = append(taskIDs, taskID)
+	}
+
+	responseData := payload.Response
+	if responseData == nil {
+		responseData = make(map[string]interface{})
+	}
+
+	// A substituted command only makes sense for the one tool call it was written for
+	if _, ok := responseData["modified_command"]; ok {
+		h.respondWithError(w, http.StatusBadRequest, "modified_command can't be set on a bulk action")
+		return
+	}
+
+	// Add metadata
+	responseData["user_agent"] = r.Header.Get("User-Agent")
+	responseData["api_request"] = true
+
+	action := domain.ActionType(payload.Action)
+	succeeded, failed, err := h.taskService.BulkTakeAction(r.Context(), taskIDs, action, responseData)
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Printf("Bulk %s: %d tasks succeeded, %d failed", action, len(succeeded), len(failed))
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":   len(failed) == 0,
+		"action":    action,
+		"succeeded": succeeded,
+		"failed":    failed,
+	})
+}
+
+// handleDeleteTask deletes a finished task and its history (API endpoint)
+func (h *WebHandler) handleDeleteTask(w http.ResponseWriter, r *http.Request) {
+	taskID, err := uuid.Parse(mux.Vars(r)["taskId"])
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid task ID")
+		return
+	}
+
+	if err := h.taskService.DeleteTask(r.Context(), taskID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrTaskNotFound):
+			h.respondWithError(w, http.StatusNotFound, "Task not found")
+		case errors.Is(err, services.ErrTaskNotDeletable):
+			h.respondWithError(w, http.StatusConflict, "Only approved, rejected, completed or failed tasks can be deleted")
+		default:
+			log.Printf("Failed to delete task %s: %v", taskID, err)
+			h.respondWithError(w, http.StatusInternalServerError, "Failed to delete task")
+		}
+		return
+	}
+
+	log.Printf("Deleted task %s", taskID.String()[:8])
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"task_id": taskID,
+	})
+}
+
+// handleBulkDeleteTasks deletes each finished task in {"task_ids": [...]} (API endpoint)
+// Tasks that can't be deleted, such as pending ones, are listed under "failed" without stopping the rest.
+func (h *WebHandler) handleBulkDeleteTasks(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		TaskIDs []string `json:"task_ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		h.respondWithError(w, http.StatusBadRequest, "Invalid JSON payload")
+		return
+	}
+
+	taskIDs := make([]uuid.UUID, 0, len(payload.TaskIDs))
+	for _, taskIDStr := range payload.TaskIDs {
+		taskID, err := uuid.Parse(taskIDStr)
+		if err != nil {
+			h.respondWithError(w, http.StatusBadRequest, fmt.Sprintf("Invalid task ID: %s", taskIDStr))
+			return
+		}
+		taskIDs = append(taskIDs, taskID)
+	}
+
+	deleted, failed, err := h.taskService.BulkDeleteTasks(r.Context(), taskIDs)
+	if err != nil {
+		h.respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Printf("Bulk delete: %d tasks deleted, %d failed", len(deleted), len(failed))
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": len(failed) == 0,
+		"deleted": deleted,
+		"failed":  failed,
+	})
+}
+
+// handleKillTmuxSession terminates a tmux session (API endpoint)
+func (h *WebHandler) handleKillTmuxSession(w http.ResponseWriter, r *http.Request) {
+	if h.tmuxController == nil {
+		h.respondWithError(w, http.StatusServiceUnavailable, "tmux integration is not configured")
+		return
+	}
+
+	sessionName := mux.Vars(r)["name"]
+
+	exists, err := h.tmuxController.SessionExists(r.Context(), sessionName)
+	if err != nil {
+		log.Printf("Failed to check tmux session %s: %v", sessionName, err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to check tmux session")
+		return
+	}
+	if !exists {
+		h.respondWithError(w, http.StatusNotFound, "tmux session not found")
+		return
+	}
+
+	if err := h.tmuxController.KillSession(r.Context(), sessionName); err != nil {
+		log.Printf("Failed to kill tmux session %s: %v", sessionName, err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to kill tmux session")
+		return
+	}
+
+	log.Printf("Killed tmux session %s", sessionName)
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("tmux session %s killed", sessionName),
+	})
+}
+
+// handleTmuxScrollback returns the last lines of a tmux session's terminal, e.g. ?lines=100 (API endpoint)
+func (h *WebHandler) handleTmuxScrollback(w http.ResponseWriter, r *http.Request) {
+	if h.tmuxController == nil {
+		h.respondWithError(w, http.StatusServiceUnavailable, "tmux integration is not configured")
+		return
+	}
+
+	sessionName := mux.Vars(r)["name"]
+
+	lines := services.DefaultScrollbackLines
+	if linesStr := r.URL.Query().Get("lines"); linesStr != "" {
+		parsed, err := strconv.Atoi(linesStr)
+		if err != nil || parsed <= 0 || parsed > services.MaxScrollbackLines {
+			h.respondWithError(w, http.StatusBadRequest, fmt.Sprintf("lines must be between 1 and %d", services.MaxScrollbackLines))
+			return
+		}
+		lines = parsed
+	}
+
+	exists, err := h.tmuxController.SessionExists(r.Context(), sessionName)
+	if err != nil {
+		log.Printf("Failed to check tmux session %s: %v", sessionName, err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to check tmux session")
+		return
+	}
+	if !exists {
+		h.respondWithError(w, http.StatusNotFound, "tmux session not found")
+		return
+	}
+
+	scrollback, err := h.tmuxController.CapturePaneScrollback(r.Context(), sessionName, lines)
+	if err != nil {
+		log.Printf("Failed to capture tmux scrollback for %s: %v", sessionName, err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to capture tmux scrollback")
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":    true,
+		"session":    sessionName,
+		"lines":      lines,
+		"scrollback": scrollback,
+	})
+}
+
+// handleHealthCheck returns server health status
+func (h *WebHandler) handleHealthCheck(w http.ResponseWriter, r *http.Request) {
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"status":  "healthy",
+		"service": "claude-control-server",
+	})
+}
+
+// handleVerifyAPIKey confirms the request's API key is valid (API endpoint)
+// APIKeyMiddleware rejects invalid keys before they get here, so reaching the handler is the answer.
+func (h *WebHandler) handleVerifyAPIKey(w http.ResponseWriter, r *http.Request) {
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"valid":   true,
+	})
+}
+
+// respondWithError sends an error response
+func (h *WebHandler) respondWithError(w http.ResponseWriter, statusCode int, message string) {
 	h.respondWithJSON(w, statusCode, map[string]interface{}{
 		"success": false,
 		"error":   message,
@@ -429,4 +1777,57 @@ func (h *WebHandler) respondWithJSON(w http.ResponseWriter, statusCode int, data
 	if err := json.NewEncoder(w).Encode(data); err != nil {
 		log.Printf("Failed to encode JSON response: %v", err)
 	}
-}
\ No newline at end of file
+}
+
+// handleWebhookConfig returns the webhook handler's current configuration (API endpoint)
+func (h *WebHandler) handleWebhookConfig(w http.ResponseWriter, r *http.Request) {
+	if h.webhookHandler == nil {
+		h.respondWithError(w, http.StatusServiceUnavailable, "Webhook handler is not configured")
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"config":  h.webhookHandler.Config(),
+	})
+}
+
+// handleListClaudeSessions returns the Claude Code sessions currently running (API endpoint)
+func (h *WebHandler) handleListClaudeSessions(w http.ResponseWriter, r *http.Request) {
+	if h.claudeAdapter == nil {
+		h.respondWithError(w, http.StatusServiceUnavailable, "Claude Code integration is not configured")
+		return
+	}
+
+	sessions, err := h.claudeAdapter.GetSessionList(r.Context())
+	if err != nil {
+		log.Printf("Failed to list Claude Code sessions: %v", err)
+		h.respondWithError(w, http.StatusInternalServerError, "Failed to list Claude Code sessions")
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":  true,
+		"sessions": sessions,
+		"count":    len(sessions),
+	})
+}
+
+// handleDecisionHealth reports blocking decision waits that have been open longer than expected
+func (h *WebHandler) handleDecisionHealth(w http.ResponseWriter, r *http.Request) {
+	maxAge := DefaultBlockingHandlerTimeout
+	if maxAgeStr := r.URL.Query().Get("max_age"); maxAgeStr != "" {
+		parsed, err := time.ParseDuration(maxAgeStr)
+		if err != nil || parsed <= 0 {
+			h.respondWithError(w, http.StatusBadRequest, "Invalid max_age duration")
+			return
+		}
+		maxAge = parsed
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":      true,
+		"stuck":        h.taskService.GetStuckDecisions(maxAge),
+		"total_active": h.taskService.GetActiveDecisions(),
+	})
+}
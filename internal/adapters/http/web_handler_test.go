@@ -0,0 +1,204 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/adapters/memory"
+	"github.com/dan/claude-control/internal/adapters/response"
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/dan/claude-control/internal/core/services"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// newTestTaskService creates a task service over an in-memory task repository
+func newTestTaskService(taskRepo ports.TaskRepository) *services.TaskService {
+	return services.NewTaskService(taskRepo, memory.NewTaskHistoryRepository(), nil, response.NewHookResponseBuilder(), &services.TaskServiceConfig{})
+}
+
+func TestParseTaskFilter_DateRangeAndSession(t *testing.T) {
+	after := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2026, 10, 2, 12, 30, 0, 0, time.FixedZone("", 2*60*60))
+
+	tests := []struct {
+		name              string
+		query             string
+		expectedAfter     *time.Time
+		expectedBefore    *time.Time
+		expectedSessionID string
+	}{
+		{"No filters", "", nil, nil, ""},
+		{"Date range", "?created_after=2026-10-01T00:00:00Z&created_before=2026-10-02T12:30:00%2B02:00", &after, &before, ""},
+		{"Session", "?session_id=abc123", nil, nil, "abc123"},
+		{"Invalid times ignored", "?created_after=yesterday&created_before=2026-10-02", nil, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := parseTaskFilter(httptest.NewRequest("GET", "/api/tasks"+tt.query, nil))
+
+			if !sameTime(filter.CreatedAfter, tt.expectedAfter) {
+				t.Errorf("CreatedAfter = %v, expected %v", filter.CreatedAfter, tt.expectedAfter)
+			}
+			if !sameTime(filter.CreatedBefore, tt.expectedBefore) {
+				t.Errorf("CreatedBefore = %v, expected %v", filter.CreatedBefore, tt.expectedBefore)
+			}
+			var sessionID string
+			if filter.SessionID != nil {
+				sessionID = *filter.SessionID
+			}
+			if sessionID != tt.expectedSessionID {
+				t.Errorf("SessionID = %q, expected %q", sessionID, tt.expectedSessionID)
+			}
+		})
+	}
+}
+
+// sameTime reports whether two optional times are both unset or the same instant
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func TestHandleQuickAction_Errors(t *testing.T) {
+	pending := domain.NewTask(&domain.HookData{Type: domain.HookTypePreToolUse, Data: &domain.PreToolUseHookData{ToolName: "Bash"}})
+	h := &WebHandler{taskService: newTestTaskService(memory.NewTaskRepository(pending))}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/tasks/{taskId}/action", h.handleQuickAction).Methods("POST")
+
+	tests := []struct {
+		name           string
+		taskID         uuid.UUID
+		expectedStatus int
+	}{
+		{"First tap approves", pending.ID, http.StatusOK},
+		{"Second tap conflicts", pending.ID, http.StatusConflict},
+		{"Unknown task", uuid.New(), http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/tasks/"+tt.taskID.String()+"/action?action=approve", nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleTaskDiff_Errors(t *testing.T) {
+	first := domain.NewTask(&domain.HookData{Type: domain.HookTypePreToolUse, Data: &domain.PreToolUseHookData{ToolName: "Bash"}})
+	second := domain.NewTask(&domain.HookData{Type: domain.HookTypePreToolUse, Data: &domain.PreToolUseHookData{ToolName: "Edit"}})
+	h := &WebHandler{taskService: newTestTaskService(memory.NewTaskRepository(first, second))}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/tasks/{taskId}/diff/{otherTaskId}", h.handleTaskDiff).Methods("GET")
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{"Both tasks exist", "/api/tasks/" + first.ID.String() + "/diff/" + second.ID.String(), http.StatusOK},
+		{"Unknown task", "/api/tasks/" + uuid.New().String() + "/diff/" + second.ID.String(), http.StatusNotFound},
+		{"Unknown other task", "/api/tasks/" + first.ID.String() + "/diff/" + uuid.New().String(), http.StatusNotFound},
+		{"Invalid other task ID", "/api/tasks/" + first.ID.String() + "/diff/not-a-uuid", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleWebhookConfig(t *testing.T) {
+	webhookHandler := NewWebhookHandler(nil)
+	webhookHandler.SetStrictMode(true)
+	webhookHandler.SetWebhookSecret("secret")
+	webhookHandler.SetBodySizeLimits(BodySizeConfig{PostToolUseMaxBytes: 1 << 20})
+	webhookHandler.SetDecisionService(&waitingDecisionService{}, []domain.HookType{domain.HookTypeUserPromptSubmit, domain.HookTypePreToolUse})
+	webhookHandler.SetStopInput("run the tests again")
+
+	tests := []struct {
+		name           string
+		handler        *WebHandler
+		expectedStatus int
+		expected       *WebhookConfig
+	}{
+		{
+			name:           "Current configuration",
+			handler:        &WebHandler{webhookHandler: webhookHandler},
+			expectedStatus: http.StatusOK,
+			expected: &WebhookConfig{
+				StopInput:              "run the tests again",
+				MaxBodySize:            DefaultWebhookMaxBytes,
+				PostToolUseMaxBodySize: 1 << 20,
+				SuspiciousPatterns:     len(DefaultSuspiciousPatterns()),
+				StrictMode:             true,
+				SignaturesRequired:     true,
+				BlockingHookTypes:      []domain.HookType{domain.HookTypePreToolUse, domain.HookTypeUserPromptSubmit},
+			},
+		},
+		{
+			name:           "Defaults",
+			handler:        &WebHandler{webhookHandler: NewWebhookHandler(nil)},
+			expectedStatus: http.StatusOK,
+			expected: &WebhookConfig{
+				StopInput:              DefaultStopInput,
+				MaxBodySize:            DefaultWebhookMaxBytes,
+				PostToolUseMaxBodySize: DefaultPostToolUseMaxBytes,
+				SuspiciousPatterns:     len(DefaultSuspiciousPatterns()),
+				BlockingHookTypes:      []domain.HookType{},
+			},
+		},
+		{
+			name:           "No webhook handler",
+			handler:        &WebHandler{},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := mux.NewRouter()
+			tt.handler.RegisterRoutes(router)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/webhook/config", nil))
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expected == nil {
+				return
+			}
+
+			var response struct {
+				Success bool          `json:"success"`
+				Config  WebhookConfig `json:"config"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if !response.Success || !reflect.DeepEqual(response.Config, *tt.expected) {
+				t.Errorf("Expected config %+v, got %+v", *tt.expected, response.Config)
+			}
+		})
+	}
+}
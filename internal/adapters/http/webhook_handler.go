@@ -2,8 +2,11 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"regexp"
+	"sync"
 	"time"
 
 	"github.com/dan/claude-control/internal/core/domain"
@@ -12,22 +15,188 @@ import (
 	"github.com/gorilla/mux"
 )
 
+// IdempotencyKeyHeader identifies a webhook request so a resend of it can be answered from cache
+const IdempotencyKeyHeader = "X-Idempotency-Key"
+
+// DefaultStopInput is what GetStopInput returns until guidance is first sent to a stopped session
+const DefaultStopInput = "continue"
+
 // WebhookHandler handles Claude Code webhook requests with validation
 type WebhookHandler struct {
 	sessionService ports.SessionService
+	settings       ports.ServerSettingsService // Optional - hooks are always processed when nil
+	receipts       ports.ReceiptRecorder       // Optional - POST /webhook/receipt returns 404 when nil
+	webhookSecret  []byte                      // Optional - webhook signatures are not checked when empty
+	idempotency    ports.IdempotencyCache      // Optional - X-Idempotency-Key is ignored when nil
+	rateLimit      mux.MiddlewareFunc          // Optional - webhooks are not rate limited when nil
+	forwarding     mux.MiddlewareFunc          // Optional - webhooks are not forwarded when nil
+	decisions      ports.HookDecisionService   // Optional - every webhook is answered straight away when nil
+	blockingHooks  map[domain.HookType]bool
+	bodySizes      BodySizeConfig
+
+	// PreToolUse commands matching a suspicious pattern are logged, or rejected in strict mode
+	suspiciousPatterns []*regexp.Regexp
+	strictMode         bool
+
+	stopInputMu sync.RWMutex
+	stopInput   string // Guidance last sent to a stopped session
+
+	// HookTypeAliases maps older hook names such as pre_tool_use to their hook type
+	HookTypeAliases map[string]domain.HookType
 }
 
 // NewWebhookHandler creates a new webhook handler
 func NewWebhookHandler(sessionService ports.SessionService) *WebhookHandler {
 	return &WebhookHandler{
-		sessionService: sessionService,
+		sessionService:     sessionService,
+		bodySizes:          DefaultBodySizeConfig(),
+		suspiciousPatterns: DefaultSuspiciousPatterns(),
+		stopInput:          DefaultStopInput,
+		HookTypeAliases:    DefaultHookTypeAliases(),
+	}
+}
+
+// GetStopInput returns the guidance last sent to a stopped Claude Code session
+func (h *WebhookHandler) GetStopInput() string {
+	h.stopInputMu.RLock()
+	defer h.stopInputMu.RUnlock()
+	return h.stopInput
+}
+
+// SetStopInput records the guidance sent to a stopped Claude Code session
+func (h *WebhookHandler) SetStopInput(stopInput string) {
+	h.stopInputMu.Lock()
+	defer h.stopInputMu.Unlock()
+	h.stopInput = stopInput
+}
+
+// SetStrictMode rejects suspicious or overlong PreToolUse commands with 400 instead of only logging them
+func (h *WebhookHandler) SetStrictMode(strictMode bool) {
+	h.strictMode = strictMode
+}
+
+// WebhookConfig is the webhook handler's current configuration, as served by GET /api/webhook/config
+type WebhookConfig struct {
+	StopInput              string            `json:"stop_input"`
+	MaxBodySize            int64             `json:"max_body_size"`
+	PostToolUseMaxBodySize int64             `json:"post_tool_use_max_body_size"`
+	SuspiciousPatterns     int               `json:"suspicious_patterns"`
+	StrictMode             bool              `json:"strict_mode"`
+	SignaturesRequired     bool              `json:"signatures_required"`
+	BlockingHookTypes      []domain.HookType `json:"blocking_hook_types"`
+}
+
+// Config returns the handler's current configuration
+func (h *WebhookHandler) Config() WebhookConfig {
+	config := WebhookConfig{
+		StopInput:              h.GetStopInput(),
+		MaxBodySize:            h.bodySizes.limitFor(domain.HookTypePreToolUse),
+		PostToolUseMaxBodySize: h.bodySizes.limitFor(domain.HookTypePostToolUse),
+		SuspiciousPatterns:     len(h.suspiciousPatterns),
+		StrictMode:             h.strictMode,
+		SignaturesRequired:     len(h.webhookSecret) > 0,
+		BlockingHookTypes:      []domain.HookType{},
+	}
+	if h.decisions != nil {
+		for _, hookType := range domain.AllHookTypes() {
+			if h.blockingHooks[hookType] {
+				config.BlockingHookTypes = append(config.BlockingHookTypes, hookType)
+			}
+		}
+	}
+	return config
+}
+
+// SetBodySizeLimits changes how large webhook request bodies may be for each hook type
+func (h *WebhookHandler) SetBodySizeLimits(bodySizes BodySizeConfig) {
+	h.bodySizes = bodySizes
+}
+
+// SetServerSettings lets operators disable hook processing at runtime
+func (h *WebhookHandler) SetServerSettings(settings ports.ServerSettingsService) {
+	h.settings = settings
+}
+
+// SetWebhookSecret requires every /webhook/ request to carry an X-Claude-Signature made with secret
+func (h *WebhookHandler) SetWebhookSecret(secret string) {
+	h.webhookSecret = []byte(secret)
+}
+
+// SetReceiptRecorder enables POST /webhook/receipt for Claude Code to acknowledge hook responses
+func (h *WebhookHandler) SetReceiptRecorder(receipts ports.ReceiptRecorder) {
+	h.receipts = receipts
+}
+
+// SetIdempotencyCache replays the cached response to webhooks resent with the same X-Idempotency-Key
+func (h *WebhookHandler) SetIdempotencyCache(cache ports.IdempotencyCache) {
+	h.idempotency = cache
+}
+
+// SetRateLimit limits how often each client may call the /webhook/ routes, e.g. with RateLimitMiddleware
+func (h *WebhookHandler) SetRateLimit(rateLimit mux.MiddlewareFunc) {
+	h.rateLimit = rateLimit
+}
+
+// SetForwarding sends a copy of every /webhook/ request on elsewhere, e.g. with ForwardingMiddleware
+// Forwarding sits inside the rate limit and body size limit, so only webhooks this server accepts for reading are copied.
+func (h *WebhookHandler) SetForwarding(forwarding mux.MiddlewareFunc) {
+	h.forwarding = forwarding
+}
+
+// SetDecisionService holds webhooks of the blocking hook types open until the user decides on the task
+// Other hook types are still answered straight away.
+func (h *WebhookHandler) SetDecisionService(decisions ports.HookDecisionService, blockingHooks []domain.HookType) {
+	h.decisions = decisions
+	h.blockingHooks = make(map[domain.HookType]bool, len(blockingHooks))
+	for _, hookType := range blockingHooks {
+		h.blockingHooks[hookType] = true
 	}
 }
 
+// isBlocking reports whether webhooks of the hook type wait for the user's decision
+func (h *WebhookHandler) isBlocking(hookType domain.HookType) bool {
+	return h.decisions != nil && h.blockingHooks[hookType]
+}
+
 // RegisterRoutes registers webhook routes with the router
 func (h *WebhookHandler) RegisterRoutes(router *mux.Router) {
+	// Delivery receipts; registered first so "receipt" isn't taken for a hook type
+	router.Handle("/webhook/receipt", h.withSignatureCheck(h.handleReceipt)).Methods("POST")
+
 	// Generic webhook handler for all hook types
-	router.HandleFunc("/webhook/{hookType}", h.handleWebhook).Methods("POST")
+	router.Handle("/webhook/{hookType}", h.withSignatureCheck(h.handleWebhook)).Methods("POST")
+
+	// Session lookup
+	router.HandleFunc("/api/sessions", h.handleGetSessions).Methods("GET")
+}
+
+// withSignatureCheck wraps a webhook handler with signature verification once a webhook secret is set
+// The rate limit is checked before anything is read, then the body size limit is applied, since
+// verification reads the whole body.
+func (h *WebhookHandler) withSignatureCheck(handler http.HandlerFunc) http.Handler {
+	checked := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, h.bodySizeLimit(r))
+		verified := HMACVerificationMiddleware(h.webhookSecret)(handler)
+		if h.forwarding != nil {
+			verified = h.forwarding(verified)
+		}
+		verified.ServeHTTP(w, r)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.rateLimit != nil {
+			h.rateLimit(checked).ServeHTTP(w, r)
+			return
+		}
+		checked.ServeHTTP(w, r)
+	})
+}
+
+// bodySizeLimit returns the body size limit for the hook type in the request path
+// Unknown hook types get the default limit and are rejected by the handler.
+func (h *WebhookHandler) bodySizeLimit(r *http.Request) int64 {
+	hookType, _, _ := h.resolveHookType(mux.Vars(r)["hookType"])
+	return h.bodySizes.limitFor(hookType)
 }
 
 // parseSessionEvent parses the incoming webhook request directly into a session event
@@ -37,6 +206,14 @@ func (h *WebhookHandler) parseSessionEvent(r *http.Request, hookType domain.Hook
 		return nil, err
 	}
 
+	// Older Claude Code versions name the hook under a different key or spelling
+	if key, name := hookEventName(rawData); key != "" {
+		if bodyHookType, aliased, err := h.resolveHookType(name); err == nil && (aliased || key != "hook_event_name") {
+			log.Printf("Deprecated: %s webhook sent %s=%q; expected hook_event_name=%q", hookType, key, name, bodyHookType)
+			rawData["hook_event_name"] = bodyHookType.String()
+		}
+	}
+
 	// Extract required fields
 	sessionID, _ := rawData["session_id"].(string)
 	cwd, _ := rawData["cwd"].(string)
@@ -62,28 +239,173 @@ func (h *WebhookHandler) respondWithJSON(w http.ResponseWriter, statusCode int,
 
 // handleWebhook handles all webhook events generically
 func (h *WebhookHandler) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	// During maintenance every hook is let through untouched
+	if h.settings != nil && h.settings.HooksDisabled() {
+		h.respondWithJSON(w, http.StatusOK, &domain.HookResponse{Continue: true})
+		return
+	}
+
 	vars := mux.Vars(r)
 	hookTypeStr := vars["hookType"]
 	
-	hookType, err := domain.ParseHookType(hookTypeStr)
+	hookType, aliased, err := h.resolveHookType(hookTypeStr)
 	if err != nil {
 		log.Printf("Unknown hook type: %s", hookTypeStr)
 		h.respondWithJSON(w, http.StatusBadRequest, map[string]string{"error": "Unknown hook type"})
 		return
 	}
+	if aliased {
+		log.Printf("Deprecated: hook type alias %q used for %s; update hooks.json to /webhook/%s", hookTypeStr, hookType, hookType)
+	}
+
+	// A resent hook gets the response it was first given without being recorded again
+	idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+	if idempotencyKey != "" && h.idempotency != nil {
+		if cached, ok := h.idempotency.Get(idempotencyKey); ok {
+			log.Printf("Replaying cached response for %s webhook with idempotency key %s", hookType, idempotencyKey)
+			h.respondWithJSON(w, http.StatusOK, cached)
+			return
+		}
+	}
 
 	event, err := h.parseSessionEvent(r, hookType)
 	if err != nil {
 		log.Printf("Failed to parse %s webhook: %v", hookTypeStr, err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondWithPayloadTooLarge(w, maxBytesErr.Limit)
+			return
+		}
 		h.respondWithJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request"})
 		return
 	}
 
+	if hookType == domain.HookTypePreToolUse {
+		if err := h.checkCommand(event); err != nil {
+			log.Printf("Rejected PreToolUse webhook for session %s: %v", event.SessionID, err)
+			h.respondWithJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+	}
+
 	if err := h.sessionService.AppendEvent(r.Context(), event.SessionID, event); err != nil {
 		log.Printf("Failed to append %s event: %v", hookTypeStr, err)
 	}
 
-	// Determine response based on hook type
-	suppressOutput := hookType == domain.HookTypeStop || hookType == domain.HookTypeSubagentStop
-	h.respondWithJSON(w, http.StatusOK, &domain.HookResponse{Continue: true, SuppressOutput: suppressOutput})
+	// Link subagents to their parent session so later events can be traced back
+	if subagentID := event.GetSubagentID(); subagentID != "" {
+		if err := h.sessionService.RecordSubagent(r.Context(), event.SessionID, subagentID); err != nil {
+			log.Printf("Warning: Failed to record subagent %s for session %s: %v", subagentID, event.SessionID, err)
+		}
+	}
+
+	if h.isBlocking(hookType) {
+		h.handleBlockingWebhook(w, r, event, idempotencyKey)
+		return
+	}
+	h.handleNonBlockingWebhook(w, event, idempotencyKey)
+}
+
+// handleNonBlockingWebhook lets Claude Code carry on straight away
+func (h *WebhookHandler) handleNonBlockingWebhook(w http.ResponseWriter, event *domain.SessionEvent, idempotencyKey string) {
+	suppressOutput := event.HookType == domain.HookTypeStop || event.HookType == domain.HookTypeSubagentStop
+	h.respondWithHookResponse(w, &domain.HookResponse{Continue: true, SuppressOutput: suppressOutput}, idempotencyKey)
+}
+
+// handleBlockingWebhook creates a task for the event and holds the request open until the user decides on it,
+// or the hook type's decision timeout passes
+func (h *WebhookHandler) handleBlockingWebhook(w http.ResponseWriter, r *http.Request, event *domain.SessionEvent, idempotencyKey string) {
+	hookData, err := event.HookData()
+	if err != nil {
+		log.Printf("Failed to read %s webhook for a decision: %v", event.HookType, err)
+		h.respondWithJSON(w, http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+		return
+	}
+
+	timeout := h.decisions.GetTimeoutForHookType(event.HookType)
+	response, err := h.decisions.CreateTaskAndWaitForDecision(r.Context(), hookData, timeout)
+	if err != nil {
+		log.Printf("Failed to create task for %s webhook: %v", event.HookType, err)
+		h.respondWithJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to create task"})
+		return
+	}
+	h.respondWithHookResponse(w, response, idempotencyKey)
+}
+
+// respondWithHookResponse sends a hook response, caching it so a resend with the same idempotency key gets it too
+func (h *WebhookHandler) respondWithHookResponse(w http.ResponseWriter, response *domain.HookResponse, idempotencyKey string) {
+	if idempotencyKey != "" && h.idempotency != nil {
+		h.idempotency.Set(idempotencyKey, response)
+	}
+	h.respondWithJSON(w, http.StatusOK, response)
+}
+
+// handleGetSessions lists recent sessions, most recently active first, or with subagent_id returns
+// that subagent's parent session
+func (h *WebhookHandler) handleGetSessions(w http.ResponseWriter, r *http.Request) {
+	subagentID := r.URL.Query().Get("subagent_id")
+	if subagentID == "" {
+		h.handleListSessions(w, r)
+		return
+	}
+
+	session, err := h.sessionService.GetParentSession(r.Context(), subagentID)
+	if err != nil {
+		log.Printf("Failed to find parent session for subagent %s: %v", subagentID, err)
+		h.respondWithJSON(w, http.StatusNotFound, map[string]string{"error": "Session not found"})
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success": true,
+		"session": session,
+	})
+}
+
+// handleListSessions lists recent sessions with whether each is still active
+func (h *WebhookHandler) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := h.sessionService.ListSessions(r.Context(), parseSessionListLimit(r))
+	if err != nil {
+		log.Printf("Failed to list sessions: %v", err)
+		h.respondWithJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to list sessions"})
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{
+		"success":  true,
+		"sessions": newSessionViews(sessions),
+	})
+}
+
+// handleReceipt records that Claude Code processed a hook response, identified by its receipt token
+func (h *WebhookHandler) handleReceipt(w http.ResponseWriter, r *http.Request) {
+	if h.receipts == nil {
+		h.respondWithJSON(w, http.StatusNotFound, map[string]string{"error": "Receipts are not enabled"})
+		return
+	}
+
+	var payload struct {
+		ReceiptToken string `json:"receipt_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.ReceiptToken == "" {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			respondWithPayloadTooLarge(w, maxBytesErr.Limit)
+			return
+		}
+		h.respondWithJSON(w, http.StatusBadRequest, map[string]string{"error": "receipt_token is required"})
+		return
+	}
+
+	if err := h.receipts.AcknowledgeReceipt(r.Context(), payload.ReceiptToken); err != nil {
+		if errors.Is(err, ports.ErrUnknownReceipt) {
+			h.respondWithJSON(w, http.StatusNotFound, map[string]string{"error": "Unknown or expired receipt"})
+			return
+		}
+		log.Printf("Failed to record receipt: %v", err)
+		h.respondWithJSON(w, http.StatusInternalServerError, map[string]string{"error": "Failed to record receipt"})
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, map[string]interface{}{"success": true})
 }
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+// DefaultMaxCommandLength is the longest PreToolUse command strict mode lets through
+const DefaultMaxCommandLength = 5000
+
+// defaultSuspiciousPatterns match commands that are rarely what the user meant Claude to run
+var defaultSuspiciousPatterns = []string{
+	`\brm\s+-[a-zA-Z]*[rR][a-zA-Z]*\s+(/|~)`,
+	`\b(curl|wget)\b[^|]*\|\s*(sudo\s+)?(ba|z)?sh\b`,
+	`\bmkfs(\.\w+)?\b`,
+	`\bdd\s+[^|]*\bof=/dev/`,
+	`\bchmod\s+(-R\s+)?777\s+/`,
+	`:\(\)\s*\{\s*:\s*\|\s*:\s*&\s*\}\s*;\s*:`,
+}
+
+// DefaultSuspiciousPatterns returns the patterns PreToolUse commands are checked against
+func DefaultSuspiciousPatterns() []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, 0, len(defaultSuspiciousPatterns))
+	for _, pattern := range defaultSuspiciousPatterns {
+		patterns = append(patterns, regexp.MustCompile(pattern))
+	}
+	return patterns
+}
+
+// checkCommand logs a PreToolUse command that matches a suspicious pattern
+// In strict mode the command is rejected instead, as is any command longer than DefaultMaxCommandLength.
+func (h *WebhookHandler) checkCommand(event *domain.SessionEvent) error {
+	hookData, err := event.HookData()
+	if err != nil {
+		return nil
+	}
+	command := hookData.GetCommand()
+
+	if h.strictMode && len(command) > DefaultMaxCommandLength {
+		return fmt.Errorf("command is %d bytes, longer than the %d allowed", len(command), DefaultMaxCommandLength)
+	}
+
+	for _, pattern := range h.suspiciousPatterns {
+		if !pattern.MatchString(command) {
+			continue
+		}
+		if h.strictMode {
+			return fmt.Errorf("command matches suspicious pattern %s", pattern)
+		}
+		log.Printf("Warning: suspicious PreToolUse command for session %s (matches %s): %s", event.SessionID, pattern, truncateString(command, 200))
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/services"
+	"github.com/gorilla/mux"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// WebSocketPingInterval is how often an idle task event connection gets a keepalive message
+	// so proxies don't close it
+	WebSocketPingInterval = 30 * time.Second
+
+	// webSocketWriteTimeout bounds how long sending one message to a client may take
+	webSocketWriteTimeout = 10 * time.Second
+)
+
+// TaskEventSubscriber publishes task creations and updates
+type TaskEventSubscriber interface {
+	SubscribeTaskEvents() (<-chan services.TaskEvent, func())
+}
+
+// WebSocketHandler pushes task events to dashboard clients over WebSocket
+type WebSocketHandler struct {
+	events       TaskEventSubscriber
+	pingInterval time.Duration
+}
+
+// webSocketPing is the keepalive message sent on idle connections
+type webSocketPing struct {
+	Type string `json:"type"`
+}
+
+// NewWebSocketHandler creates a handler streaming the subscriber's task events
+func NewWebSocketHandler(events TaskEventSubscriber) *WebSocketHandler {
+	return &WebSocketHandler{
+		events:       events,
+		pingInterval: WebSocketPingInterval,
+	}
+}
+
+// RegisterRoutes registers the task event WebSocket with the router
+func (h *WebSocketHandler) RegisterRoutes(router *mux.Router) {
+	router.Handle("/ws/tasks", h).Methods("GET")
+}
+
+// ServeHTTP upgrades the request to a WebSocket and streams task events until the client disconnects
+func (h *WebSocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server := websocket.Server{
+		Handshake: checkWebSocketOrigin,
+		Handler:   h.streamTaskEvents,
+	}
+	server.ServeHTTP(w, r)
+}
+
+// checkWebSocketOrigin rejects browser connections opened by pages on another site
+// The dashboard session cookie is sent with cross-site WebSocket requests, so the origin is the only guard.
+func checkWebSocketOrigin(config *websocket.Config, r *http.Request) error {
+	origin, err := websocket.Origin(config, r)
+	if err != nil {
+		return err
+	}
+	if origin != nil && origin.Host != r.Host {
+		return fmt.Errorf("cross-origin WebSocket from %s rejected", origin.Host)
+	}
+	config.Origin = origin
+	return nil
+}
+
+// streamTaskEvents sends each task event to the client as JSON
+func (h *WebSocketHandler) streamTaskEvents(ws *websocket.Conn) {
+	events, unsubscribe := h.events.SubscribeTaskEvents()
+	defer unsubscribe()
+
+	// Clients never send anything; reading only detects when they go away
+	disconnected := make(chan struct{})
+	go func() {
+		io.Copy(io.Discard, ws)
+		close(disconnected)
+	}()
+
+	ping := time.NewTicker(h.pingInterval)
+	defer ping.Stop()
+
+	for {
+		var message interface{}
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			message = event
+		case <-ping.C:
+			message = webSocketPing{Type: "ping"}
+		case <-disconnected:
+			return
+		}
+
+		ws.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout))
+		if err := websocket.JSON.Send(ws, message); err != nil {
+			log.Printf("Closing task event WebSocket for %s: %v", ws.Request().RemoteAddr, err)
+			return
+		}
+	}
+}
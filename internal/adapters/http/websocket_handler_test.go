@@ -0,0 +1,110 @@
+package http
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/services"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"golang.org/x/net/websocket"
+)
+
+// broadcasterSubscriber serves task events from an in-memory broadcaster
+type broadcasterSubscriber struct {
+	*services.EventBroadcaster[services.TaskEvent]
+}
+
+func (s broadcasterSubscriber) SubscribeTaskEvents() (<-chan services.TaskEvent, func()) {
+	return s.Subscribe()
+}
+
+// newWebSocketTestServer starts a server with the task event WebSocket and returns its ws:// URL and origin
+func newWebSocketTestServer(t *testing.T, handler *WebSocketHandler) (string, string) {
+	router := mux.NewRouter()
+	handler.RegisterRoutes(router)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/tasks", server.URL
+}
+
+// waitForSubscribers waits until the broadcaster has n subscribers
+func waitForSubscribers(t *testing.T, broadcaster *services.EventBroadcaster[services.TaskEvent], n int) {
+	deadline := time.Now().Add(2 * time.Second)
+	for broadcaster.Subscribers() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d subscribers, got %d", n, broadcaster.Subscribers())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWebSocketHandler_StreamsTaskEvents(t *testing.T) {
+	broadcaster := services.NewEventBroadcaster[services.TaskEvent](services.DefaultEventSubscriberBuffer)
+	url, origin := newWebSocketTestServer(t, NewWebSocketHandler(broadcasterSubscriber{broadcaster}))
+
+	ws, err := websocket.Dial(url, "", origin)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer ws.Close()
+	waitForSubscribers(t, broadcaster, 1)
+
+	task := &domain.Task{ID: uuid.New(), HookType: domain.HookTypePreToolUse, Status: domain.TaskStatusPending}
+	broadcaster.Publish(services.TaskEvent{Type: services.TaskEventCreated, Task: task})
+
+	var received struct {
+		Type string `json:"type"`
+		Task struct {
+			ID uuid.UUID
+		} `json:"task"`
+	}
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if err := websocket.JSON.Receive(ws, &received); err != nil {
+		t.Fatalf("Failed to receive event: %v", err)
+	}
+	if received.Type != string(services.TaskEventCreated) || received.Task.ID != task.ID {
+		t.Errorf("Expected created event for task %s, got %+v", task.ID, received)
+	}
+
+	ws.Close()
+	waitForSubscribers(t, broadcaster, 0)
+}
+
+func TestWebSocketHandler_Ping(t *testing.T) {
+	broadcaster := services.NewEventBroadcaster[services.TaskEvent](services.DefaultEventSubscriberBuffer)
+	handler := NewWebSocketHandler(broadcasterSubscriber{broadcaster})
+	handler.pingInterval = 10 * time.Millisecond
+	url, origin := newWebSocketTestServer(t, handler)
+
+	ws, err := websocket.Dial(url, "", origin)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer ws.Close()
+
+	var received webSocketPing
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if err := websocket.JSON.Receive(ws, &received); err != nil {
+		t.Fatalf("Failed to receive ping: %v", err)
+	}
+	if received.Type != "ping" {
+		t.Errorf("Expected a ping, got %+v", received)
+	}
+}
+
+func TestWebSocketHandler_RejectsCrossOrigin(t *testing.T) {
+	broadcaster := services.NewEventBroadcaster[services.TaskEvent](services.DefaultEventSubscriberBuffer)
+	url, _ := newWebSocketTestServer(t, NewWebSocketHandler(broadcasterSubscriber{broadcaster}))
+
+	if ws, err := websocket.Dial(url, "", "http://evil.example.com"); err == nil {
+		ws.Close()
+		t.Fatal("Expected a cross-origin connection to be rejected")
+	}
+	if broadcaster.Subscribers() != 0 {
+		t.Errorf("Expected no subscribers after a rejected connection, got %d", broadcaster.Subscribers())
+	}
+}
@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+var _ ports.TaskHistoryRepository = (*TaskHistoryRepository)(nil)
+
+// TaskHistoryRepository implements the TaskHistoryRepository port in memory
+type TaskHistoryRepository struct {
+	entries sync.Map // uuid.UUID -> *domain.TaskHistory
+}
+
+// NewTaskHistoryRepository creates an empty in-memory task history repository
+func NewTaskHistoryRepository() *TaskHistoryRepository {
+	return &TaskHistoryRepository{}
+}
+
+// Create stores a new task history entry
+func (r *TaskHistoryRepository) Create(ctx context.Context, history *domain.TaskHistory) error {
+	if _, exists := r.entries.LoadOrStore(history.ID, copyHistory(history)); exists {
+		return fmt.Errorf("failed to create task history: entry already exists: %s", history.ID)
+	}
+	return nil
+}
+
+// GetByTaskID retrieves all history entries for a task, oldest first
+func (r *TaskHistoryRepository) GetByTaskID(ctx context.Context, taskID uuid.UUID) ([]*domain.TaskHistory, error) {
+	return r.List(ctx, ports.TaskHistoryFilter{TaskID: &taskID, SortBy: "created_at", SortOrder: "asc"})
+}
+
+// List retrieves history entries with optional filtering, newest first unless the filter sorts oldest first
+func (r *TaskHistoryRepository) List(ctx context.Context, filter ports.TaskHistoryFilter) ([]*domain.TaskHistory, error) {
+	if filter.SortBy != "" && filter.SortBy != "created_at" {
+		return nil, fmt.Errorf("failed to list task history: cannot sort by %q", filter.SortBy)
+	}
+	descending := filter.SortBy == "" || filter.SortOrder == "desc"
+
+	var histories []*domain.TaskHistory
+	r.entries.Range(func(_, value any) bool {
+		history := value.(*domain.TaskHistory)
+		if filter.TaskID != nil && history.TaskID != *filter.TaskID {
+			return true
+		}
+		if filter.Action != nil && history.Action != *filter.Action {
+			return true
+		}
+		histories = append(histories, copyHistory(history))
+		return true
+	})
+
+	sort.Slice(histories, func(i, j int) bool {
+		a, b := histories[i], histories[j]
+		if descending {
+			a, b = b, a
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	})
+
+	if filter.Offset > 0 {
+		if filter.Offset >= len(histories) {
+			return nil, nil
+		}
+		histories = histories[filter.Offset:]
+	}
+	if filter.Limit > 0 && filter.Limit < len(histories) {
+		histories = histories[:filter.Limit]
+	}
+	return histories, nil
+}
+
+// DeleteOlderThan removes history entries older than the given number of days and returns how many were deleted
+func (r *TaskHistoryRepository) DeleteOlderThan(ctx context.Context, days int) (int64, error) {
+	cutoff := time.Now().AddDate(0, 0, -days)
+
+	var deleted int64
+	r.entries.Range(func(key, value any) bool {
+		if value.(*domain.TaskHistory).CreatedAt.Before(cutoff) && r.entries.CompareAndDelete(key, value) {
+			deleted++
+		}
+		return true
+	})
+	return deleted, nil
+}
+
+// GetHourlyActivity counts history events per hour since the given time, oldest hour first
+// Hours without any events are omitted.
+func (r *TaskHistoryRepository) GetHourlyActivity(ctx context.Context, since time.Time) ([]ports.HourlyBucket, error) {
+	byHour := make(map[time.Time]*ports.HourlyBucket)
+	r.entries.Range(func(_, value any) bool {
+		history := value.(*domain.TaskHistory)
+		if history.CreatedAt.Before(since) {
+			return true
+		}
+
+		hour := history.CreatedAt.Truncate(time.Hour)
+		bucket, ok := byHour[hour]
+		if !ok {
+			bucket = &ports.HourlyBucket{Hour: hour}
+			byHour[hour] = bucket
+		}
+		bucket.EventCount++
+		if history.Action != domain.HistoryActionCreated && history.Action != domain.HistoryActionNotified {
+			bucket.ActionsTaken++
+		}
+		return true
+	})
+
+	buckets := make([]ports.HourlyBucket, 0, len(byHour))
+	for _, bucket := range byHour {
+		buckets = append(buckets, *bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].Hour.Before(buckets[j].Hour)
+	})
+	return buckets, nil
+}
+
+// copyHistory returns a copy of the history entry that shares no mutable fields with it
+func copyHistory(history *domain.TaskHistory) *domain.TaskHistory {
+	copied := *history
+	if history.Data != nil {
+		copied.Data = make(map[string]interface{}, len(history.Data))
+		for key, value := range history.Data {
+			copied.Data[key] = value
+		}
+	}
+	return &copied
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+func TestTaskHistoryRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewTaskHistoryRepository()
+	taskID := uuid.New()
+	hour := time.Now().Truncate(time.Hour)
+
+	entries := []*domain.TaskHistory{
+		{ID: uuid.New(), TaskID: taskID, Action: domain.HistoryActionCreated, CreatedAt: hour.Add(-50 * time.Minute)},
+		{ID: uuid.New(), TaskID: taskID, Action: domain.HistoryActionNotified, CreatedAt: hour.Add(-40 * time.Minute)},
+		{ID: uuid.New(), TaskID: taskID, Action: string(domain.ActionTypeApprove), CreatedAt: hour.Add(5 * time.Minute)},
+		{ID: uuid.New(), TaskID: uuid.New(), Action: domain.HistoryActionCreated, CreatedAt: hour.AddDate(0, 0, -10)},
+	}
+	for _, entry := range entries {
+		if err := repo.Create(ctx, entry); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	if err := repo.Create(ctx, entries[0]); err == nil {
+		t.Error("Expected creating an entry twice to fail")
+	}
+
+	forTask, err := repo.GetByTaskID(ctx, taskID)
+	if err != nil {
+		t.Fatalf("GetByTaskID failed: %v", err)
+	}
+	if len(forTask) != 3 || forTask[0].Action != domain.HistoryActionCreated || forTask[2].Action != string(domain.ActionTypeApprove) {
+		t.Errorf("Expected the task's 3 entries oldest first, got %+v", forTask)
+	}
+
+	created := domain.HistoryActionCreated
+	listed, err := repo.List(ctx, ports.TaskHistoryFilter{Action: &created, Limit: 1})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != entries[0].ID {
+		t.Errorf("Expected the newest created entry, got %+v", listed)
+	}
+
+	buckets, err := repo.GetHourlyActivity(ctx, hour.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetHourlyActivity failed: %v", err)
+	}
+	expected := []ports.HourlyBucket{
+		{Hour: hour.Add(-time.Hour), EventCount: 2, ActionsTaken: 0},
+		{Hour: hour, EventCount: 1, ActionsTaken: 1},
+	}
+	if len(buckets) != len(expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, buckets)
+	}
+	for i := range expected {
+		if !buckets[i].Hour.Equal(expected[i].Hour) || buckets[i].EventCount != expected[i].EventCount || buckets[i].ActionsTaken != expected[i].ActionsTaken {
+			t.Errorf("Bucket %d: expected %+v, got %+v", i, expected[i], buckets[i])
+		}
+	}
+
+	deleted, err := repo.DeleteOlderThan(ctx, 7)
+	if err != nil {
+		t.Fatalf("DeleteOlderThan failed: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Expected the 10 day old entry to be deleted, got %d deleted", deleted)
+	}
+}
@@ -0,0 +1,509 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+var _ ports.TaskRepository = (*TaskRepository)(nil)
+
+// TaskRepository implements the TaskRepository port in memory, for tests and running without a database
+// Tasks are copied in and out so callers can't change stored tasks without calling Update, as with PostgreSQL.
+type TaskRepository struct {
+	tasks   sync.Map // uuid.UUID -> *domain.Task
+	archive sync.Map // uuid.UUID -> *domain.Task
+}
+
+// NewTaskRepository creates an in-memory task repository holding the given tasks
+func NewTaskRepository(tasks ...*domain.Task) *TaskRepository {
+	r := &TaskRepository{}
+	for _, task := range tasks {
+		r.tasks.Store(task.ID, copyTask(task))
+	}
+	return r
+}
+
+// Create stores a new task
+func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
+	if _, exists := r.tasks.LoadOrStore(task.ID, copyTask(task)); exists {
+		return fmt.Errorf("failed to create task: task already exists: %s", task.ID)
+	}
+	return nil
+}
+
+// GetByID retrieves a task by its ID
+func (r *TaskRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Task, error) {
+	stored, ok := r.tasks.Load(id)
+	if !ok {
+		return nil, fmt.Errorf("task not found: %s", id)
+	}
+	return copyTask(stored.(*domain.Task)), nil
+}
+
+// Update persists every field of an existing task
+func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
+	if _, ok := r.tasks.Load(task.ID); !ok {
+		return fmt.Errorf("task not found: %s", task.ID)
+	}
+	r.tasks.Store(task.ID, copyTask(task))
+	return nil
+}
+
+// List retrieves tasks with optional filtering
+func (r *TaskRepository) List(ctx context.Context, filter ports.TaskFilter) ([]*domain.Task, error) {
+	return listFrom(&r.tasks, filter)
+}
+
+// Count counts the tasks List would return for the filter, ignoring its limit and offset
+func (r *TaskRepository) Count(ctx context.Context, filter ports.TaskFilter) (int64, error) {
+	return int64(len(matchingTasks(&r.tasks, filter))), nil
+}
+
+// Delete removes a task by ID
+func (r *TaskRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, ok := r.tasks.LoadAndDelete(id); !ok {
+		return fmt.Errorf("task not found: %s", id)
+	}
+	return nil
+}
+
+// ListBySession retrieves tasks matching the filter ordered by session, oldest first within each session
+// The filter's sort is ignored; tasks without a session ID come last.
+func (r *TaskRepository) ListBySession(ctx context.Context, filter ports.TaskFilter) ([]*domain.Task, error) {
+	tasks := matchingTasks(&r.tasks, filter)
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i].HookData.GetSessionID(), tasks[j].HookData.GetSessionID()
+		if a != b {
+			return b == "" || (a != "" && a < b)
+		}
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+	return paginate(tasks, filter.Limit, filter.Offset), nil
+}
+
+// GetPendingTasks retrieves all tasks that require user action, oldest first
+func (r *TaskRepository) GetPendingTasks(ctx context.Context) ([]*domain.Task, error) {
+	status := domain.TaskStatusPending
+	return r.List(ctx, ports.TaskFilter{Status: &status, SortBy: "created_at", SortOrder: "asc"})
+}
+
+// GetChildTasks retrieves the tasks whose parent is taskID, oldest first
+func (r *TaskRepository) GetChildTasks(ctx context.Context, taskID uuid.UUID) ([]*domain.Task, error) {
+	var children []*domain.Task
+	r.tasks.Range(func(_, value any) bool {
+		task := value.(*domain.Task)
+		if task.ParentTaskID != nil && *task.ParentTaskID == taskID {
+			children = append(children, copyTask(task))
+		}
+		return true
+	})
+
+	sort.Slice(children, func(i, j int) bool {
+		if !children[i].CreatedAt.Equal(children[j].CreatedAt) {
+			return children[i].CreatedAt.Before(children[j].CreatedAt)
+		}
+		return children[i].ID.String() < children[j].ID.String()
+	})
+	return children, nil
+}
+
+// GetPendingTasksForSession retrieves the pending tasks raised by one Claude Code session, oldest first
+func (r *TaskRepository) GetPendingTasksForSession(ctx context.Context, sessionID string) ([]*domain.Task, error) {
+	status := domain.TaskStatusPending
+	return r.List(ctx, ports.TaskFilter{Status: &status, SessionID: &sessionID, SortBy: "created_at", SortOrder: "asc"})
+}
+
+// GetSessionSummary counts one Claude Code session's tasks by status and averages how long its decisions took
+// A session with no tasks comes back with every count at zero.
+func (r *TaskRepository) GetSessionSummary(ctx context.Context, sessionID string) (*ports.SessionSummary, error) {
+	summary := &ports.SessionSummary{SessionID: sessionID}
+	var decisions []float64
+	for _, task := range matchingTasks(&r.tasks, ports.TaskFilter{SessionID: &sessionID}) {
+		summary.TotalTasks++
+		switch task.Status {
+		case domain.TaskStatusPending:
+			summary.PendingTasks++
+		case domain.TaskStatusApproved:
+			summary.ApprovedTasks++
+		case domain.TaskStatusRejected:
+			summary.RejectedTasks++
+		}
+		if summary.FirstSeen.IsZero() || task.CreatedAt.Before(summary.FirstSeen) {
+			summary.FirstSeen = task.CreatedAt
+		}
+		if task.CreatedAt.After(summary.LastSeen) {
+			summary.LastSeen = task.CreatedAt
+		}
+		if task.ActionTaken != nil {
+			decisions = append(decisions, decisionDurationMs(task))
+		}
+	}
+	summary.AvgDecisionDurationMs = average(decisions)
+	return summary, nil
+}
+
+// GetExpiredSnoozes retrieves pending tasks whose snooze ended at or before now
+func (r *TaskRepository) GetExpiredSnoozes(ctx context.Context, now time.Time) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	r.tasks.Range(func(_, value any) bool {
+		task := value.(*domain.Task)
+		if task.Status == domain.TaskStatusPending && task.SnoozedUntil != nil && !task.SnoozedUntil.After(now) {
+			tasks = append(tasks, copyTask(task))
+		}
+		return true
+	})
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].SnoozedUntil.Before(*tasks[j].SnoozedUntil)
+	})
+	return tasks, nil
+}
+
+// ClearSnooze removes a pending task's snooze, returning false if the task was resolved or unsnoozed meanwhile
+// Only the snooze is swapped in, so a concurrent decision stored in between is never overwritten.
+func (r *TaskRepository) ClearSnooze(ctx context.Context, id uuid.UUID) (bool, error) {
+	for {
+		stored, ok := r.tasks.Load(id)
+		if !ok {
+			return false, nil
+		}
+		task := stored.(*domain.Task)
+		if task.Status != domain.TaskStatusPending || task.SnoozedUntil == nil {
+			return false, nil
+		}
+
+		cleared := copyTask(task)
+		cleared.SnoozedUntil = nil
+		if r.tasks.CompareAndSwap(id, stored, cleared) {
+			return true, nil
+		}
+	}
+}
+
+// GetToolUsageStats aggregates tool calls created at or after since, most used tool first
+// A failed task with no action timed out waiting for a decision; latency only averages approvals and rejections.
+func (r *TaskRepository) GetToolUsageStats(ctx context.Context, since time.Time) ([]ports.ToolUsageStat, error) {
+	byTool := make(map[string]*ports.ToolUsageStat)
+	latencies := make(map[string][]float64)
+	for _, task := range matchingTasks(&r.tasks, ports.TaskFilter{CreatedAfter: &since}) {
+		toolName := task.HookData.GetToolName()
+		if toolName == "" {
+			continue
+		}
+
+		stat, ok := byTool[toolName]
+		if !ok {
+			stat = &ports.ToolUsageStat{ToolName: toolName}
+			byTool[toolName] = stat
+		}
+		stat.CallCount++
+
+		switch {
+		case task.ActionTaken == nil:
+			if task.Status == domain.TaskStatusFailed {
+				stat.TimeoutCount++
+			}
+		case *task.ActionTaken == domain.ActionTypeApprove:
+			stat.ApprovalCount++
+			latencies[toolName] = append(latencies[toolName], decisionDurationMs(task))
+		case *task.ActionTaken == domain.ActionTypeReject:
+			stat.RejectionCount++
+			latencies[toolName] = append(latencies[toolName], decisionDurationMs(task))
+		}
+	}
+
+	stats := make([]ports.ToolUsageStat, 0, len(byTool))
+	for toolName, stat := range byTool {
+		stat.AvgDecisionLatencyMs = average(latencies[toolName])
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].CallCount != stats[j].CallCount {
+			return stats[i].CallCount > stats[j].CallCount
+		}
+		return stats[i].ToolName < stats[j].ToolName
+	})
+	return stats, nil
+}
+
+// GetTaskStats counts the tasks created since a point in time by hook type, status and action taken,
+// with the average and 95th percentile time to a decision
+func (r *TaskRepository) GetTaskStats(ctx context.Context, since time.Time) (*ports.TaskStats, error) {
+	stats := &ports.TaskStats{
+		ByHookType: make(map[domain.HookType]int64),
+		ByStatus:   make(map[string]int64),
+		ByAction:   make(map[domain.ActionType]int64),
+	}
+
+	var decisions []float64
+	for _, task := range matchingTasks(&r.tasks, ports.TaskFilter{CreatedAfter: &since}) {
+		stats.TotalTasks++
+		stats.ByHookType[task.HookType]++
+		stats.ByStatus[task.Status.String()]++
+		if task.Status == domain.TaskStatusPending {
+			stats.PendingTasks++
+		}
+		if task.ActionTaken != nil {
+			stats.ByAction[*task.ActionTaken]++
+			decisions = append(decisions, decisionDurationMs(task))
+		}
+	}
+
+	stats.AvgDecisionDurationMs = average(decisions)
+	stats.P95DecisionDurationMs = percentile(decisions, 0.95)
+	return stats, nil
+}
+
+// GetCompactStats counts the PreCompact tasks created since a point in time by trigger and session,
+// with the average transcript line count over those whose transcript was read
+func (r *TaskRepository) GetCompactStats(ctx context.Context, since time.Time) (*ports.CompactStats, error) {
+	hookType := domain.HookTypePreCompact
+	stats := &ports.CompactStats{BySession: make(map[string]int64)}
+
+	var transcriptLines []float64
+	for _, task := range matchingTasks(&r.tasks, ports.TaskFilter{HookType: &hookType, CreatedAfter: &since}) {
+		stats.TotalCompacts++
+		if sessionID := task.HookData.GetSessionID(); sessionID != "" {
+			stats.BySession[sessionID]++
+		}
+
+		data, ok := task.HookData.Data.(*domain.PreCompactHookData)
+		if !ok {
+			continue
+		}
+		switch data.Trigger {
+		case "auto":
+			stats.AutoCompacts++
+		case "manual":
+			stats.ManualCompacts++
+		}
+		if data.TranscriptLines > 0 {
+			transcriptLines = append(transcriptLines, float64(data.TranscriptLines))
+		}
+	}
+
+	stats.AvgTranscriptLines = average(transcriptLines)
+	return stats, nil
+}
+
+// CountByStatus returns the number of tasks in each status
+// Statuses with no tasks are absent from the map.
+func (r *TaskRepository) CountByStatus(ctx context.Context) (map[domain.TaskStatus]int, error) {
+	counts := make(map[domain.TaskStatus]int)
+	r.tasks.Range(func(_, value any) bool {
+		counts[value.(*domain.Task).Status]++
+		return true
+	})
+	return counts, nil
+}
+
+// CountByHookType returns the number of tasks raised by each hook type
+// Hook types with no tasks are absent from the map.
+func (r *TaskRepository) CountByHookType(ctx context.Context) (map[domain.HookType]int, error) {
+	counts := make(map[domain.HookType]int)
+	r.tasks.Range(func(_, value any) bool {
+		counts[value.(*domain.Task).HookType]++
+		return true
+	})
+	return counts, nil
+}
+
+// CountConcurrentSessions counts the distinct sessions with a pending task created after since
+func (r *TaskRepository) CountConcurrentSessions(ctx context.Context, since time.Time) (int, error) {
+	sessions := make(map[string]bool)
+	r.tasks.Range(func(_, value any) bool {
+		task := value.(*domain.Task)
+		if task.Status == domain.TaskStatusPending && task.CreatedAt.After(since) {
+			if sessionID := task.HookData.GetSessionID(); sessionID != "" {
+				sessions[sessionID] = true
+			}
+		}
+		return true
+	})
+	return len(sessions), nil
+}
+
+// GetTasksByHookType retrieves tasks of one hook type, newest first
+func (r *TaskRepository) GetTasksByHookType(ctx context.Context, hookType domain.HookType) ([]*domain.Task, error) {
+	return r.List(ctx, ports.TaskFilter{HookType: &hookType, SortBy: "created_at", SortOrder: "desc"})
+}
+
+// ArchiveCompleted moves tasks that are no longer pending and haven't changed in olderThan to the archive
+func (r *TaskRepository) ArchiveCompleted(ctx context.Context, olderThan time.Duration) (int, error) {
+	cutoff := time.Now().Add(-olderThan)
+
+	archived := 0
+	r.tasks.Range(func(key, value any) bool {
+		task := value.(*domain.Task)
+		if task.Status != domain.TaskStatusPending && task.UpdatedAt.Before(cutoff) {
+			if _, exists := r.archive.LoadOrStore(key, task); !exists {
+				archived++
+			}
+			r.tasks.CompareAndDelete(key, value)
+		}
+		return true
+	})
+	return archived, nil
+}
+
+// ListArchived retrieves archived tasks with the same filtering as List
+func (r *TaskRepository) ListArchived(ctx context.Context, filter ports.TaskFilter) ([]*domain.Task, error) {
+	return listFrom(&r.archive, filter)
+}
+
+// listFrom filters, sorts and pages the tasks in the live or archive map
+// Tasks are newest first unless the filter names created_at, updated_at or status to sort by.
+func listFrom(tasks *sync.Map, filter ports.TaskFilter) ([]*domain.Task, error) {
+	matched := matchingTasks(tasks, filter)
+
+	sortBy, descending := filter.SortBy, filter.SortOrder == "desc"
+	if sortBy == "" {
+		sortBy, descending = "created_at", true
+	}
+
+	var less func(a, b *domain.Task) bool
+	switch sortBy {
+	case "created_at":
+		less = func(a, b *domain.Task) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	case "updated_at":
+		less = func(a, b *domain.Task) bool { return a.UpdatedAt.Before(b.UpdatedAt) }
+	case "status":
+		less = func(a, b *domain.Task) bool { return a.Status < b.Status }
+	default:
+		return nil, fmt.Errorf("failed to list tasks: cannot sort by %q", filter.SortBy)
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		if descending {
+			return less(matched[j], matched[i])
+		}
+		return less(matched[i], matched[j])
+	})
+	return paginate(matched, filter.Limit, filter.Offset), nil
+}
+
+// matchingTasks returns copies of the tasks matching every field set in the filter, ordered by ID
+// Ordering by ID keeps ties stable when the caller sorts, since sync.Map ranges in no particular order.
+func matchingTasks(tasks *sync.Map, filter ports.TaskFilter) []*domain.Task {
+	var matched []*domain.Task
+	tasks.Range(func(_, value any) bool {
+		task := value.(*domain.Task)
+		if taskMatches(task, filter) {
+			matched = append(matched, copyTask(task))
+		}
+		return true
+	})
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].ID.String() < matched[j].ID.String()
+	})
+	return matched
+}
+
+// taskMatches applies the same conditions as the PostgreSQL repository's WHERE clause
+func taskMatches(task *domain.Task, filter ports.TaskFilter) bool {
+	if filter.Status != nil && task.Status != *filter.Status {
+		return false
+	}
+	if filter.HookType != nil && task.HookType != *filter.HookType {
+		return false
+	}
+	if filter.CreatedAfter != nil && task.CreatedAt.Before(*filter.CreatedAfter) {
+		return false
+	}
+	if filter.CreatedBefore != nil && task.CreatedAt.After(*filter.CreatedBefore) {
+		return false
+	}
+	if filter.SessionID != nil && task.HookData.GetSessionID() != *filter.SessionID {
+		return false
+	}
+	if filter.SearchQuery != nil && *filter.SearchQuery != "" {
+		// Matches anywhere in the hook data, so commands, file paths and prompts are all searched
+		hookDataJSON, err := json.Marshal(task.HookData)
+		if err != nil || !strings.Contains(strings.ToLower(string(hookDataJSON)), strings.ToLower(*filter.SearchQuery)) {
+			return false
+		}
+	}
+	return true
+}
+
+// paginate applies a filter's offset and then its limit; zero means no limit or offset
+func paginate(tasks []*domain.Task, limit, offset int) []*domain.Task {
+	if offset > 0 {
+		if offset >= len(tasks) {
+			return nil
+		}
+		tasks = tasks[offset:]
+	}
+	if limit > 0 && limit < len(tasks) {
+		tasks = tasks[:limit]
+	}
+	return tasks
+}
+
+// copyTask returns a copy of the task that shares no mutable fields with it
+func copyTask(task *domain.Task) *domain.Task {
+	copied := *task
+	if task.HookData != nil {
+		hookData := *task.HookData
+		copied.HookData = &hookData
+	}
+	if task.ActionTaken != nil {
+		action := *task.ActionTaken
+		copied.ActionTaken = &action
+	}
+	if task.SnoozedUntil != nil {
+		snoozedUntil := *task.SnoozedUntil
+		copied.SnoozedUntil = &snoozedUntil
+	}
+	if task.ParentTaskID != nil {
+		parentTaskID := *task.ParentTaskID
+		copied.ParentTaskID = &parentTaskID
+	}
+	if task.ResponseData != nil {
+		copied.ResponseData = make(map[string]interface{}, len(task.ResponseData))
+		for key, value := range task.ResponseData {
+			copied.ResponseData[key] = value
+		}
+	}
+	return &copied
+}
+
+// decisionDurationMs returns how long a decided task waited for its decision, in milliseconds
+func decisionDurationMs(task *domain.Task) float64 {
+	return float64(task.UpdatedAt.Sub(task.CreatedAt)) / float64(time.Millisecond)
+}
+
+// average returns the mean of values, or 0 when there are none
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+// percentile interpolates between the closest ranks like PostgreSQL's PERCENTILE_CONT, or returns 0 for no values
+func percentile(values []float64, fraction float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	rank := fraction * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
@@ -0,0 +1,124 @@
+package mock
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// Ensure MockNotificationSender satisfies the NotificationSender port
+var _ ports.NotificationSender = (*MockNotificationSender)(nil)
+var _ ports.NotificationRouter = (*MockNotificationSender)(nil)
+
+// MockNotificationSender implements the NotificationSender port and records every call for tests
+type MockNotificationSender struct {
+	topic  string
+	calls  []domain.Notification
+	topics []string
+	err    error
+	onSend func(notification *domain.Notification)
+	mutex  sync.Mutex
+}
+
+// NewMockNotificationSender creates a new mock notification sender publishing to the given topic
+func NewMockNotificationSender(topic string) *MockNotificationSender {
+	return &MockNotificationSender{
+		topic: topic,
+	}
+}
+
+// Send records the notification and runs the OnSend callback, or returns the configured error without recording it
+func (m *MockNotificationSender) Send(ctx context.Context, notification *domain.Notification) error {
+	m.mutex.Lock()
+	if m.err != nil {
+		err := m.err
+		m.mutex.Unlock()
+		return err
+	}
+
+	notification.MarkSent()
+	m.calls = append(m.calls, *notification)
+	m.topics = append(m.topics, m.topic)
+	onSend := m.onSend
+	m.mutex.Unlock()
+
+	// Run outside the lock so the callback can inspect the mock
+	if onSend != nil {
+		onSend(notification)
+	}
+	return nil
+}
+
+// Verify returns the configured error, mirroring an unavailable notification service
+func (m *MockNotificationSender) Verify(ctx context.Context) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.err
+}
+
+// Calls returns a copy of all notifications sent so far
+func (m *MockNotificationSender) Calls() []domain.Notification {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	calls := make([]domain.Notification, len(m.calls))
+	copy(calls, m.calls)
+	return calls
+}
+
+// DestinationFor reports the mock's topic on a "mock" channel
+func (m *MockNotificationSender) DestinationFor(notification *domain.Notification) ports.NotificationDestination {
+	return ports.NotificationDestination{Channel: "mock", Topic: m.topic}
+}
+
+// Reset clears recorded calls, any configured error and the OnSend callback
+func (m *MockNotificationSender) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.calls = nil
+	m.topics = nil
+	m.err = nil
+	m.onSend = nil
+}
+
+// SetError makes subsequent Send and Verify calls fail with err (nil restores success)
+func (m *MockNotificationSender) SetError(err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.err = err
+}
+
+// OnSend runs onSend with every notification once it is recorded, like a user acting on it the moment it arrives
+func (m *MockNotificationSender) OnSend(onSend func(notification *domain.Notification)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.onSend = onSend
+}
+
+// AssertSentCount fails the test if the number of sent notifications is not n
+func (m *MockNotificationSender) AssertSentCount(t *testing.T, n int) {
+	t.Helper()
+
+	if got := len(m.Calls()); got != n {
+		t.Errorf("Expected %d notifications to be sent, got %d", n, got)
+	}
+}
+
+// AssertSentToTopic fails the test if no notification was sent to the given topic
+func (m *MockNotificationSender) AssertSentToTopic(t *testing.T, topic string) {
+	t.Helper()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, sentTopic := range m.topics {
+		if sentTopic == topic {
+			return
+		}
+	}
+	t.Errorf("Expected a notification to be sent to topic %s, sent to %v", topic, m.topics)
+}
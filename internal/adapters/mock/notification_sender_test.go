@@ -0,0 +1,63 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+func TestMockNotificationSender_OnSend(t *testing.T) {
+	sender := NewMockNotificationSender("claude-notifications")
+	ctx := context.Background()
+
+	var received []uuid.UUID
+	sender.OnSend(func(notification *domain.Notification) {
+		if !notification.IsSent() {
+			t.Error("Expected the notification to be marked as sent before OnSend runs")
+		}
+		sender.AssertSentCount(t, len(received)+1)
+		received = append(received, notification.TaskID)
+	})
+
+	notification := domain.NewNotification(uuid.New(), domain.HookTypePreToolUse, "localhost:8080", "")
+	if err := sender.Send(ctx, notification); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	sender.AssertSentToTopic(t, "claude-notifications")
+	if len(received) != 1 || received[0] != notification.TaskID {
+		t.Errorf("Expected OnSend to receive the notification, got %v", received)
+	}
+
+	sender.Reset()
+	if err := sender.Send(ctx, notification); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(received) != 1 {
+		t.Error("Expected Reset to clear the OnSend callback")
+	}
+}
+
+func TestMockNotificationSender_SetErrorAndReset(t *testing.T) {
+	sender := NewMockNotificationSender("claude-notifications")
+	ctx := context.Background()
+	expectedErr := errors.New("ntfy unavailable")
+
+	sender.SetError(expectedErr)
+	notification := domain.NewNotification(uuid.New(), domain.HookTypePreToolUse, "localhost:8080", "")
+	if err := sender.Send(ctx, notification); !errors.Is(err, expectedErr) {
+		t.Errorf("Expected error %v, got %v", expectedErr, err)
+	}
+	if err := sender.Verify(ctx); !errors.Is(err, expectedErr) {
+		t.Errorf("Expected Verify error %v, got %v", expectedErr, err)
+	}
+	sender.AssertSentCount(t, 0)
+
+	sender.Reset()
+	if err := sender.Send(ctx, notification); err != nil {
+		t.Errorf("Expected no error after Reset, got %v", err)
+	}
+	sender.AssertSentCount(t, 1)
+}
@@ -0,0 +1,143 @@
+package mock
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// Ensure MockTMuxController satisfies the TMuxController port
+var _ ports.TMuxController = (*MockTMuxController)(nil)
+
+// TMuxCall is one recorded call to the mock tmux controller
+type TMuxCall struct {
+	Method      string
+	SessionName string
+	Keys        string // Keys for SendKeys, the command for SendCommand, the line count for CapturePaneScrollback
+}
+
+// tmuxResponse is the configured outcome of a tmux controller method
+type tmuxResponse struct {
+	result interface{}
+	err    error
+}
+
+// MockTMuxController implements the TMuxController port without a tmux server and records every call for tests
+type MockTMuxController struct {
+	calls     []TMuxCall
+	responses map[string]tmuxResponse
+	mutex     sync.Mutex
+}
+
+// NewMockTMuxController creates a new mock tmux controller where every call succeeds with a zero result
+func NewMockTMuxController() *MockTMuxController {
+	return &MockTMuxController{
+		responses: make(map[string]tmuxResponse),
+	}
+}
+
+// SetResponse configures what a method returns, keyed by method name (e.g. "SendKeys")
+// result must match the method's return type: []ports.TMuxSession for ListSessions, bool for SessionExists,
+// *ports.TMuxSession for GetSessionInfo and string for CapturePaneScrollback. It is ignored for methods that
+// only return an error.
+func (m *MockTMuxController) SetResponse(method string, result interface{}, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.responses[method] = tmuxResponse{result: result, err: err}
+}
+
+// record stores a call and returns the response configured for its method
+func (m *MockTMuxController) record(method, sessionName, keys string) tmuxResponse {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.calls = append(m.calls, TMuxCall{Method: method, SessionName: sessionName, Keys: keys})
+	return m.responses[method]
+}
+
+// SendKeys records the keystrokes sent to a session
+func (m *MockTMuxController) SendKeys(ctx context.Context, sessionName string, keys string) error {
+	return m.record("SendKeys", sessionName, keys).err
+}
+
+// SendCommand records the command sent to a session
+func (m *MockTMuxController) SendCommand(ctx context.Context, sessionName string, command string) error {
+	return m.record("SendCommand", sessionName, command).err
+}
+
+// ListSessions returns the configured sessions
+func (m *MockTMuxController) ListSessions(ctx context.Context) ([]ports.TMuxSession, error) {
+	response := m.record("ListSessions", "", "")
+	sessions, _ := response.result.([]ports.TMuxSession)
+	return sessions, response.err
+}
+
+// SessionExists returns the configured existence result
+func (m *MockTMuxController) SessionExists(ctx context.Context, sessionName string) (bool, error) {
+	response := m.record("SessionExists", sessionName, "")
+	exists, _ := response.result.(bool)
+	return exists, response.err
+}
+
+// CreateSession records the session creation
+func (m *MockTMuxController) CreateSession(ctx context.Context, sessionName string) error {
+	return m.record("CreateSession", sessionName, "").err
+}
+
+// KillSession records the session termination
+func (m *MockTMuxController) KillSession(ctx context.Context, sessionName string) error {
+	return m.record("KillSession", sessionName, "").err
+}
+
+// GetSessionInfo returns the configured session info
+func (m *MockTMuxController) GetSessionInfo(ctx context.Context, sessionName string) (*ports.TMuxSession, error) {
+	response := m.record("GetSessionInfo", sessionName, "")
+	info, _ := response.result.(*ports.TMuxSession)
+	return info, response.err
+}
+
+// CapturePaneScrollback returns the configured scrollback text
+func (m *MockTMuxController) CapturePaneScrollback(ctx context.Context, sessionName string, maxLines int) (string, error) {
+	response := m.record("CapturePaneScrollback", sessionName, strconv.Itoa(maxLines))
+	scrollback, _ := response.result.(string)
+	return scrollback, response.err
+}
+
+// Calls returns a copy of all calls made so far
+func (m *MockTMuxController) Calls() []TMuxCall {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	calls := make([]TMuxCall, len(m.calls))
+	copy(calls, m.calls)
+	return calls
+}
+
+// Reset clears recorded calls and configured responses
+func (m *MockTMuxController) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.calls = nil
+	m.responses = make(map[string]tmuxResponse)
+}
+
+// AssertSentKeys fails the test if keys were never sent to the session with SendKeys
+func (m *MockTMuxController) AssertSentKeys(t *testing.T, session, keys string) {
+	t.Helper()
+
+	var sent []string
+	for _, call := range m.Calls() {
+		if call.Method != "SendKeys" || call.SessionName != session {
+			continue
+		}
+		if call.Keys == keys {
+			return
+		}
+		sent = append(sent, call.Keys)
+	}
+	t.Errorf("Expected keys %q to be sent to tmux session %s, sent %q", keys, session, sent)
+}
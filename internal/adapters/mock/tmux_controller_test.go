@@ -0,0 +1,87 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+func TestMockTMuxController_RecordsCalls(t *testing.T) {
+	controller := NewMockTMuxController()
+	ctx := context.Background()
+
+	if err := controller.CreateSession(ctx, "claude-haiper"); err != nil {
+		t.Fatalf("Unexpected error creating session: %v", err)
+	}
+	if err := controller.SendKeys(ctx, "claude-haiper", "y"); err != nil {
+		t.Fatalf("Unexpected error sending keys: %v", err)
+	}
+	if err := controller.SendCommand(ctx, "claude-haiper", "continue"); err != nil {
+		t.Fatalf("Unexpected error sending command: %v", err)
+	}
+
+	expected := []TMuxCall{
+		{Method: "CreateSession", SessionName: "claude-haiper"},
+		{Method: "SendKeys", SessionName: "claude-haiper", Keys: "y"},
+		{Method: "SendCommand", SessionName: "claude-haiper", Keys: "continue"},
+	}
+	calls := controller.Calls()
+	if len(calls) != len(expected) {
+		t.Fatalf("Expected %d calls, got %+v", len(expected), calls)
+	}
+	for i, call := range calls {
+		if call != expected[i] {
+			t.Errorf("Call %d: expected %+v, got %+v", i, expected[i], call)
+		}
+	}
+
+	controller.AssertSentKeys(t, "claude-haiper", "y")
+
+	controller.Reset()
+	if len(controller.Calls()) != 0 {
+		t.Error("Expected no calls after reset")
+	}
+}
+
+func TestMockTMuxController_SetResponse(t *testing.T) {
+	controller := NewMockTMuxController()
+	ctx := context.Background()
+	sessionErr := errors.New("no server running")
+
+	controller.SetResponse("ListSessions", []ports.TMuxSession{{Name: "claude-haiper"}}, nil)
+	controller.SetResponse("SessionExists", true, nil)
+	controller.SetResponse("GetSessionInfo", &ports.TMuxSession{Name: "claude-haiper", Windows: 2}, nil)
+	controller.SetResponse("CapturePaneScrollback", "$ make test\nFAIL\n", nil)
+	controller.SetResponse("SendKeys", nil, sessionErr)
+
+	sessions, err := controller.ListSessions(ctx)
+	if err != nil || len(sessions) != 1 || sessions[0].Name != "claude-haiper" {
+		t.Errorf("Expected configured sessions, got %+v (err: %v)", sessions, err)
+	}
+
+	exists, err := controller.SessionExists(ctx, "claude-haiper")
+	if err != nil || !exists {
+		t.Errorf("Expected session to exist, got %t (err: %v)", exists, err)
+	}
+
+	info, err := controller.GetSessionInfo(ctx, "claude-haiper")
+	if err != nil || info == nil || info.Windows != 2 {
+		t.Errorf("Expected configured session info, got %+v (err: %v)", info, err)
+	}
+
+	scrollback, err := controller.CapturePaneScrollback(ctx, "claude-haiper", 100)
+	if err != nil || scrollback != "$ make test\nFAIL\n" {
+		t.Errorf("Expected configured scrollback, got %q (err: %v)", scrollback, err)
+	}
+
+	if err := controller.SendKeys(ctx, "claude-haiper", "y"); !errors.Is(err, sessionErr) {
+		t.Errorf("Expected configured SendKeys error, got %v", err)
+	}
+
+	// Methods without a configured response succeed with a zero result
+	if err := controller.KillSession(ctx, "claude-haiper"); err != nil {
+		t.Errorf("Expected KillSession to succeed by default, got %v", err)
+	}
+}
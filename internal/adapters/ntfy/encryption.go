@@ -0,0 +1,93 @@
+package ntfy
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// EncryptedTag marks a notification whose title and message are encrypted
+const EncryptedTag = "encrypted"
+
+// KeyFingerprintTagPrefix prefixes the tag naming which key a notification was encrypted with
+const KeyFingerprintTagPrefix = "key-"
+
+// ErrDecryptionFailed is returned when a message was encrypted with a different key or has been altered
+var ErrDecryptionFailed = errors.New("failed to decrypt message: wrong key or corrupted message")
+
+// EncryptMessage encrypts text with AES-256-GCM under a key derived from passphrase
+// The result is base64 of the random nonce followed by the sealed text, so it's safe in a JSON string.
+func EncryptMessage(passphrase, text string) (string, error) {
+	gcm, err := newGCM(passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(text), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptMessage reverses EncryptMessage
+func DecryptMessage(passphrase, encrypted string) (string, error) {
+	gcm, err := newGCM(passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encrypted))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode message: %w", err)
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", ErrDecryptionFailed
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	text, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrDecryptionFailed
+	}
+	return string(text), nil
+}
+
+// KeyFingerprint identifies the key derived from passphrase without revealing it
+// It's sent as a notification tag so the reader knows which key to decrypt with after a key rotation.
+func KeyFingerprint(passphrase string) string {
+	key := deriveEncryptionKey(passphrase)
+	fingerprint := sha256.Sum256(key)
+	return hex.EncodeToString(fingerprint[:8])
+}
+
+// deriveEncryptionKey turns a passphrase of any length into a 32-byte AES-256 key
+func deriveEncryptionKey(passphrase string) []byte {
+	key := sha256.Sum256([]byte("ntfy-encryption:" + passphrase))
+	return key[:]
+}
+
+// newGCM creates the AES-GCM cipher for a passphrase
+func newGCM(passphrase string) (cipher.AEAD, error) {
+	if passphrase == "" {
+		return nil, errors.New("encryption key is empty")
+	}
+
+	block, err := aes.NewCipher(deriveEncryptionKey(passphrase))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return gcm, nil
+}
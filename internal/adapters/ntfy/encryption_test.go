@@ -0,0 +1,103 @@
+package ntfy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"command description", "Approve Bash: rm -rf build && aws s3 sync dist s3://prod-bucket"},
+		{"empty", ""},
+		{"unicode", "🔧 Claude needs approval: café → naïve"},
+		{"long", strings.Repeat("secret ", 5000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := EncryptMessage("correct horse battery staple", tt.text)
+			if err != nil {
+				t.Fatalf("Failed to encrypt: %v", err)
+			}
+			if tt.text != "" && strings.Contains(encrypted, tt.text) {
+				t.Error("Encrypted message contains the plaintext")
+			}
+
+			decrypted, err := DecryptMessage("correct horse battery staple", encrypted)
+			if err != nil {
+				t.Fatalf("Failed to decrypt: %v", err)
+			}
+			if decrypted != tt.text {
+				t.Errorf("Expected %q after round trip, got %q", tt.text, decrypted)
+			}
+		})
+	}
+}
+
+func TestEncryptMessageUsesFreshNonce(t *testing.T) {
+	first, err := EncryptMessage("key", "same text")
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	second, err := EncryptMessage("key", "same text")
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	if first == second {
+		t.Error("Expected encrypting the same text twice to give different ciphertexts")
+	}
+}
+
+func TestDecryptMessageFailures(t *testing.T) {
+	encrypted, err := EncryptMessage("right key", "Approve Bash: git push --force")
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	tampered := []byte(encrypted)
+	tampered[len(tampered)/2] ^= 'A' ^ 'B'
+
+	tests := []struct {
+		name      string
+		key       string
+		encrypted string
+		expected  error
+	}{
+		{"wrong key", "wrong key", encrypted, ErrDecryptionFailed},
+		{"tampered", "right key", string(tampered), nil},
+		{"too short", "right key", "AAAA", ErrDecryptionFailed},
+		{"not base64", "right key", "not base64!", nil},
+		{"empty key", "", encrypted, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecryptMessage(tt.key, tt.encrypted)
+			if err == nil {
+				t.Fatal("Expected decryption to fail")
+			}
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestKeyFingerprint(t *testing.T) {
+	fingerprint := KeyFingerprint("correct horse battery staple")
+	if len(fingerprint) != 16 {
+		t.Errorf("Expected a 16-character fingerprint, got %q", fingerprint)
+	}
+	if fingerprint != KeyFingerprint("correct horse battery staple") {
+		t.Error("Expected the fingerprint to be stable")
+	}
+	if fingerprint == KeyFingerprint("another key") {
+		t.Error("Expected different keys to have different fingerprints")
+	}
+	if strings.Contains(fingerprint, "horse") {
+		t.Error("Fingerprint leaks the key")
+	}
+}
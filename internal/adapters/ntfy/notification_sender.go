@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -31,21 +32,20 @@ func NewNotificationSender(config *ports.NotificationConfig) *NotificationSender
 
 // Send sends a notification via NTFY
 func (n *NotificationSender) Send(ctx context.Context, notification *domain.Notification) error {
+	title, message, tags, err := n.protectContent(notification)
+	if err != nil {
+		return err
+	}
+
 	// Create NTFY message payload
 	payload := map[string]interface{}{
-		"topic":    n.config.Topic,
-		"title":    notification.Title,
-		"message":  notification.Message,
+		"topic":    n.topicFor(notification),
+		"title":    title,
+		"message":  message,
 		"priority": n.mapPriority(notification.Priority),
-		"tags":     notification.Tags,
+		"tags":     tags,
 		"click":    notification.ActionURL,
-		"actions": []map[string]interface{}{
-			{
-				"action": "view",
-				"label":  "Open Task",
-				"url":    notification.ActionURL,
-			},
-		},
+		"actions":  actionsFor(notification),
 	}
 
 	// Marshal payload to JSON
@@ -89,6 +89,46 @@ func (n *NotificationSender) Send(ctx context.Context, notification *domain.Noti
 	return nil
 }
 
+// actionsFor returns the notification's buttons: open the task, plus approve and reject when quick actions are set
+// The quick action buttons POST straight to the server from the NTFY app and clear the notification.
+func actionsFor(notification *domain.Notification) []map[string]interface{} {
+	actions := []map[string]interface{}{
+		{
+			"action": "view",
+			"label":  "Open Task",
+			"url":    notification.ActionURL,
+		},
+	}
+	if notification.QuickApproveURL != "" && notification.QuickRejectURL != "" {
+		actions = append(actions,
+			map[string]interface{}{"action": "http", "label": "Approve", "url": notification.QuickApproveURL, "method": "POST", "clear": true},
+			map[string]interface{}{"action": "http", "label": "Reject", "url": notification.QuickRejectURL, "method": "POST", "clear": true},
+		)
+	}
+	return actions
+}
+
+// protectContent returns the title, message and tags to publish, encrypting the title and message
+// when an encryption key is configured. Encrypted notifications are tagged with the key's fingerprint.
+func (n *NotificationSender) protectContent(notification *domain.Notification) (string, string, []string, error) {
+	if n.config.NTFYEncryptionKey == "" {
+		return notification.Title, notification.Message, notification.Tags, nil
+	}
+
+	title, err := EncryptMessage(n.config.NTFYEncryptionKey, notification.Title)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to encrypt notification title: %w", err)
+	}
+	message, err := EncryptMessage(n.config.NTFYEncryptionKey, notification.Message)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to encrypt notification message: %w", err)
+	}
+
+	tags := append([]string{}, notification.Tags...)
+	tags = append(tags, EncryptedTag, KeyFingerprintTagPrefix+KeyFingerprint(n.config.NTFYEncryptionKey))
+	return title, message, tags, nil
+}
+
 // Verify checks if the notification service is available and configured correctly
 func (n *NotificationSender) Verify(ctx context.Context) error {
 	// Create a simple health check request
@@ -120,6 +160,60 @@ func (n *NotificationSender) Verify(ctx context.Context) error {
 	return nil
 }
 
+// DestinationFor reports the NTFY topic a notification is published to
+func (n *NotificationSender) DestinationFor(notification *domain.Notification) ports.NotificationDestination {
+	return ports.NotificationDestination{Channel: "ntfy", Topic: n.topicFor(notification)}
+}
+
+// topicFor picks the NTFY topic for a notification, routing by project when configured
+func (n *NotificationSender) topicFor(notification *domain.Notification) string {
+	if n.config.TopicFromCWD && notification.SourceCWD != "" {
+		if topic := TopicFromCWD(n.config.TopicPrefix, notification.SourceCWD); topic != "" {
+			return topic
+		}
+	}
+	return n.config.Topic
+}
+
+// TopicFromCWD derives a project topic "<prefix>-<slug>" from the last element of a working directory
+// The slug is lowercase with runs of characters NTFY doesn't allow in topics replaced by "-".
+func TopicFromCWD(prefix, cwd string) string {
+	base := filepath.Base(filepath.Clean(cwd))
+	if base == "." || base == string(filepath.Separator) {
+		return ""
+	}
+
+	var slug strings.Builder
+	lastDash := false
+	for _, r := range strings.ToLower(base) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			slug.WriteRune(r)
+			lastDash = false
+			continue
+		}
+		if !lastDash {
+			slug.WriteRune('-')
+			lastDash = true
+		}
+	}
+
+	project := strings.Trim(slug.String(), "-")
+	if project == "" {
+		return ""
+	}
+
+	topic := project
+	if prefix != "" {
+		topic = prefix + "-" + project
+	}
+
+	// NTFY topics are limited to 64 characters
+	if len(topic) > 64 {
+		topic = strings.TrimRight(topic[:64], "-")
+	}
+	return topic
+}
+
 // mapPriority converts domain notification priority to NTFY priority
 func (n *NotificationSender) mapPriority(priority domain.NotificationPriority) int {
 	switch priority {
@@ -0,0 +1,209 @@
+package ntfy
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+func TestTopicFromCWD(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		cwd      string
+		expected string
+	}{
+		{"simple project", "claude", "/Users/dan/Software/haiper", "claude-haiper"},
+		{"trailing slash", "claude", "/Users/dan/Software/haiper/", "claude-haiper"},
+		{"uppercase and spaces", "claude", "/Users/dan/My Project", "claude-my-project"},
+		{"dots and punctuation", "claude", "/srv/api.example.com", "claude-api-example-com"},
+		{"repeated separators", "claude", "/srv/foo -- bar", "claude-foo-bar"},
+		{"underscores kept", "claude", "/srv/my_service", "claude-my_service"},
+		{"no prefix", "", "/srv/haiper", "haiper"},
+		{"root directory", "claude", "/", ""},
+		{"empty cwd", "claude", "", ""},
+		{"only punctuation", "claude", "/srv/...!", ""},
+		{"long name truncated", "claude", "/srv/" + strings.Repeat("a", 100), "claude-" + strings.Repeat("a", 57)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TopicFromCWD(tt.prefix, tt.cwd); got != tt.expected {
+				t.Errorf("TopicFromCWD(%q, %q) = %q, expected %q", tt.prefix, tt.cwd, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNotificationSender_TopicFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    ports.NotificationConfig
+		sourceCWD string
+		expected  string
+	}{
+		{
+			name:      "routing disabled",
+			config:    ports.NotificationConfig{Topic: "claude-notifications", TopicPrefix: "claude"},
+			sourceCWD: "/srv/haiper",
+			expected:  "claude-notifications",
+		},
+		{
+			name:      "routing enabled",
+			config:    ports.NotificationConfig{Topic: "claude-notifications", TopicFromCWD: true, TopicPrefix: "claude"},
+			sourceCWD: "/srv/haiper",
+			expected:  "claude-haiper",
+		},
+		{
+			name:      "routing enabled without cwd",
+			config:    ports.NotificationConfig{Topic: "claude-notifications", TopicFromCWD: true, TopicPrefix: "claude"},
+			sourceCWD: "",
+			expected:  "claude-notifications",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := NewNotificationSender(&tt.config)
+			notification := &domain.Notification{SourceCWD: tt.sourceCWD}
+			if got := sender.topicFor(notification); got != tt.expected {
+				t.Errorf("Expected topic %q, got %q", tt.expected, got)
+			}
+
+			expectedDestination := ports.NotificationDestination{Channel: "ntfy", Topic: tt.expected}
+			if got := sender.DestinationFor(notification); got != expectedDestination {
+				t.Errorf("Expected destination %+v, got %+v", expectedDestination, got)
+			}
+		})
+	}
+}
+
+func TestNotificationSender_SendEncrypted(t *testing.T) {
+	tests := []struct {
+		name          string
+		encryptionKey string
+	}{
+		{"plaintext", ""},
+		{"encrypted", "correct horse battery staple"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var err error
+				body, err = io.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("Failed to read request body: %v", err)
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			sender := NewNotificationSender(&ports.NotificationConfig{
+				ServerURL:         server.URL,
+				Topic:             "claude-notifications",
+				NTFYEncryptionKey: tt.encryptionKey,
+			})
+			notification := &domain.Notification{
+				Title:     "🔧 Claude needs approval",
+				Message:   "Bash: aws s3 rm s3://prod-backups --recursive",
+				ActionURL: "http://localhost:8080/task/5d0c8a9e",
+				Tags:      []string{"claude-code", "tool-approval"},
+			}
+			if err := sender.Send(context.Background(), notification); err != nil {
+				t.Fatalf("Failed to send notification: %v", err)
+			}
+
+			var payload struct {
+				Title   string   `json:"title"`
+				Message string   `json:"message"`
+				Click   string   `json:"click"`
+				Tags    []string `json:"tags"`
+			}
+			if err := json.Unmarshal(body, &payload); err != nil {
+				t.Fatalf("Failed to decode NTFY payload: %v", err)
+			}
+			if payload.Click != notification.ActionURL {
+				t.Errorf("Expected click URL %q, got %q", notification.ActionURL, payload.Click)
+			}
+
+			if tt.encryptionKey == "" {
+				if payload.Title != notification.Title || payload.Message != notification.Message {
+					t.Errorf("Expected plaintext title and message, got %q / %q", payload.Title, payload.Message)
+				}
+				return
+			}
+
+			for _, plaintext := range []string{notification.Title, notification.Message, "aws s3 rm", "prod-backups"} {
+				if strings.Contains(string(body), plaintext) {
+					t.Errorf("NTFY payload contains plaintext %q", plaintext)
+				}
+			}
+			if title, err := DecryptMessage(tt.encryptionKey, payload.Title); err != nil || title != notification.Title {
+				t.Errorf("Expected title to decrypt to %q, got %q (%v)", notification.Title, title, err)
+			}
+			if message, err := DecryptMessage(tt.encryptionKey, payload.Message); err != nil || message != notification.Message {
+				t.Errorf("Expected message to decrypt to %q, got %q (%v)", notification.Message, message, err)
+			}
+
+			expectedTags := []string{"claude-code", "tool-approval", EncryptedTag, KeyFingerprintTagPrefix + KeyFingerprint(tt.encryptionKey)}
+			if strings.Join(payload.Tags, ",") != strings.Join(expectedTags, ",") {
+				t.Errorf("Expected tags %v, got %v", expectedTags, payload.Tags)
+			}
+			if len(notification.Tags) != 2 {
+				t.Errorf("Expected the notification's own tags to be left alone, got %v", notification.Tags)
+			}
+		})
+	}
+}
+
+func TestActionsFor(t *testing.T) {
+	tests := []struct {
+		name           string
+		notification   *domain.Notification
+		expectedLabels []string
+	}{
+		{
+			name:           "Without quick actions",
+			notification:   &domain.Notification{ActionURL: "http://localhost:8080/task/5d0c8a9e"},
+			expectedLabels: []string{"Open Task"},
+		},
+		{
+			name: "With quick actions",
+			notification: &domain.Notification{
+				ActionURL:       "http://localhost:8080/task/5d0c8a9e",
+				QuickApproveURL: "http://localhost:8080/api/tasks/5d0c8a9e/action?action=approve&token=1.ab",
+				QuickRejectURL:  "http://localhost:8080/api/tasks/5d0c8a9e/action?action=reject&token=1.cd",
+			},
+			expectedLabels: []string{"Open Task", "Approve", "Reject"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actions := actionsFor(tt.notification)
+			if len(actions) != len(tt.expectedLabels) {
+				t.Fatalf("Expected %d actions, got %v", len(tt.expectedLabels), actions)
+			}
+			for i, label := range tt.expectedLabels {
+				if actions[i]["label"] != label {
+					t.Errorf("Expected action %d to be %q, got %v", i, label, actions[i])
+				}
+			}
+			if len(actions) == 3 {
+				approve := actions[1]
+				if approve["action"] != "http" || approve["method"] != "POST" || approve["url"] != tt.notification.QuickApproveURL {
+					t.Errorf("Expected the approve button to POST to the quick approve URL, got %v", approve)
+				}
+			}
+		})
+	}
+}
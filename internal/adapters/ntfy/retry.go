@@ -0,0 +1,165 @@
+package ntfy
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// DefaultFailedNotificationHistory is how many permanently failed notifications are remembered
+const DefaultFailedNotificationHistory = 100
+
+// Ensure RetryableNotificationSender satisfies the NotificationSender port
+var _ ports.NotificationSender = (*RetryableNotificationSender)(nil)
+var _ ports.NotificationRouter = (*RetryableNotificationSender)(nil)
+
+// RetryConfig controls how often and how patiently a failed send is retried
+type RetryConfig struct {
+	MaxRetries   int           `json:"max_retries" yaml:"max_retries"`     // Retries after the first attempt
+	InitialDelay time.Duration `json:"initial_delay" yaml:"initial_delay"` // Wait before the first retry, doubled for each one after
+	MaxDelay     time.Duration `json:"max_delay" yaml:"max_delay"`         // Longest wait between two attempts
+}
+
+// DefaultRetryConfig retries three times over about 3.5 seconds, short enough to finish within a webhook request
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries:   3,
+		InitialDelay: 500 * time.Millisecond,
+		MaxDelay:     5 * time.Second,
+	}
+}
+
+// FailedNotification records a notification that could not be sent after every retry
+type FailedNotification struct {
+	NotificationID uuid.UUID `json:"notification_id"`
+	TaskID         uuid.UUID `json:"task_id"`
+	Reason         string    `json:"reason"`
+	Attempts       int       `json:"attempts"`
+	FailedAt       time.Time `json:"failed_at"`
+}
+
+// RetryableNotificationSender retries another sender's failed sends with exponential backoff
+// Waits end early when the context is done, so a webhook's deadline bounds the retries.
+type RetryableNotificationSender struct {
+	sender ports.NotificationSender
+	config RetryConfig
+
+	failed []FailedNotification
+	next   int
+	full   bool
+	mutex  sync.Mutex
+
+	// wait pauses between attempts; replaced in tests
+	wait func(ctx context.Context, delay time.Duration) error
+}
+
+// NewRetryableNotificationSender wraps sender so each notification is tried up to config.MaxRetries+1 times
+func NewRetryableNotificationSender(sender ports.NotificationSender, config RetryConfig) *RetryableNotificationSender {
+	if config.MaxDelay > 0 && config.InitialDelay > config.MaxDelay {
+		config.InitialDelay = config.MaxDelay
+	}
+
+	return &RetryableNotificationSender{
+		sender: sender,
+		config: config,
+		failed: make([]FailedNotification, DefaultFailedNotificationHistory),
+		wait:   waitForRetry,
+	}
+}
+
+// Send sends the notification, retrying failures until one succeeds, the retries run out or ctx is done
+func (r *RetryableNotificationSender) Send(ctx context.Context, notification *domain.Notification) error {
+	delay := r.config.InitialDelay
+	attempts := 0
+	for {
+		attempts++
+		err := r.sender.Send(ctx, notification)
+		if err == nil {
+			if attempts > 1 {
+				log.Printf("Notification %s sent on attempt %d", notification.ID, attempts)
+			}
+			return nil
+		}
+
+		if attempts > r.config.MaxRetries {
+			return r.recordFailure(notification, attempts, err)
+		}
+		log.Printf("Warning: notification %s failed on attempt %d, retrying in %s: %v", notification.ID, attempts, delay, err)
+
+		if waitErr := r.wait(ctx, delay); waitErr != nil {
+			return r.recordFailure(notification, attempts, fmt.Errorf("%w (gave up waiting to retry: %v)", err, waitErr))
+		}
+		delay = nextRetryDelay(delay, r.config.MaxDelay)
+	}
+}
+
+// Verify checks the wrapped sender; it is not retried, so startup reports problems right away
+func (r *RetryableNotificationSender) Verify(ctx context.Context) error {
+	return r.sender.Verify(ctx)
+}
+
+// DestinationFor reports where the wrapped sender delivers the notification, if it can tell
+func (r *RetryableNotificationSender) DestinationFor(notification *domain.Notification) ports.NotificationDestination {
+	if router, ok := r.sender.(ports.NotificationRouter); ok {
+		return router.DestinationFor(notification)
+	}
+	return ports.NotificationDestination{}
+}
+
+// GetFailedNotifications returns the remembered permanent failures, oldest first
+func (r *RetryableNotificationSender) GetFailedNotifications() []FailedNotification {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !r.full {
+		return append([]FailedNotification{}, r.failed[:r.next]...)
+	}
+	return append(append([]FailedNotification{}, r.failed[r.next:]...), r.failed[:r.next]...)
+}
+
+// recordFailure remembers a notification that will not be retried again and returns the error to report
+func (r *RetryableNotificationSender) recordFailure(notification *domain.Notification, attempts int, err error) error {
+	r.mutex.Lock()
+	r.failed[r.next] = FailedNotification{
+		NotificationID: notification.ID,
+		TaskID:         notification.TaskID,
+		Reason:         err.Error(),
+		Attempts:       attempts,
+		FailedAt:       time.Now(),
+	}
+	r.next = (r.next + 1) % len(r.failed)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.mutex.Unlock()
+
+	return fmt.Errorf("notification failed after %d attempts: %w", attempts, err)
+}
+
+// nextRetryDelay doubles the delay, capped at maxDelay
+func nextRetryDelay(delay, maxDelay time.Duration) time.Duration {
+	delay *= 2
+	if maxDelay > 0 && delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+// waitForRetry sleeps for delay, returning early with the context's error if it is done first
+func waitForRetry(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
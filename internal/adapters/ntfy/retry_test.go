@@ -0,0 +1,151 @@
+package ntfy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// flakySender fails the given number of sends, then succeeds
+type flakySender struct {
+	failures int
+	calls    int
+}
+
+func (s *flakySender) Send(ctx context.Context, notification *domain.Notification) error {
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("ntfy returned status 503")
+	}
+	return nil
+}
+
+func (s *flakySender) Verify(ctx context.Context) error {
+	return nil
+}
+
+func (s *flakySender) DestinationFor(notification *domain.Notification) ports.NotificationDestination {
+	return ports.NotificationDestination{Channel: "ntfy", Topic: "claude"}
+}
+
+// newTestRetrySender records the waits between attempts instead of sleeping
+func newTestRetrySender(sender ports.NotificationSender, config RetryConfig) (*RetryableNotificationSender, *[]time.Duration) {
+	var waits []time.Duration
+	retry := NewRetryableNotificationSender(sender, config)
+	retry.wait = func(ctx context.Context, delay time.Duration) error {
+		waits = append(waits, delay)
+		return ctx.Err()
+	}
+	return retry, &waits
+}
+
+func TestRetryableNotificationSender_Send(t *testing.T) {
+	config := RetryConfig{MaxRetries: 4, InitialDelay: time.Second, MaxDelay: 5 * time.Second}
+
+	tests := []struct {
+		name          string
+		failures      int
+		expectErr     bool
+		expectedCalls int
+		expectedWaits []time.Duration
+	}{
+		{"First attempt succeeds", 0, false, 1, nil},
+		{"Succeeds on a retry", 2, false, 3, []time.Duration{time.Second, 2 * time.Second}},
+		{"Delay capped at MaxDelay", 4, false, 5, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}},
+		{"Retries run out", 10, true, 5, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &flakySender{failures: tt.failures}
+			retry, waits := newTestRetrySender(sender, config)
+			notification := domain.NewNotification(uuid.New(), domain.HookTypePreToolUse, "localhost:8080", "")
+
+			err := retry.Send(context.Background(), notification)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("Expected error %t, got %v", tt.expectErr, err)
+			}
+			if sender.calls != tt.expectedCalls {
+				t.Errorf("Expected %d attempts, got %d", tt.expectedCalls, sender.calls)
+			}
+			if len(*waits) != len(tt.expectedWaits) {
+				t.Fatalf("Expected waits %v, got %v", tt.expectedWaits, *waits)
+			}
+			for i := range tt.expectedWaits {
+				if (*waits)[i] != tt.expectedWaits[i] {
+					t.Errorf("Expected waits %v, got %v", tt.expectedWaits, *waits)
+					break
+				}
+			}
+
+			failed := retry.GetFailedNotifications()
+			if !tt.expectErr {
+				if len(failed) != 0 {
+					t.Errorf("Expected no failed notifications, got %+v", failed)
+				}
+				return
+			}
+			if len(failed) != 1 {
+				t.Fatalf("Expected 1 failed notification, got %+v", failed)
+			}
+			if failed[0].NotificationID != notification.ID || failed[0].TaskID != notification.TaskID || failed[0].Attempts != 5 {
+				t.Errorf("Unexpected failed notification: %+v", failed[0])
+			}
+			if !strings.Contains(failed[0].Reason, "503") {
+				t.Errorf("Expected the last error as the reason, got %q", failed[0].Reason)
+			}
+		})
+	}
+}
+
+func TestRetryableNotificationSender_StopsWhenContextDone(t *testing.T) {
+	sender := &flakySender{failures: 10}
+	retry, waits := newTestRetrySender(sender, DefaultRetryConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := retry.Send(ctx, domain.NewNotification(uuid.New(), domain.HookTypeStop, "localhost:8080", ""))
+	if err == nil {
+		t.Fatal("Expected an error once the context is done")
+	}
+	if sender.calls != 1 || len(*waits) != 1 {
+		t.Errorf("Expected one attempt and one abandoned wait, got %d attempts and waits %v", sender.calls, *waits)
+	}
+	if failed := retry.GetFailedNotifications(); len(failed) != 1 || !strings.Contains(failed[0].Reason, "context canceled") {
+		t.Errorf("Expected the cancellation in the failure reason, got %+v", failed)
+	}
+}
+
+func TestRetryableNotificationSender_FailedHistoryWraps(t *testing.T) {
+	retry, _ := newTestRetrySender(&flakySender{failures: 1000}, RetryConfig{})
+
+	var last uuid.UUID
+	for i := 0; i < DefaultFailedNotificationHistory+5; i++ {
+		notification := domain.NewNotification(uuid.New(), domain.HookTypeStop, "localhost:8080", "")
+		retry.Send(context.Background(), notification)
+		last = notification.ID
+	}
+
+	failed := retry.GetFailedNotifications()
+	if len(failed) != DefaultFailedNotificationHistory {
+		t.Fatalf("Expected %d failed notifications, got %d", DefaultFailedNotificationHistory, len(failed))
+	}
+	if failed[len(failed)-1].NotificationID != last {
+		t.Error("Expected the most recent failure last")
+	}
+}
+
+func TestRetryableNotificationSender_DestinationFor(t *testing.T) {
+	retry := NewRetryableNotificationSender(&flakySender{}, DefaultRetryConfig())
+	destination := retry.DestinationFor(domain.NewNotification(uuid.New(), domain.HookTypeStop, "localhost:8080", ""))
+	if destination.Channel != "ntfy" || destination.Topic != "claude" {
+		t.Errorf("Expected the wrapped sender's destination, got %+v", destination)
+	}
+}
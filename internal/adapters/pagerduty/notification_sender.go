@@ -0,0 +1,161 @@
+package pagerduty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// DefaultEventsURL is the PagerDuty Events API v2 endpoint
+const DefaultEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// routingKeyLength is the length of a PagerDuty Events API v2 integration key
+const routingKeyLength = 32
+
+// maxSummaryLength is the longest event summary PagerDuty accepts
+const maxSummaryLength = 1024
+
+// Ensure NotificationSender satisfies the NotificationSender port
+var _ ports.NotificationSender = (*NotificationSender)(nil)
+var _ ports.NotificationRouter = (*NotificationSender)(nil)
+
+// Config holds configuration for the PagerDuty notification sender
+type Config struct {
+	RoutingKey string `json:"routing_key"`          // Integration key of the Events API v2 integration
+	EventsURL  string `json:"events_url,omitempty"` // Defaults to DefaultEventsURL
+	Source     string `json:"source,omitempty"`     // Shown as the event source, defaults to "claude-control"
+}
+
+// event is a PagerDuty Events API v2 trigger event
+type event struct {
+	RoutingKey  string       `json:"routing_key"`
+	EventAction string       `json:"event_action"`
+	DedupKey    string       `json:"dedup_key"`
+	Payload     eventPayload `json:"payload"`
+	Links       []eventLink  `json:"links"`
+}
+
+// eventPayload describes the alert raised by an event
+type eventPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Timestamp     string                 `json:"timestamp"`
+	CustomDetails map[string]interface{} `json:"custom_details"`
+}
+
+// eventLink is a link attached to the incident
+type eventLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+// NotificationSender implements the NotificationSender port for PagerDuty
+type NotificationSender struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// NewNotificationSender creates a new PagerDuty notification sender
+func NewNotificationSender(config Config) *NotificationSender {
+	if config.EventsURL == "" {
+		config.EventsURL = DefaultEventsURL
+	}
+	if config.Source == "" {
+		config.Source = "claude-control"
+	}
+
+	return &NotificationSender{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Send triggers a PagerDuty event for the notification, deduplicated by task ID
+func (n *NotificationSender) Send(ctx context.Context, notification *domain.Notification) error {
+	payload := event{
+		RoutingKey:  n.config.RoutingKey,
+		EventAction: "trigger",
+		DedupKey:    notification.TaskID.String(),
+		Payload: eventPayload{
+			Summary:   summarize(notification),
+			Source:    n.config.Source,
+			Severity:  notification.Priority.ToSeverity(),
+			Timestamp: notification.CreatedAt.Format(time.RFC3339),
+			CustomDetails: map[string]interface{}{
+				"task_id":    notification.TaskID.String(),
+				"urgency":    notification.Priority.ToPagerDutyUrgency(),
+				"tags":       notification.Tags,
+				"source_cwd": notification.SourceCWD,
+			},
+		},
+		Links: []eventLink{
+			{Href: notification.ActionURL, Text: "Open Task"},
+		},
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal PagerDuty event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.config.EventsURL, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send PagerDuty event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// The Events API answers 202 Accepted once the event is queued
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("PagerDuty Events API returned status %d", resp.StatusCode)
+	}
+
+	notification.MarkSent()
+
+	return nil
+}
+
+// Verify checks that the routing key and events URL are well formed
+// The Events API has no health or dry-run endpoint, so nothing is sent.
+func (n *NotificationSender) Verify(ctx context.Context) error {
+	if len(n.config.RoutingKey) != routingKeyLength {
+		return fmt.Errorf("PagerDuty routing key must be %d characters, got %d", routingKeyLength, len(n.config.RoutingKey))
+	}
+
+	eventsURL, err := url.Parse(n.config.EventsURL)
+	if err != nil || eventsURL.Scheme == "" || eventsURL.Host == "" {
+		return fmt.Errorf("invalid PagerDuty events URL: %s", n.config.EventsURL)
+	}
+
+	return nil
+}
+
+// DestinationFor reports the PagerDuty channel; the routing key is a secret so no topic is given
+func (n *NotificationSender) DestinationFor(notification *domain.Notification) ports.NotificationDestination {
+	return ports.NotificationDestination{Channel: "pagerduty"}
+}
+
+// summarize builds the event summary from the notification title and message
+func summarize(notification *domain.Notification) string {
+	summary := notification.Title + ": " + notification.Message
+	if len(summary) > maxSummaryLength {
+		summary = strings.ToValidUTF8(summary[:maxSummaryLength], "")
+	}
+	return summary
+}
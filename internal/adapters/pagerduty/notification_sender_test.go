@@ -0,0 +1,103 @@
+package pagerduty
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+const testRoutingKey = "0123456789abcdef0123456789abcdef"
+
+func TestNotificationSender_Send(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode event: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender := NewNotificationSender(Config{RoutingKey: testRoutingKey, EventsURL: server.URL})
+	taskID := uuid.New()
+	notification := domain.NewNotification(taskID, domain.HookTypePreToolUse, "control.example.com", "/srv/haiper")
+	notification.Priority = domain.PriorityUrgent
+
+	if err := sender.Send(context.Background(), notification); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !notification.IsSent() {
+		t.Error("Expected notification to be marked sent")
+	}
+
+	if received["routing_key"] != testRoutingKey {
+		t.Errorf("Expected routing_key %s, got %v", testRoutingKey, received["routing_key"])
+	}
+	if received["event_action"] != "trigger" {
+		t.Errorf("Expected event_action trigger, got %v", received["event_action"])
+	}
+	if received["dedup_key"] != taskID.String() {
+		t.Errorf("Expected dedup_key %s, got %v", taskID, received["dedup_key"])
+	}
+
+	payload, _ := received["payload"].(map[string]interface{})
+	if payload["severity"] != "critical" {
+		t.Errorf("Expected severity critical, got %v", payload["severity"])
+	}
+	if !strings.HasPrefix(payload["summary"].(string), notification.Title) {
+		t.Errorf("Expected summary to start with the title, got %v", payload["summary"])
+	}
+
+	links, _ := received["links"].([]interface{})
+	if len(links) != 1 {
+		t.Fatalf("Expected 1 link, got %v", received["links"])
+	}
+	if href := links[0].(map[string]interface{})["href"]; href != notification.ActionURL {
+		t.Errorf("Expected link href %s, got %v", notification.ActionURL, href)
+	}
+}
+
+func TestNotificationSender_SendErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sender := NewNotificationSender(Config{RoutingKey: testRoutingKey, EventsURL: server.URL})
+	notification := domain.NewNotification(uuid.New(), domain.HookTypeNotification, "localhost:8080", "")
+
+	if err := sender.Send(context.Background(), notification); err == nil {
+		t.Error("Expected error for a rejected event")
+	}
+	if notification.IsSent() {
+		t.Error("Expected notification not to be marked sent")
+	}
+}
+
+func TestNotificationSender_Verify(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    Config
+		expectErr bool
+	}{
+		{"Valid config", Config{RoutingKey: testRoutingKey}, false},
+		{"Missing routing key", Config{}, true},
+		{"Short routing key", Config{RoutingKey: "abc"}, true},
+		{"Invalid events URL", Config{RoutingKey: testRoutingKey, EventsURL: "not a url"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewNotificationSender(tt.config).Verify(context.Background())
+			if (err != nil) != tt.expectErr {
+				t.Errorf("Verify() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
+	}
+}
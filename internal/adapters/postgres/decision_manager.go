@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+var _ ports.TaskDecisionManager = (*PersistentDecisionManager)(nil)
+
+// pendingDecisionWriteTimeout bounds removing a pending decision row, which happens after the request's context may be done
+const pendingDecisionWriteTimeout = 5 * time.Second
+
+// PendingDecision is a row of the pending_decisions table: a blocking webhook waiting on the user
+type PendingDecision struct {
+	ID        int64     `json:"id"`
+	TaskID    string    `json:"task_id"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PersistentDecisionManager records each decision being waited on in the pending_decisions table
+// Decisions are still delivered through the wrapped in-memory manager; the table only outlives it, so a restart
+// leaves a trace of the webhooks it dropped for operators or an external process to find.
+type PersistentDecisionManager struct {
+	ports.TaskDecisionManager
+	db *sql.DB
+}
+
+// NewPersistentDecisionManager wraps decisions so every wait is also stored in PostgreSQL
+func NewPersistentDecisionManager(db *sql.DB, decisions ports.TaskDecisionManager) *PersistentDecisionManager {
+	return &PersistentDecisionManager{TaskDecisionManager: decisions, db: db}
+}
+
+// WaitForDecision waits for a user decision with timeout while the wait is recorded in pending_decisions
+func (m *PersistentDecisionManager) WaitForDecision(ctx context.Context, taskID string, timeout time.Duration) (domain.ActionType, error) {
+	return m.WaitForSessionDecision(ctx, taskID, "", timeout)
+}
+
+// WaitForSessionDecision waits for a user decision with timeout while the wait is recorded in pending_decisions
+func (m *PersistentDecisionManager) WaitForSessionDecision(ctx context.Context, taskID, sessionID string, timeout time.Duration) (domain.ActionType, error) {
+	m.OpenSessionDecision(ctx, taskID, sessionID, timeout)
+	return m.AwaitDecision(ctx, taskID, timeout)
+}
+
+// OpenSessionDecision opens the wrapped manager's decision channel and records the wait in pending_decisions
+// The channel is opened first so a decision sent during the database round-trip isn't lost. Failing to write
+// the row is only logged; the decision itself never depends on the database.
+func (m *PersistentDecisionManager) OpenSessionDecision(ctx context.Context, taskID, sessionID string, timeout time.Duration) {
+	m.TaskDecisionManager.OpenSessionDecision(ctx, taskID, sessionID, timeout)
+	if err := m.insertPending(ctx, taskID, timeout); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// AwaitDecision waits on the wrapped manager and removes the pending_decisions row once the wait ends
+func (m *PersistentDecisionManager) AwaitDecision(ctx context.Context, taskID string, timeout time.Duration) (domain.ActionType, error) {
+	defer func() {
+		deleteCtx, cancel := context.WithTimeout(context.Background(), pendingDecisionWriteTimeout)
+		defer cancel()
+		if err := m.deletePending(deleteCtx, taskID); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}()
+
+	return m.TaskDecisionManager.AwaitDecision(ctx, taskID, timeout)
+}
+
+// insertPending records that a blocking webhook is waiting on the task until timeout passes
+func (m *PersistentDecisionManager) insertPending(ctx context.Context, taskID string, timeout time.Duration) error {
+	query := `
+		INSERT INTO pending_decisions (task_id, created_at, expires_at)
+		VALUES ($1, NOW(), NOW() + make_interval(secs => $2))`
+
+	if _, err := m.db.ExecContext(ctx, query, taskID, timeout.Seconds()); err != nil {
+		return fmt.Errorf("failed to record pending decision for task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// deletePending removes the task's pending decision once it is resolved or has timed out
+func (m *PersistentDecisionManager) deletePending(ctx context.Context, taskID string) error {
+	if _, err := m.db.ExecContext(ctx, `DELETE FROM pending_decisions WHERE task_id = $1`, taskID); err != nil {
+		return fmt.Errorf("failed to remove pending decision for task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// ListPending returns every decision still recorded as pending, oldest first
+func (m *PersistentDecisionManager) ListPending(ctx context.Context) ([]PendingDecision, error) {
+	return m.queryPending(ctx, `SELECT id, task_id, created_at, expires_at FROM pending_decisions ORDER BY created_at, id`)
+}
+
+// queryPending runs a query selecting id, task_id, created_at and expires_at from pending_decisions
+func (m *PersistentDecisionManager) queryPending(ctx context.Context, query string, args ...interface{}) ([]PendingDecision, error) {
+	rows, err := m.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending decisions: %w", err)
+	}
+	defer rows.Close()
+
+	pending := []PendingDecision{}
+	for rows.Next() {
+		var decision PendingDecision
+		if err := rows.Scan(&decision.ID, &decision.TaskID, &decision.CreatedAt, &decision.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan pending decision: %w", err)
+		}
+		pending = append(pending, decision)
+	}
+	return pending, rows.Err()
+}
+
+// WarnUnclaimed logs a warning for each pending decision older than timeout and returns how many there were
+// Run at startup: no webhook can still be waiting on these, so the Claude Code sessions behind them never got an answer.
+func (m *PersistentDecisionManager) WarnUnclaimed(ctx context.Context, timeout time.Duration) (int, error) {
+	query := `
+		SELECT id, task_id, created_at, expires_at FROM pending_decisions
+		WHERE created_at < NOW() - make_interval(secs => $1)
+		ORDER BY created_at, id`
+
+	unclaimed, err := m.queryPending(ctx, query, timeout.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	for _, decision := range unclaimed {
+		log.Printf("Warning: decision for task %s has been pending since %s and was never claimed", decision.TaskID, decision.CreatedAt.Format(time.RFC3339))
+	}
+	return len(unclaimed), nil
+}
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// gatedDecisionManager waits for each decision until the test releases it
+type gatedDecisionManager struct {
+	ports.TaskDecisionManager
+	waiting  chan struct{}
+	decision chan domain.ActionType
+}
+
+func (m *gatedDecisionManager) OpenSessionDecision(ctx context.Context, taskID, sessionID string, timeout time.Duration) {
+}
+
+func (m *gatedDecisionManager) AwaitDecision(ctx context.Context, taskID string, timeout time.Duration) (domain.ActionType, error) {
+	m.waiting <- struct{}{}
+	return <-m.decision, nil
+}
+
+func TestPersistentDecisionManager_WaitForDecision(t *testing.T) {
+	db := openTestDB(t)
+	gated := &gatedDecisionManager{waiting: make(chan struct{}), decision: make(chan domain.ActionType)}
+	manager := NewPersistentDecisionManager(db, gated)
+	ctx := context.Background()
+	taskID := uuid.New().String()
+
+	done := make(chan domain.ActionType, 1)
+	go func() {
+		decision, _ := manager.WaitForDecision(ctx, taskID, time.Minute)
+		done <- decision
+	}()
+	<-gated.waiting
+
+	pending := findPendingDecision(t, manager, taskID)
+	if pending == nil {
+		t.Fatal("Expected a pending decision row while the webhook waits")
+	}
+	if wait := pending.ExpiresAt.Sub(pending.CreatedAt); wait != time.Minute {
+		t.Errorf("Expected the row to expire after the timeout, got %v", wait)
+	}
+
+	gated.decision <- domain.ActionTypeApprove
+	if decision := <-done; decision != domain.ActionTypeApprove {
+		t.Errorf("Expected the wrapped manager's decision, got %q", decision)
+	}
+	if findPendingDecision(t, manager, taskID) != nil {
+		t.Error("Expected the pending decision row to be removed once resolved")
+	}
+}
+
+// orderRecordingDecisionManager notes whether the database had been written to when its channel was opened
+type orderRecordingDecisionManager struct {
+	ports.TaskDecisionManager
+	mock           sqlmock.Sqlmock
+	openedAfterSQL bool
+}
+
+func (m *orderRecordingDecisionManager) OpenSessionDecision(ctx context.Context, taskID, sessionID string, timeout time.Duration) {
+	m.openedAfterSQL = m.mock.ExpectationsWereMet() == nil
+}
+
+func TestPersistentDecisionManager_OpensChannelBeforeInsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	inner := &orderRecordingDecisionManager{mock: mock}
+	manager := NewPersistentDecisionManager(db, inner)
+	taskID := uuid.New().String()
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO pending_decisions")).
+		WithArgs(taskID, time.Minute.Seconds()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	manager.OpenSessionDecision(context.Background(), taskID, "abc123", time.Minute)
+
+	if inner.openedAfterSQL {
+		t.Error("Expected the decision channel to be open before the pending decision row is written")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPersistentDecisionManager_WarnUnclaimed(t *testing.T) {
+	db := openTestDB(t)
+	manager := NewPersistentDecisionManager(db, nil)
+	ctx := context.Background()
+
+	before, err := manager.WarnUnclaimed(ctx, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A row left behind by a server that stopped while a webhook waited an hour ago, and one just written
+	staleTaskID, freshTaskID := uuid.New().String(), uuid.New().String()
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO pending_decisions (task_id, created_at, expires_at)
+		VALUES ($1, NOW() - INTERVAL '1 hour', NOW() - INTERVAL '55 minutes')`, staleTaskID); err != nil {
+		t.Fatalf("Failed to insert stale pending decision: %v", err)
+	}
+	if err := manager.insertPending(ctx, freshTaskID, 5*time.Minute); err != nil {
+		t.Fatalf("Failed to insert fresh pending decision: %v", err)
+	}
+	t.Cleanup(func() {
+		manager.deletePending(context.Background(), staleTaskID)
+		manager.deletePending(context.Background(), freshTaskID)
+	})
+
+	after, err := manager.WarnUnclaimed(ctx, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if after-before != 1 {
+		t.Errorf("Expected only the decision older than the timeout to be reported, got %d more", after-before)
+	}
+}
+
+// findPendingDecision returns the task's pending decision row, or nil if there is none
+func findPendingDecision(t *testing.T, manager *PersistentDecisionManager, taskID string) *PendingDecision {
+	t.Helper()
+
+	pending, err := manager.ListPending(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to list pending decisions: %v", err)
+	}
+	for _, decision := range pending {
+		if decision.TaskID == taskID {
+			return &decision
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/dan/claude-control/internal/core/domain"
 	"github.com/dan/claude-control/internal/core/ports"
@@ -151,26 +152,58 @@ func (r *TaskHistoryRepository) List(ctx context.Context, filter ports.TaskHisto
 	return histories, nil
 }
 
-// DeleteOlderThan removes history entries older than specified duration
-func (r *TaskHistoryRepository) DeleteOlderThan(ctx context.Context, days int) error {
+// DeleteOlderThan removes history entries older than the given number of days and returns how many were deleted
+func (r *TaskHistoryRepository) DeleteOlderThan(ctx context.Context, days int) (int64, error) {
 	query := `
 		DELETE FROM task_history
 		WHERE created_at < NOW() - INTERVAL '%d days'`
 
 	result, err := r.db.ExecContext(ctx, fmt.Sprintf(query, days))
 	if err != nil {
-		return fmt.Errorf("failed to delete old task history: %w", err)
+		return 0, fmt.Errorf("failed to delete old task history: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
-	// Log the number of deleted rows (you might want to use a proper logger)
-	fmt.Printf("Deleted %d old task history entries\n", rowsAffected)
+	return rowsAffected, nil
+}
 
-	return nil
+// GetHourlyActivity counts history events per hour since the given time, oldest hour first
+// Hours without any events are omitted.
+func (r *TaskHistoryRepository) GetHourlyActivity(ctx context.Context, since time.Time) ([]ports.HourlyBucket, error) {
+	query := `
+		SELECT
+			date_trunc('hour', created_at) AS hour,
+			COUNT(*) AS events,
+			COUNT(CASE WHEN action NOT IN ($2, $3) THEN 1 END) AS actions
+		FROM task_history
+		WHERE created_at >= $1
+		GROUP BY 1
+		ORDER BY 1`
+
+	rows, err := r.db.QueryContext(ctx, query, since, domain.HistoryActionCreated, domain.HistoryActionNotified)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hourly activity: %w", err)
+	}
+	defer rows.Close()
+
+	buckets := []ports.HourlyBucket{}
+	for rows.Next() {
+		var bucket ports.HourlyBucket
+		if err := rows.Scan(&bucket.Hour, &bucket.EventCount, &bucket.ActionsTaken); err != nil {
+			return nil, fmt.Errorf("failed to scan hourly activity: %w", err)
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating hourly activity: %w", err)
+	}
+
+	return buckets, nil
 }
 
 // scanTaskHistory scans a database row into a TaskHistory struct
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/dan/claude-control/internal/core/domain"
 	"github.com/dan/claude-control/internal/core/ports"
@@ -13,6 +14,9 @@ import (
 	_ "github.com/lib/pq"
 )
 
+// taskColumns are the columns scanTask reads, in order
+const taskColumns = "id, hook_type, task_data, status, created_at, updated_at, action_taken, response_data, snoozed_until, parent_task_id"
+
 // TaskRepository implements the TaskRepository port for PostgreSQL
 type TaskRepository struct {
 	db *sql.DB
@@ -26,27 +30,22 @@ func NewTaskRepository(db *sql.DB) *TaskRepository {
 // Create stores a new task
 func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
 	query := `
-		INSERT INTO tasks (id, hook_type, task_data, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)`
+		INSERT INTO tasks (id, hook_type, task_data, status, created_at, updated_at, parent_task_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
 
-	// Serialize hook data to JSON for PostgreSQL storage
-	hookDataJSON, err := json.Marshal(task.HookData)
+	hookDataJSON, err := hookDataColumn(task)
 	if err != nil {
-		return fmt.Errorf("failed to marshal hook data: %w", err)
-	}
-	
-	hookDataStr := string(hookDataJSON)
-	if hookDataStr == "" || hookDataStr == "null" {
-		hookDataStr = "{}"
+		return err
 	}
 
 	_, err = r.db.ExecContext(ctx, query,
 		task.ID,
 		task.HookType.String(),
-		hookDataStr,
+		hookDataJSON,
 		task.Status.String(),
 		task.CreatedAt,
 		task.UpdatedAt,
+		task.ParentTaskID,
 	)
 
 	if err != nil {
@@ -59,7 +58,7 @@ func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
 // GetByID retrieves a task by its ID
 func (r *TaskRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Task, error) {
 	query := `
-		SELECT id, hook_type, task_data, status, created_at, updated_at, action_taken, response_data
+		SELECT ` + taskColumns + `
 		FROM tasks
 		WHERE id = $1`
 
@@ -80,7 +79,7 @@ func (r *TaskRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Tas
 func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
 	query := `
 		UPDATE tasks
-		SET hook_type = $2, task_data = $3, status = $4, updated_at = $5, action_taken = $6, response_data = $7
+		SET hook_type = $2, task_data = $3, status = $4, updated_at = $5, action_taken = $6, response_data = $7, snoozed_until = $8
 		WHERE id = $1`
 
 	var actionTaken *string
@@ -98,20 +97,20 @@ func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
 		}
 	}
 
-	// Serialize hook data to JSON for PostgreSQL storage
-	hookDataJSON, err := json.Marshal(task.HookData)
+	hookDataJSON, err := hookDataColumn(task)
 	if err != nil {
-		return fmt.Errorf("failed to marshal hook data: %w", err)
+		return err
 	}
 
 	result, err := r.db.ExecContext(ctx, query,
 		task.ID,
 		task.HookType.String(),
-		string(hookDataJSON), // Serialize structured hook data
+		hookDataJSON,
 		task.Status.String(),
 		task.UpdatedAt,
 		actionTaken,
 		responseDataJSON,
+		task.SnoozedUntil,
 	)
 
 	if err != nil {
@@ -132,28 +131,76 @@ func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
 
 // List retrieves tasks with optional filtering
 func (r *TaskRepository) List(ctx context.Context, filter ports.TaskFilter) ([]*domain.Task, error) {
-	query := "SELECT id, hook_type, task_data, status, created_at, updated_at, action_taken, response_data FROM tasks"
-	args := []interface{}{}
-	conditions := []string{}
-	argIndex := 1
+	return r.listFrom(ctx, "tasks", filter)
+}
 
-	// Add WHERE conditions
-	if filter.Status != nil {
-		conditions = append(conditions, fmt.Sprintf("status = $%d", argIndex))
-		args = append(args, filter.Status.String())
-		argIndex++
+// Count counts the tasks List would return for the filter, ignoring its limit and offset
+func (r *TaskRepository) Count(ctx context.Context, filter ports.TaskFilter) (int64, error) {
+	where, args := taskFilterConditions(filter)
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks"+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count tasks: %w", err)
 	}
 
-	if filter.HookType != nil {
-		conditions = append(conditions, fmt.Sprintf("hook_type = $%d", argIndex))
-		args = append(args, filter.HookType.String())
-		argIndex++
+	return count, nil
+}
+
+// ListArchived retrieves archived tasks with the same filtering as List
+func (r *TaskRepository) ListArchived(ctx context.Context, filter ports.TaskFilter) ([]*domain.Task, error) {
+	return r.listFrom(ctx, "tasks_archive", filter)
+}
+
+// ArchiveCompleted moves tasks that are no longer pending and haven't changed in olderThan to tasks_archive
+func (r *TaskRepository) ArchiveCompleted(ctx context.Context, olderThan time.Duration) (int, error) {
+	cutoff := time.Now().Add(-olderThan)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	archiveQuery := `
+		INSERT INTO tasks_archive (id, hook_type, task_data, status, created_at, updated_at, action_taken, response_data, snoozed_until, parent_task_id, archived_at)
+		SELECT id, hook_type, task_data, status, created_at, updated_at, action_taken, response_data, snoozed_until, parent_task_id, NOW()
+		FROM tasks
+		WHERE status <> $1 AND updated_at < $2
+		ON CONFLICT (id) DO NOTHING`
+
+	result, err := tx.ExecContext(ctx, archiveQuery, domain.TaskStatusPending.String(), cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive tasks: %w", err)
+	}
+
+	archived, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	// Only delete rows that made it into the archive
+	deleteQuery := `
+		DELETE FROM tasks
+		WHERE id IN (SELECT id FROM tasks_archive)`
+
+	if _, err := tx.ExecContext(ctx, deleteQuery); err != nil {
+		return 0, fmt.Errorf("failed to delete archived tasks: %w", err)
 	}
 
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit task archive: %w", err)
 	}
 
+	return int(archived), nil
+}
+
+// listFrom runs a filtered task query against the live or archive table
+func (r *TaskRepository) listFrom(ctx context.Context, table string, filter ports.TaskFilter) ([]*domain.Task, error) {
+	query := "SELECT " + taskColumns + " FROM " + table
+	where, args := taskFilterConditions(filter)
+	query += where
+	argIndex := len(args) + 1
+
 	// Add ORDER BY
 	if filter.SortBy != "" {
 		orderDirection := "ASC"
@@ -177,6 +224,76 @@ func (r *TaskRepository) List(ctx context.Context, filter ports.TaskFilter) ([]*
 		args = append(args, filter.Offset)
 	}
 
+	return r.queryTasks(ctx, query, args...)
+}
+
+// ListBySession retrieves tasks matching the filter ordered by session, oldest first within each session
+// The filter's sort is ignored; tasks without a session ID come last.
+func (r *TaskRepository) ListBySession(ctx context.Context, filter ports.TaskFilter) ([]*domain.Task, error) {
+	query := "SELECT " + taskColumns + " FROM tasks"
+	where, args := taskFilterConditions(filter)
+	query += where + " ORDER BY session_id ASC NULLS LAST, created_at ASC"
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	return r.queryTasks(ctx, query, args...)
+}
+
+// taskFilterConditions builds the WHERE clause and its arguments for a task filter
+func taskFilterConditions(filter ports.TaskFilter) (string, []interface{}) {
+	args := []interface{}{}
+	conditions := []string{}
+
+	if filter.Status != nil {
+		args = append(args, filter.Status.String())
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	if filter.HookType != nil {
+		args = append(args, filter.HookType.String())
+		conditions = append(conditions, fmt.Sprintf("hook_type = $%d", len(args)))
+	}
+
+	// Matches anywhere in the hook data, so commands, file paths and prompts are all searched
+	if filter.SearchQuery != nil && *filter.SearchQuery != "" {
+		args = append(args, "%"+likeEscaper.Replace(*filter.SearchQuery)+"%")
+		conditions = append(conditions, fmt.Sprintf("task_data::text ILIKE $%d", len(args)))
+	}
+
+	if filter.CreatedAfter != nil {
+		args = append(args, *filter.CreatedAfter)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+
+	if filter.CreatedBefore != nil {
+		args = append(args, *filter.CreatedBefore)
+		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", len(args)))
+	}
+
+	if filter.SessionID != nil {
+		args = append(args, *filter.SessionID)
+		conditions = append(conditions, fmt.Sprintf("task_data->'data'->>'session_id' = $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// likeEscaper makes LIKE wildcards in a search query match literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// queryTasks runs a query selecting full task rows and scans the results
+func (r *TaskRepository) queryTasks(ctx context.Context, query string, args ...interface{}) ([]*domain.Task, error) {
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tasks: %w", err)
@@ -230,6 +347,330 @@ func (r *TaskRepository) GetPendingTasks(ctx context.Context) ([]*domain.Task, e
 	return r.List(ctx, filter)
 }
 
+// GetChildTasks retrieves the tasks whose parent is taskID, oldest first
+func (r *TaskRepository) GetChildTasks(ctx context.Context, taskID uuid.UUID) ([]*domain.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE parent_task_id = $1
+		ORDER BY created_at ASC`
+
+	return r.queryTasks(ctx, query, taskID)
+}
+
+// GetPendingTasksForSession retrieves the pending tasks raised by one Claude Code session, oldest first
+// Filters in SQL on the session ID stored in the hook data, served by idx_tasks_pending_session.
+func (r *TaskRepository) GetPendingTasksForSession(ctx context.Context, sessionID string) ([]*domain.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE task_data->'data'->>'session_id' = $1 AND status = $2
+		ORDER BY created_at ASC`
+
+	return r.queryTasks(ctx, query, sessionID, domain.TaskStatusPending.String())
+}
+
+// GetSessionSummary counts one Claude Code session's tasks by status and averages how long its decisions took
+// A session with no tasks comes back with every count at zero.
+func (r *TaskRepository) GetSessionSummary(ctx context.Context, sessionID string) (*ports.SessionSummary, error) {
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = $2),
+			COUNT(*) FILTER (WHERE status = $3),
+			COUNT(*) FILTER (WHERE status = $4),
+			MIN(created_at),
+			MAX(created_at),
+			COALESCE(AVG(EXTRACT(EPOCH FROM (updated_at - created_at)) * 1000)
+				FILTER (WHERE action_taken IS NOT NULL), 0)
+		FROM tasks
+		WHERE task_data->'data'->>'session_id' = $1`
+
+	summary := &ports.SessionSummary{SessionID: sessionID}
+	var firstSeen, lastSeen sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, sessionID,
+		domain.TaskStatusPending.String(), domain.TaskStatusApproved.String(), domain.TaskStatusRejected.String(),
+	).Scan(
+		&summary.TotalTasks,
+		&summary.PendingTasks,
+		&summary.ApprovedTasks,
+		&summary.RejectedTasks,
+		&firstSeen,
+		&lastSeen,
+		&summary.AvgDecisionDurationMs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session summary: %w", err)
+	}
+
+	summary.FirstSeen = firstSeen.Time
+	summary.LastSeen = lastSeen.Time
+	return summary, nil
+}
+
+// GetExpiredSnoozes retrieves pending tasks whose snooze ended at or before now
+func (r *TaskRepository) GetExpiredSnoozes(ctx context.Context, now time.Time) ([]*domain.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE snoozed_until IS NOT NULL AND snoozed_until <= $1 AND status = $2
+		ORDER BY snoozed_until ASC`
+
+	return r.queryTasks(ctx, query, now, domain.TaskStatusPending.String())
+}
+
+// ClearSnooze removes a pending task's snooze, returning false if the task was resolved or unsnoozed meanwhile
+// Only snoozed_until is written so a concurrent decision on the task is never overwritten.
+func (r *TaskRepository) ClearSnooze(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `
+		UPDATE tasks
+		SET snoozed_until = NULL
+		WHERE id = $1 AND status = $2 AND snoozed_until IS NOT NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id, domain.TaskStatusPending.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to clear snooze: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetToolUsageStats aggregates tool calls created at or after since, most used tool first
+// Approvals and rejections come from the action taken; a failed task with no action timed out waiting
+// for a decision. Decision latency only averages over approved and rejected calls.
+func (r *TaskRepository) GetToolUsageStats(ctx context.Context, since time.Time) ([]ports.ToolUsageStat, error) {
+	query := `
+		SELECT
+			task_data->'data'->>'tool_name' AS tool_name,
+			COUNT(*) AS call_count,
+			COUNT(CASE WHEN action_taken = $2 THEN 1 END) AS approval_count,
+			COUNT(CASE WHEN action_taken = $3 THEN 1 END) AS rejection_count,
+			COUNT(CASE WHEN status = $4 AND action_taken IS NULL THEN 1 END) AS timeout_count,
+			COALESCE(AVG(CASE WHEN action_taken IN ($2, $3)
+				THEN EXTRACT(EPOCH FROM (updated_at - created_at)) * 1000 END), 0) AS avg_decision_latency_ms
+		FROM tasks
+		WHERE created_at >= $1 AND task_data->'data'->>'tool_name' IS NOT NULL
+		GROUP BY tool_name
+		ORDER BY call_count DESC, tool_name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, since,
+		string(domain.ActionTypeApprove), string(domain.ActionTypeReject), domain.TaskStatusFailed.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tool usage stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []ports.ToolUsageStat{}
+	for rows.Next() {
+		var stat ports.ToolUsageStat
+		if err := rows.Scan(
+			&stat.ToolName,
+			&stat.CallCount,
+			&stat.ApprovalCount,
+			&stat.RejectionCount,
+			&stat.TimeoutCount,
+			&stat.AvgDecisionLatencyMs,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan tool usage stat: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tool usage stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetTaskStats counts the tasks created since a point in time by hook type, status and action taken,
+// with the average and 95th percentile time to a decision. The breakdowns come from one grouped query:
+// each grouping set yields one row per key, and the empty set yields the overall totals.
+func (r *TaskRepository) GetTaskStats(ctx context.Context, since time.Time) (*ports.TaskStats, error) {
+	query := `
+		SELECT
+			CASE
+				WHEN GROUPING(hook_type) = 0 THEN 'hook_type'
+				WHEN GROUPING(status) = 0 THEN 'status'
+				WHEN GROUPING(action_taken) = 0 THEN 'action'
+				ELSE 'total'
+			END AS dimension,
+			COALESCE(hook_type, status, action_taken, '') AS key,
+			COUNT(*) AS task_count,
+			COUNT(*) FILTER (WHERE status = $2) AS pending_count,
+			COALESCE(AVG(EXTRACT(EPOCH FROM (updated_at - created_at)) * 1000)
+				FILTER (WHERE action_taken IS NOT NULL), 0) AS avg_decision_duration_ms,
+			COALESCE(PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (updated_at - created_at)) * 1000)
+				FILTER (WHERE action_taken IS NOT NULL), 0) AS p95_decision_duration_ms
+		FROM tasks
+		WHERE created_at >= $1
+		GROUP BY GROUPING SETS ((hook_type), (status), (action_taken), ())`
+
+	rows, err := r.db.QueryContext(ctx, query, since, domain.TaskStatusPending.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &ports.TaskStats{
+		ByHookType: make(map[domain.HookType]int64),
+		ByStatus:   make(map[string]int64),
+		ByAction:   make(map[domain.ActionType]int64),
+	}
+	for rows.Next() {
+		var dimension, key string
+		var count, pending int64
+		var avgMs, p95Ms float64
+		if err := rows.Scan(&dimension, &key, &count, &pending, &avgMs, &p95Ms); err != nil {
+			return nil, fmt.Errorf("failed to scan task stats row: %w", err)
+		}
+
+		switch dimension {
+		case "hook_type":
+			stats.ByHookType[domain.HookType(key)] = count
+		case "status":
+			stats.ByStatus[key] = count
+		case "action":
+			// Tasks still awaiting a decision group under a NULL action
+			if key != "" {
+				stats.ByAction[domain.ActionType(key)] = count
+			}
+		case "total":
+			stats.TotalTasks = count
+			stats.PendingTasks = pending
+			stats.AvgDecisionDurationMs = avgMs
+			stats.P95DecisionDurationMs = p95Ms
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetCompactStats counts the PreCompact tasks created since a point in time by trigger and session,
+// with the average transcript line count over those whose transcript was read
+func (r *TaskRepository) GetCompactStats(ctx context.Context, since time.Time) (*ports.CompactStats, error) {
+	query := `
+		SELECT
+			COALESCE(task_data->'data'->>'session_id', '') AS session_id,
+			COUNT(*) AS compact_count,
+			COUNT(*) FILTER (WHERE task_data->'data'->>'trigger' = 'auto') AS auto_count,
+			COUNT(*) FILTER (WHERE task_data->'data'->>'trigger' = 'manual') AS manual_count,
+			COALESCE(SUM((task_data->'data'->>'transcript_lines')::bigint), 0) AS transcript_lines,
+			COUNT(task_data->'data'->'transcript_lines') AS counted
+		FROM tasks
+		WHERE hook_type = $1 AND created_at >= $2
+		GROUP BY 1`
+
+	rows, err := r.db.QueryContext(ctx, query, domain.HookTypePreCompact.String(), since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compact stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &ports.CompactStats{BySession: make(map[string]int64)}
+	var transcriptLines, counted int64
+	for rows.Next() {
+		var sessionID string
+		var total, auto, manual, lines, sessionCounted int64
+		if err := rows.Scan(&sessionID, &total, &auto, &manual, &lines, &sessionCounted); err != nil {
+			return nil, fmt.Errorf("failed to scan compact stats row: %w", err)
+		}
+
+		stats.TotalCompacts += total
+		stats.AutoCompacts += auto
+		stats.ManualCompacts += manual
+		transcriptLines += lines
+		counted += sessionCounted
+		if sessionID != "" {
+			stats.BySession[sessionID] = total
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating compact stats: %w", err)
+	}
+
+	if counted > 0 {
+		stats.AvgTranscriptLines = float64(transcriptLines) / float64(counted)
+	}
+	return stats, nil
+}
+
+// CountConcurrentSessions counts the distinct sessions with a pending task created after since
+func (r *TaskRepository) CountConcurrentSessions(ctx context.Context, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(DISTINCT task_data->'data'->>'session_id')
+		FROM tasks
+		WHERE status = $1 AND created_at > $2`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, domain.TaskStatusPending.String(), since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count concurrent sessions: %w", err)
+	}
+	return count, nil
+}
+
+// CountByStatus returns the number of tasks in each status
+// Statuses with no tasks are absent from the map.
+func (r *TaskRepository) CountByStatus(ctx context.Context) (map[domain.TaskStatus]int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT status, COUNT(*) FROM tasks GROUP BY status`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks by status: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[domain.TaskStatus]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan task status count: %w", err)
+		}
+		counts[domain.TaskStatus(status)] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task status counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// CountByHookType returns the number of tasks raised by each hook type
+// Hook types with no tasks are absent from the map.
+func (r *TaskRepository) CountByHookType(ctx context.Context) (map[domain.HookType]int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT hook_type, COUNT(*) FROM tasks GROUP BY hook_type`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks by hook type: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[domain.HookType]int)
+	for rows.Next() {
+		var hookType string
+		var count int
+		if err := rows.Scan(&hookType, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan hook type count: %w", err)
+		}
+		counts[domain.HookType(hookType)] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating hook type counts: %w", err)
+	}
+
+	return counts, nil
+}
+
 // GetTasksByHookType retrieves tasks filtered by hook type
 func (r *TaskRepository) GetTasksByHookType(ctx context.Context, hookType domain.HookType) ([]*domain.Task, error) {
 	filter := ports.TaskFilter{
@@ -240,67 +681,71 @@ func (r *TaskRepository) GetTasksByHookType(ctx context.Context, hookType domain
 	return r.List(ctx, filter)
 }
 
+// taskRecord is a tasks row in the JSON form of domain.Task.Serialize, so rows are decoded by domain.DeserializeTask
+type taskRecord struct {
+	ID           uuid.UUID       `json:"id"`
+	HookType     string          `json:"hook_type"`
+	HookData     json.RawMessage `json:"hook_data"`
+	Status       string          `json:"status"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+	ActionTaken  *string         `json:"action_taken,omitempty"`
+	ResponseData json.RawMessage `json:"response_data,omitempty"`
+	SnoozedUntil *time.Time      `json:"snoozed_until,omitempty"`
+	ParentTaskID *uuid.UUID      `json:"parent_task_id,omitempty"`
+}
+
+// hookDataColumn returns the task's hook data for the task_data column, exactly as Serialize encodes it
+func hookDataColumn(task *domain.Task) (string, error) {
+	data, err := task.Serialize()
+	if err != nil {
+		return "", err
+	}
+
+	var record taskRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return "", fmt.Errorf("failed to read serialized task: %w", err)
+	}
+	if len(record.HookData) == 0 || string(record.HookData) == "null" {
+		return "{}", nil
+	}
+	return string(record.HookData), nil
+}
+
 // scanTask scans a database row into a Task struct
+// The row goes through domain.DeserializeTask, which restores the concrete data struct for the hook type,
+// so accessors such as GetSessionID work on loaded tasks as they do on new ones.
 func (r *TaskRepository) scanTask(scanner interface {
 	Scan(dest ...interface{}) error
 }) (*domain.Task, error) {
-	var task domain.Task
-	var hookTypeStr, statusStr string
-	var actionTakenStr *string
-	var responseDataJSON []byte
-	var hookDataJSON []byte
+	var record taskRecord
+	var hookDataJSON, responseDataJSON []byte
 
 	err := scanner.Scan(
-		&task.ID,
-		&hookTypeStr,
+		&record.ID,
+		&record.HookType,
 		&hookDataJSON,
-		&statusStr,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-		&actionTakenStr,
+		&record.Status,
+		&record.CreatedAt,
+		&record.UpdatedAt,
+		&record.ActionTaken,
 		&responseDataJSON,
+		&record.SnoozedUntil,
+		&record.ParentTaskID,
 	)
-
 	if err != nil {
 		return nil, err
 	}
+	record.HookData = hookDataJSON
+	record.ResponseData = responseDataJSON
 
-	// Parse hook type
-	hookType, err := domain.ParseHookType(hookTypeStr)
+	data, err := json.Marshal(record)
 	if err != nil {
-		return nil, fmt.Errorf("invalid hook type in database: %s", hookTypeStr)
+		return nil, fmt.Errorf("failed to encode task %s: %w", record.ID, err)
 	}
-	task.HookType = hookType
-
-	// Parse hook data
-	if len(hookDataJSON) > 0 {
-		var hookData domain.HookData
-		if err := json.Unmarshal(hookDataJSON, &hookData); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal hook data: %w", err)
-		}
-		task.HookData = &hookData
-	}
-
-	// Parse status
-	task.Status = domain.TaskStatus(statusStr)
-	if !task.Status.IsValid() {
-		return nil, fmt.Errorf("invalid status in database: %s", statusStr)
-	}
-
-	// Parse action taken
-	if actionTakenStr != nil {
-		actionType := domain.ActionType(*actionTakenStr)
-		task.ActionTaken = &actionType
-	}
-
-	// Parse response data
-	if responseDataJSON != nil {
-		var responseData map[string]interface{}
-		if err := json.Unmarshal(responseDataJSON, &responseData); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal response data: %w", err)
-		}
-		task.ResponseData = responseData
+	task, err := domain.DeserializeTask(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid task %s in database: %w", record.ID, err)
 	}
-
-	return &task, nil
-}
\ No newline at end of file
+	return task, nil
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	_ "github.com/lib/pq"
+)
+
+const (
+	benchSessionCount    = 50
+	benchTasksPerSession = 40
+	benchTargetSessionID = "bench-session-0"
+)
+
+// openTestDB connects to TEST_DATABASE_URL, skipping the test or benchmark if it isn't set
+// The database must already have the schema from init.sql applied.
+func openTestDB(tb testing.TB) *sql.DB {
+	tb.Helper()
+
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		tb.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		tb.Fatalf("Failed to open database: %v", err)
+	}
+	tb.Cleanup(func() { db.Close() })
+
+	if err := db.Ping(); err != nil {
+		tb.Fatalf("Failed to ping database: %v", err)
+	}
+	return db
+}
+
+// seedPendingTasks creates pending tasks spread across many sessions and removes them when the benchmark ends
+func seedPendingTasks(b *testing.B, repo *TaskRepository) {
+	b.Helper()
+	ctx := context.Background()
+
+	for s := 0; s < benchSessionCount; s++ {
+		for i := 0; i < benchTasksPerSession; i++ {
+			task := domain.NewTask(&domain.HookData{
+				Type: domain.HookTypePreToolUse,
+				Data: &domain.PreToolUseHookData{
+					BaseHookData: domain.BaseHookData{
+						HookEventName: "PreToolUse",
+						SessionID:     fmt.Sprintf("bench-session-%d", s),
+					},
+					ToolName:  "Bash",
+					ToolInput: &domain.ToolInput{Command: "ls -la"},
+				},
+			})
+			if err := repo.Create(ctx, task); err != nil {
+				b.Fatalf("Failed to seed task: %v", err)
+			}
+			taskID := task.ID
+			b.Cleanup(func() { repo.Delete(context.Background(), taskID) })
+		}
+	}
+}
+
+// BenchmarkGetPendingTasks_FilterBySessionInGo is the old approach: load every pending task, then filter
+func BenchmarkGetPendingTasks_FilterBySessionInGo(b *testing.B) {
+	repo := NewTaskRepository(openTestDB(b))
+	seedPendingTasks(b, repo)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tasks, err := repo.GetPendingTasks(ctx)
+		if err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+
+		var sessionTasks []*domain.Task
+		for _, task := range tasks {
+			if task.HookData.GetSessionID() == benchTargetSessionID {
+				sessionTasks = append(sessionTasks, task)
+			}
+		}
+		if len(sessionTasks) < benchTasksPerSession {
+			b.Fatalf("Expected at least %d tasks, got %d", benchTasksPerSession, len(sessionTasks))
+		}
+	}
+}
+
+// BenchmarkGetPendingTasksForSession filters by session in SQL using idx_tasks_pending_session
+func BenchmarkGetPendingTasksForSession(b *testing.B) {
+	repo := NewTaskRepository(openTestDB(b))
+	seedPendingTasks(b, repo)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tasks, err := repo.GetPendingTasksForSession(ctx, benchTargetSessionID)
+		if err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+		if len(tasks) < benchTasksPerSession {
+			b.Fatalf("Expected at least %d tasks, got %d", benchTasksPerSession, len(tasks))
+		}
+	}
+}
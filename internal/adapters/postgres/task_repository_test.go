@@ -0,0 +1,533 @@
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+func TestTaskRepository_CountByStatusAndHookType(t *testing.T) {
+	repo := NewTaskRepository(openTestDB(t))
+	ctx := context.Background()
+
+	statusesBefore, err := repo.CountByStatus(ctx)
+	if err != nil {
+		t.Fatalf("Failed to count tasks by status: %v", err)
+	}
+	hookTypesBefore, err := repo.CountByHookType(ctx)
+	if err != nil {
+		t.Fatalf("Failed to count tasks by hook type: %v", err)
+	}
+
+	created := []struct {
+		hookType domain.HookType
+		status   domain.TaskStatus
+	}{
+		{domain.HookTypePreToolUse, domain.TaskStatusPending},
+		{domain.HookTypePreToolUse, domain.TaskStatusPending},
+		{domain.HookTypePreToolUse, domain.TaskStatusApproved},
+		{domain.HookTypeStop, domain.TaskStatusRejected},
+	}
+	for _, c := range created {
+		task := domain.NewTask(&domain.HookData{
+			Type: c.hookType,
+			Data: &domain.BaseHookData{HookEventName: c.hookType.String(), SessionID: "count-test-session"},
+		})
+		task.Status = c.status
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		taskID := task.ID
+		t.Cleanup(func() { repo.Delete(context.Background(), taskID) })
+	}
+
+	statusesAfter, err := repo.CountByStatus(ctx)
+	if err != nil {
+		t.Fatalf("Failed to count tasks by status: %v", err)
+	}
+	expectedStatuses := map[domain.TaskStatus]int{
+		domain.TaskStatusPending:  2,
+		domain.TaskStatusApproved: 1,
+		domain.TaskStatusRejected: 1,
+	}
+	for status, added := range expectedStatuses {
+		if got := statusesAfter[status] - statusesBefore[status]; got != added {
+			t.Errorf("Expected %d more %s tasks, got %d", added, status, got)
+		}
+	}
+
+	hookTypesAfter, err := repo.CountByHookType(ctx)
+	if err != nil {
+		t.Fatalf("Failed to count tasks by hook type: %v", err)
+	}
+	expectedHookTypes := map[domain.HookType]int{
+		domain.HookTypePreToolUse: 3,
+		domain.HookTypeStop:       1,
+	}
+	for hookType, added := range expectedHookTypes {
+		if got := hookTypesAfter[hookType] - hookTypesBefore[hookType]; got != added {
+			t.Errorf("Expected %d more %s tasks, got %d", added, hookType, got)
+		}
+	}
+}
+
+func TestTaskRepository_CountConcurrentSessions(t *testing.T) {
+	repo := NewTaskRepository(openTestDB(t))
+	ctx := context.Background()
+	since := time.Now().Add(-time.Second)
+
+	created := []struct {
+		sessionID string
+		status    domain.TaskStatus
+	}{
+		{"concurrent-test-a", domain.TaskStatusPending},
+		{"concurrent-test-a", domain.TaskStatusPending},
+		{"concurrent-test-b", domain.TaskStatusPending},
+		{"concurrent-test-c", domain.TaskStatusApproved},
+	}
+	for _, c := range created {
+		task := domain.NewTask(&domain.HookData{
+			Type: domain.HookTypePreToolUse,
+			Data: &domain.PreToolUseHookData{
+				BaseHookData: domain.BaseHookData{HookEventName: "PreToolUse", SessionID: c.sessionID},
+				ToolName:     "Bash",
+				ToolInput:    &domain.ToolInput{Command: "ls"},
+			},
+		})
+		task.Status = c.status
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		taskID := task.ID
+		t.Cleanup(func() { repo.Delete(context.Background(), taskID) })
+	}
+
+	count, err := repo.CountConcurrentSessions(ctx, since)
+	if err != nil {
+		t.Fatalf("Failed to count concurrent sessions: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 sessions with pending tasks, got %d", count)
+	}
+}
+
+func TestTaskRepository_GetSessionSummary(t *testing.T) {
+	repo := NewTaskRepository(openTestDB(t))
+	ctx := context.Background()
+
+	decisions := []struct {
+		status domain.TaskStatus
+		action domain.ActionType
+	}{
+		{domain.TaskStatusPending, ""},
+		{domain.TaskStatusPending, ""},
+		{domain.TaskStatusApproved, domain.ActionTypeApprove},
+		{domain.TaskStatusRejected, domain.ActionTypeReject},
+	}
+	for _, d := range decisions {
+		task := domain.NewTask(&domain.HookData{
+			Type: domain.HookTypePreToolUse,
+			Data: &domain.BaseHookData{HookEventName: "PreToolUse", SessionID: "summary-test-session"},
+		})
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		taskID := task.ID
+		t.Cleanup(func() { repo.Delete(context.Background(), taskID) })
+
+		if d.action == "" {
+			continue
+		}
+		// Each decision takes ten seconds
+		action := d.action
+		task.Status = d.status
+		task.ActionTaken = &action
+		task.UpdatedAt = task.CreatedAt.Add(10 * time.Second)
+		if err := repo.Update(ctx, task); err != nil {
+			t.Fatalf("Failed to update task: %v", err)
+		}
+	}
+
+	summary, err := repo.GetSessionSummary(ctx, "summary-test-session")
+	if err != nil {
+		t.Fatalf("Failed to get session summary: %v", err)
+	}
+	if summary.TotalTasks != 4 || summary.PendingTasks != 2 || summary.ApprovedTasks != 1 || summary.RejectedTasks != 1 {
+		t.Errorf("Expected 4 tasks (2 pending, 1 approved, 1 rejected), got %+v", summary)
+	}
+	if summary.FirstSeen.IsZero() || summary.LastSeen.Before(summary.FirstSeen) {
+		t.Errorf("Expected first and last seen times, got %v and %v", summary.FirstSeen, summary.LastSeen)
+	}
+	if summary.AvgDecisionDurationMs < 9000 || summary.AvgDecisionDurationMs > 11000 {
+		t.Errorf("Expected an average decision of about 10s, got %vms", summary.AvgDecisionDurationMs)
+	}
+
+	empty, err := repo.GetSessionSummary(ctx, "summary-test-unknown-session")
+	if err != nil {
+		t.Fatalf("Failed to get summary of an unknown session: %v", err)
+	}
+	if empty.TotalTasks != 0 || !empty.FirstSeen.IsZero() {
+		t.Errorf("Expected an empty summary for an unknown session, got %+v", empty)
+	}
+}
+
+func TestTaskRepository_GetCompactStats(t *testing.T) {
+	repo := NewTaskRepository(openTestDB(t))
+	ctx := context.Background()
+	since := time.Now().Add(-time.Minute)
+
+	before, err := repo.GetCompactStats(ctx, since)
+	if err != nil {
+		t.Fatalf("Failed to get compact stats: %v", err)
+	}
+
+	compacts := []struct {
+		sessionID       string
+		trigger         string
+		transcriptLines int
+	}{
+		{"compact-test-busy", "auto", 1200},
+		{"compact-test-busy", "auto", 800},
+		{"compact-test-busy", "manual", 0},
+		{"compact-test-quiet", "manual", 0},
+	}
+	for _, c := range compacts {
+		task := domain.NewTask(&domain.HookData{
+			Type: domain.HookTypePreCompact,
+			Data: &domain.PreCompactHookData{
+				BaseHookData:    domain.BaseHookData{HookEventName: "PreCompact", SessionID: c.sessionID},
+				Trigger:         c.trigger,
+				TranscriptLines: c.transcriptLines,
+			},
+		})
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		taskID := task.ID
+		t.Cleanup(func() { repo.Delete(context.Background(), taskID) })
+	}
+
+	after, err := repo.GetCompactStats(ctx, since)
+	if err != nil {
+		t.Fatalf("Failed to get compact stats: %v", err)
+	}
+	if after.TotalCompacts-before.TotalCompacts != 4 || after.AutoCompacts-before.AutoCompacts != 2 || after.ManualCompacts-before.ManualCompacts != 2 {
+		t.Errorf("Expected 4 more compacts (2 auto, 2 manual), went from %+v to %+v", before, after)
+	}
+	if after.BySession["compact-test-busy"] != 3 || after.BySession["compact-test-quiet"] != 1 {
+		t.Errorf("Expected 3 and 1 compacts by session, got %v", after.BySession)
+	}
+	if before.TotalCompacts == 0 && after.AvgTranscriptLines != 1000 {
+		t.Errorf("Expected the average over the two counted transcripts, got %v", after.AvgTranscriptLines)
+	}
+}
+
+func TestTaskRepository_GetByIDRestoresHookData(t *testing.T) {
+	repo := NewTaskRepository(openTestDB(t))
+	ctx := context.Background()
+
+	task := domain.NewTask(&domain.HookData{
+		Type: domain.HookTypePreToolUse,
+		Data: &domain.PreToolUseHookData{
+			BaseHookData: domain.BaseHookData{HookEventName: "PreToolUse", SessionID: "round-trip-session"},
+			ToolName:     "Bash",
+			ToolInput:    &domain.ToolInput{Command: "go test ./..."},
+		},
+	})
+	if err := repo.Create(ctx, task); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	t.Cleanup(func() { repo.Delete(context.Background(), task.ID) })
+
+	loaded, err := repo.GetByID(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if loaded.HookData == nil {
+		t.Fatal("Expected hook data to be restored")
+	}
+	if _, ok := loaded.HookData.Data.(*domain.PreToolUseHookData); !ok {
+		t.Errorf("Expected *domain.PreToolUseHookData, got %T", loaded.HookData.Data)
+	}
+	if got := loaded.HookData.GetSessionID(); got != "round-trip-session" {
+		t.Errorf("Expected session ID round-trip-session, got %q", got)
+	}
+	if got := loaded.HookData.GetCommand(); got != "go test ./..." {
+		t.Errorf("Expected command to survive the round trip, got %q", got)
+	}
+}
+
+func TestTaskRepository_ScanTaskDeserializesRow(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := NewTaskRepository(db)
+
+	task := domain.NewTask(&domain.HookData{
+		Type: domain.HookTypePostToolUse,
+		Data: &domain.PostToolUseHookData{
+			BaseHookData: domain.BaseHookData{HookEventName: "PostToolUse", SessionID: "abc123"},
+			ToolName:     "Bash",
+			ToolResponse: &domain.ToolResponse{Stdout: "ok", Success: true},
+		},
+	})
+	hookDataJSON, err := hookDataColumn(task)
+	if err != nil {
+		t.Fatalf("Failed to encode hook data: %v", err)
+	}
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO tasks")).
+		WithArgs(task.ID, "PostToolUse", hookDataJSON, "pending", task.CreatedAt, task.UpdatedAt, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := repo.Create(context.Background(), task); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+
+	createdAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "hook_type", "task_data", "status", "created_at", "updated_at", "action_taken", "response_data", "snoozed_until", "parent_task_id"}).
+		AddRow(task.ID, "PostToolUse", []byte(hookDataJSON), "approved", createdAt, createdAt, "approve", []byte(`{"comment":"ok"}`), nil, nil)
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE id = $1")).WithArgs(task.ID).WillReturnRows(rows)
+
+	loaded, err := repo.GetByID(context.Background(), task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get task: %v", err)
+	}
+	if data, ok := loaded.HookData.Data.(*domain.PostToolUseHookData); !ok || data.ToolResponse.Stdout != "ok" {
+		t.Errorf("Expected the concrete PostToolUse data, got %#v", loaded.HookData.Data)
+	}
+	if loaded.Status != domain.TaskStatusApproved || loaded.ActionTaken == nil || *loaded.ActionTaken != domain.ActionTypeApprove {
+		t.Errorf("Expected an approved task, got %s with %v", loaded.Status, loaded.ActionTaken)
+	}
+	if loaded.ResponseData["comment"] != "ok" || !loaded.CreatedAt.Equal(createdAt) {
+		t.Errorf("Expected the row's response data and times, got %+v", loaded)
+	}
+
+	rows = sqlmock.NewRows([]string{"id", "hook_type", "task_data", "status", "created_at", "updated_at", "action_taken", "response_data", "snoozed_until", "parent_task_id"}).
+		AddRow(task.ID, "PostToolUse", []byte(hookDataJSON), "waiting", createdAt, createdAt, nil, nil, nil, nil)
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE id = $1")).WithArgs(task.ID).WillReturnRows(rows)
+	if _, err := repo.GetByID(context.Background(), task.ID); err == nil {
+		t.Error("Expected an error for a row with an unknown status")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTaskRepository_DeleteRemovesHistory(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTaskRepository(db)
+	historyRepo := NewTaskHistoryRepository(db)
+	ctx := context.Background()
+
+	task := domain.NewTask(&domain.HookData{
+		Type: domain.HookTypeStop,
+		Data: &domain.StopHookData{BaseHookData: domain.BaseHookData{HookEventName: "Stop", SessionID: "delete-test-session"}},
+	})
+	task.Status = domain.TaskStatusCompleted
+	if err := repo.Create(ctx, task); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if err := historyRepo.Create(ctx, domain.NewTaskHistory(task.ID, domain.HistoryActionCreated, nil)); err != nil {
+		t.Fatalf("Failed to create history: %v", err)
+	}
+
+	if err := repo.Delete(ctx, task.ID); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+
+	if _, err := repo.GetByID(ctx, task.ID); err == nil {
+		t.Error("Expected the task to be gone")
+	}
+	history, err := historyRepo.GetByTaskID(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("Failed to get history: %v", err)
+	}
+	if len(history) != 0 {
+		t.Errorf("Expected history to be deleted with the task, got %d entries", len(history))
+	}
+	if err := repo.Delete(ctx, task.ID); err == nil {
+		t.Error("Expected deleting a missing task to fail")
+	}
+}
+
+func TestTaskFilterConditions(t *testing.T) {
+	status := domain.TaskStatusPending
+	search := "docker"
+	wildcards := "100%_done"
+	empty := ""
+	sessionID := "abc123"
+	after := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	before := after.Add(24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		filter        ports.TaskFilter
+		expectedWhere string
+		expectedArgs  []interface{}
+	}{
+		{"no filter", ports.TaskFilter{}, "", []interface{}{}},
+		{"empty search", ports.TaskFilter{SearchQuery: &empty}, "", []interface{}{}},
+		{"search", ports.TaskFilter{SearchQuery: &search}, " WHERE task_data::text ILIKE $1", []interface{}{"%docker%"}},
+		{
+			"status and search",
+			ports.TaskFilter{Status: &status, SearchQuery: &search},
+			" WHERE status = $1 AND task_data::text ILIKE $2",
+			[]interface{}{"pending", "%docker%"},
+		},
+		{"wildcards match literally", ports.TaskFilter{SearchQuery: &wildcards}, " WHERE task_data::text ILIKE $1", []interface{}{`%100\%\_done%`}},
+		{"session", ports.TaskFilter{SessionID: &sessionID}, " WHERE task_data->'data'->>'session_id' = $1", []interface{}{"abc123"}},
+		{
+			"date range",
+			ports.TaskFilter{CreatedAfter: &after, CreatedBefore: &before},
+			" WHERE created_at >= $1 AND created_at <= $2",
+			[]interface{}{after, before},
+		},
+		{
+			"status, date range and session",
+			ports.TaskFilter{Status: &status, CreatedAfter: &after, SessionID: &sessionID},
+			" WHERE status = $1 AND created_at >= $2 AND task_data->'data'->>'session_id' = $3",
+			[]interface{}{"pending", after, "abc123"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, args := taskFilterConditions(tt.filter)
+			if where != tt.expectedWhere {
+				t.Errorf("Expected %q, got %q", tt.expectedWhere, where)
+			}
+			if !reflect.DeepEqual(args, tt.expectedArgs) {
+				t.Errorf("Expected args %v, got %v", tt.expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestTaskRepository_ListDateRangeAndSession(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := NewTaskRepository(db)
+
+	sessionID := "abc123"
+	after := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	before := after.Add(24 * time.Hour)
+	taskID := uuid.New()
+
+	rows := sqlmock.NewRows([]string{"id", "hook_type", "task_data", "status", "created_at", "updated_at", "action_taken", "response_data", "snoozed_until", "parent_task_id"}).
+		AddRow(taskID, "PreToolUse", []byte(`{"type":"PreToolUse","data":{"session_id":"abc123","tool_name":"Bash"}}`), "pending", after, after, nil, nil, nil, nil)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM tasks WHERE created_at >= $1 AND created_at <= $2 AND task_data->'data'->>'session_id' = $3 ORDER BY created_at DESC LIMIT $4")).
+		WithArgs(after, before, sessionID, 10).
+		WillReturnRows(rows)
+
+	tasks, err := repo.List(context.Background(), ports.TaskFilter{CreatedAfter: &after, CreatedBefore: &before, SessionID: &sessionID, Limit: 10})
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != taskID || tasks[0].HookData.GetSessionID() != sessionID {
+		t.Errorf("Expected the session's task, got %+v", tasks)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTaskRepository_GetChildTasks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := NewTaskRepository(db)
+
+	parentID, childID := uuid.New(), uuid.New()
+	createdAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "hook_type", "task_data", "status", "created_at", "updated_at", "action_taken", "response_data", "snoozed_until", "parent_task_id"}).
+		AddRow(childID, "SubagentStop", []byte(`{"type":"SubagentStop","data":{"session_id":"abc123"}}`), "pending", createdAt, createdAt, nil, nil, nil, parentID.String())
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE parent_task_id = $1")).
+		WithArgs(parentID).
+		WillReturnRows(rows)
+
+	children, err := repo.GetChildTasks(context.Background(), parentID)
+	if err != nil {
+		t.Fatalf("Failed to get child tasks: %v", err)
+	}
+	if len(children) != 1 || children[0].ID != childID || children[0].ParentTaskID == nil || *children[0].ParentTaskID != parentID {
+		t.Errorf("Expected the child task linked to its parent, got %+v", children)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTaskRepository_Count(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	defer db.Close()
+	repo := NewTaskRepository(db)
+
+	status := domain.TaskStatusPending
+	sessionID := "abc123"
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM tasks WHERE status = $1 AND task_data->'data'->>'session_id' = $2")).
+		WithArgs("pending", sessionID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	// Limit and offset page through List's results but don't change the total
+	count, err := repo.Count(context.Background(), ports.TaskFilter{Status: &status, SessionID: &sessionID, Limit: 10, Offset: 20})
+	if err != nil {
+		t.Fatalf("Failed to count tasks: %v", err)
+	}
+	if count != 42 {
+		t.Errorf("Expected 42 tasks, got %d", count)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTaskRepository_ListSearch(t *testing.T) {
+	repo := NewTaskRepository(openTestDB(t))
+	ctx := context.Background()
+
+	sessionID := "search-test-" + time.Now().Format("150405.000000000")
+	matching := domain.NewTask(&domain.HookData{
+		Type: domain.HookTypePreToolUse,
+		Data: &domain.PreToolUseHookData{
+			BaseHookData: domain.BaseHookData{HookEventName: "PreToolUse", SessionID: sessionID},
+			ToolName:     "Bash",
+			ToolInput:    &domain.ToolInput{Command: "DOCKER compose up " + sessionID},
+		},
+	})
+	other := domain.NewTask(&domain.HookData{
+		Type: domain.HookTypeStop,
+		Data: &domain.BaseHookData{HookEventName: "Stop", SessionID: sessionID},
+	})
+	for _, task := range []*domain.Task{matching, other} {
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Failed to create task: %v", err)
+		}
+		defer repo.Delete(ctx, task.ID)
+	}
+
+	search := "docker compose up " + sessionID
+	tasks, err := repo.List(ctx, ports.TaskFilter{SearchQuery: &search})
+	if err != nil {
+		t.Fatalf("Failed to search tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != matching.ID {
+		t.Errorf("Expected only the task running the command, got %d tasks", len(tasks))
+	}
+}
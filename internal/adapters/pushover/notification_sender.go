@@ -0,0 +1,156 @@
+package pushover
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// DefaultAPIURL is the base URL of the Pushover API
+const DefaultAPIURL = "https://api.pushover.net/1"
+
+// Pushover message limits; longer values are rejected rather than truncated by the API
+const (
+	maxTitleLength   = 250
+	maxMessageLength = 1024
+)
+
+// Emergency priority messages repeat every emergencyRetry until acknowledged, for at most emergencyExpire
+const (
+	emergencyPriority = 2
+	emergencyRetry    = 60 * time.Second
+	emergencyExpire   = time.Hour
+)
+
+// Ensure NotificationSender satisfies the NotificationSender port
+var _ ports.NotificationSender = (*NotificationSender)(nil)
+var _ ports.NotificationRouter = (*NotificationSender)(nil)
+
+// Config holds configuration for the Pushover notification sender
+type Config struct {
+	APIToken string `json:"-"`                 // Application API token
+	UserKey  string `json:"-"`                 // User or group key to deliver to
+	APIURL   string `json:"api_url,omitempty"` // Defaults to DefaultAPIURL
+}
+
+// apiResponse is the body of every Pushover API response
+type apiResponse struct {
+	Status int      `json:"status"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// NotificationSender implements the NotificationSender port for Pushover
+type NotificationSender struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// NewNotificationSender creates a new Pushover notification sender
+func NewNotificationSender(config Config) *NotificationSender {
+	if config.APIURL == "" {
+		config.APIURL = DefaultAPIURL
+	}
+
+	return &NotificationSender{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Send pushes the notification to the user's devices with a link to the task page
+func (n *NotificationSender) Send(ctx context.Context, notification *domain.Notification) error {
+	if err := n.post(ctx, "/messages.json", buildMessage(n.config, notification)); err != nil {
+		return fmt.Errorf("failed to send Pushover message: %w", err)
+	}
+
+	notification.MarkSent()
+
+	return nil
+}
+
+// Verify checks the API token and user key with Pushover's validate endpoint, which sends nothing
+func (n *NotificationSender) Verify(ctx context.Context) error {
+	if n.config.APIToken == "" || n.config.UserKey == "" {
+		return fmt.Errorf("Pushover API token and user key must both be set")
+	}
+
+	form := url.Values{
+		"token": {n.config.APIToken},
+		"user":  {n.config.UserKey},
+	}
+	if err := n.post(ctx, "/users/validate.json", form); err != nil {
+		return fmt.Errorf("failed to validate Pushover user: %w", err)
+	}
+
+	return nil
+}
+
+// DestinationFor reports the Pushover channel; the user key is a secret so no topic is given
+func (n *NotificationSender) DestinationFor(notification *domain.Notification) ports.NotificationDestination {
+	return ports.NotificationDestination{Channel: "pushover"}
+}
+
+// post submits a form to an API endpoint and turns a rejected request into an error with Pushover's reasons
+func (n *NotificationSender) post(ctx context.Context, endpoint string, form url.Values) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", n.config.APIURL+endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("Pushover API returned status %d with an unreadable body: %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || result.Status != 1 {
+		return fmt.Errorf("Pushover API returned status %d: %s", resp.StatusCode, strings.Join(result.Errors, "; "))
+	}
+
+	return nil
+}
+
+// buildMessage fills in the messages.json form for a notification
+func buildMessage(config Config, notification *domain.Notification) url.Values {
+	priority := notification.Priority.ToPushoverPriority()
+
+	form := url.Values{
+		"token":     {config.APIToken},
+		"user":      {config.UserKey},
+		"title":     {truncate(notification.Title, maxTitleLength)},
+		"message":   {truncate(notification.Message, maxMessageLength)},
+		"priority":  {strconv.Itoa(priority)},
+		"url":       {notification.ActionURL},
+		"url_title": {"Open Task"},
+		"timestamp": {strconv.FormatInt(notification.CreatedAt.Unix(), 10)},
+	}
+	if priority == emergencyPriority {
+		form.Set("retry", strconv.Itoa(int(emergencyRetry.Seconds())))
+		form.Set("expire", strconv.Itoa(int(emergencyExpire.Seconds())))
+	}
+	return form
+}
+
+// truncate shortens text to at most limit characters
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit])
+}
@@ -0,0 +1,130 @@
+package pushover
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+func TestNotificationSender_Send(t *testing.T) {
+	var path string
+	var received url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Failed to parse form: %v", err)
+		}
+		received = r.PostForm
+		io.WriteString(w, `{"status":1,"request":"647d2300-702c-4b38-8b2f-d56326ae460b"}`)
+	}))
+	defer server.Close()
+
+	sender := NewNotificationSender(Config{APIToken: "app-token", UserKey: "user-key", APIURL: server.URL})
+	notification := domain.NewNotification(uuid.New(), domain.HookTypePreToolUse, "control.example.com", "/srv/haiper")
+	notification.Message = strings.Repeat("x", maxMessageLength+10)
+
+	if err := sender.Send(context.Background(), notification); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !notification.IsSent() {
+		t.Error("Expected notification to be marked sent")
+	}
+
+	if path != "/messages.json" {
+		t.Errorf("Expected a post to /messages.json, got %s", path)
+	}
+	expected := map[string]string{
+		"token":     "app-token",
+		"user":      "user-key",
+		"title":     notification.Title,
+		"priority":  "1",
+		"url":       notification.ActionURL,
+		"url_title": "Open Task",
+	}
+	for key, value := range expected {
+		if got := received.Get(key); got != value {
+			t.Errorf("Expected %s %q, got %q", key, value, got)
+		}
+	}
+	if got := len(received.Get("message")); got != maxMessageLength {
+		t.Errorf("Expected message truncated to %d characters, got %d", maxMessageLength, got)
+	}
+	if received.Has("retry") || received.Has("expire") {
+		t.Error("Expected no retry or expire below emergency priority")
+	}
+}
+
+func TestBuildMessage_EmergencyPriority(t *testing.T) {
+	notification := domain.NewNotification(uuid.New(), domain.HookTypeNotification, "localhost:8080", "")
+	notification.Priority = domain.PriorityUrgent
+
+	form := buildMessage(Config{}, notification)
+	if form.Get("priority") != "2" {
+		t.Errorf("Expected priority 2, got %s", form.Get("priority"))
+	}
+	// Pushover rejects emergency messages without both
+	if form.Get("retry") != "60" || form.Get("expire") != "3600" {
+		t.Errorf("Expected retry 60 and expire 3600, got %s and %s", form.Get("retry"), form.Get("expire"))
+	}
+}
+
+func TestNotificationSender_SendRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"user":"invalid","errors":["user identifier is invalid"],"status":0}`)
+	}))
+	defer server.Close()
+
+	sender := NewNotificationSender(Config{APIToken: "app-token", UserKey: "user-key", APIURL: server.URL})
+	notification := domain.NewNotification(uuid.New(), domain.HookTypeStop, "localhost:8080", "")
+
+	err := sender.Send(context.Background(), notification)
+	if err == nil || !strings.Contains(err.Error(), "user identifier is invalid") {
+		t.Errorf("Expected an error with Pushover's reason, got %v", err)
+	}
+	if notification.IsSent() {
+		t.Error("Expected notification not to be marked sent")
+	}
+}
+
+func TestNotificationSender_Verify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/validate.json" {
+			t.Errorf("Expected a post to /users/validate.json, got %s", r.URL.Path)
+		}
+		if r.PostFormValue("user") != "user-key" {
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"status":0,"errors":["user key is invalid"]}`)
+			return
+		}
+		io.WriteString(w, `{"status":1,"devices":["phone"]}`)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		config    Config
+		expectErr bool
+	}{
+		{"Valid user", Config{APIToken: "app-token", UserKey: "user-key", APIURL: server.URL}, false},
+		{"Invalid user", Config{APIToken: "app-token", UserKey: "someone-else", APIURL: server.URL}, true},
+		{"Missing user key", Config{APIToken: "app-token", APIURL: server.URL}, true},
+		{"Missing API token", Config{UserKey: "user-key", APIURL: server.URL}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewNotificationSender(tt.config).Verify(context.Background())
+			if (err != nil) != tt.expectErr {
+				t.Errorf("Verify() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package response
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/dan/claude-control/internal/core/domain"
@@ -35,6 +36,11 @@ func (b *HookResponseBuilder) BuildTimeoutResponse(taskID string, timeout time.D
 	return domain.NewTimeoutResponse(taskID, timeout)
 }
 
+// BuildModifiedCommandResponse creates a response that lets Claude Code continue with a substituted command
+func (b *HookResponseBuilder) BuildModifiedCommandResponse(taskID, modifiedCommand string) *domain.HookResponse {
+	return domain.NewModifiedCommandResponse(taskID, modifiedCommand)
+}
+
 // BuildContinueResponse creates a non-blocking response that allows continuation
 func (b *HookResponseBuilder) BuildContinueResponse() *domain.HookResponse {
 	return domain.NewContinueResponse()
@@ -46,9 +52,24 @@ func (b *HookResponseBuilder) BuildSuppressedResponse() *domain.HookResponse {
 }
 
 // BuildResponseFromDecision creates appropriate response based on user decision
-func (b *HookResponseBuilder) BuildResponseFromDecision(taskID string, decision domain.ActionType) *domain.HookResponse {
+// An optional modified command replaces the tool command on approval and is ignored otherwise.
+// Every response is checked with HookResponseValidator. The stop reasons are fixed here, so a violation is a bug
+// in the builder rather than in the decision, and it panics instead of returning an error callers can't act on.
+func (b *HookResponseBuilder) BuildResponseFromDecision(taskID string, decision domain.ActionType, modifiedCommand ...string) *domain.HookResponse {
+	response := b.responseFromDecision(taskID, decision, modifiedCommand...)
+	if err := (&HookResponseValidator{}).ValidateResponse(response); err != nil {
+		panic(fmt.Sprintf("hook response for decision %q breaks Claude Code's hook output rules: %v", decision, err))
+	}
+	return response
+}
+
+// responseFromDecision picks the response for a decision
+func (b *HookResponseBuilder) responseFromDecision(taskID string, decision domain.ActionType, modifiedCommand ...string) *domain.HookResponse {
 	switch decision {
 	case domain.ActionTypeApprove:
+		if len(modifiedCommand) > 0 && modifiedCommand[0] != "" {
+			return b.BuildModifiedCommandResponse(taskID, modifiedCommand[0])
+		}
 		return b.BuildApprovedResponse(taskID)
 	case domain.ActionTypeReject:
 		return b.BuildRejectedResponse(taskID, "User rejected this action")
@@ -58,4 +79,4 @@ func (b *HookResponseBuilder) BuildResponseFromDecision(taskID string, decision
 		// For any other action, default to approved
 		return b.BuildApprovedResponse(taskID)
 	}
-}
\ No newline at end of file
+}
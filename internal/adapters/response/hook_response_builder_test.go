@@ -0,0 +1,60 @@
+package response
+
+import (
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+func TestBuildModifiedCommandResponse(t *testing.T) {
+	builder := &HookResponseBuilder{}
+
+	response := builder.BuildModifiedCommandResponse("task-123", "ls -la /tmp")
+
+	if !response.Continue {
+		t.Error("Expected response to continue")
+	}
+	if response.ModifiedCommand != "ls -la /tmp" {
+		t.Errorf("Expected modified command %q, got %q", "ls -la /tmp", response.ModifiedCommand)
+	}
+	if response.TaskID != "task-123" {
+		t.Errorf("Expected task ID task-123, got %s", response.TaskID)
+	}
+	if err := response.Validate(); err != nil {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+}
+
+func TestBuildResponseFromDecision_ModifiedCommand(t *testing.T) {
+	builder := &HookResponseBuilder{}
+
+	tests := []struct {
+		name            string
+		decision        domain.ActionType
+		modifiedCommand []string
+		wantContinue    bool
+		wantCommand     string
+	}{
+		{name: "approve without command", decision: domain.ActionTypeApprove, wantContinue: true},
+		{name: "approve with empty command", decision: domain.ActionTypeApprove, modifiedCommand: []string{""}, wantContinue: true},
+		{name: "approve with command", decision: domain.ActionTypeApprove, modifiedCommand: []string{"go test ./..."}, wantContinue: true, wantCommand: "go test ./..."},
+		{name: "reject ignores command", decision: domain.ActionTypeReject, modifiedCommand: []string{"go test ./..."}, wantContinue: false},
+		{name: "cancel ignores command", decision: domain.ActionTypeCancel, modifiedCommand: []string{"go test ./..."}, wantContinue: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := builder.BuildResponseFromDecision("task-123", tt.decision, tt.modifiedCommand...)
+
+			if response.Continue != tt.wantContinue {
+				t.Errorf("Expected continue=%v, got %v", tt.wantContinue, response.Continue)
+			}
+			if response.ModifiedCommand != tt.wantCommand {
+				t.Errorf("Expected modified command %q, got %q", tt.wantCommand, response.ModifiedCommand)
+			}
+			if err := response.Validate(); err != nil {
+				t.Errorf("Unexpected validation error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// MaxStopReasonLength is the longest stop reason, in characters, Claude Code shows; it ignores longer ones
+const MaxStopReasonLength = 500
+
+var (
+	// ErrMissingStopReason is returned for a response that stops Claude Code without saying why
+	ErrMissingStopReason = errors.New("stop reason is required when continue is false")
+
+	// ErrStopReasonTooLong is returned for a stop reason Claude Code would ignore
+	ErrStopReasonTooLong = fmt.Errorf("stop reason must be at most %d characters", MaxStopReasonLength)
+
+	// ErrSuppressedStop is returned for a response that hides output while stopping Claude Code
+	ErrSuppressedStop = errors.New("suppress output is only allowed when continue is true")
+)
+
+// HookResponseValidator implements the HookResponseValidator port
+type HookResponseValidator struct{}
+
+// NewHookResponseValidator creates a new hook response validator
+func NewHookResponseValidator() ports.HookResponseValidator {
+	return &HookResponseValidator{}
+}
+
+// ValidateResponse checks that a stopping response explains why in a reason Claude Code will show,
+// and that only a continuing response suppresses output
+func (v *HookResponseValidator) ValidateResponse(response *domain.HookResponse) error {
+	if !response.Continue && response.StopReason == "" {
+		return ErrMissingStopReason
+	}
+	if length := utf8.RuneCountInString(response.StopReason); length > MaxStopReasonLength {
+		return fmt.Errorf("%w, got %d", ErrStopReasonTooLong, length)
+	}
+	if response.SuppressOutput && !response.Continue {
+		return ErrSuppressedStop
+	}
+	return nil
+}
+
+// ValidateJSON parses a hook response and validates it
+func (v *HookResponseValidator) ValidateJSON(data []byte) error {
+	var response domain.HookResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return fmt.Errorf("failed to parse hook response: %w", err)
+	}
+	return v.ValidateResponse(&response)
+}
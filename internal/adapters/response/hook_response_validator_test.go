@@ -0,0 +1,82 @@
+package response
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+func TestHookResponseValidator_ValidateResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *domain.HookResponse
+		expected error
+	}{
+		{"Approved", domain.NewApprovedResponse("task-123"), nil},
+		{"Rejected", domain.NewRejectedResponse("task-123", "User rejected this action"), nil},
+		{"Timed out", domain.NewTimeoutResponse("task-123", 5*time.Minute), nil},
+		{"Suppressed", domain.NewSuppressedResponse(), nil},
+		{"Stop without a reason", &domain.HookResponse{Continue: false}, ErrMissingStopReason},
+		{"Reason at the limit", domain.NewRejectedResponse("task-123", strings.Repeat("é", MaxStopReasonLength)), nil},
+		{"Reason over the limit", domain.NewRejectedResponse("task-123", strings.Repeat("a", MaxStopReasonLength+1)), ErrStopReasonTooLong},
+		{"Suppressed stop", &domain.HookResponse{Continue: false, StopReason: "Blocked", SuppressOutput: true}, ErrSuppressedStop},
+	}
+
+	validator := NewHookResponseValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateResponse(tt.response)
+			if tt.expected == nil && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("Expected error %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestHookResponseValidator_ValidateJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		expectErr bool
+	}{
+		{"Continue", `{"continue": true}`, false},
+		{"Stop with a reason", `{"continue": false, "stopReason": "User rejected this action"}`, false},
+		{"Stop without a reason", `{"continue": false}`, true},
+		{"Suppressed stop", `{"continue": false, "stopReason": "Blocked", "suppressOutput": true}`, true},
+		{"Not JSON", `continue`, true},
+	}
+
+	validator := NewHookResponseValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validator.ValidateJSON([]byte(tt.data)); (err != nil) != tt.expectErr {
+				t.Errorf("ValidateJSON() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestBuildResponseFromDecision_Valid(t *testing.T) {
+	builder := &HookResponseBuilder{}
+	validator := NewHookResponseValidator()
+
+	for _, decision := range []domain.ActionType{domain.ActionTypeApprove, domain.ActionTypeReject, domain.ActionTypeCancel, domain.ActionTypeSkip} {
+		t.Run(string(decision), func(t *testing.T) {
+			response := builder.BuildResponseFromDecision("task-123", decision)
+
+			data, err := response.ToJSON()
+			if err != nil {
+				t.Fatalf("Failed to encode response: %v", err)
+			}
+			if err := validator.ValidateJSON(data); err != nil {
+				t.Errorf("Expected the %s response to stay valid once encoded, got %v", decision, err)
+			}
+		})
+	}
+}
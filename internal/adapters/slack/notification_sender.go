@@ -0,0 +1,204 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// maxSectionTextLength is the longest text Slack accepts in a section block
+const maxSectionTextLength = 3000
+
+// verifyResponseBody is what Slack answers, with a 400, to a webhook post without text
+const verifyResponseBody = "no_text"
+
+// Ensure NotificationSender satisfies the NotificationSender port
+var _ ports.NotificationSender = (*NotificationSender)(nil)
+var _ ports.NotificationRouter = (*NotificationSender)(nil)
+
+// Config holds configuration for the Slack notification sender
+type Config struct {
+	WebhookURL string `json:"-"` // Incoming webhook URL; it embeds the channel's secret token
+}
+
+// message is a Slack incoming webhook payload
+// The blocks go inside an attachment because only attachments can be colored.
+type message struct {
+	Text        string       `json:"text"`
+	Attachments []attachment `json:"attachments,omitempty"`
+}
+
+// attachment is a colored group of Block Kit blocks
+type attachment struct {
+	Color  string  `json:"color"`
+	Blocks []block `json:"blocks"`
+}
+
+// block is a Block Kit layout block
+type block struct {
+	Type     string    `json:"type"`
+	Text     *text     `json:"text,omitempty"`
+	Elements []element `json:"elements,omitempty"`
+}
+
+// text is a Block Kit text object
+type text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// element is a button in an actions block or a text item in a context block
+type element struct {
+	Type  string `json:"type"`
+	Text  any    `json:"text"`
+	URL   string `json:"url,omitempty"`
+	Style string `json:"style,omitempty"`
+}
+
+// NotificationSender implements the NotificationSender port for Slack incoming webhooks
+type NotificationSender struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// NewNotificationSender creates a new Slack notification sender
+func NewNotificationSender(config Config) *NotificationSender {
+	return &NotificationSender{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Send posts the notification to the Slack channel as a colored Block Kit message
+func (n *NotificationSender) Send(ctx context.Context, notification *domain.Notification) error {
+	resp, err := n.post(ctx, buildMessage(notification))
+	if err != nil {
+		return fmt.Errorf("failed to send Slack message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("Slack webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	notification.MarkSent()
+
+	return nil
+}
+
+// Verify checks the webhook URL is live without posting anything to the channel
+// A post with empty text is rejected by Slack with 400 "no_text"; any other answer means the URL is wrong.
+func (n *NotificationSender) Verify(ctx context.Context) error {
+	if n.config.WebhookURL == "" {
+		return fmt.Errorf("Slack webhook URL is not set")
+	}
+
+	resp, err := n.post(ctx, message{Text: ""})
+	if err != nil {
+		return fmt.Errorf("failed to reach Slack webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode != http.StatusBadRequest || strings.TrimSpace(string(body)) != verifyResponseBody {
+		return fmt.Errorf("Slack webhook answered status %d %q, expected %d %q",
+			resp.StatusCode, strings.TrimSpace(string(body)), http.StatusBadRequest, verifyResponseBody)
+	}
+
+	return nil
+}
+
+// DestinationFor reports the Slack channel; the webhook URL is a secret so no topic is given
+func (n *NotificationSender) DestinationFor(notification *domain.Notification) ports.NotificationDestination {
+	return ports.NotificationDestination{Channel: "slack"}
+}
+
+// post sends a message to the webhook URL
+func (n *NotificationSender) post(ctx context.Context, payload message) (*http.Response, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Slack message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.config.WebhookURL, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return n.httpClient.Do(req)
+}
+
+// buildMessage lays out the notification as a section with the title and message, buttons linking
+// to the task page and a context line naming the session and hook type
+func buildMessage(notification *domain.Notification) message {
+	summary := "*" + escape(notification.Title) + "*\n" + escape(notification.Message)
+	if len(summary) > maxSectionTextLength {
+		summary = strings.ToValidUTF8(summary[:maxSectionTextLength], "")
+	}
+
+	blocks := []block{
+		{Type: "section", Text: &text{Type: "mrkdwn", Text: summary}},
+		{Type: "actions", Elements: actionButtons(notification)},
+	}
+	if details := contextDetails(notification); len(details) > 0 {
+		blocks = append(blocks, block{Type: "context", Elements: details})
+	}
+
+	return message{
+		Text: notification.Title + ": " + notification.Message,
+		Attachments: []attachment{
+			{Color: notification.Priority.ToSlackColor(), Blocks: blocks},
+		},
+	}
+}
+
+// actionButtons links to the task page; hooks waiting on a decision get approve and reject buttons
+// Both open the task page, where the decision is made, since a webhook message can't call back.
+func actionButtons(notification *domain.Notification) []element {
+	if !notification.HookType.IsBlocking() {
+		return []element{button("Open Task", notification.ActionURL, "")}
+	}
+	return []element{
+		button("Approve", notification.ActionURL, "primary"),
+		button("Reject", notification.ActionURL, "danger"),
+	}
+}
+
+// button creates a link button
+func button(label, url, style string) element {
+	return element{
+		Type:  "button",
+		Text:  text{Type: "plain_text", Text: label},
+		URL:   url,
+		Style: style,
+	}
+}
+
+// contextDetails lists the session ID and hook type, skipping whichever is unknown
+func contextDetails(notification *domain.Notification) []element {
+	var details []element
+	if notification.SessionID != "" {
+		details = append(details, element{Type: "mrkdwn", Text: "Session: `" + escape(notification.SessionID) + "`"})
+	}
+	if notification.HookType != "" {
+		details = append(details, element{Type: "mrkdwn", Text: "Hook: " + escape(notification.HookType.String())})
+	}
+	return details
+}
+
+// escape escapes the characters Slack treats as markup in message text
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
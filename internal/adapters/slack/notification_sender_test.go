@@ -0,0 +1,147 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+func TestNotificationSender_Send(t *testing.T) {
+	var received message
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode message: %v", err)
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	sender := NewNotificationSender(Config{WebhookURL: server.URL})
+	notification := domain.NewNotification(uuid.New(), domain.HookTypePreToolUse, "control.example.com", "/srv/haiper")
+	notification.SessionID = "abc123"
+	notification.Message = "Run rm -rf <build> & friends"
+	notification.Priority = domain.PriorityUrgent
+
+	if err := sender.Send(context.Background(), notification); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !notification.IsSent() {
+		t.Error("Expected notification to be marked sent")
+	}
+
+	if len(received.Attachments) != 1 {
+		t.Fatalf("Expected 1 attachment, got %d", len(received.Attachments))
+	}
+	attachment := received.Attachments[0]
+	if attachment.Color != "danger" {
+		t.Errorf("Expected color danger, got %s", attachment.Color)
+	}
+	if len(attachment.Blocks) != 3 {
+		t.Fatalf("Expected section, actions and context blocks, got %+v", attachment.Blocks)
+	}
+
+	section := attachment.Blocks[0]
+	if section.Type != "section" || !strings.Contains(section.Text.Text, "Run rm -rf &lt;build&gt; &amp; friends") {
+		t.Errorf("Expected an escaped section with the message, got %+v", section)
+	}
+
+	actions := attachment.Blocks[1]
+	if actions.Type != "actions" || len(actions.Elements) != 2 {
+		t.Fatalf("Expected approve and reject buttons, got %+v", actions)
+	}
+	for _, button := range actions.Elements {
+		if button.URL != notification.ActionURL {
+			t.Errorf("Expected button to link to %s, got %s", notification.ActionURL, button.URL)
+		}
+	}
+
+	details := attachment.Blocks[2]
+	if details.Type != "context" || len(details.Elements) != 2 {
+		t.Fatalf("Expected session and hook type context, got %+v", details)
+	}
+	if details.Elements[0].Text != "Session: `abc123`" || details.Elements[1].Text != "Hook: PreToolUse" {
+		t.Errorf("Unexpected context elements: %+v", details.Elements)
+	}
+}
+
+func TestBuildMessage_NonBlockingHook(t *testing.T) {
+	notification := domain.NewNotification(uuid.New(), domain.HookTypeStop, "localhost:8080", "")
+
+	msg := buildMessage(notification)
+	blocks := msg.Attachments[0].Blocks
+	if len(blocks) != 3 {
+		t.Fatalf("Expected 3 blocks, got %d", len(blocks))
+	}
+	if buttons := blocks[1].Elements; len(buttons) != 1 || buttons[0].URL != notification.ActionURL {
+		t.Errorf("Expected a single Open Task button, got %+v", buttons)
+	}
+	if details := blocks[2].Elements; len(details) != 1 || details[0].Text != "Hook: Stop" {
+		t.Errorf("Expected only the hook type without a session ID, got %+v", details)
+	}
+	if msg.Attachments[0].Color != "good" {
+		t.Errorf("Expected color good, got %s", msg.Attachments[0].Color)
+	}
+}
+
+func TestNotificationSender_SendErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, "no_service")
+	}))
+	defer server.Close()
+
+	sender := NewNotificationSender(Config{WebhookURL: server.URL})
+	notification := domain.NewNotification(uuid.New(), domain.HookTypeNotification, "localhost:8080", "")
+
+	err := sender.Send(context.Background(), notification)
+	if err == nil || !strings.Contains(err.Error(), "no_service") {
+		t.Errorf("Expected an error naming Slack's answer, got %v", err)
+	}
+	if notification.IsSent() {
+		t.Error("Expected notification not to be marked sent")
+	}
+}
+
+func TestNotificationSender_Verify(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		expectErr bool
+	}{
+		{"Live webhook", http.StatusBadRequest, "no_text", false},
+		{"Revoked webhook", http.StatusNotFound, "no_service", true},
+		{"Not Slack", http.StatusOK, "ok", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var received map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&received)
+				if text, ok := received["text"]; !ok || text != "" {
+					t.Errorf("Expected an empty text field, got %v", received)
+				}
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			err := NewNotificationSender(Config{WebhookURL: server.URL}).Verify(context.Background())
+			if (err != nil) != tt.expectErr {
+				t.Errorf("Verify() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
+	}
+
+	if err := NewNotificationSender(Config{}).Verify(context.Background()); err == nil {
+		t.Error("Expected an error without a webhook URL")
+	}
+}
@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// gatedDecisionManager waits for each decision until the test releases it
+type gatedDecisionManager struct {
+	ports.TaskDecisionManager
+	waiting  chan struct{}
+	decision chan domain.ActionType
+}
+
+func (m *gatedDecisionManager) OpenSessionDecision(ctx context.Context, taskID, sessionID string, timeout time.Duration) {
+}
+
+func (m *gatedDecisionManager) AwaitDecision(ctx context.Context, taskID string, timeout time.Duration) (domain.ActionType, error) {
+	m.waiting <- struct{}{}
+	return <-m.decision, nil
+}
+
+func TestPersistentDecisionManager_WaitForDecision(t *testing.T) {
+	gated := &gatedDecisionManager{waiting: make(chan struct{}), decision: make(chan domain.ActionType)}
+	manager := NewPersistentDecisionManager(openTestDB(t), gated)
+	taskID := uuid.New().String()
+
+	done := make(chan domain.ActionType, 1)
+	go func() {
+		decision, _ := manager.WaitForDecision(context.Background(), taskID, time.Minute)
+		done <- decision
+	}()
+	<-gated.waiting
+
+	pending, err := manager.ListPending(context.Background())
+	if err != nil {
+		t.Fatalf("ListPending failed: %v", err)
+	}
+	if len(pending) != 1 || pending[0].TaskID != taskID {
+		t.Fatalf("Expected a pending decision row while the webhook waits, got %+v", pending)
+	}
+	if wait := pending[0].ExpiresAt.Sub(pending[0].CreatedAt); wait != time.Minute {
+		t.Errorf("Expected the row to expire after the timeout, got %v", wait)
+	}
+
+	gated.decision <- domain.ActionTypeApprove
+	if decision := <-done; decision != domain.ActionTypeApprove {
+		t.Errorf("Expected the wrapped manager's decision, got %q", decision)
+	}
+	if pending, _ := manager.ListPending(context.Background()); len(pending) != 0 {
+		t.Errorf("Expected the pending decision row to be removed once resolved, got %+v", pending)
+	}
+}
+
+func TestPersistentDecisionManager_WarnUnclaimed(t *testing.T) {
+	db := openTestDB(t)
+	manager := NewPersistentDecisionManager(db, nil)
+	ctx := context.Background()
+
+	// A row left behind by a server that stopped while a webhook waited an hour ago, and one just written
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO pending_decisions (task_id, created_at, expires_at)
+		VALUES (?, datetime('now', '-1 hour'), datetime('now', '-55 minutes'))`, uuid.New().String()); err != nil {
+		t.Fatalf("Failed to insert stale pending decision: %v", err)
+	}
+	if err := manager.insertPending(ctx, uuid.New().String(), 5*time.Minute); err != nil {
+		t.Fatalf("Failed to insert fresh pending decision: %v", err)
+	}
+
+	unclaimed, err := manager.WarnUnclaimed(ctx, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if unclaimed != 1 {
+		t.Errorf("Expected only the decision older than the timeout to be reported, got %d", unclaimed)
+	}
+}
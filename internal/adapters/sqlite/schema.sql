@@ -0,0 +1,92 @@
+-- SQLite schema for Claude Control, applied by Open; mirrors init.sql for PostgreSQL
+-- UUIDs and JSON are stored as TEXT, and timestamps as TEXT in UTC so they compare in order.
+
+CREATE TABLE IF NOT EXISTS tasks (
+    id TEXT PRIMARY KEY,
+    hook_type TEXT NOT NULL,
+    task_data TEXT NOT NULL,
+    status TEXT DEFAULT 'pending' NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    action_taken TEXT,
+    response_data TEXT,
+    snoozed_until TIMESTAMP,
+    -- Task this one depends on; rejecting it cancels the child
+    parent_task_id TEXT REFERENCES tasks(id) ON DELETE SET NULL,
+    -- Session ID copied out of the hook data so lookups by session don't parse task_data
+    session_id TEXT GENERATED ALWAYS AS (json_extract(task_data, '$.data.session_id')) VIRTUAL
+);
+
+-- Task archive table (completed tasks moved out of tasks by the nightly archive job)
+CREATE TABLE IF NOT EXISTS tasks_archive (
+    id TEXT PRIMARY KEY,
+    hook_type TEXT NOT NULL,
+    task_data TEXT NOT NULL,
+    status TEXT NOT NULL,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    action_taken TEXT,
+    response_data TEXT,
+    snoozed_until TIMESTAMP,
+    parent_task_id TEXT,
+    archived_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    session_id TEXT GENERATED ALWAYS AS (json_extract(task_data, '$.data.session_id')) VIRTUAL
+);
+
+CREATE TABLE IF NOT EXISTS task_history (
+    id TEXT PRIMARY KEY,
+    task_id TEXT REFERENCES tasks(id) ON DELETE CASCADE,
+    action TEXT NOT NULL,
+    data TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per Claude Code session; subagent_ids is a JSON array
+CREATE TABLE IF NOT EXISTS sessions (
+    id TEXT PRIMARY KEY,
+    subagent_ids TEXT DEFAULT '[]' NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_activity_at TIMESTAMP,
+    last_hook_type TEXT
+);
+
+-- Append-only log of hook events
+CREATE TABLE IF NOT EXISTS session_events (
+    id TEXT PRIMARY KEY,
+    session_id TEXT REFERENCES sessions(id) ON DELETE CASCADE,
+    hook_type TEXT NOT NULL,
+    cwd TEXT,
+    transcript_path TEXT,
+    event_data TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Runtime settings toggled by operators
+CREATE TABLE IF NOT EXISTS server_settings (
+    setting_name TEXT PRIMARY KEY,
+    setting_value TEXT NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Blocking webhooks waiting on the user; rows left after a restart were never answered
+CREATE TABLE IF NOT EXISTS pending_decisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status);
+CREATE INDEX IF NOT EXISTS idx_tasks_hook_type ON tasks(hook_type);
+CREATE INDEX IF NOT EXISTS idx_tasks_created_at ON tasks(created_at);
+CREATE INDEX IF NOT EXISTS idx_tasks_snoozed_until ON tasks(snoozed_until) WHERE snoozed_until IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_session_id ON tasks(session_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_task_id ON tasks(parent_task_id) WHERE parent_task_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_pending_session ON tasks(session_id, created_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_tasks_archive_created_at ON tasks_archive(created_at);
+CREATE INDEX IF NOT EXISTS idx_task_history_task_id ON task_history(task_id);
+CREATE INDEX IF NOT EXISTS idx_task_history_created_at ON task_history(created_at);
+CREATE INDEX IF NOT EXISTS idx_session_events_session_id ON session_events(session_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_session_events_hook_type ON session_events(hook_type);
+CREATE INDEX IF NOT EXISTS idx_pending_decisions_task_id ON pending_decisions(task_id);
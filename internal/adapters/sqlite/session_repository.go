@@ -0,0 +1,312 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+var _ ports.SessionRepository = (*SessionRepository)(nil)
+
+// sessionColumns are the columns scanSession reads, in order
+// Sessions created before last_activity_at existed fall back to updated_at, which every event touches.
+const sessionColumns = "id, subagent_ids, created_at, updated_at, COALESCE(last_activity_at, updated_at), last_hook_type"
+
+// SessionRepository implements the SessionRepository port for SQLite
+// Session events are an append-only log; they are never updated once stored.
+type SessionRepository struct {
+	db *sql.DB
+}
+
+// NewSessionRepository creates a new SQLite session repository
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// GetSession retrieves a session by its ID, and creates it if it doesn't exist
+func (r *SessionRepository) GetSession(ctx context.Context, sessionID string) (*domain.Session, error) {
+	return r.UpsertSession(ctx, sessionID)
+}
+
+// UpsertSession creates the session if needed, touches its updated_at and loads its event count
+func (r *SessionRepository) UpsertSession(ctx context.Context, sessionID string) (*domain.Session, error) {
+	query := `
+		INSERT INTO sessions (id, created_at, updated_at)
+		VALUES (?1, ?2, ?2)
+		ON CONFLICT (id) DO UPDATE SET updated_at = ?2
+		RETURNING ` + sessionColumns
+
+	session, err := r.scanSession(r.db.QueryRowContext(ctx, query, sessionID, time.Now().UTC()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert session: %w", err)
+	}
+
+	countQuery := `SELECT COUNT(*) FROM session_events WHERE session_id = ?`
+	if err := r.db.QueryRowContext(ctx, countQuery, sessionID).Scan(&session.EventCount); err != nil {
+		return nil, fmt.Errorf("failed to count session events: %w", err)
+	}
+
+	return session, nil
+}
+
+// UpdateSession persists changes to a session, including its subagent IDs
+func (r *SessionRepository) UpdateSession(ctx context.Context, session *domain.Session) error {
+	query := `
+		UPDATE sessions
+		SET subagent_ids = ?, updated_at = ?
+		WHERE id = ?`
+
+	subagentIDs := session.SubagentIDs
+	if subagentIDs == nil {
+		subagentIDs = []string{}
+	}
+	subagentJSON, err := json.Marshal(subagentIDs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal subagent IDs: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, query,
+		string(subagentJSON),
+		utc(session.UpdatedAt),
+		session.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("session not found: %s", session.ID)
+	}
+
+	return nil
+}
+
+// FindSessionBySubagentID retrieves the parent session of a subagent
+func (r *SessionRepository) FindSessionBySubagentID(ctx context.Context, subagentID string) (*domain.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE EXISTS (SELECT 1 FROM json_each(sessions.subagent_ids) WHERE json_each.value = ?)
+		ORDER BY updated_at DESC
+		LIMIT 1`
+
+	session, err := r.scanSession(r.db.QueryRowContext(ctx, query, subagentID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("no session found for subagent: %s", subagentID)
+		}
+		return nil, fmt.Errorf("failed to find session by subagent: %w", err)
+	}
+
+	return session, nil
+}
+
+// ListSessions returns the most recently active sessions with their event counts, newest first
+func (r *SessionRepository) ListSessions(ctx context.Context, limit int) ([]*domain.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `,
+			(SELECT COUNT(*) FROM session_events WHERE session_events.session_id = sessions.id)
+		FROM sessions
+		ORDER BY COALESCE(last_activity_at, updated_at) DESC
+		LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []*domain.Session{}
+	for rows.Next() {
+		var eventCount int
+		session, err := r.scanSession(rows, &eventCount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		session.EventCount = eventCount
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// AddEvent stores a new event for a session, creating the session if it doesn't exist
+func (r *SessionRepository) AddEvent(ctx context.Context, sessionID string, event *domain.SessionEvent) error {
+	eventJSON, err := json.Marshal(event.EventData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Events can arrive out of order, so only a newer event replaces the last hook type
+	sessionQuery := `
+		INSERT INTO sessions (id, created_at, updated_at, last_activity_at, last_hook_type)
+		VALUES (?1, ?2, ?2, ?3, ?4)
+		ON CONFLICT (id) DO UPDATE SET
+			updated_at = ?2,
+			last_hook_type = CASE
+				WHEN sessions.last_activity_at IS NULL OR sessions.last_activity_at <= excluded.last_activity_at
+				THEN excluded.last_hook_type
+				ELSE sessions.last_hook_type
+			END,
+			last_activity_at = MAX(COALESCE(sessions.last_activity_at, excluded.last_activity_at), excluded.last_activity_at)`
+
+	if _, err := tx.ExecContext(ctx, sessionQuery, sessionID, time.Now().UTC(), utc(event.CreatedAt), event.HookType); err != nil {
+		return fmt.Errorf("failed to upsert session: %w", err)
+	}
+
+	eventQuery := `
+		INSERT INTO session_events (id, session_id, hook_type, cwd, transcript_path, event_data, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = tx.ExecContext(ctx, eventQuery,
+		event.ID,
+		sessionID,
+		event.HookType,
+		event.CWD,
+		event.TranscriptPath,
+		string(eventJSON),
+		utc(event.CreatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add session event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit session event: %w", err)
+	}
+
+	return nil
+}
+
+// GetEvents retrieves events for a session with optional filtering
+func (r *SessionRepository) GetEvents(ctx context.Context, sessionID string, filter ports.EventFilter) ([]*domain.SessionEvent, error) {
+	query := "SELECT id, session_id, hook_type, cwd, transcript_path, event_data, created_at FROM session_events"
+	args := []interface{}{sessionID}
+	conditions := []string{"session_id = ?"}
+
+	if filter.HookType != nil {
+		conditions = append(conditions, "hook_type = ?")
+		args = append(args, *filter.HookType)
+	}
+
+	query += " WHERE " + strings.Join(conditions, " AND ")
+
+	// Events are returned in the order they happened unless asked otherwise;
+	// created_at is the only sortable column
+	orderDirection := "ASC"
+	if filter.SortOrder == "desc" {
+		orderDirection = "DESC"
+	}
+	query += " ORDER BY created_at " + orderDirection
+
+	query, args = withLimitOffset(query, args, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.SessionEvent
+	for rows.Next() {
+		event, err := r.scanSessionEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating session events: %w", err)
+	}
+
+	return events, nil
+}
+
+// scanSession scans a database row of sessionColumns into a Session struct
+// Any extra destinations are filled from the columns selected after sessionColumns.
+func (r *SessionRepository) scanSession(scanner interface {
+	Scan(dest ...interface{}) error
+}, extra ...interface{}) (*domain.Session, error) {
+	var session domain.Session
+	var subagentJSON string
+	var createdAt, updatedAt, lastActivityAt timestamp
+	var lastHookType sql.NullString
+
+	dest := append([]interface{}{
+		&session.ID,
+		&subagentJSON,
+		&createdAt,
+		&updatedAt,
+		&lastActivityAt,
+		&lastHookType,
+	}, extra...)
+
+	if err := scanner.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	session.SubagentIDs = []string{}
+	if err := json.Unmarshal([]byte(subagentJSON), &session.SubagentIDs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal subagent IDs: %w", err)
+	}
+	session.CreatedAt = createdAt.Time
+	session.UpdatedAt = updatedAt.Time
+	session.LastActivityAt = lastActivityAt.Time
+	session.LastHookType = domain.HookType(lastHookType.String)
+
+	return &session, nil
+}
+
+// scanSessionEvent scans a database row into a SessionEvent struct
+func (r *SessionRepository) scanSessionEvent(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*domain.SessionEvent, error) {
+	var event domain.SessionEvent
+	var eventJSON *string
+
+	err := scanner.Scan(
+		&event.ID,
+		&event.SessionID,
+		&event.HookType,
+		&event.CWD,
+		&event.TranscriptPath,
+		&eventJSON,
+		&event.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if eventJSON != nil {
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(*eventJSON), &data); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event data: %w", err)
+		}
+		event.EventData = data
+	}
+
+	return &event, nil
+}
@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// newTestEvent creates a session event of hookType that fired age ago
+func newTestEvent(hookType domain.HookType, age time.Duration) *domain.SessionEvent {
+	return &domain.SessionEvent{
+		ID:        uuid.New(),
+		HookType:  hookType,
+		CWD:       "/home/dan/project",
+		EventData: map[string]interface{}{"hook_event_name": hookType.String()},
+		CreatedAt: time.Now().Add(-age),
+	}
+}
+
+func TestSessionRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSessionRepository(openTestDB(t))
+
+	session, err := repo.GetSession(ctx, "alpha")
+	if err != nil {
+		t.Fatalf("GetSession failed: %v", err)
+	}
+	if session.ID != "alpha" || session.EventCount != 0 || len(session.SubagentIDs) != 0 || session.CreatedAt.IsZero() {
+		t.Errorf("Expected a new empty session, got %+v", session)
+	}
+
+	// The Stop event arrives after an older PreToolUse event, which must not replace it
+	stop := newTestEvent(domain.HookTypeStop, time.Minute)
+	late := newTestEvent(domain.HookTypePreToolUse, time.Hour)
+	for _, event := range []*domain.SessionEvent{stop, late} {
+		if err := repo.AddEvent(ctx, "alpha", event); err != nil {
+			t.Fatalf("AddEvent failed: %v", err)
+		}
+	}
+	if err := repo.AddEvent(ctx, "beta", newTestEvent(domain.HookTypeNotification, 2*time.Hour)); err != nil {
+		t.Fatalf("AddEvent failed: %v", err)
+	}
+
+	session, err = repo.GetSession(ctx, "alpha")
+	if err != nil {
+		t.Fatalf("GetSession failed: %v", err)
+	}
+	if session.EventCount != 2 || session.LastHookType != domain.HookTypeStop {
+		t.Errorf("Expected 2 events ending with Stop, got %d ending with %s", session.EventCount, session.LastHookType)
+	}
+	if !session.LastActivityAt.Equal(stop.CreatedAt) {
+		t.Errorf("Expected the last activity at the newest event %v, got %v", stop.CreatedAt, session.LastActivityAt)
+	}
+
+	events, err := repo.GetEvents(ctx, "alpha", ports.EventFilter{})
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if len(events) != 2 || events[0].ID != late.ID || events[1].ID != stop.ID {
+		t.Fatalf("Expected alpha's events oldest first, got %+v", events)
+	}
+	if data, ok := events[1].EventData.(map[string]interface{}); !ok || data["hook_event_name"] != "Stop" {
+		t.Errorf("Expected the event data to round trip, got %v", events[1].EventData)
+	}
+
+	stopType := domain.HookTypeStop
+	events, err = repo.GetEvents(ctx, "alpha", ports.EventFilter{HookType: &stopType, Limit: 5})
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != stop.ID {
+		t.Errorf("Expected only the Stop event, got %+v", events)
+	}
+	events, err = repo.GetEvents(ctx, "alpha", ports.EventFilter{Offset: 1})
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != stop.ID {
+		t.Errorf("Expected the offset to skip the oldest event, got %+v", events)
+	}
+
+	session.AddSubagentID("subagent-1")
+	if err := repo.UpdateSession(ctx, session); err != nil {
+		t.Fatalf("UpdateSession failed: %v", err)
+	}
+	parent, err := repo.FindSessionBySubagentID(ctx, "subagent-1")
+	if err != nil {
+		t.Fatalf("FindSessionBySubagentID failed: %v", err)
+	}
+	if parent.ID != "alpha" || len(parent.SubagentIDs) != 1 {
+		t.Errorf("Expected alpha with its subagent, got %+v", parent)
+	}
+	if _, err := repo.FindSessionBySubagentID(ctx, "subagent-2"); err == nil {
+		t.Error("Expected an error for an unknown subagent")
+	}
+	if err := repo.UpdateSession(ctx, &domain.Session{ID: "missing"}); err == nil {
+		t.Error("Expected updating a missing session to fail")
+	}
+
+	sessions, err := repo.ListSessions(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != "alpha" || sessions[0].EventCount != 2 || sessions[1].ID != "beta" {
+		t.Errorf("Expected alpha then beta with their event counts, got %+v", sessions)
+	}
+}
@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+var _ ports.SettingsRepository = (*SettingsRepository)(nil)
+
+// SettingsRepository implements the SettingsRepository port for SQLite
+type SettingsRepository struct {
+	db *sql.DB
+}
+
+// NewSettingsRepository creates a new SQLite settings repository
+func NewSettingsRepository(db *sql.DB) *SettingsRepository {
+	return &SettingsRepository{db: db}
+}
+
+// GetSetting retrieves a setting's value, returning found=false if it has never been set
+func (r *SettingsRepository) GetSetting(ctx context.Context, name string) (string, bool, error) {
+	query := `SELECT setting_value FROM server_settings WHERE setting_name = ?`
+
+	var value string
+	err := r.db.QueryRowContext(ctx, query, name).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get setting %s: %w", name, err)
+	}
+
+	return value, true, nil
+}
+
+// SetSetting creates or replaces a setting's value
+func (r *SettingsRepository) SetSetting(ctx context.Context, name string, value string) error {
+	query := `
+		INSERT INTO server_settings (setting_name, setting_value, updated_at)
+		VALUES (?1, ?2, ?3)
+		ON CONFLICT (setting_name) DO UPDATE SET setting_value = ?2, updated_at = ?3`
+
+	if _, err := r.db.ExecContext(ctx, query, name, value, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to set setting %s: %w", name, err)
+	}
+
+	return nil
+}
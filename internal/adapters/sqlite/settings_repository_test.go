@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSettingsRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewSettingsRepository(openTestDB(t))
+
+	if _, found, err := repo.GetSetting(ctx, "hooks_disabled"); err != nil || found {
+		t.Fatalf("Expected an unset setting, got found=%t err=%v", found, err)
+	}
+
+	for _, value := range []string{"true", "false"} {
+		if err := repo.SetSetting(ctx, "hooks_disabled", value); err != nil {
+			t.Fatalf("SetSetting failed: %v", err)
+		}
+		got, found, err := repo.GetSetting(ctx, "hooks_disabled")
+		if err != nil || !found || got != value {
+			t.Errorf("Expected %q, got %q (found=%t err=%v)", value, got, found, err)
+		}
+	}
+}
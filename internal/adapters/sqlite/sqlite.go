@@ -0,0 +1,93 @@
+// Package sqlite implements the repository ports on an embedded SQLite database
+// It mirrors the postgres adapters so a single-user install can run without a database server.
+package sqlite
+
+import (
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+//go:embed schema.sql
+var schema string
+
+// Open opens the SQLite database file at path, creating it and any missing tables
+// Times are stored as UTC text in SQLite's own format so they sort and compare as strings,
+// and a single connection serialises writes instead of failing with SQLITE_BUSY.
+func Open(path string) (*sql.DB, error) {
+	dsn := path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_time_format=sqlite"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to apply sqlite schema: %w", err)
+	}
+
+	return db, nil
+}
+
+// utc converts a time to UTC before it is bound, since stored times are compared as text
+func utc(t time.Time) time.Time {
+	return t.UTC()
+}
+
+// utcPtr converts an optional time to UTC, keeping nil as NULL
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	converted := t.UTC()
+	return &converted
+}
+
+// timestampLayouts are the text forms SQLite hands back for times: our own writes, then CURRENT_TIMESTAMP and strftime
+var timestampLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	time.RFC3339Nano,
+}
+
+// timestamp scans a time from a column or expression
+// The driver only converts columns declared as TIMESTAMP; expressions such as MIN or COALESCE come back as text.
+type timestamp struct {
+	Time  time.Time
+	Valid bool
+}
+
+// Scan implements sql.Scanner
+func (ts *timestamp) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*ts = timestamp{}
+		return nil
+	case time.Time:
+		*ts = timestamp{Time: v, Valid: true}
+		return nil
+	case []byte:
+		return ts.parse(string(v))
+	case string:
+		return ts.parse(v)
+	}
+	return fmt.Errorf("cannot scan %T into a timestamp", value)
+}
+
+// parse reads a time in any of timestampLayouts, taking times without a zone to be UTC
+func (ts *timestamp) parse(text string) error {
+	text = strings.TrimSpace(text)
+	for _, layout := range timestampLayouts {
+		if parsed, err := time.Parse(layout, text); err == nil {
+			*ts = timestamp{Time: parsed, Valid: true}
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid timestamp: %q", text)
+}
@@ -0,0 +1,215 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+var _ ports.TaskHistoryRepository = (*TaskHistoryRepository)(nil)
+
+// TaskHistoryRepository implements the TaskHistoryRepository port for SQLite
+type TaskHistoryRepository struct {
+	db *sql.DB
+}
+
+// NewTaskHistoryRepository creates a new SQLite task history repository
+func NewTaskHistoryRepository(db *sql.DB) *TaskHistoryRepository {
+	return &TaskHistoryRepository{db: db}
+}
+
+// Create stores a new task history entry
+func (r *TaskHistoryRepository) Create(ctx context.Context, history *domain.TaskHistory) error {
+	query := `
+		INSERT INTO task_history (id, task_id, action, data, created_at)
+		VALUES (?, ?, ?, ?, ?)`
+
+	var data *string
+	if history.Data != nil {
+		dataJSON, err := json.Marshal(history.Data)
+		if err != nil {
+			return fmt.Errorf("failed to marshal history data: %w", err)
+		}
+		dataStr := string(dataJSON)
+		data = &dataStr
+	}
+
+	_, err := r.db.ExecContext(ctx, query,
+		history.ID,
+		history.TaskID,
+		history.Action,
+		data,
+		utc(history.CreatedAt),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create task history: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTaskID retrieves all history entries for a task
+func (r *TaskHistoryRepository) GetByTaskID(ctx context.Context, taskID uuid.UUID) ([]*domain.TaskHistory, error) {
+	query := `
+		SELECT id, task_id, action, data, created_at
+		FROM task_history
+		WHERE task_id = ?
+		ORDER BY created_at ASC`
+
+	return r.queryHistory(ctx, query, taskID)
+}
+
+// List retrieves history entries with optional filtering
+func (r *TaskHistoryRepository) List(ctx context.Context, filter ports.TaskHistoryFilter) ([]*domain.TaskHistory, error) {
+	query := "SELECT id, task_id, action, data, created_at FROM task_history"
+	args := []interface{}{}
+	conditions := []string{}
+
+	if filter.TaskID != nil {
+		conditions = append(conditions, "task_id = ?")
+		args = append(args, *filter.TaskID)
+	}
+
+	if filter.Action != nil {
+		conditions = append(conditions, "action = ?")
+		args = append(args, *filter.Action)
+	}
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	if filter.SortBy != "" {
+		if filter.SortBy != "created_at" {
+			return nil, fmt.Errorf("failed to list task history: cannot sort by %q", filter.SortBy)
+		}
+		orderDirection := "ASC"
+		if filter.SortOrder == "desc" {
+			orderDirection = "DESC"
+		}
+		query += " ORDER BY created_at " + orderDirection
+	} else {
+		query += " ORDER BY created_at DESC"
+	}
+
+	query, args = withLimitOffset(query, args, filter.Limit, filter.Offset)
+	return r.queryHistory(ctx, query, args...)
+}
+
+// queryHistory runs a query selecting full task history rows and scans the results
+func (r *TaskHistoryRepository) queryHistory(ctx context.Context, query string, args ...interface{}) ([]*domain.TaskHistory, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task history: %w", err)
+	}
+	defer rows.Close()
+
+	var histories []*domain.TaskHistory
+	for rows.Next() {
+		history, err := r.scanTaskHistory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task history: %w", err)
+		}
+		histories = append(histories, history)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task history: %w", err)
+	}
+
+	return histories, nil
+}
+
+// DeleteOlderThan removes history entries older than the given number of days and returns how many were deleted
+func (r *TaskHistoryRepository) DeleteOlderThan(ctx context.Context, days int) (int64, error) {
+	query := `
+		DELETE FROM task_history
+		WHERE created_at < datetime('now', ?)`
+
+	result, err := r.db.ExecContext(ctx, query, fmt.Sprintf("-%d days", days))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old task history: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// GetHourlyActivity counts history events per hour since the given time, oldest hour first
+// Hours without any events are omitted.
+func (r *TaskHistoryRepository) GetHourlyActivity(ctx context.Context, since time.Time) ([]ports.HourlyBucket, error) {
+	query := `
+		SELECT
+			strftime('%Y-%m-%d %H:00:00', created_at) AS hour,
+			COUNT(*) AS events,
+			COUNT(CASE WHEN action NOT IN (?, ?) THEN 1 END) AS actions
+		FROM task_history
+		WHERE created_at >= ?
+		GROUP BY 1
+		ORDER BY 1`
+
+	rows, err := r.db.QueryContext(ctx, query, domain.HistoryActionCreated, domain.HistoryActionNotified, utc(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hourly activity: %w", err)
+	}
+	defer rows.Close()
+
+	buckets := []ports.HourlyBucket{}
+	for rows.Next() {
+		var bucket ports.HourlyBucket
+		var hour timestamp
+		if err := rows.Scan(&hour, &bucket.EventCount, &bucket.ActionsTaken); err != nil {
+			return nil, fmt.Errorf("failed to scan hourly activity: %w", err)
+		}
+		bucket.Hour = hour.Time
+		buckets = append(buckets, bucket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating hourly activity: %w", err)
+	}
+
+	return buckets, nil
+}
+
+// scanTaskHistory scans a database row into a TaskHistory struct
+func (r *TaskHistoryRepository) scanTaskHistory(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*domain.TaskHistory, error) {
+	var history domain.TaskHistory
+	var dataJSON *string
+
+	err := scanner.Scan(
+		&history.ID,
+		&history.TaskID,
+		&history.Action,
+		&dataJSON,
+		&history.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if dataJSON != nil {
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(*dataJSON), &data); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal history data: %w", err)
+		}
+		history.Data = data
+	}
+
+	return &history, nil
+}
@@ -0,0 +1,781 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+var _ ports.TaskRepository = (*TaskRepository)(nil)
+
+// taskColumns are the columns scanTask reads, in order
+const taskColumns = "id, hook_type, task_data, status, created_at, updated_at, action_taken, response_data, snoozed_until, parent_task_id"
+
+// decisionDurationMs is how long a task took to decide, in milliseconds
+const decisionDurationMs = "(julianday(updated_at) - julianday(created_at)) * 86400000"
+
+// TaskRepository implements the TaskRepository port for SQLite
+type TaskRepository struct {
+	db *sql.DB
+}
+
+// NewTaskRepository creates a new SQLite task repository
+func NewTaskRepository(db *sql.DB) *TaskRepository {
+	return &TaskRepository{db: db}
+}
+
+// Create stores a new task
+func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
+	query := `
+		INSERT INTO tasks (id, hook_type, task_data, status, created_at, updated_at, parent_task_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	hookDataJSON, err := json.Marshal(task.HookData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hook data: %w", err)
+	}
+
+	hookDataStr := string(hookDataJSON)
+	if hookDataStr == "" || hookDataStr == "null" {
+		hookDataStr = "{}"
+	}
+
+	_, err = r.db.ExecContext(ctx, query,
+		task.ID,
+		task.HookType.String(),
+		hookDataStr,
+		task.Status.String(),
+		utc(task.CreatedAt),
+		utc(task.UpdatedAt),
+		task.ParentTaskID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create task: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a task by its ID
+func (r *TaskRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Task, error) {
+	query := "SELECT " + taskColumns + " FROM tasks WHERE id = ?"
+
+	task, err := r.scanTask(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("task not found: %s", id)
+		}
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	return task, nil
+}
+
+// Update updates an existing task
+func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
+	query := `
+		UPDATE tasks
+		SET hook_type = ?, task_data = ?, status = ?, updated_at = ?, action_taken = ?, response_data = ?, snoozed_until = ?
+		WHERE id = ?`
+
+	var actionTaken *string
+	if task.ActionTaken != nil {
+		action := task.ActionTaken.String()
+		actionTaken = &action
+	}
+
+	var responseData *string
+	if task.ResponseData != nil {
+		responseDataJSON, err := json.Marshal(task.ResponseData)
+		if err != nil {
+			return fmt.Errorf("failed to marshal response data: %w", err)
+		}
+		responseDataStr := string(responseDataJSON)
+		responseData = &responseDataStr
+	}
+
+	hookDataJSON, err := json.Marshal(task.HookData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hook data: %w", err)
+	}
+
+	result, err := r.db.ExecContext(ctx, query,
+		task.HookType.String(),
+		string(hookDataJSON),
+		task.Status.String(),
+		utc(task.UpdatedAt),
+		actionTaken,
+		responseData,
+		utcPtr(task.SnoozedUntil),
+		task.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("task not found: %s", task.ID)
+	}
+
+	return nil
+}
+
+// List retrieves tasks with optional filtering
+func (r *TaskRepository) List(ctx context.Context, filter ports.TaskFilter) ([]*domain.Task, error) {
+	return r.listFrom(ctx, "tasks", filter)
+}
+
+// Count counts the tasks List would return for the filter, ignoring its limit and offset
+func (r *TaskRepository) Count(ctx context.Context, filter ports.TaskFilter) (int64, error) {
+	where, args := taskFilterConditions(filter)
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks"+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count tasks: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListArchived retrieves archived tasks with the same filtering as List
+func (r *TaskRepository) ListArchived(ctx context.Context, filter ports.TaskFilter) ([]*domain.Task, error) {
+	return r.listFrom(ctx, "tasks_archive", filter)
+}
+
+// ArchiveCompleted moves tasks that are no longer pending and haven't changed in olderThan to tasks_archive
+func (r *TaskRepository) ArchiveCompleted(ctx context.Context, olderThan time.Duration) (int, error) {
+	now := time.Now().UTC()
+	cutoff := now.Add(-olderThan)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	archiveQuery := `
+		INSERT INTO tasks_archive (id, hook_type, task_data, status, created_at, updated_at, action_taken, response_data, snoozed_until, parent_task_id, archived_at)
+		SELECT id, hook_type, task_data, status, created_at, updated_at, action_taken, response_data, snoozed_until, parent_task_id, ?
+		FROM tasks
+		WHERE status <> ? AND updated_at < ?
+		ON CONFLICT (id) DO NOTHING`
+
+	result, err := tx.ExecContext(ctx, archiveQuery, now, domain.TaskStatusPending.String(), cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive tasks: %w", err)
+	}
+
+	archived, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	// Only delete rows that made it into the archive
+	deleteQuery := `
+		DELETE FROM tasks
+		WHERE id IN (SELECT id FROM tasks_archive)`
+
+	if _, err := tx.ExecContext(ctx, deleteQuery); err != nil {
+		return 0, fmt.Errorf("failed to delete archived tasks: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit task archive: %w", err)
+	}
+
+	return int(archived), nil
+}
+
+// sortColumns are the columns a task filter may sort by
+var sortColumns = map[string]bool{"created_at": true, "updated_at": true, "status": true}
+
+// listFrom runs a filtered task query against the live or archive table
+func (r *TaskRepository) listFrom(ctx context.Context, table string, filter ports.TaskFilter) ([]*domain.Task, error) {
+	query := "SELECT " + taskColumns + " FROM " + table
+	where, args := taskFilterConditions(filter)
+	query += where
+
+	if filter.SortBy != "" {
+		if !sortColumns[filter.SortBy] {
+			return nil, fmt.Errorf("failed to list tasks: cannot sort by %q", filter.SortBy)
+		}
+		orderDirection := "ASC"
+		if filter.SortOrder == "desc" {
+			orderDirection = "DESC"
+		}
+		query += fmt.Sprintf(" ORDER BY %s %s", filter.SortBy, orderDirection)
+	} else {
+		query += " ORDER BY created_at DESC"
+	}
+
+	query, args = withLimitOffset(query, args, filter.Limit, filter.Offset)
+	return r.queryTasks(ctx, query, args...)
+}
+
+// ListBySession retrieves tasks matching the filter ordered by session, oldest first within each session
+// The filter's sort is ignored; tasks without a session ID come last.
+func (r *TaskRepository) ListBySession(ctx context.Context, filter ports.TaskFilter) ([]*domain.Task, error) {
+	query := "SELECT " + taskColumns + " FROM tasks"
+	where, args := taskFilterConditions(filter)
+	query += where + " ORDER BY session_id ASC NULLS LAST, created_at ASC"
+
+	query, args = withLimitOffset(query, args, filter.Limit, filter.Offset)
+	return r.queryTasks(ctx, query, args...)
+}
+
+// withLimitOffset appends LIMIT and OFFSET clauses for positive values
+// SQLite only accepts OFFSET after a LIMIT, so an offset alone gets LIMIT -1, which means no limit.
+func withLimitOffset(query string, args []interface{}, limit, offset int) (string, []interface{}) {
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	} else if offset > 0 {
+		query += " LIMIT -1"
+	}
+
+	if offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, offset)
+	}
+
+	return query, args
+}
+
+// taskFilterConditions builds the WHERE clause and its arguments for a task filter
+func taskFilterConditions(filter ports.TaskFilter) (string, []interface{}) {
+	args := []interface{}{}
+	conditions := []string{}
+
+	if filter.Status != nil {
+		args = append(args, filter.Status.String())
+		conditions = append(conditions, "status = ?")
+	}
+
+	if filter.HookType != nil {
+		args = append(args, filter.HookType.String())
+		conditions = append(conditions, "hook_type = ?")
+	}
+
+	// Matches anywhere in the hook data, so commands, file paths and prompts are all searched;
+	// LIKE already ignores case for ASCII in SQLite
+	if filter.SearchQuery != nil && *filter.SearchQuery != "" {
+		args = append(args, "%"+likeEscaper.Replace(*filter.SearchQuery)+"%")
+		conditions = append(conditions, `task_data LIKE ? ESCAPE '\'`)
+	}
+
+	if filter.CreatedAfter != nil {
+		args = append(args, utc(*filter.CreatedAfter))
+		conditions = append(conditions, "created_at >= ?")
+	}
+
+	if filter.CreatedBefore != nil {
+		args = append(args, utc(*filter.CreatedBefore))
+		conditions = append(conditions, "created_at <= ?")
+	}
+
+	if filter.SessionID != nil {
+		args = append(args, *filter.SessionID)
+		conditions = append(conditions, "session_id = ?")
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// likeEscaper makes LIKE wildcards in a search query match literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// queryTasks runs a query selecting full task rows and scans the results
+func (r *TaskRepository) queryTasks(ctx context.Context, query string, args ...interface{}) ([]*domain.Task, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []*domain.Task
+	for rows.Next() {
+		task, err := r.scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// Delete removes a task by ID
+func (r *TaskRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM tasks WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("task not found: %s", id)
+	}
+
+	return nil
+}
+
+// GetPendingTasks retrieves all tasks that require user action
+func (r *TaskRepository) GetPendingTasks(ctx context.Context) ([]*domain.Task, error) {
+	status := domain.TaskStatusPending
+	return r.List(ctx, ports.TaskFilter{Status: &status, SortBy: "created_at", SortOrder: "asc"})
+}
+
+// GetChildTasks retrieves the tasks whose parent is taskID, oldest first
+func (r *TaskRepository) GetChildTasks(ctx context.Context, taskID uuid.UUID) ([]*domain.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE parent_task_id = ?
+		ORDER BY created_at ASC`
+
+	return r.queryTasks(ctx, query, taskID)
+}
+
+// GetPendingTasksForSession retrieves the pending tasks raised by one Claude Code session, oldest first
+// Filters on the generated session_id column, served by idx_tasks_pending_session.
+func (r *TaskRepository) GetPendingTasksForSession(ctx context.Context, sessionID string) ([]*domain.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE session_id = ? AND status = ?
+		ORDER BY created_at ASC`
+
+	return r.queryTasks(ctx, query, sessionID, domain.TaskStatusPending.String())
+}
+
+// GetSessionSummary counts one Claude Code session's tasks by status and averages how long its decisions took
+// A session with no tasks comes back with every count at zero.
+func (r *TaskRepository) GetSessionSummary(ctx context.Context, sessionID string) (*ports.SessionSummary, error) {
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = ?),
+			COUNT(*) FILTER (WHERE status = ?),
+			COUNT(*) FILTER (WHERE status = ?),
+			MIN(created_at),
+			MAX(created_at),
+			COALESCE(AVG(` + decisionDurationMs + `) FILTER (WHERE action_taken IS NOT NULL), 0)
+		FROM tasks
+		WHERE session_id = ?`
+
+	summary := &ports.SessionSummary{SessionID: sessionID}
+	var firstSeen, lastSeen timestamp
+	err := r.db.QueryRowContext(ctx, query,
+		domain.TaskStatusPending.String(), domain.TaskStatusApproved.String(), domain.TaskStatusRejected.String(),
+		sessionID,
+	).Scan(
+		&summary.TotalTasks,
+		&summary.PendingTasks,
+		&summary.ApprovedTasks,
+		&summary.RejectedTasks,
+		&firstSeen,
+		&lastSeen,
+		&summary.AvgDecisionDurationMs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session summary: %w", err)
+	}
+
+	summary.FirstSeen = firstSeen.Time
+	summary.LastSeen = lastSeen.Time
+	return summary, nil
+}
+
+// GetExpiredSnoozes retrieves pending tasks whose snooze ended at or before now
+func (r *TaskRepository) GetExpiredSnoozes(ctx context.Context, now time.Time) ([]*domain.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE snoozed_until IS NOT NULL AND snoozed_until <= ? AND status = ?
+		ORDER BY snoozed_until ASC`
+
+	return r.queryTasks(ctx, query, utc(now), domain.TaskStatusPending.String())
+}
+
+// ClearSnooze removes a pending task's snooze, returning false if the task was resolved or unsnoozed meanwhile
+// Only snoozed_until is written so a concurrent decision on the task is never overwritten.
+func (r *TaskRepository) ClearSnooze(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := `
+		UPDATE tasks
+		SET snoozed_until = NULL
+		WHERE id = ? AND status = ? AND snoozed_until IS NOT NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id, domain.TaskStatusPending.String())
+	if err != nil {
+		return false, fmt.Errorf("failed to clear snooze: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetToolUsageStats aggregates tool calls created at or after since, most used tool first
+// Approvals and rejections come from the action taken; a failed task with no action timed out waiting
+// for a decision. Decision latency only averages over approved and rejected calls.
+func (r *TaskRepository) GetToolUsageStats(ctx context.Context, since time.Time) ([]ports.ToolUsageStat, error) {
+	query := `
+		SELECT
+			json_extract(task_data, '$.data.tool_name') AS tool_name,
+			COUNT(*) AS call_count,
+			COUNT(CASE WHEN action_taken = ?2 THEN 1 END) AS approval_count,
+			COUNT(CASE WHEN action_taken = ?3 THEN 1 END) AS rejection_count,
+			COUNT(CASE WHEN status = ?4 AND action_taken IS NULL THEN 1 END) AS timeout_count,
+			COALESCE(AVG(CASE WHEN action_taken IN (?2, ?3)
+				THEN ` + decisionDurationMs + ` END), 0) AS avg_decision_latency_ms
+		FROM tasks
+		WHERE created_at >= ?1 AND json_extract(task_data, '$.data.tool_name') IS NOT NULL
+		GROUP BY tool_name
+		ORDER BY call_count DESC, tool_name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, utc(since),
+		string(domain.ActionTypeApprove), string(domain.ActionTypeReject), domain.TaskStatusFailed.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tool usage stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []ports.ToolUsageStat{}
+	for rows.Next() {
+		var stat ports.ToolUsageStat
+		if err := rows.Scan(
+			&stat.ToolName,
+			&stat.CallCount,
+			&stat.ApprovalCount,
+			&stat.RejectionCount,
+			&stat.TimeoutCount,
+			&stat.AvgDecisionLatencyMs,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan tool usage stat: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tool usage stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetTaskStats counts the tasks created since a point in time by hook type, status and action taken,
+// with the average and 95th percentile time to a decision. SQLite has no grouping sets, so each breakdown
+// is its own branch of a UNION ALL, and has no PERCENTILE_CONT, so the percentile is computed here.
+func (r *TaskRepository) GetTaskStats(ctx context.Context, since time.Time) (*ports.TaskStats, error) {
+	query := `
+		SELECT 'hook_type', hook_type, COUNT(*), 0 FROM tasks WHERE created_at >= ?1 GROUP BY hook_type
+		UNION ALL
+		SELECT 'status', status, COUNT(*), 0 FROM tasks WHERE created_at >= ?1 GROUP BY status
+		UNION ALL
+		SELECT 'action', COALESCE(action_taken, ''), COUNT(*), 0 FROM tasks WHERE created_at >= ?1 GROUP BY action_taken
+		UNION ALL
+		SELECT 'total', '', COUNT(*), COUNT(*) FILTER (WHERE status = ?2) FROM tasks WHERE created_at >= ?1`
+
+	rows, err := r.db.QueryContext(ctx, query, utc(since), domain.TaskStatusPending.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &ports.TaskStats{
+		ByHookType: make(map[domain.HookType]int64),
+		ByStatus:   make(map[string]int64),
+		ByAction:   make(map[domain.ActionType]int64),
+	}
+	for rows.Next() {
+		var dimension, key string
+		var count, pending int64
+		if err := rows.Scan(&dimension, &key, &count, &pending); err != nil {
+			return nil, fmt.Errorf("failed to scan task stats row: %w", err)
+		}
+
+		switch dimension {
+		case "hook_type":
+			stats.ByHookType[domain.HookType(key)] = count
+		case "status":
+			stats.ByStatus[key] = count
+		case "action":
+			// Tasks still awaiting a decision group under a NULL action
+			if key != "" {
+				stats.ByAction[domain.ActionType(key)] = count
+			}
+		case "total":
+			stats.TotalTasks = count
+			stats.PendingTasks = pending
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task stats: %w", err)
+	}
+
+	durations, err := r.decisionDurations(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	if len(durations) > 0 {
+		var total float64
+		for _, duration := range durations {
+			total += duration
+		}
+		stats.AvgDecisionDurationMs = total / float64(len(durations))
+		stats.P95DecisionDurationMs = percentile(durations, 0.95)
+	}
+
+	return stats, nil
+}
+
+// decisionDurations returns how long each decided task created since a point in time took, shortest first
+func (r *TaskRepository) decisionDurations(ctx context.Context, since time.Time) ([]float64, error) {
+	query := `
+		SELECT ` + decisionDurationMs + ` AS duration_ms
+		FROM tasks
+		WHERE created_at >= ? AND action_taken IS NOT NULL
+		ORDER BY duration_ms`
+
+	rows, err := r.db.QueryContext(ctx, query, utc(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decision durations: %w", err)
+	}
+	defer rows.Close()
+
+	var durations []float64
+	for rows.Next() {
+		var duration float64
+		if err := rows.Scan(&duration); err != nil {
+			return nil, fmt.Errorf("failed to scan decision duration: %w", err)
+		}
+		durations = append(durations, duration)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating decision durations: %w", err)
+	}
+
+	return durations, nil
+}
+
+// percentile interpolates between the closest ranks of sorted values, as PostgreSQL's PERCENTILE_CONT does
+func percentile(sorted []float64, fraction float64) float64 {
+	rank := fraction * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+// GetCompactStats counts the PreCompact tasks created since a point in time by trigger and session,
+// with the average transcript line count over those whose transcript was read
+func (r *TaskRepository) GetCompactStats(ctx context.Context, since time.Time) (*ports.CompactStats, error) {
+	query := `
+		SELECT
+			COALESCE(session_id, '') AS session,
+			COUNT(*) AS compact_count,
+			COUNT(*) FILTER (WHERE json_extract(task_data, '$.data.trigger') = 'auto') AS auto_count,
+			COUNT(*) FILTER (WHERE json_extract(task_data, '$.data.trigger') = 'manual') AS manual_count,
+			COALESCE(SUM(CAST(json_extract(task_data, '$.data.transcript_lines') AS INTEGER)), 0) AS transcript_lines,
+			COUNT(json_extract(task_data, '$.data.transcript_lines')) AS counted
+		FROM tasks
+		WHERE hook_type = ? AND created_at >= ?
+		GROUP BY 1`
+
+	rows, err := r.db.QueryContext(ctx, query, domain.HookTypePreCompact.String(), utc(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compact stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &ports.CompactStats{BySession: make(map[string]int64)}
+	var transcriptLines, counted int64
+	for rows.Next() {
+		var sessionID string
+		var total, auto, manual, lines, sessionCounted int64
+		if err := rows.Scan(&sessionID, &total, &auto, &manual, &lines, &sessionCounted); err != nil {
+			return nil, fmt.Errorf("failed to scan compact stats row: %w", err)
+		}
+
+		stats.TotalCompacts += total
+		stats.AutoCompacts += auto
+		stats.ManualCompacts += manual
+		transcriptLines += lines
+		counted += sessionCounted
+		if sessionID != "" {
+			stats.BySession[sessionID] = total
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating compact stats: %w", err)
+	}
+
+	if counted > 0 {
+		stats.AvgTranscriptLines = float64(transcriptLines) / float64(counted)
+	}
+	return stats, nil
+}
+
+// CountConcurrentSessions counts the distinct sessions with a pending task created after since
+func (r *TaskRepository) CountConcurrentSessions(ctx context.Context, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(DISTINCT session_id)
+		FROM tasks
+		WHERE status = ? AND created_at > ?`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, domain.TaskStatusPending.String(), utc(since)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count concurrent sessions: %w", err)
+	}
+	return count, nil
+}
+
+// CountByStatus returns the number of tasks in each status
+// Statuses with no tasks are absent from the map.
+func (r *TaskRepository) CountByStatus(ctx context.Context) (map[domain.TaskStatus]int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT status, COUNT(*) FROM tasks GROUP BY status`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks by status: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[domain.TaskStatus]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan task status count: %w", err)
+		}
+		counts[domain.TaskStatus(status)] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task status counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// CountByHookType returns the number of tasks raised by each hook type
+// Hook types with no tasks are absent from the map.
+func (r *TaskRepository) CountByHookType(ctx context.Context) (map[domain.HookType]int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT hook_type, COUNT(*) FROM tasks GROUP BY hook_type`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks by hook type: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[domain.HookType]int)
+	for rows.Next() {
+		var hookType string
+		var count int
+		if err := rows.Scan(&hookType, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan hook type count: %w", err)
+		}
+		counts[domain.HookType(hookType)] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating hook type counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetTasksByHookType retrieves tasks filtered by hook type
+func (r *TaskRepository) GetTasksByHookType(ctx context.Context, hookType domain.HookType) ([]*domain.Task, error) {
+	return r.List(ctx, ports.TaskFilter{HookType: &hookType, SortBy: "created_at", SortOrder: "desc"})
+}
+
+// scanTask scans a database row of taskColumns into a Task struct
+func (r *TaskRepository) scanTask(scanner interface {
+	Scan(dest ...interface{}) error
+}) (*domain.Task, error) {
+	var task domain.Task
+	var hookTypeStr, statusStr string
+	var actionTakenStr, responseDataJSON *string
+	var hookDataJSON string
+
+	err := scanner.Scan(
+		&task.ID,
+		&hookTypeStr,
+		&hookDataJSON,
+		&statusStr,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&actionTakenStr,
+		&responseDataJSON,
+		&task.SnoozedUntil,
+		&task.ParentTaskID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hookType, err := domain.ParseHookType(hookTypeStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hook type in database: %s", hookTypeStr)
+	}
+	task.HookType = hookType
+
+	// HookData.UnmarshalJSON restores the concrete data struct for the hook type
+	if hookDataJSON != "" {
+		var hookData domain.HookData
+		if err := json.Unmarshal([]byte(hookDataJSON), &hookData); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal hook data: %w", err)
+		}
+		task.HookData = &hookData
+	}
+
+	task.Status = domain.TaskStatus(statusStr)
+	if !task.Status.IsValid() {
+		return nil, fmt.Errorf("invalid status in database: %s", statusStr)
+	}
+
+	if actionTakenStr != nil {
+		actionType := domain.ActionType(*actionTakenStr)
+		task.ActionTaken = &actionType
+	}
+
+	if responseDataJSON != nil {
+		var responseData map[string]interface{}
+		if err := json.Unmarshal([]byte(*responseDataJSON), &responseData); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response data: %w", err)
+		}
+		task.ResponseData = responseData
+	}
+
+	return &task, nil
+}
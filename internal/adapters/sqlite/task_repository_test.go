@@ -0,0 +1,396 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// openTestDB opens a fresh database file that is removed when the test ends
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := Open(filepath.Join(t.TempDir(), "claude_control.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// newTestRepository creates a task repository on a fresh database holding tasks
+// Each task is updated after it is created, since Create only stores a new task's fields.
+func newTestRepository(t *testing.T, tasks ...*domain.Task) *TaskRepository {
+	t.Helper()
+	ctx := context.Background()
+
+	repo := NewTaskRepository(openTestDB(t))
+	for _, task := range tasks {
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if err := repo.Update(ctx, task); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+	}
+	return repo
+}
+
+// closeTo reports whether two durations in milliseconds match to within a millisecond
+// julianday arithmetic isn't exact, so durations computed in SQL are off by fractions of a millisecond.
+func closeTo(got, expected float64) bool {
+	return math.Abs(got-expected) < 1
+}
+
+// newTestTask creates a task for a session, created age ago
+func newTestTask(hookType domain.HookType, status domain.TaskStatus, sessionID string, age time.Duration) *domain.Task {
+	data := &domain.PreToolUseHookData{
+		BaseHookData: domain.BaseHookData{HookEventName: hookType.String(), SessionID: sessionID},
+		ToolName:     "Bash",
+		ToolInput:    &domain.ToolInput{Command: "make " + sessionID},
+	}
+	task := domain.NewTask(&domain.HookData{Type: hookType, Data: data})
+	task.Status = status
+	task.CreatedAt = time.Now().Add(-age)
+	task.UpdatedAt = task.CreatedAt
+	return task
+}
+
+// taskIDs returns the IDs of tasks in order
+func taskIDs(tasks []*domain.Task) []uuid.UUID {
+	ids := make([]uuid.UUID, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+func TestTaskRepository_List(t *testing.T) {
+	oldest := newTestTask(domain.HookTypePreToolUse, domain.TaskStatusApproved, "alpha", 3*time.Hour)
+	middle := newTestTask(domain.HookTypePreToolUse, domain.TaskStatusPending, "beta", 2*time.Hour)
+	newest := newTestTask(domain.HookTypeStop, domain.TaskStatusPending, "alpha", time.Hour)
+	repo := newTestRepository(t, oldest, middle, newest)
+
+	pending := domain.TaskStatusPending
+	stop := domain.HookTypeStop
+	alpha := "alpha"
+	search := "MAKE BETA"
+	after := time.Now().Add(-150 * time.Minute)
+	before := time.Now().Add(-90 * time.Minute)
+
+	tests := []struct {
+		name     string
+		filter   ports.TaskFilter
+		expected []*domain.Task
+	}{
+		{"No filter is newest first", ports.TaskFilter{}, []*domain.Task{newest, middle, oldest}},
+		{"Status", ports.TaskFilter{Status: &pending}, []*domain.Task{newest, middle}},
+		{"Hook type", ports.TaskFilter{HookType: &stop}, []*domain.Task{newest}},
+		{"Session", ports.TaskFilter{SessionID: &alpha}, []*domain.Task{newest, oldest}},
+		{"Search ignores case", ports.TaskFilter{SearchQuery: &search}, []*domain.Task{middle}},
+		{"Date range", ports.TaskFilter{CreatedAfter: &after, CreatedBefore: &before}, []*domain.Task{middle}},
+		{"Oldest first", ports.TaskFilter{SortBy: "created_at", SortOrder: "asc"}, []*domain.Task{oldest, middle, newest}},
+		{"Sort by status", ports.TaskFilter{SessionID: &alpha, SortBy: "status", SortOrder: "desc"}, []*domain.Task{newest, oldest}},
+		{"Limit and offset", ports.TaskFilter{Limit: 1, Offset: 1}, []*domain.Task{middle}},
+		{"Offset past the end", ports.TaskFilter{Offset: 5}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := repo.List(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			got, expected := taskIDs(tasks), taskIDs(tt.expected)
+			if len(got) != len(expected) {
+				t.Fatalf("Expected %v, got %v", expected, got)
+			}
+			for i := range expected {
+				if got[i] != expected[i] {
+					t.Errorf("Task %d: expected %s, got %s", i, expected[i], got[i])
+				}
+			}
+
+			count, err := repo.Count(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("Count failed: %v", err)
+			}
+			tt.filter.Limit, tt.filter.Offset = 0, 0
+			unpaged, _ := repo.List(context.Background(), tt.filter)
+			if count != int64(len(unpaged)) {
+				t.Errorf("Expected Count to ignore limit and offset and return %d, got %d", len(unpaged), count)
+			}
+		})
+	}
+
+	if _, err := repo.List(context.Background(), ports.TaskFilter{SortBy: "hook_data"}); err == nil {
+		t.Error("Expected an error sorting by an unknown field")
+	}
+}
+
+func TestTaskRepository_PendingAndHookTypeQueries(t *testing.T) {
+	ctx := context.Background()
+	older := newTestTask(domain.HookTypePreToolUse, domain.TaskStatusPending, "alpha", 2*time.Hour)
+	newer := newTestTask(domain.HookTypePreToolUse, domain.TaskStatusPending, "beta", time.Hour)
+	decided := newTestTask(domain.HookTypePreToolUse, domain.TaskStatusRejected, "alpha", 30*time.Minute)
+	stop := newTestTask(domain.HookTypeStop, domain.TaskStatusPending, "alpha", time.Minute)
+	repo := newTestRepository(t, newer, decided, older, stop)
+
+	pending, err := repo.GetPendingTasks(ctx)
+	if err != nil {
+		t.Fatalf("GetPendingTasks failed: %v", err)
+	}
+	if ids := taskIDs(pending); len(ids) != 3 || ids[0] != older.ID || ids[1] != newer.ID || ids[2] != stop.ID {
+		t.Errorf("Expected pending tasks oldest first, got %v", ids)
+	}
+
+	forSession, err := repo.GetPendingTasksForSession(ctx, "alpha")
+	if err != nil {
+		t.Fatalf("GetPendingTasksForSession failed: %v", err)
+	}
+	if ids := taskIDs(forSession); len(ids) != 2 || ids[0] != older.ID || ids[1] != stop.ID {
+		t.Errorf("Expected alpha's pending tasks oldest first, got %v", ids)
+	}
+
+	preToolUse, err := repo.GetTasksByHookType(ctx, domain.HookTypePreToolUse)
+	if err != nil {
+		t.Fatalf("GetTasksByHookType failed: %v", err)
+	}
+	if ids := taskIDs(preToolUse); len(ids) != 3 || ids[0] != decided.ID || ids[2] != older.ID {
+		t.Errorf("Expected PreToolUse tasks newest first, got %v", ids)
+	}
+
+	bySession, err := repo.ListBySession(ctx, ports.TaskFilter{})
+	if err != nil {
+		t.Fatalf("ListBySession failed: %v", err)
+	}
+	if ids := taskIDs(bySession); len(ids) != 4 || ids[0] != older.ID || ids[2] != stop.ID || ids[3] != newer.ID {
+		t.Errorf("Expected tasks grouped by session, oldest first, got %v", ids)
+	}
+}
+
+func TestTaskRepository_GetChildTasks(t *testing.T) {
+	parent := newTestTask(domain.HookTypePreToolUse, domain.TaskStatusPending, "alpha", 3*time.Hour)
+	older := newTestTask(domain.HookTypeSubagentStop, domain.TaskStatusPending, "alpha", 2*time.Hour)
+	newer := newTestTask(domain.HookTypeSubagentStop, domain.TaskStatusApproved, "alpha", time.Hour)
+	other := newTestTask(domain.HookTypeSubagentStop, domain.TaskStatusPending, "alpha", time.Minute)
+	older.ParentTaskID = &parent.ID
+	newer.ParentTaskID = &parent.ID
+	repo := newTestRepository(t, parent, newer, older, other)
+
+	children, err := repo.GetChildTasks(context.Background(), parent.ID)
+	if err != nil {
+		t.Fatalf("GetChildTasks failed: %v", err)
+	}
+	if ids := taskIDs(children); len(ids) != 2 || ids[0] != older.ID || ids[1] != newer.ID {
+		t.Errorf("Expected the parent's children oldest first, got %v", ids)
+	}
+	if *children[0].ParentTaskID != parent.ID {
+		t.Errorf("Expected the parent ID to round trip, got %v", children[0].ParentTaskID)
+	}
+
+	children, err = repo.GetChildTasks(context.Background(), other.ID)
+	if err != nil {
+		t.Fatalf("GetChildTasks failed: %v", err)
+	}
+	if len(children) != 0 {
+		t.Errorf("Expected no children, got %v", taskIDs(children))
+	}
+}
+
+func TestTaskRepository_CreateUpdateDelete(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	task := newTestTask(domain.HookTypePreToolUse, domain.TaskStatusPending, "alpha", 0)
+
+	if err := repo.Update(ctx, task); err == nil {
+		t.Error("Expected updating a missing task to fail")
+	}
+	if err := repo.Create(ctx, task); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := repo.Create(ctx, task); err == nil {
+		t.Error("Expected creating a task twice to fail")
+	}
+
+	// Changes only reach the repository through Update
+	task.TakeAction(domain.ActionTypeApprove, nil)
+	stored, err := repo.GetByID(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if stored.Status != domain.TaskStatusPending {
+		t.Errorf("Expected the stored task to stay pending until updated, got %s", stored.Status)
+	}
+	if err := repo.Update(ctx, task); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if stored, _ := repo.GetByID(ctx, task.ID); stored.Status != domain.TaskStatusApproved {
+		t.Errorf("Expected the update to be stored, got %s", stored.Status)
+	}
+
+	if err := repo.Delete(ctx, task.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, task.ID); err == nil {
+		t.Error("Expected the deleted task to be gone")
+	}
+	if err := repo.Delete(ctx, task.ID); err == nil {
+		t.Error("Expected deleting a missing task to fail")
+	}
+}
+
+func TestTaskRepository_Snoozes(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	expired := newTestTask(domain.HookTypePreToolUse, domain.TaskStatusPending, "alpha", time.Hour)
+	expiredAt := now.Add(-time.Minute)
+	expired.SnoozedUntil = &expiredAt
+	later := newTestTask(domain.HookTypePreToolUse, domain.TaskStatusPending, "alpha", time.Hour)
+	laterAt := now.Add(time.Hour)
+	later.SnoozedUntil = &laterAt
+	resolved := newTestTask(domain.HookTypePreToolUse, domain.TaskStatusApproved, "alpha", time.Hour)
+	resolved.SnoozedUntil = &expiredAt
+	repo := newTestRepository(t, expired, later, resolved)
+
+	tasks, err := repo.GetExpiredSnoozes(ctx, now)
+	if err != nil {
+		t.Fatalf("GetExpiredSnoozes failed: %v", err)
+	}
+	if ids := taskIDs(tasks); len(ids) != 1 || ids[0] != expired.ID {
+		t.Errorf("Expected only the expired pending snooze, got %v", ids)
+	}
+
+	tests := []struct {
+		name     string
+		id       uuid.UUID
+		expected bool
+	}{
+		{"Snoozed pending task", expired.ID, true},
+		{"Already cleared", expired.ID, false},
+		{"Resolved task", resolved.ID, false},
+		{"Missing task", uuid.New(), false},
+	}
+	for _, tt := range tests {
+		cleared, err := repo.ClearSnooze(ctx, tt.id)
+		if err != nil {
+			t.Fatalf("%s: ClearSnooze failed: %v", tt.name, err)
+		}
+		if cleared != tt.expected {
+			t.Errorf("%s: expected %t, got %t", tt.name, tt.expected, cleared)
+		}
+	}
+}
+
+func TestTaskRepository_ArchiveCompleted(t *testing.T) {
+	ctx := context.Background()
+	old := newTestTask(domain.HookTypePreToolUse, domain.TaskStatusApproved, "alpha", 48*time.Hour)
+	oldPending := newTestTask(domain.HookTypePreToolUse, domain.TaskStatusPending, "alpha", 48*time.Hour)
+	recent := newTestTask(domain.HookTypePreToolUse, domain.TaskStatusRejected, "alpha", time.Minute)
+	repo := newTestRepository(t, old, oldPending, recent)
+
+	archived, err := repo.ArchiveCompleted(ctx, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("ArchiveCompleted failed: %v", err)
+	}
+	if archived != 1 {
+		t.Fatalf("Expected 1 archived task, got %d", archived)
+	}
+	if _, err := repo.GetByID(ctx, old.ID); err == nil {
+		t.Error("Expected the archived task to leave the live tasks")
+	}
+
+	listed, err := repo.ListArchived(ctx, ports.TaskFilter{})
+	if err != nil {
+		t.Fatalf("ListArchived failed: %v", err)
+	}
+	if ids := taskIDs(listed); len(ids) != 1 || ids[0] != old.ID {
+		t.Errorf("Expected the old resolved task in the archive, got %v", ids)
+	}
+	if count, _ := repo.Count(ctx, ports.TaskFilter{}); count != 2 {
+		t.Errorf("Expected 2 live tasks, got %d", count)
+	}
+}
+
+func TestTaskRepository_Stats(t *testing.T) {
+	ctx := context.Background()
+	approved := newTestTask(domain.HookTypePreToolUse, domain.TaskStatusPending, "alpha", time.Hour)
+	approved.TakeAction(domain.ActionTypeApprove, nil)
+	approved.UpdatedAt = approved.CreatedAt.Add(2 * time.Second)
+	rejected := newTestTask(domain.HookTypePreToolUse, domain.TaskStatusPending, "beta", time.Hour)
+	rejected.TakeAction(domain.ActionTypeReject, nil)
+	rejected.UpdatedAt = rejected.CreatedAt.Add(4 * time.Second)
+	timedOut := newTestTask(domain.HookTypePreToolUse, domain.TaskStatusFailed, "beta", time.Hour)
+	pending := newTestTask(domain.HookTypePreToolUse, domain.TaskStatusPending, "gamma", time.Minute)
+	compact := domain.NewTask(&domain.HookData{Type: domain.HookTypePreCompact, Data: &domain.PreCompactHookData{
+		BaseHookData:    domain.BaseHookData{SessionID: "alpha"},
+		Trigger:         "auto",
+		TranscriptLines: 120,
+	}})
+	repo := newTestRepository(t, approved, rejected, timedOut, pending, compact)
+	since := time.Now().Add(-2 * time.Hour)
+
+	toolStats, err := repo.GetToolUsageStats(ctx, since)
+	if err != nil {
+		t.Fatalf("GetToolUsageStats failed: %v", err)
+	}
+	expectedTool := ports.ToolUsageStat{ToolName: "Bash", CallCount: 4, ApprovalCount: 1, RejectionCount: 1, TimeoutCount: 1, AvgDecisionLatencyMs: 3000}
+	if len(toolStats) != 1 {
+		t.Fatalf("Expected %+v, got %+v", expectedTool, toolStats)
+	}
+	latency := toolStats[0].AvgDecisionLatencyMs
+	toolStats[0].AvgDecisionLatencyMs = expectedTool.AvgDecisionLatencyMs
+	if toolStats[0] != expectedTool || !closeTo(latency, 3000) {
+		t.Errorf("Expected %+v, got %+v", expectedTool, toolStats)
+	}
+
+	taskStats, err := repo.GetTaskStats(ctx, since)
+	if err != nil {
+		t.Fatalf("GetTaskStats failed: %v", err)
+	}
+	if taskStats.TotalTasks != 5 || taskStats.PendingTasks != 2 || taskStats.ByHookType[domain.HookTypePreToolUse] != 4 {
+		t.Errorf("Unexpected task counts: %+v", taskStats)
+	}
+	if taskStats.ByAction[domain.ActionTypeApprove] != 1 || taskStats.ByStatus["failed"] != 1 {
+		t.Errorf("Unexpected breakdowns: %+v", taskStats)
+	}
+	if !closeTo(taskStats.AvgDecisionDurationMs, 3000) || !closeTo(taskStats.P95DecisionDurationMs, 3900) {
+		t.Errorf("Expected 3000ms average and 3900ms p95, got %v and %v", taskStats.AvgDecisionDurationMs, taskStats.P95DecisionDurationMs)
+	}
+
+	compactStats, err := repo.GetCompactStats(ctx, since)
+	if err != nil {
+		t.Fatalf("GetCompactStats failed: %v", err)
+	}
+	if compactStats.TotalCompacts != 1 || compactStats.AutoCompacts != 1 || compactStats.BySession["alpha"] != 1 || compactStats.AvgTranscriptLines != 120 {
+		t.Errorf("Unexpected compact stats: %+v", compactStats)
+	}
+
+	summary, err := repo.GetSessionSummary(ctx, "beta")
+	if err != nil {
+		t.Fatalf("GetSessionSummary failed: %v", err)
+	}
+	if summary.TotalTasks != 2 || summary.RejectedTasks != 1 || !closeTo(summary.AvgDecisionDurationMs, 4000) {
+		t.Errorf("Unexpected session summary: %+v", summary)
+	}
+
+	concurrent, err := repo.CountConcurrentSessions(ctx, time.Now().Add(-10*time.Minute))
+	if err != nil {
+		t.Fatalf("CountConcurrentSessions failed: %v", err)
+	}
+	if concurrent != 2 {
+		t.Errorf("Expected the 2 sessions with a recent pending task, got %d", concurrent)
+	}
+
+	byStatus, _ := repo.CountByStatus(ctx)
+	if byStatus[domain.TaskStatusPending] != 2 || byStatus[domain.TaskStatusApproved] != 1 {
+		t.Errorf("Unexpected status counts: %v", byStatus)
+	}
+}
@@ -11,6 +11,9 @@ import (
 	"github.com/dan/claude-control/internal/core/ports"
 )
 
+// Ensure Controller satisfies the TMuxController port
+var _ ports.TMuxController = (*Controller)(nil)
+
 // Controller implements the TMuxController port
 type Controller struct {
 	config *ports.TMuxConfig
@@ -202,6 +205,30 @@ func (c *Controller) GetSessionInfo(ctx context.Context, sessionName string) (*p
 	return session, nil
 }
 
+// CapturePaneScrollback returns up to maxLines of the session's scrollback plus the visible pane
+// Wrapped lines are joined so long command output reads as it was printed.
+func (c *Controller) CapturePaneScrollback(ctx context.Context, sessionName string, maxLines int) (string, error) {
+	cmd := exec.CommandContext(ctx, "tmux", c.capturePaneArgs(sessionName, maxLines)...)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to capture tmux scrollback for %s: %w", sessionName, err)
+	}
+
+	return string(output), nil
+}
+
+// capturePaneArgs builds the tmux arguments that print a session's pane starting maxLines into the history
+func (c *Controller) capturePaneArgs(sessionName string, maxLines int) []string {
+	args := []string{"capture-pane", "-p", "-J", "-t", sessionName, "-S", "-" + strconv.Itoa(maxLines)}
+
+	// Add socket path if configured
+	if c.config.SocketPath != "" {
+		args = append([]string{"-S", c.config.SocketPath}, args...)
+	}
+	return args
+}
+
 // formatTimestamp converts tmux timestamp to readable format
 func (c *Controller) formatTimestamp(timestamp string) string {
 	if timestamp == "" || timestamp == "0" {
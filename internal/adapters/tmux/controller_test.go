@@ -0,0 +1,211 @@
+package tmux
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+func TestController_CapturePaneArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   ports.TMuxConfig
+		expected []string
+	}{
+		{
+			name:     "default socket",
+			config:   ports.TMuxConfig{},
+			expected: []string{"capture-pane", "-p", "-J", "-t", "claude-haiper", "-S", "-100"},
+		},
+		{
+			name:     "custom socket",
+			config:   ports.TMuxConfig{SocketPath: "/tmp/tmux-claude"},
+			expected: []string{"-S", "/tmp/tmux-claude", "capture-pane", "-p", "-J", "-t", "claude-haiper", "-S", "-100"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := NewController(&tt.config)
+			if got := controller.capturePaneArgs("claude-haiper", 100); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestController_ListSessions(t *testing.T) {
+	created := time.Unix(1705311000, 0).Format("2006-01-02 15:04:05")
+	lastUsed := time.Unix(1705314600, 0).Format("2006-01-02 15:04:05")
+
+	tests := []struct {
+		name      string
+		stdout    string
+		stderr    string
+		exitCode  int
+		expected  []ports.TMuxSession
+		expectErr bool
+	}{
+		{
+			name:   "Sessions",
+			stdout: "claude-haiper:3:1705311000:1:1705314600\nscratch:1:1705311000:0:0\n",
+			expected: []ports.TMuxSession{
+				{Name: "claude-haiper", Windows: 3, Created: created, Attached: true, LastUsed: lastUsed},
+				{Name: "scratch", Windows: 1, Created: created, Attached: false, LastUsed: "N/A"},
+			},
+		},
+		{
+			name:     "No server running",
+			stderr:   "no server running on /tmp/tmux-1000/default\n",
+			exitCode: 1,
+			expected: []ports.TMuxSession{},
+		},
+		{
+			name:     "Malformed lines are skipped",
+			stdout:   "claude-haiper:3:1705311000:1:1705314600\nnot a session\n\n",
+			expected: []ports.TMuxSession{{Name: "claude-haiper", Windows: 3, Created: created, Attached: true, LastUsed: lastUsed}},
+		},
+		{
+			name:      "tmux fails",
+			stderr:    "error connecting to /tmp/tmux-1000/default (Permission denied)\n",
+			exitCode:  2,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := NewFakeTmux(t)
+			fake.Respond("list-sessions", tt.stdout, tt.stderr, tt.exitCode)
+
+			sessions, err := NewController(&ports.TMuxConfig{}).ListSessions(context.Background())
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("Expected an error, got sessions %+v", sessions)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(sessions, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, sessions)
+			}
+		})
+	}
+}
+
+func TestController_SessionExists(t *testing.T) {
+	tests := []struct {
+		name      string
+		stderr    string
+		exitCode  int
+		expected  bool
+		expectErr bool
+	}{
+		{"Found", "", 0, true, false},
+		{"Not found", "can't find session: claude-haiper\n", 1, false, false},
+		{"tmux fails", "", 2, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := NewFakeTmux(t)
+			fake.Respond("has-session", "", tt.stderr, tt.exitCode)
+
+			exists, err := NewController(&ports.TMuxConfig{SocketPath: "/tmp/tmux-claude"}).SessionExists(context.Background(), "claude-haiper")
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("SessionExists() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if exists != tt.expected {
+				t.Errorf("Expected exists %t, got %t", tt.expected, exists)
+			}
+
+			expectedCall := []string{"-S", "/tmp/tmux-claude", "has-session", "-t", "claude-haiper"}
+			if calls := fake.Calls(); len(calls) != 1 || !reflect.DeepEqual(calls[0], expectedCall) {
+				t.Errorf("Expected one call %v, got %v", expectedCall, calls)
+			}
+		})
+	}
+}
+
+func TestController_SendCommand(t *testing.T) {
+	t.Run("Types the command then Enter", func(t *testing.T) {
+		fake := NewFakeTmux(t)
+
+		if err := NewController(&ports.TMuxConfig{}).SendCommand(context.Background(), "claude-haiper", "continue"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := [][]string{
+			{"send-keys", "-t", "claude-haiper", "'continue'"},
+			{"send-keys", "-t", "claude-haiper", "Enter"},
+		}
+		if calls := fake.Calls(); !reflect.DeepEqual(calls, expected) {
+			t.Errorf("Expected calls %v, got %v", expected, calls)
+		}
+	})
+
+	t.Run("Missing session", func(t *testing.T) {
+		fake := NewFakeTmux(t)
+		fake.Respond("send-keys", "", "can't find pane: claude-haiper\n", 1)
+
+		err := NewController(&ports.TMuxConfig{}).SendCommand(context.Background(), "claude-haiper", "continue")
+		if err == nil || !strings.Contains(err.Error(), "can't find pane") {
+			t.Errorf("Expected an error with tmux's output, got %v", err)
+		}
+		if calls := fake.Calls(); len(calls) != 1 {
+			t.Errorf("Expected Enter not to be sent after the command failed, got calls %v", calls)
+		}
+	})
+}
+
+func TestController_CreateSession(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    ports.TMuxConfig
+		stderr    string
+		exitCode  int
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name:     "Default socket",
+			expected: []string{"new-session", "-d", "-s", "claude-haiper"},
+		},
+		{
+			name:     "Custom socket",
+			config:   ports.TMuxConfig{SocketPath: "/tmp/tmux-claude"},
+			expected: []string{"-S", "/tmp/tmux-claude", "new-session", "-d", "-s", "claude-haiper"},
+		},
+		{
+			name:      "Duplicate session",
+			stderr:    "duplicate session: claude-haiper\n",
+			exitCode:  1,
+			expected:  []string{"new-session", "-d", "-s", "claude-haiper"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := NewFakeTmux(t)
+			fake.Respond("new-session", "", tt.stderr, tt.exitCode)
+
+			err := NewController(&tt.config).CreateSession(context.Background(), "claude-haiper")
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("CreateSession() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr && !strings.Contains(err.Error(), tt.stderr) {
+				t.Errorf("Expected the error to include tmux's output, got %v", err)
+			}
+			if calls := fake.Calls(); len(calls) != 1 || !reflect.DeepEqual(calls[0], tt.expected) {
+				t.Errorf("Expected one call %v, got %v", tt.expected, calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package tmux
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeTmuxScript records each call's arguments, then answers with the output, stderr and exit code
+// configured for the subcommand. A leading -S <socket> is skipped when picking the subcommand.
+const fakeTmuxScript = `#!/bin/sh
+dir=$(dirname "$0")
+printf '%s\037' "$@" >> "$dir/calls"
+printf '\n' >> "$dir/calls"
+
+if [ "$1" = "-S" ]; then
+	shift 2
+fi
+[ -f "$dir/$1.out" ] && cat "$dir/$1.out"
+[ -f "$dir/$1.err" ] && cat "$dir/$1.err" >&2
+exit "$(cat "$dir/$1.code" 2>/dev/null || echo 0)"
+`
+
+// FakeTmux is a tmux binary put first on PATH for the length of a test
+// Subcommands without a response succeed with no output, like tmux commands that only act.
+type FakeTmux struct {
+	t   *testing.T
+	dir string
+}
+
+// NewFakeTmux writes the fake tmux script to a temporary directory and puts it first on PATH
+func NewFakeTmux(t *testing.T) *FakeTmux {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("The fake tmux binary is a shell script")
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "tmux"), []byte(fakeTmuxScript), 0755); err != nil {
+		t.Fatalf("Failed to write fake tmux: %v", err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return &FakeTmux{t: t, dir: dir}
+}
+
+// Respond sets what the subcommand prints to stdout and stderr and the exit code it returns
+func (f *FakeTmux) Respond(subcommand, stdout, stderr string, exitCode int) {
+	f.t.Helper()
+	files := map[string]string{
+		subcommand + ".out":  stdout,
+		subcommand + ".err":  stderr,
+		subcommand + ".code": strconv.Itoa(exitCode),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(f.dir, name), []byte(content), 0644); err != nil {
+			f.t.Fatalf("Failed to write fake tmux response: %v", err)
+		}
+	}
+}
+
+// Calls returns the arguments of every call to tmux so far, in order
+func (f *FakeTmux) Calls() [][]string {
+	f.t.Helper()
+	data, err := os.ReadFile(filepath.Join(f.dir, "calls"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		f.t.Fatalf("Failed to read fake tmux calls: %v", err)
+	}
+
+	var calls [][]string
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		calls = append(calls, strings.Split(strings.TrimSuffix(line, "\x1f"), "\x1f"))
+	}
+	return calls
+}
@@ -0,0 +1,208 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DefaultMaxTailBytes is how much of the end of a transcript is read when looking for recent entries
+	DefaultMaxTailBytes = 1 << 20
+
+	// maxContentLength caps each entry's content so one long tool result doesn't swamp the page
+	maxContentLength = 2000
+
+	// transcriptFileExtension is the extension of Claude Code transcripts; nothing else is read
+	transcriptFileExtension = ".jsonl"
+)
+
+// TranscriptEntry is one message from a Claude Code session transcript
+type TranscriptEntry struct {
+	Role      string `json:"role"`
+	Content   string `json:"content"`
+	Timestamp string `json:"ts,omitempty"`
+}
+
+// UnmarshalJSON decodes a transcript line, either a flat {"role","content","ts"} entry or Claude Code's
+// nested form where the message sits under "message" and its content may be a list of content blocks
+func (e *TranscriptEntry) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Role      string          `json:"role"`
+		Content   json.RawMessage `json:"content"`
+		TS        string          `json:"ts"`
+		Timestamp string          `json:"timestamp"`
+		Message   *struct {
+			Role    string          `json:"role"`
+			Content json.RawMessage `json:"content"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	e.Role = raw.Role
+	e.Timestamp = raw.TS
+	if e.Timestamp == "" {
+		e.Timestamp = raw.Timestamp
+	}
+
+	content := raw.Content
+	if raw.Message != nil {
+		if e.Role == "" {
+			e.Role = raw.Message.Role
+		}
+		content = raw.Message.Content
+	}
+	e.Content = truncate(contentText(content), maxContentLength)
+	return nil
+}
+
+// contentText returns message content as text, joining the text blocks of structured content
+func contentText(content json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(content, &text); err == nil {
+		return text
+	}
+
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(content, &blocks); err != nil {
+		return ""
+	}
+
+	parts := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		switch {
+		case block.Type == "text" && block.Text != "":
+			parts = append(parts, block.Text)
+		case block.Type == "tool_use" && block.Name != "":
+			parts = append(parts, fmt.Sprintf("[%s]", block.Name))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// TranscriptReader reads recent messages from Claude Code transcripts
+type TranscriptReader struct {
+	maxTailBytes int64
+}
+
+// NewTranscriptReader creates a reader that looks at most maxTailBytes back from the end of a transcript
+func NewTranscriptReader(maxTailBytes int64) *TranscriptReader {
+	if maxTailBytes <= 0 {
+		maxTailBytes = DefaultMaxTailBytes
+	}
+	return &TranscriptReader{maxTailBytes: maxTailBytes}
+}
+
+// ReadLast returns up to the last n messages of the transcript at path, oldest first
+// The path comes from a webhook body, so only absolute paths to regular .jsonl files are read. Lines that
+// aren't messages, such as summaries, or that can't be parsed are skipped.
+func (r *TranscriptReader) ReadLast(path string, n int) ([]TranscriptEntry, error) {
+	if n <= 0 {
+		return []TranscriptEntry{}, nil
+	}
+
+	cleaned, err := validateTranscriptPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(cleaned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat transcript: %w", err)
+	}
+
+	offset := info.Size() - r.maxTailBytes
+	if offset < 0 {
+		offset = 0
+	}
+	tail, err := io.ReadAll(io.NewSectionReader(file, offset, info.Size()-offset))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+
+	// Reading from the middle of the file almost always starts part way through a line
+	if offset > 0 {
+		if newline := bytes.IndexByte(tail, '\n'); newline >= 0 {
+			tail = tail[newline+1:]
+		} else {
+			tail = nil
+		}
+	}
+
+	return lastEntries(tail, n)
+}
+
+// lastEntries parses JSONL data and keeps the last n message entries
+func lastEntries(data []byte, n int) ([]TranscriptEntry, error) {
+	entries := make([]TranscriptEntry, 0, n)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry TranscriptEntry
+		if err := json.Unmarshal(line, &entry); err != nil || entry.Role == "" || entry.Content == "" {
+			continue
+		}
+
+		if len(entries) == n {
+			entries = append(entries[:0], entries[1:]...)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan transcript: %w", err)
+	}
+	return entries, nil
+}
+
+// validateTranscriptPath checks that a hook-supplied transcript path names a regular .jsonl file
+func validateTranscriptPath(transcriptPath string) (string, error) {
+	if !filepath.IsAbs(transcriptPath) {
+		return "", fmt.Errorf("transcript path %q is not absolute", transcriptPath)
+	}
+
+	cleaned := filepath.Clean(transcriptPath)
+	if filepath.Ext(cleaned) != transcriptFileExtension {
+		return "", fmt.Errorf("transcript path %q is not a %s file", transcriptPath, transcriptFileExtension)
+	}
+
+	// Lstat so a symlink can't point the reader at a file outside the transcript
+	info, err := os.Lstat(cleaned)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat transcript: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("transcript path %q is not a regular file", transcriptPath)
+	}
+	return cleaned, nil
+}
+
+// truncate shortens text to at most limit characters
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit])
+}
@@ -0,0 +1,107 @@
+package transcript
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTranscript(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+	return path
+}
+
+func TestTranscriptReader_ReadLast(t *testing.T) {
+	path := writeTranscript(t,
+		`{"role":"user","content":"first","ts":"2025-08-01T09:00:00Z"}`,
+		`{"type":"summary","summary":"Refactor the parser"}`,
+		`not json`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"Running the tests"},{"type":"tool_use","name":"Bash"}]},"timestamp":"2025-08-01T09:00:05Z"}`,
+		`{"role":"user","content":"third","ts":"2025-08-01T09:01:00Z"}`,
+	)
+
+	entries, err := NewTranscriptReader(0).ReadLast(path, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []TranscriptEntry{
+		{Role: "assistant", Content: "Running the tests\n[Bash]", Timestamp: "2025-08-01T09:00:05Z"},
+		{Role: "user", Content: "third", Timestamp: "2025-08-01T09:01:00Z"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d: %v", len(expected), len(entries), entries)
+	}
+	for i := range expected {
+		if entries[i] != expected[i] {
+			t.Errorf("Entry %d: expected %+v, got %+v", i, expected[i], entries[i])
+		}
+	}
+}
+
+func TestTranscriptReader_ReadLastOnlyReadsTheTail(t *testing.T) {
+	var lines []string
+	for i := 0; i < 50; i++ {
+		lines = append(lines, fmt.Sprintf(`{"role":"user","content":"message %02d"}`, i))
+	}
+	path := writeTranscript(t, lines...)
+
+	// About three lines fit in the tail; the partial first line must be dropped, not misparsed
+	entries, err := NewTranscriptReader(120).ReadLast(path, 20)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) == 0 || len(entries) > 3 {
+		t.Fatalf("Expected between 1 and 3 entries from the tail, got %d", len(entries))
+	}
+	if last := entries[len(entries)-1].Content; last != "message 49" {
+		t.Errorf("Expected the last message to be message 49, got %q", last)
+	}
+}
+
+func TestTranscriptReader_ReadLastRejectsUnsafePaths(t *testing.T) {
+	dir := t.TempDir()
+	target := writeTranscript(t, `{"role":"user","content":"secret"}`)
+	link := filepath.Join(dir, "link.jsonl")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+	notTranscript := filepath.Join(dir, "notes.txt")
+	os.WriteFile(notTranscript, []byte("hello"), 0o600)
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"Relative path", "session.jsonl"},
+		{"Wrong extension", notTranscript},
+		{"Symlink", link},
+		{"Missing file", filepath.Join(dir, "missing.jsonl")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTranscriptReader(0).ReadLast(tt.path, 20); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestTranscriptEntry_TruncatesLongContent(t *testing.T) {
+	path := writeTranscript(t, fmt.Sprintf(`{"role":"user","content":%q}`, strings.Repeat("x", maxContentLength+50)))
+
+	entries, err := NewTranscriptReader(0).ReadLast(path, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(entries) != 1 || len(entries[0].Content) != maxContentLength {
+		t.Errorf("Expected one entry truncated to %d characters, got %v", maxContentLength, entries)
+	}
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultTaskOverdueThreshold is how long a task can wait for a decision before the dashboard flags it as stale
+const DefaultTaskOverdueThreshold = 10 * time.Minute
+
+// FormatAge describes how long ago something happened, e.g. "just now" or "3 hours ago"
+// Ages are rounded down to the largest whole unit; anything under a minute, or in the future, is "just now".
+func FormatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return pluralAgo(int(age/time.Minute), "minute")
+	case age < 24*time.Hour:
+		return pluralAgo(int(age/time.Hour), "hour")
+	default:
+		return pluralAgo(int(age/(24*time.Hour)), "day")
+	}
+}
+
+// pluralAgo formats "1 minute ago" or "n minutes ago"
+func pluralAgo(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s ago", unit)
+	}
+	return fmt.Sprintf("%d %ss ago", n, unit)
+}
+
+// IsOverdue returns true if something that started at since has been waiting longer than threshold as of now
+func IsOverdue(since, now time.Time, threshold time.Duration) bool {
+	return now.Sub(since) > threshold
+}
+
+// AgeString describes how long ago the task was created, e.g. "2 minutes ago"
+func (t *Task) AgeString() string {
+	return FormatAge(time.Since(t.CreatedAt))
+}
+
+// IsOverdue returns true if the task is still pending and was created longer than threshold ago
+func (t *Task) IsOverdue(threshold time.Duration) bool {
+	return t.Status == TaskStatusPending && IsOverdue(t.CreatedAt, time.Now(), threshold)
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		age      time.Duration
+		expected string
+	}{
+		{0, "just now"},
+		{-5 * time.Second, "just now"},
+		{45 * time.Second, "just now"},
+		{59 * time.Second, "just now"},
+		{60 * time.Second, "1 minute ago"},
+		{90 * time.Second, "1 minute ago"},
+		{2 * time.Minute, "2 minutes ago"},
+		{59*time.Minute + 59*time.Second, "59 minutes ago"},
+		{time.Hour, "1 hour ago"},
+		{2 * time.Hour, "2 hours ago"},
+		{23 * time.Hour, "23 hours ago"},
+		{24 * time.Hour, "1 day ago"},
+		{50 * time.Hour, "2 days ago"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.age.String(), func(t *testing.T) {
+			if got := FormatAge(tt.age); got != tt.expected {
+				t.Errorf("FormatAge(%s) = %q, want %q", tt.age, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsOverdue(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		age      time.Duration
+		expected bool
+	}{
+		{"Just created", 0, false},
+		{"45 seconds", 45 * time.Second, false},
+		{"Exactly at threshold", DefaultTaskOverdueThreshold, false},
+		{"Just past threshold", DefaultTaskOverdueThreshold + time.Second, true},
+		{"2 hours", 2 * time.Hour, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsOverdue(now.Add(-tt.age), now, DefaultTaskOverdueThreshold); got != tt.expected {
+				t.Errorf("IsOverdue after %s = %t, want %t", tt.age, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTask_AgeAndOverdue(t *testing.T) {
+	tests := []struct {
+		name            string
+		age             time.Duration
+		status          TaskStatus
+		expectedAge     string
+		expectedOverdue bool
+	}{
+		{"Just created", 0, TaskStatusPending, "just now", false},
+		{"45 seconds", 45 * time.Second, TaskStatusPending, "just now", false},
+		{"90 seconds", 90 * time.Second, TaskStatusPending, "1 minute ago", false},
+		{"2 hours pending", 2 * time.Hour, TaskStatusPending, "2 hours ago", true},
+		{"2 hours approved", 2 * time.Hour, TaskStatusApproved, "2 hours ago", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{Status: tt.status, CreatedAt: time.Now().Add(-tt.age)}
+			if got := task.AgeString(); got != tt.expectedAge {
+				t.Errorf("AgeString() = %q, want %q", got, tt.expectedAge)
+			}
+			if got := task.IsOverdue(DefaultTaskOverdueThreshold); got != tt.expectedOverdue {
+				t.Errorf("IsOverdue() = %t, want %t", got, tt.expectedOverdue)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestVerifyApprovalToken(t *testing.T) {
+	taskID := uuid.New()
+	secret := "quick-action-secret"
+	token := GenerateApprovalToken(taskID, ActionTypeApprove, secret, time.Minute)
+	expiry, signature, _ := strings.Cut(token, ".")
+
+	tests := []struct {
+		name        string
+		token       string
+		taskID      uuid.UUID
+		action      ActionType
+		secret      string
+		expectedErr error
+	}{
+		{"Valid token", token, taskID, ActionTypeApprove, secret, nil},
+		{"Other task", token, uuid.New(), ActionTypeApprove, secret, ErrInvalidApprovalToken},
+		{"Other action", token, taskID, ActionTypeReject, secret, ErrInvalidApprovalToken},
+		{"Other secret", token, taskID, ActionTypeApprove, "another-secret", ErrInvalidApprovalToken},
+		{"No secret configured", token, taskID, ActionTypeApprove, "", ErrInvalidApprovalToken},
+		{"Extended expiry", "9999999999." + signature, taskID, ActionTypeApprove, secret, ErrInvalidApprovalToken},
+		{"Signature not hex", expiry + ".not-hex", taskID, ActionTypeApprove, secret, ErrInvalidApprovalToken},
+		{"No separator", signature, taskID, ActionTypeApprove, secret, ErrInvalidApprovalToken},
+		{"Empty token", "", taskID, ActionTypeApprove, secret, ErrInvalidApprovalToken},
+		{"Expired", GenerateApprovalToken(taskID, ActionTypeApprove, secret, -time.Minute), taskID, ActionTypeApprove, secret, ErrApprovalTokenExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyApprovalToken(tt.token, tt.taskID, tt.action, tt.secret)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestNotification_SetQuickActions(t *testing.T) {
+	taskID := uuid.New()
+	notification := NewNotification(taskID, HookTypePreToolUse, "claude.example.com/control", "")
+	notification.SetQuickActions("https://claude.example.com/control", "quick-action-secret", time.Minute)
+
+	for action, quickURL := range map[ActionType]string{ActionTypeApprove: notification.QuickApproveURL, ActionTypeReject: notification.QuickRejectURL} {
+		parsed, err := url.Parse(quickURL)
+		if err != nil {
+			t.Fatalf("Invalid quick %s URL %q: %v", action, quickURL, err)
+		}
+		if parsed.Scheme != "https" || parsed.Path != "/control/api/tasks/"+taskID.String()+"/action" || parsed.Query().Get("action") != string(action) {
+			t.Errorf("Unexpected quick %s URL %q", action, quickURL)
+		}
+		if err := VerifyApprovalToken(parsed.Query().Get("token"), taskID, action, "quick-action-secret"); err != nil {
+			t.Errorf("Expected a valid %s token, got %v", action, err)
+		}
+	}
+
+	withoutSecret := NewNotification(taskID, HookTypePreToolUse, "claude.example.com", "")
+	withoutSecret.SetQuickActions("https://claude.example.com", "", time.Minute)
+	alert := NewConcurrentSessionsNotification(3, "claude.example.com")
+	alert.SetQuickActions("https://claude.example.com", "quick-action-secret", time.Minute)
+	for _, n := range []*Notification{withoutSecret, alert} {
+		if n.QuickApproveURL != "" || n.QuickRejectURL != "" {
+			t.Errorf("Expected no quick actions, got %q and %q", n.QuickApproveURL, n.QuickRejectURL)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RiskLevel rates how much damage a shell command could do if approved by mistake
+type RiskLevel string
+
+const (
+	RiskLevelSafe     RiskLevel = "safe"
+	RiskLevelLow      RiskLevel = "low"
+	RiskLevelMedium   RiskLevel = "medium"
+	RiskLevelHigh     RiskLevel = "high"
+	RiskLevelCritical RiskLevel = "critical"
+)
+
+// riskLevelOrder lists risk levels from least to most dangerous
+var riskLevelOrder = []RiskLevel{RiskLevelSafe, RiskLevelLow, RiskLevelMedium, RiskLevelHigh, RiskLevelCritical}
+
+// severity returns the level's position in riskLevelOrder, so levels can be compared
+func (l RiskLevel) severity() int {
+	for i, level := range riskLevelOrder {
+		if level == l {
+			return i
+		}
+	}
+	return 0
+}
+
+// ToNotificationPriority returns how urgently the user should be notified about a command at this risk level
+func (l RiskLevel) ToNotificationPriority() NotificationPriority {
+	switch l {
+	case RiskLevelSafe:
+		return PriorityLow
+	case RiskLevelLow:
+		return PriorityNormal
+	case RiskLevelHigh, RiskLevelCritical:
+		return PriorityUrgent
+	default:
+		return PriorityHigh
+	}
+}
+
+// Command categories describe what kind of effect a command has
+const (
+	CommandCategoryRead    = "read"
+	CommandCategoryWrite   = "write"
+	CommandCategoryExec    = "exec"
+	CommandCategoryNetwork = "network"
+	CommandCategoryDelete  = "delete"
+)
+
+// CommandRisk is the classification of a shell command Claude wants to run
+type CommandRisk struct {
+	Level       RiskLevel `json:"level"`
+	Category    string    `json:"category"`
+	Explanation string    `json:"explanation"`
+}
+
+// commandRule classifies a command segment that matches its pattern
+type commandRule struct {
+	pattern *regexp.Regexp
+	risk    CommandRisk
+}
+
+// rule builds a commandRule; patterns are matched against a single command segment with leading whitespace removed
+func rule(pattern string, level RiskLevel, category, explanation string) commandRule {
+	return commandRule{
+		pattern: regexp.MustCompile(pattern),
+		risk:    CommandRisk{Level: level, Category: category, Explanation: explanation},
+	}
+}
+
+// pipedScriptRules are checked against the whole command, since they only make sense across a pipe
+var pipedScriptRules = []commandRule{
+	rule(`\b(curl|wget)\b[^|]*\|\s*(sudo\s+)?(ba|z)?sh\b`, RiskLevelCritical, CommandCategoryExec, "Runs a script downloaded from the network"),
+}
+
+// defaultCommandRules are checked in order against each command segment; the first match wins
+var defaultCommandRules = []commandRule{
+	// Deleting
+	rule(`^rm\s+(.*\s)?-([a-zA-Z]*r[a-zA-Z]*f|[a-zA-Z]*f[a-zA-Z]*r)\b`, RiskLevelCritical, CommandCategoryDelete, "Recursively force-deletes files"),
+	rule(`^rm\s+(.*\s)?(-[a-zA-Z]*[rR]|--recursive)\b`, RiskLevelHigh, CommandCategoryDelete, "Recursively deletes files"),
+	rule(`^(mkfs(\.\w+)?|shred|wipefs)\b`, RiskLevelCritical, CommandCategoryDelete, "Destroys data on a disk or file"),
+	rule(`^dd\b`, RiskLevelCritical, CommandCategoryWrite, "Writes raw data to a file or device"),
+	rule(`^find\b.*\s(-delete|-exec\s+rm)\b`, RiskLevelHigh, CommandCategoryDelete, "Deletes every file a search matches"),
+	rule(`^git\s+(reset\s+--hard|clean\s+-[a-zA-Z]*f)`, RiskLevelHigh, CommandCategoryDelete, "Discards uncommitted changes"),
+	rule(`^(rm|rmdir|unlink)\b`, RiskLevelMedium, CommandCategoryDelete, "Deletes files"),
+
+	// Git
+	rule(`^git\s+push\b.*\s(--force|-f)\b`, RiskLevelHigh, CommandCategoryNetwork, "Force-pushes, which can overwrite remote history"),
+	rule(`^git\s+(push|pull|fetch|clone)\b`, RiskLevelMedium, CommandCategoryNetwork, "Talks to a git remote"),
+	rule(`^git\s+(status|log|diff|show|blame|branch|remote|rev-parse|ls-files)\b`, RiskLevelSafe, CommandCategoryRead, "Reads repository state"),
+	rule(`^git\s+`, RiskLevelLow, CommandCategoryWrite, "Changes the local repository"),
+
+	// Privileges and system state
+	rule(`^(chmod|chown)\s+(.*\s)?(777|[ugoa]*\+s)\b`, RiskLevelHigh, CommandCategoryWrite, "Opens up file permissions"),
+	rule(`^(chmod|chown|chgrp)\b`, RiskLevelMedium, CommandCategoryWrite, "Changes file permissions"),
+	rule(`^(shutdown|reboot|halt|poweroff|kill|killall|pkill|systemctl|service|crontab|mount|umount)\b`, RiskLevelHigh, CommandCategoryExec, "Changes running processes or system services"),
+
+	// Network
+	rule(`^(npm|yarn|pnpm|pip3?|go|cargo|gem|brew|apt(-get)?)\s+(install|add|get)\b`, RiskLevelMedium, CommandCategoryNetwork, "Downloads and installs packages"),
+	rule(`^(curl|wget|ssh|scp|rsync|nc|netcat|telnet|ftp|sftp)\b`, RiskLevelMedium, CommandCategoryNetwork, "Connects to another machine"),
+
+	// Running code
+	rule(`^(go|cargo|make|npm|yarn|pnpm|pytest|tsc|eslint|gofmt)\b`, RiskLevelLow, CommandCategoryExec, "Runs a build, test or lint tool"),
+	rule(`^(python3?|node|ruby|perl|bash|sh|zsh|eval|exec|docker|xargs)\b`, RiskLevelMedium, CommandCategoryExec, "Runs arbitrary code"),
+
+	// Writing
+	rule(`^sed\s+(.*\s)?-i`, RiskLevelLow, CommandCategoryWrite, "Edits files in place"),
+	rule(`^(mv|cp|mkdir|touch|ln|tee|patch)\b`, RiskLevelLow, CommandCategoryWrite, "Creates or changes files"),
+
+	// Reading
+	rule(`^(ls|cat|head|tail|less|more|grep|rg|ag|find|pwd|cd|echo|printf|wc|which|tree|stat|file|du|df|diff|sort|uniq|cut|awk|sed|jq|env|whoami|date|true|test|ps)\b`, RiskLevelSafe, CommandCategoryRead, "Only reads files or system state"),
+}
+
+// unknownCommandRisk is used for commands no rule recognises; they could do anything, so they aren't rated safe
+var unknownCommandRisk = CommandRisk{Level: RiskLevelMedium, Category: CommandCategoryExec, Explanation: "Runs an unrecognized command"}
+
+// commandSeparators splits a command line into the commands it runs
+var commandSeparators = regexp.MustCompile(`&&|\|\||[;|\n]`)
+
+// envAssignmentPrefix matches VAR=value assignments in front of a command
+var envAssignmentPrefix = regexp.MustCompile(`^(\w+=\S*\s+)+`)
+
+// CommandClassifier rates the risk of shell commands with pattern tables
+type CommandClassifier struct {
+	rules []commandRule
+}
+
+// NewCommandClassifier creates a classifier with the built-in rules for common shell commands
+func NewCommandClassifier() *CommandClassifier {
+	return &CommandClassifier{rules: defaultCommandRules}
+}
+
+// Classify rates a command line by its riskiest part
+// A line like "cd build && rm -rf *" is split on ;, &&, || and pipes, and the most dangerous segment decides the result.
+func (c *CommandClassifier) Classify(command string) CommandRisk {
+	for _, piped := range pipedScriptRules {
+		if piped.pattern.MatchString(command) {
+			return piped.risk
+		}
+	}
+
+	var riskiest *CommandRisk
+	for _, segment := range commandSeparators.Split(command, -1) {
+		segment = strings.TrimSpace(segment)
+		if segment == "" {
+			continue
+		}
+		risk := c.classifySegment(segment)
+		if riskiest == nil || risk.Level.severity() > riskiest.Level.severity() {
+			riskiest = &risk
+		}
+	}
+
+	if riskiest == nil {
+		return CommandRisk{Level: RiskLevelSafe, Category: CommandCategoryRead, Explanation: "Empty command"}
+	}
+	return *riskiest
+}
+
+// classifySegment rates a single command, raising anything run through sudo to at least high
+func (c *CommandClassifier) classifySegment(segment string) CommandRisk {
+	segment = envAssignmentPrefix.ReplaceAllString(segment, "")
+
+	if rest, ok := strings.CutPrefix(segment, "sudo "); ok {
+		risk := c.classifySegment(strings.TrimSpace(rest))
+		if risk.Level.severity() < RiskLevelHigh.severity() {
+			risk.Level = RiskLevelHigh
+		}
+		risk.Explanation = fmt.Sprintf("%s as root", risk.Explanation)
+		return risk
+	}
+
+	for _, r := range c.rules {
+		if r.pattern.MatchString(segment) {
+			return r.risk
+		}
+	}
+	return unknownCommandRisk
+}
+
+// UpdateCommandRisk records the risk of the hook's shell command, if it has one
+func (h *HookData) UpdateCommandRisk() {
+	if command := h.GetCommand(); command != "" {
+		risk := NewCommandClassifier().Classify(command)
+		h.CommandRisk = &risk
+	}
+}
@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// DangerScoreEscalationThreshold is the danger score at which a task's notification is escalated to urgent
+const DangerScoreEscalationThreshold = 0.5
+
+// DiffOperation identifies whether a diff line was added or removed
+type DiffOperation string
+
+const (
+	DiffOperationAdd    DiffOperation = "add"
+	DiffOperationDelete DiffOperation = "delete"
+)
+
+// DiffLine is a single added or removed line of a file edit
+type DiffLine struct {
+	Operation DiffOperation `json:"operation"`
+	Text      string        `json:"text"`
+}
+
+// FileDiff summarises the line changes made by a file edit and how risky they look
+type FileDiff struct {
+	Additions    int        `json:"additions"`
+	Deletions    int        `json:"deletions"`
+	ChangedLines []DiffLine `json:"changed_lines"`
+	DangerScore  float64    `json:"danger_score"` // 0 (harmless) to 1 (almost certainly dangerous)
+}
+
+// dangerPattern is a heuristic for risky content, weighted by how alarming a match is
+type dangerPattern struct {
+	substring string
+	weight    float64
+}
+
+// addedLineDangerPatterns are checked against lines an edit adds
+var addedLineDangerPatterns = []dangerPattern{
+	{"chmod +x", 0.3},
+	{"chmod 7", 0.3},
+	{"chmod u+s", 0.6},
+	{"~/.ssh", 0.5},
+	{".ssh/authorized_keys", 0.7},
+	{"id_rsa", 0.5},
+	{"rm -rf", 0.4},
+	{"sudo ", 0.3},
+	{"| sh", 0.5},
+	{"| bash", 0.5},
+	{"eval(", 0.2},
+	{"/etc/passwd", 0.5},
+	{"/etc/sudoers", 0.7},
+	{"crontab", 0.3},
+}
+
+// sensitivePathDangerPatterns are checked against the path of the edited file
+var sensitivePathDangerPatterns = []dangerPattern{
+	{"/.ssh/", 0.7},
+	{"/.aws/", 0.6},
+	{"/.bashrc", 0.4},
+	{"/.zshrc", 0.4},
+	{"/.profile", 0.4},
+	{"/etc/", 0.5},
+	{".env", 0.3},
+}
+
+// ComputeFileDiff computes the line-level changes between two versions of a file
+func ComputeFileDiff(oldContent, newContent string) FileDiff {
+	dmp := diffmatchpatch.New()
+	oldChars, newChars, lines := dmp.DiffLinesToChars(oldContent, newContent)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(oldChars, newChars, false), lines)
+
+	fileDiff := FileDiff{ChangedLines: []DiffLine{}}
+	for _, diff := range diffs {
+		var operation DiffOperation
+		switch diff.Type {
+		case diffmatchpatch.DiffInsert:
+			operation = DiffOperationAdd
+		case diffmatchpatch.DiffDelete:
+			operation = DiffOperationDelete
+		default:
+			continue
+		}
+
+		for _, line := range splitDiffLines(diff.Text) {
+			fileDiff.ChangedLines = append(fileDiff.ChangedLines, DiffLine{Operation: operation, Text: line})
+			if operation == DiffOperationAdd {
+				fileDiff.Additions++
+			} else {
+				fileDiff.Deletions++
+			}
+		}
+	}
+
+	fileDiff.DangerScore = scoreAddedLines(fileDiff.ChangedLines)
+	return fileDiff
+}
+
+// splitDiffLines splits a diff chunk into lines, dropping the empty element after a trailing newline
+func splitDiffLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// scoreAddedLines sums the weights of every danger pattern found in the added lines, capped at 1
+// Each pattern counts once no matter how many lines match it.
+func scoreAddedLines(lines []DiffLine) float64 {
+	score := 0.0
+	for _, pattern := range addedLineDangerPatterns {
+		for _, line := range lines {
+			if line.Operation == DiffOperationAdd && strings.Contains(line.Text, pattern.substring) {
+				score += pattern.weight
+				break
+			}
+		}
+	}
+	return capDangerScore(score)
+}
+
+// scoreFilePath returns the danger weight of editing a file at this path
+func scoreFilePath(filePath string) float64 {
+	score := 0.0
+	for _, pattern := range sensitivePathDangerPatterns {
+		if strings.Contains(filePath, pattern.substring) {
+			score += pattern.weight
+		}
+	}
+	return capDangerScore(score)
+}
+
+// capDangerScore limits a danger score to 1
+func capDangerScore(score float64) float64 {
+	if score > 1 {
+		return 1
+	}
+	return score
+}
+
+// FileDiff computes the diff for an Edit tool call, or returns nil if the tool input isn't a file edit
+// Editing a sensitive path such as ~/.ssh raises the danger score on top of the content heuristics.
+func (t *ToolInput) FileDiff() *FileDiff {
+	if t == nil || (t.OldString == "" && t.NewString == "") {
+		return nil
+	}
+
+	fileDiff := ComputeFileDiff(t.OldString, t.NewString)
+	fileDiff.DangerScore = capDangerScore(fileDiff.DangerScore + scoreFilePath(t.FilePath))
+	return &fileDiff
+}
+
+// UpdateDangerScore records the danger score of the hook's file edit, if it has one
+func (h *HookData) UpdateDangerScore() {
+	if fileDiff := h.FileDiff(); fileDiff != nil {
+		h.DangerScore = fileDiff.DangerScore
+	}
+}
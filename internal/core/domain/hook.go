@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 )
@@ -33,6 +34,14 @@ const (
 	HookTypePreCompact HookType = "PreCompact"
 )
 
+// AllHookTypes returns every hook type Claude Code can send, in the order its docs list them
+func AllHookTypes() []HookType {
+	return []HookType{
+		HookTypePreToolUse, HookTypePostToolUse, HookTypeNotification,
+		HookTypeUserPromptSubmit, HookTypeStop, HookTypeSubagentStop, HookTypePreCompact,
+	}
+}
+
 func (h HookType) String() string {
 	return string(h)
 }
@@ -47,6 +56,57 @@ func (h HookType) IsValid() bool {
 	}
 }
 
+// ToNotificationPriority returns how urgently the user should be notified about this hook type
+func (h HookType) ToNotificationPriority() NotificationPriority {
+	switch h {
+	case HookTypePreToolUse, HookTypeNotification:
+		return PriorityHigh
+	case HookTypePostToolUse, HookTypeStop, HookTypeSubagentStop:
+		return PriorityLow
+	default:
+		return PriorityNormal
+	}
+}
+
+// ToNotificationTags returns the notification tags for this hook type
+func (h HookType) ToNotificationTags() []string {
+	tags := []string{"claude-code"}
+	switch h {
+	case HookTypePreToolUse:
+		tags = append(tags, "tool-approval")
+	case HookTypeNotification:
+		tags = append(tags, "attention")
+	case HookTypeUserPromptSubmit:
+		tags = append(tags, "prompt")
+	case HookTypePostToolUse:
+		tags = append(tags, "completed")
+	case HookTypeStop:
+		tags = append(tags, "finished")
+	case HookTypeSubagentStop:
+		tags = append(tags, "subagent")
+	case HookTypePreCompact:
+		tags = append(tags, "compact")
+	}
+	return tags
+}
+
+// IsBlocking returns true if Claude Code waits for a user decision before continuing by default
+// The server can be configured to block on other hook types; see DefaultBlockingHookTypes.
+func (h HookType) IsBlocking() bool {
+	return h == HookTypePreToolUse || h == HookTypeUserPromptSubmit
+}
+
+// DefaultBlockingHookTypes returns the hook types whose webhooks wait for a user decision unless configured otherwise
+func DefaultBlockingHookTypes() []HookType {
+	var blocking []HookType
+	for _, hookType := range AllHookTypes() {
+		if hookType.IsBlocking() {
+			blocking = append(blocking, hookType)
+		}
+	}
+	return blocking
+}
+
 func ParseHookType(s string) (HookType, error) {
 	hookType := HookType(strings.TrimSpace(s))
 	if !hookType.IsValid() {
@@ -59,6 +119,15 @@ func ParseHookType(s string) (HookType, error) {
 type ToolInput struct {
 	Command     string `json:"command,omitempty"`
 	Description string `json:"description,omitempty"`
+
+	// Edit tool parameters
+	FilePath   string `json:"file_path,omitempty"`
+	OldString  string `json:"old_string,omitempty"`
+	NewString  string `json:"new_string,omitempty"`
+	ReplaceAll bool   `json:"replace_all,omitempty"`
+
+	// Write tool parameters; FilePath names the file
+	Content string `json:"content,omitempty"`
 }
 
 // ToolResponse represents tool execution results from Claude Code
@@ -123,10 +192,195 @@ type PreCompactHookData struct {
 	BaseHookData
 	Trigger            string `json:"trigger,omitempty"` // "manual" or "auto"
 	CustomInstructions string `json:"custom_instructions,omitempty"`
+
+	// TranscriptLines is a rough estimate of the context size: the transcript's line count when the hook
+	// arrived. It's filled in by the server when transcript reading is enabled, never sent by Claude Code.
+	TranscriptLines int `json:"transcript_lines,omitempty"`
 }
 
 // HookData represents the unified hook data structure
 type HookData struct {
 	Type HookType    `json:"type"`
 	Data interface{} `json:"data"`
+
+	// DangerScore rates how risky a file edit looks (0-1); set when the task is created
+	DangerScore float64 `json:"danger_score,omitempty"`
+
+	// CommandRisk classifies the shell command of a Bash tool call; set when the task is created
+	CommandRisk *CommandRisk `json:"command_risk,omitempty"`
+}
+
+// FileDiff returns the diff for a hook about an Edit tool call, or nil for any other hook
+func (h *HookData) FileDiff() *FileDiff {
+	switch data := h.Data.(type) {
+	case *PreToolUseHookData:
+		return data.ToolInput.FileDiff()
+	case *PostToolUseHookData:
+		return data.ToolInput.FileDiff()
+	}
+	return nil
+}
+
+// GetCWD returns the working directory Claude Code was running in when the hook fired
+func (h *HookData) GetCWD() string {
+	switch data := h.Data.(type) {
+	case *PreToolUseHookData:
+		return data.CWD
+	case *PostToolUseHookData:
+		return data.CWD
+	case *NotificationHookData:
+		return data.CWD
+	case *UserPromptSubmitHookData:
+		return data.CWD
+	case *StopHookData:
+		return data.CWD
+	case *SubagentStopHookData:
+		return data.CWD
+	case *PreCompactHookData:
+		return data.CWD
+	case map[string]interface{}:
+		cwd, _ := data["cwd"].(string)
+		return cwd
+	}
+	return ""
+}
+
+// GetSessionID returns the ID of the Claude Code session that fired the hook
+func (h *HookData) GetSessionID() string {
+	if h == nil {
+		return ""
+	}
+	switch data := h.Data.(type) {
+	case *PreToolUseHookData:
+		return data.SessionID
+	case *PostToolUseHookData:
+		return data.SessionID
+	case *NotificationHookData:
+		return data.SessionID
+	case *UserPromptSubmitHookData:
+		return data.SessionID
+	case *StopHookData:
+		return data.SessionID
+	case *SubagentStopHookData:
+		return data.SessionID
+	case *PreCompactHookData:
+		return data.SessionID
+	case map[string]interface{}:
+		sessionID, _ := data["session_id"].(string)
+		return sessionID
+	}
+	return ""
+}
+
+// GetToolName returns the tool a PreToolUse or PostToolUse hook is about, or "" for any other hook
+func (h *HookData) GetToolName() string {
+	if h == nil {
+		return ""
+	}
+	switch data := h.Data.(type) {
+	case *PreToolUseHookData:
+		return data.ToolName
+	case *PostToolUseHookData:
+		return data.ToolName
+	case map[string]interface{}:
+		toolName, _ := data["tool_name"].(string)
+		return toolName
+	}
+	return ""
+}
+
+// GetTranscriptPath returns the path of the session transcript Claude Code was writing when the hook fired
+func (h *HookData) GetTranscriptPath() string {
+	if h == nil {
+		return ""
+	}
+	switch data := h.Data.(type) {
+	case *PreToolUseHookData:
+		return data.TranscriptPath
+	case *PostToolUseHookData:
+		return data.TranscriptPath
+	case *NotificationHookData:
+		return data.TranscriptPath
+	case *UserPromptSubmitHookData:
+		return data.TranscriptPath
+	case *StopHookData:
+		return data.TranscriptPath
+	case *SubagentStopHookData:
+		return data.TranscriptPath
+	case *PreCompactHookData:
+		return data.TranscriptPath
+	case map[string]interface{}:
+		transcriptPath, _ := data["transcript_path"].(string)
+		return transcriptPath
+	}
+	return ""
+}
+
+// GetCommand returns the shell command of a tool call Claude Code is about to run, or "" for any other hook
+func (h *HookData) GetCommand() string {
+	if h == nil {
+		return ""
+	}
+	if data, ok := h.Data.(*PreToolUseHookData); ok && data.ToolInput != nil {
+		return data.ToolInput.Command
+	}
+	return ""
+}
+
+// UnmarshalJSON decodes hook data, restoring the concrete data struct named by the type discriminator
+// Data that does not fit the typed struct (e.g. rows written before a field changed type) is kept as a generic map.
+func (h *HookData) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type        HookType        `json:"type"`
+		Data        json.RawMessage `json:"data"`
+		DangerScore float64         `json:"danger_score"`
+		CommandRisk *CommandRisk    `json:"command_risk"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	h.Type = raw.Type
+	h.DangerScore = raw.DangerScore
+	h.CommandRisk = raw.CommandRisk
+	h.Data = nil
+	if len(raw.Data) == 0 || string(raw.Data) == "null" {
+		return nil
+	}
+
+	if typed := newHookDataStruct(raw.Type); typed != nil {
+		if err := json.Unmarshal(raw.Data, typed); err == nil {
+			h.Data = typed
+			return nil
+		}
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(raw.Data, &generic); err != nil {
+		return fmt.Errorf("failed to decode %s hook data: %w", raw.Type, err)
+	}
+	h.Data = generic
+	return nil
+}
+
+// newHookDataStruct returns an empty typed data struct for the hook type, or nil if the type is unknown
+func newHookDataStruct(hookType HookType) interface{} {
+	switch hookType {
+	case HookTypePreToolUse:
+		return &PreToolUseHookData{}
+	case HookTypePostToolUse:
+		return &PostToolUseHookData{}
+	case HookTypeNotification:
+		return &NotificationHookData{}
+	case HookTypeUserPromptSubmit:
+		return &UserPromptSubmitHookData{}
+	case HookTypeStop:
+		return &StopHookData{}
+	case HookTypeSubagentStop:
+		return &SubagentStopHookData{}
+	case HookTypePreCompact:
+		return &PreCompactHookData{}
+	default:
+		return nil
+	}
 }
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FieldDiff describes a single field that differs between two hook events
+type FieldDiff struct {
+	Field  string      `json:"field"`  // JSON path of the field, e.g. "tool_input.command"
+	Before interface{} `json:"before"` // nil when the field is absent from the earlier event
+	After  interface{} `json:"after"`  // nil when the field is absent from the later event
+}
+
+// DiffHookData compares the exported fields of two hook events and returns the fields that changed
+// Fields are named by their JSON path so the result lines up with the webhook payloads.
+// Events of different hook types can be compared; fields present on only one side diff against nil.
+func DiffHookData(before, after *HookData) ([]FieldDiff, error) {
+	if before == nil || after == nil {
+		return nil, errors.New("cannot diff nil hook data")
+	}
+
+	beforeFields, beforeOrder, err := flattenHookData(before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read earlier hook data: %w", err)
+	}
+
+	afterFields, afterOrder, err := flattenHookData(after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read later hook data: %w", err)
+	}
+
+	diffs := []FieldDiff{}
+	if before.Type != after.Type {
+		diffs = append(diffs, FieldDiff{Field: "type", Before: before.Type, After: after.Type})
+	}
+
+	// Keep the earlier event's field order, then any fields only the later event has
+	seen := make(map[string]bool, len(beforeOrder))
+	fields := make([]string, 0, len(beforeOrder)+len(afterOrder))
+	for _, field := range append(beforeOrder, afterOrder...) {
+		if !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+
+	for _, field := range fields {
+		beforeValue, afterValue := beforeFields[field], afterFields[field]
+		if reflect.DeepEqual(beforeValue, afterValue) {
+			continue
+		}
+		diffs = append(diffs, FieldDiff{Field: field, Before: beforeValue, After: afterValue})
+	}
+
+	return diffs, nil
+}
+
+// flattenHookData flattens the hook-specific data into JSON paths and their values
+func flattenHookData(hookData *HookData) (map[string]interface{}, []string, error) {
+	fields := make(map[string]interface{})
+	order := []string{}
+
+	if hookData.Data == nil {
+		return fields, order, nil
+	}
+
+	value := reflect.ValueOf(hookData.Data)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return fields, order, nil
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct && value.Kind() != reflect.Map {
+		return nil, nil, fmt.Errorf("unsupported hook data type %T", hookData.Data)
+	}
+
+	flattenValue("", value, fields, &order)
+	return fields, order, nil
+}
+
+// flattenValue records leaf values under their JSON path, descending into structs and maps
+func flattenValue(path string, value reflect.Value, fields map[string]interface{}, order *[]string) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		valueType := value.Type()
+		for i := 0; i < valueType.NumField(); i++ {
+			field := valueType.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			// Embedded structs such as BaseHookData share their parent's path
+			if field.Anonymous {
+				flattenValue(path, value.Field(i), fields, order)
+				continue
+			}
+
+			name := jsonFieldName(field)
+			if name == "-" {
+				continue
+			}
+			flattenValue(joinFieldPath(path, name), value.Field(i), fields, order)
+		}
+
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			recordField(path, value.Interface(), fields, order)
+			return
+		}
+
+		keys := make([]string, 0, value.Len())
+		for _, key := range value.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			flattenValue(joinFieldPath(path, key), value.MapIndex(reflect.ValueOf(key).Convert(value.Type().Key())), fields, order)
+		}
+
+	default:
+		recordField(path, value.Interface(), fields, order)
+	}
+}
+
+// recordField stores a leaf value, remembering the order fields were first seen in
+func recordField(path string, value interface{}, fields map[string]interface{}, order *[]string) {
+	if _, exists := fields[path]; !exists {
+		*order = append(*order, path)
+	}
+	fields[path] = value
+}
+
+// jsonFieldName returns the JSON name of a struct field, falling back to the Go name
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// joinFieldPath appends a field name to a dotted JSON path
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+)
+
+// sampleHookData builds a representative event for each hook type; variants differ in their values
+func sampleHookData(hookType HookType, variant int) *HookData {
+	base := BaseHookData{
+		HookEventName:  hookType.String(),
+		SessionID:      "c3e0f54b-0df7-4aa2-8179-1ee1b8c17147",
+		CWD:            fmt.Sprintf("/Users/dan/Software/haiper-%d", variant),
+		TranscriptPath: "/Users/dan/.claude/projects/test.jsonl",
+	}
+
+	var data interface{}
+	switch hookType {
+	case HookTypePreToolUse:
+		data = &PreToolUseHookData{
+			BaseHookData: base,
+			ToolName:     "Bash",
+			ToolInput:    &ToolInput{Command: fmt.Sprintf("ls -la /tmp/%d", variant), Description: "List files"},
+		}
+	case HookTypePostToolUse:
+		data = &PostToolUseHookData{
+			BaseHookData: base,
+			ToolName:     "Bash",
+			ToolInput:    &ToolInput{Command: "make status", Description: "Check docker status"},
+			ToolResponse: &ToolResponse{Stdout: fmt.Sprintf("Container running %d", variant), Success: variant == 0},
+		}
+	case HookTypeNotification:
+		data = &NotificationHookData{BaseHookData: base, Message: fmt.Sprintf("Claude needs attention (%d)", variant)}
+	case HookTypeUserPromptSubmit:
+		data = &UserPromptSubmitHookData{BaseHookData: base, UserPrompt: fmt.Sprintf("Fix the tests, attempt %d", variant)}
+	case HookTypeStop:
+		data = &StopHookData{BaseHookData: base, StopHookActive: variant == 1}
+	case HookTypeSubagentStop:
+		data = &SubagentStopHookData{BaseHookData: base, StopHookActive: variant == 1, SubagentID: fmt.Sprintf("subagent-%d", variant)}
+	case HookTypePreCompact:
+		trigger := "manual"
+		if variant == 1 {
+			trigger = "auto"
+		}
+		data = &PreCompactHookData{BaseHookData: base, Trigger: trigger}
+	}
+
+	return &HookData{Type: hookType, Data: data}
+}
+
+// findDiff returns the diff for field, or nil if the field didn't change
+func findDiff(diffs []FieldDiff, field string) *FieldDiff {
+	for i := range diffs {
+		if diffs[i].Field == field {
+			return &diffs[i]
+		}
+	}
+	return nil
+}
+
+func TestDiffHookData(t *testing.T) {
+	tests := []struct {
+		name       string
+		beforeType HookType
+		afterType  HookType
+		expected   []FieldDiff
+		unchanged  []string
+	}{
+		{
+			name:       "Retried command",
+			beforeType: HookTypePreToolUse,
+			afterType:  HookTypePreToolUse,
+			expected: []FieldDiff{
+				{Field: "cwd", Before: "/Users/dan/Software/haiper-0", After: "/Users/dan/Software/haiper-1"},
+				{Field: "tool_input.command", Before: "ls -la /tmp/0", After: "ls -la /tmp/1"},
+			},
+			unchanged: []string{"type", "session_id", "tool_name", "tool_input.description"},
+		},
+		{
+			name:       "Tool result",
+			beforeType: HookTypePostToolUse,
+			afterType:  HookTypePostToolUse,
+			expected: []FieldDiff{
+				{Field: "tool_response.stdout", Before: "Container running 0", After: "Container running 1"},
+				{Field: "tool_response.success", Before: true, After: false},
+			},
+			unchanged: []string{"tool_input.command"},
+		},
+		{
+			name:       "Subagent",
+			beforeType: HookTypeSubagentStop,
+			afterType:  HookTypeSubagentStop,
+			expected: []FieldDiff{
+				{Field: "stop_hook_active", Before: false, After: true},
+				{Field: "subagent_id", Before: "subagent-0", After: "subagent-1"},
+			},
+		},
+		{
+			name:       "Compaction trigger",
+			beforeType: HookTypePreCompact,
+			afterType:  HookTypePreCompact,
+			expected:   []FieldDiff{{Field: "trigger", Before: "manual", After: "auto"}},
+		},
+		{
+			name:       "Different hook types",
+			beforeType: HookTypePreToolUse,
+			afterType:  HookTypeStop,
+			expected: []FieldDiff{
+				{Field: "type", Before: HookTypePreToolUse, After: HookTypeStop},
+				{Field: "hook_event_name", Before: "PreToolUse", After: "Stop"},
+				{Field: "tool_name", Before: "Bash", After: nil},
+				{Field: "stop_hook_active", Before: nil, After: true},
+			},
+			unchanged: []string{"session_id", "transcript_path"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffs, err := DiffHookData(sampleHookData(tt.beforeType, 0), sampleHookData(tt.afterType, 1))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			for _, expected := range tt.expected {
+				diff := findDiff(diffs, expected.Field)
+				if diff == nil {
+					t.Errorf("Expected a diff for %s, got %v", expected.Field, diffs)
+					continue
+				}
+				if diff.Before != expected.Before || diff.After != expected.After {
+					t.Errorf("Expected %s to change from %v to %v, got %v to %v", expected.Field, expected.Before, expected.After, diff.Before, diff.After)
+				}
+			}
+			for _, field := range tt.unchanged {
+				if diff := findDiff(diffs, field); diff != nil {
+					t.Errorf("Expected no diff for %s, got %v", field, *diff)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffHookData_IdenticalEvents(t *testing.T) {
+	diffs, err := DiffHookData(sampleHookData(HookTypePreToolUse, 0), sampleHookData(HookTypePreToolUse, 0))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("Expected no diffs for identical events, got %v", diffs)
+	}
+}
+
+func TestDiffHookData_RawMapData(t *testing.T) {
+	before := &HookData{Type: HookTypePreToolUse, Data: map[string]interface{}{
+		"tool_name":  "Edit",
+		"tool_input": map[string]interface{}{"file_path": "main.go"},
+	}}
+	after := &HookData{Type: HookTypePreToolUse, Data: map[string]interface{}{
+		"tool_name":  "Edit",
+		"tool_input": map[string]interface{}{"file_path": "main_test.go"},
+	}}
+
+	diffs, err := DiffHookData(before, after)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diffs) != 1 || diffs[0].Field != "tool_input.file_path" {
+		t.Fatalf("Expected a single tool_input.file_path diff, got %v", diffs)
+	}
+	if diffs[0].Before != "main.go" || diffs[0].After != "main_test.go" {
+		t.Errorf("Unexpected diff values: %v", diffs[0])
+	}
+}
+
+func TestDiffHookData_Errors(t *testing.T) {
+	if _, err := DiffHookData(nil, sampleHookData(HookTypeStop, 0)); err == nil {
+		t.Error("Expected error for nil hook data")
+	}
+
+	unsupported := &HookData{Type: HookTypeStop, Data: "not a struct"}
+	if _, err := DiffHookData(unsupported, sampleHookData(HookTypeStop, 0)); err == nil {
+		t.Error("Expected error for unsupported hook data type")
+	}
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -20,12 +22,23 @@ type HookResponse struct {
 	// true = hide output, false = show output (default)
 	SuppressOutput bool `json:"suppressOutput,omitempty"`
 
+	// ModifiedCommand replaces the tool command Claude Code is about to run (PreToolUse only)
+	// Experimental: the Claude Code hook spec does not guarantee this field is honoured
+	ModifiedCommand string `json:"modified_command,omitempty"`
+
+	// ReceiptToken identifies this response in POST /webhook/receipt once Claude Code has processed it
+	// Versions that don't send receipts ignore the field
+	ReceiptToken string `json:"_receipt,omitempty"`
+
 	// Metadata for internal tracking
 	TaskID    string    `json:"-"` // Internal - not sent to Claude Code
 	Decision  ActionType `json:"-"` // Internal - tracks user decision
 	CreatedAt time.Time `json:"-"` // Internal - when response was created
 }
 
+// MaxModifiedCommandLength is the longest command a user may substitute when approving a tool call
+const MaxModifiedCommandLength = 5000
+
 // HookResponseType represents different types of hook responses
 type HookResponseType string
 
@@ -61,6 +74,17 @@ func NewApprovedResponse(taskID string) *HookResponse {
 	}
 }
 
+// NewModifiedCommandResponse creates an approval that tells Claude Code to run modifiedCommand instead
+func NewModifiedCommandResponse(taskID, modifiedCommand string) *HookResponse {
+	return &HookResponse{
+		Continue:        true,
+		ModifiedCommand: modifiedCommand,
+		TaskID:          taskID,
+		Decision:        ActionTypeApprove,
+		CreatedAt:       time.Now(),
+	}
+}
+
 // NewRejectedResponse creates a response that blocks Claude Code with user rejection
 func NewRejectedResponse(taskID, reason string) *HookResponse {
 	return &HookResponse{
@@ -99,6 +123,29 @@ func NewSuppressedResponse() *HookResponse {
 	}
 }
 
+// ValidateModifiedCommand checks that a user-substituted command can be returned to Claude Code
+func ValidateModifiedCommand(command string) error {
+	if len(command) > MaxModifiedCommandLength {
+		return fmt.Errorf("modified command is %d characters, maximum is %d", len(command), MaxModifiedCommandLength)
+	}
+	if strings.ContainsRune(command, 0) {
+		return fmt.Errorf("modified command must not contain null bytes")
+	}
+	return nil
+}
+
+// Validate checks that the response is consistent before it is returned to Claude Code
+// A substituted command only makes sense when the tool call goes ahead.
+func (hr *HookResponse) Validate() error {
+	if hr.ModifiedCommand == "" {
+		return nil
+	}
+	if !hr.Continue {
+		return fmt.Errorf("modified command must be empty when continue is false")
+	}
+	return ValidateModifiedCommand(hr.ModifiedCommand)
+}
+
 // ToJSON converts the hook response to JSON bytes for Claude Code
 func (hr *HookResponse) ToJSON() ([]byte, error) {
 	return json.Marshal(hr)
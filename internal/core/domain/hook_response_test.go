@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestHookResponse_ModifiedCommandJSON(t *testing.T) {
+	tests := []struct {
+		name            string
+		modifiedCommand string
+		wantField       bool
+	}{
+		{name: "set", modifiedCommand: "ls -la /tmp", wantField: true},
+		{name: "empty", modifiedCommand: "", wantField: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &HookResponse{Decision: ActionTypeApprove, ModifiedCommand: tt.modifiedCommand}
+
+			data, err := json.Marshal(response)
+			if err != nil {
+				t.Fatalf("Failed to marshal response: %v", err)
+			}
+
+			var fields map[string]interface{}
+			if err := json.Unmarshal(data, &fields); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+
+			value, ok := fields["modified_command"]
+			if ok != tt.wantField {
+				t.Fatalf("Expected modified_command present=%v, got %s", tt.wantField, data)
+			}
+			if ok && value != tt.modifiedCommand {
+				t.Errorf("Expected modified_command %q, got %v", tt.modifiedCommand, value)
+			}
+		})
+	}
+}
+
+func TestValidateModifiedCommand(t *testing.T) {
+	if err := ValidateModifiedCommand("echo hello"); err != nil {
+		t.Errorf("Unexpected error for short command: %v", err)
+	}
+	if err := ValidateModifiedCommand(strings.Repeat("a", MaxModifiedCommandLength)); err != nil {
+		t.Errorf("Unexpected error for command at the limit: %v", err)
+	}
+	if err := ValidateModifiedCommand(strings.Repeat("a", MaxModifiedCommandLength+1)); err == nil {
+		t.Error("Expected error for command over the limit")
+	}
+	if err := ValidateModifiedCommand("rm -rf build\x00; echo done"); err == nil {
+		t.Error("Expected error for command containing a null byte")
+	}
+}
+
+func TestNewModifiedCommandResponse(t *testing.T) {
+	response := NewModifiedCommandResponse("task-123", "ls -la /tmp")
+
+	if !response.Continue {
+		t.Error("Expected a modified command response to continue")
+	}
+	if response.ModifiedCommand != "ls -la /tmp" {
+		t.Errorf("Expected modified command %q, got %q", "ls -la /tmp", response.ModifiedCommand)
+	}
+	if response.GetResponseType() != HookResponseApproved {
+		t.Errorf("Expected response type %s, got %s", HookResponseApproved, response.GetResponseType())
+	}
+	if err := response.Validate(); err != nil {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+}
+
+func TestHookResponse_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *HookResponse
+		wantErr  bool
+	}{
+		{name: "continue without command", response: &HookResponse{Continue: true}, wantErr: false},
+		{name: "block without command", response: &HookResponse{Continue: false, StopReason: "rejected"}, wantErr: false},
+		{name: "continue with command", response: &HookResponse{Continue: true, ModifiedCommand: "ls"}, wantErr: false},
+		{name: "block with command", response: &HookResponse{Continue: false, ModifiedCommand: "ls"}, wantErr: true},
+		{name: "command over the limit", response: &HookResponse{Continue: true, ModifiedCommand: strings.Repeat("a", MaxModifiedCommandLength+1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.response.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHookResponse_ReceiptJSON(t *testing.T) {
+	withReceipt, err := json.Marshal(&HookResponse{Continue: true, ReceiptToken: "6f1c9a52"})
+	if err != nil {
+		t.Fatalf("Failed to marshal response: %v", err)
+	}
+	if !strings.Contains(string(withReceipt), `"_receipt":"6f1c9a52"`) {
+		t.Errorf("Expected _receipt in %s", withReceipt)
+	}
+
+	withoutReceipt, err := json.Marshal(&HookResponse{Continue: true})
+	if err != nil {
+		t.Fatalf("Failed to marshal response: %v", err)
+	}
+	if strings.Contains(string(withoutReceipt), "_receipt") {
+		t.Errorf("Expected no _receipt field in %s", withoutReceipt)
+	}
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"strings"
+)
+
+// summaryTextLimit is how many characters of free text (messages, prompts, commands) a summary keeps
+const summaryTextLimit = 80
+
+// Summary returns a concise single-line description of the hook, e.g. "[Bash] ls -la /home/user"
+func (h *HookData) Summary() string {
+	if h == nil {
+		return ""
+	}
+
+	switch data := h.Data.(type) {
+	case *PreToolUseHookData:
+		return summarizeToolCall(data.ToolName, data.ToolInput)
+	case *PostToolUseHookData:
+		return "Completed " + summarizeToolCall(data.ToolName, data.ToolInput)
+	case *NotificationHookData:
+		return "Notification: " + truncateSummaryText(data.Message)
+	case *UserPromptSubmitHookData:
+		return "Prompt: " + truncateSummaryText(data.UserPrompt)
+	case *StopHookData:
+		return "Session stopped"
+	case *SubagentStopHookData:
+		if data.SubagentID != "" {
+			return "Subagent " + data.SubagentID + " stopped"
+		}
+		return "Subagent stopped"
+	case *PreCompactHookData:
+		if data.Trigger != "" {
+			return "Compacting context (" + data.Trigger + ")"
+		}
+		return "Compacting context"
+	}
+
+	// Untyped data from rows that no longer fit their struct
+	return h.Type.String() + " event"
+}
+
+// Summary returns a concise single-line description of the task's hook, for log lines and notifications
+func (t *Task) Summary() string {
+	return t.HookData.Summary()
+}
+
+// summarizeToolCall describes a tool call by its most telling input: the command, file or description
+func summarizeToolCall(toolName string, toolInput *ToolInput) string {
+	summary := "[" + toolName + "]"
+	if toolInput == nil {
+		return summary
+	}
+
+	switch {
+	case toolInput.Command != "":
+		return summary + " " + truncateSummaryText(toolInput.Command)
+	case toolInput.FilePath != "":
+		return summary + " " + toolInput.FilePath
+	case toolInput.Description != "":
+		return summary + " " + truncateSummaryText(toolInput.Description)
+	}
+	return summary
+}
+
+// truncateSummaryText collapses text onto one line and cuts it to summaryTextLimit characters
+func truncateSummaryText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+
+	runes := []rune(text)
+	if len(runes) <= summaryTextLimit {
+		return text
+	}
+	return string(runes[:summaryTextLimit]) + "…"
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHookData_Summary(t *testing.T) {
+	longMessage := strings.Repeat("a", 100)
+
+	tests := []struct {
+		name     string
+		hookData *HookData
+		expected string
+	}{
+		{
+			name:     "PreToolUse Bash",
+			hookData: &HookData{Type: HookTypePreToolUse, Data: &PreToolUseHookData{ToolName: "Bash", ToolInput: &ToolInput{Command: "ls -la /home/user"}}},
+			expected: "[Bash] ls -la /home/user",
+		},
+		{
+			name:     "PreToolUse Edit",
+			hookData: &HookData{Type: HookTypePreToolUse, Data: &PreToolUseHookData{ToolName: "Edit", ToolInput: &ToolInput{FilePath: "/srv/haiper/main.go", OldString: "a", NewString: "b"}}},
+			expected: "[Edit] /srv/haiper/main.go",
+		},
+		{
+			name:     "PreToolUse without input",
+			hookData: &HookData{Type: HookTypePreToolUse, Data: &PreToolUseHookData{ToolName: "TodoWrite"}},
+			expected: "[TodoWrite]",
+		},
+		{
+			name:     "PreToolUse multi-line command",
+			hookData: &HookData{Type: HookTypePreToolUse, Data: &PreToolUseHookData{ToolName: "Bash", ToolInput: &ToolInput{Command: "cd /tmp &&\n  make test"}}},
+			expected: "[Bash] cd /tmp && make test",
+		},
+		{
+			name:     "PostToolUse",
+			hookData: &HookData{Type: HookTypePostToolUse, Data: &PostToolUseHookData{ToolName: "Bash", ToolInput: &ToolInput{Command: "go test ./..."}}},
+			expected: "Completed [Bash] go test ./...",
+		},
+		{
+			name:     "Notification",
+			hookData: &HookData{Type: HookTypeNotification, Data: &NotificationHookData{Message: "Claude needs your permission to use Bash"}},
+			expected: "Notification: Claude needs your permission to use Bash",
+		},
+		{
+			name:     "Notification truncated",
+			hookData: &HookData{Type: HookTypeNotification, Data: &NotificationHookData{Message: longMessage}},
+			expected: "Notification: " + strings.Repeat("a", 80) + "…",
+		},
+		{
+			name:     "UserPromptSubmit",
+			hookData: &HookData{Type: HookTypeUserPromptSubmit, Data: &UserPromptSubmitHookData{UserPrompt: "Fix the failing tests"}},
+			expected: "Prompt: Fix the failing tests",
+		},
+		{
+			name:     "Stop",
+			hookData: &HookData{Type: HookTypeStop, Data: &StopHookData{}},
+			expected: "Session stopped",
+		},
+		{
+			name:     "SubagentStop",
+			hookData: &HookData{Type: HookTypeSubagentStop, Data: &SubagentStopHookData{SubagentID: "agent-7"}},
+			expected: "Subagent agent-7 stopped",
+		},
+		{
+			name:     "PreCompact",
+			hookData: &HookData{Type: HookTypePreCompact, Data: &PreCompactHookData{Trigger: "auto"}},
+			expected: "Compacting context (auto)",
+		},
+		{
+			name:     "Untyped data",
+			hookData: &HookData{Type: HookTypeStop, Data: map[string]interface{}{"session_id": "abc"}},
+			expected: "Stop event",
+		},
+		{
+			name:     "Nil hook data",
+			hookData: nil,
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hookData.Summary(); got != tt.expected {
+				t.Errorf("Summary() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTask_Summary(t *testing.T) {
+	tests := []struct {
+		name     string
+		task     *Task
+		expected string
+	}{
+		{"PreToolUse", NewTask(sampleHookData(HookTypePreToolUse, 0)), "[Bash] ls -la /tmp/0"},
+		{"PostToolUse", NewTask(sampleHookData(HookTypePostToolUse, 0)), "Completed [Bash] make status"},
+		{"Notification", NewTask(sampleHookData(HookTypeNotification, 0)), "Notification: Claude needs attention (0)"},
+		{"UserPromptSubmit", NewTask(sampleHookData(HookTypeUserPromptSubmit, 0)), "Prompt: Fix the tests, attempt 0"},
+		{"Stop", NewTask(sampleHookData(HookTypeStop, 0)), "Session stopped"},
+		{"SubagentStop", NewTask(sampleHookData(HookTypeSubagentStop, 0)), "Subagent subagent-0 stopped"},
+		{"PreCompact", NewTask(sampleHookData(HookTypePreCompact, 0)), "Compacting context (manual)"},
+		{"No hook data", &Task{HookType: HookTypeStop}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.task.Summary(); got != tt.expected {
+				t.Errorf("Summary() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
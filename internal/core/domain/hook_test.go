@@ -0,0 +1,266 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestHookType_NotificationMapping(t *testing.T) {
+	tests := []struct {
+		hookType         HookType
+		expectedPriority NotificationPriority
+		expectedTags     []string
+		expectedBlocking bool
+	}{
+		{HookTypePreToolUse, PriorityHigh, []string{"claude-code", "tool-approval"}, true},
+		{HookTypePostToolUse, PriorityLow, []string{"claude-code", "completed"}, false},
+		{HookTypeNotification, PriorityHigh, []string{"claude-code", "attention"}, false},
+		{HookTypeUserPromptSubmit, PriorityNormal, []string{"claude-code", "prompt"}, true},
+		{HookTypeStop, PriorityLow, []string{"claude-code", "finished"}, false},
+		{HookTypeSubagentStop, PriorityLow, []string{"claude-code", "subagent"}, false},
+		{HookTypePreCompact, PriorityNormal, []string{"claude-code", "compact"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hookType.String(), func(t *testing.T) {
+			if got := tt.hookType.ToNotificationPriority(); got != tt.expectedPriority {
+				t.Errorf("ToNotificationPriority() = %s, expected %s", got, tt.expectedPriority)
+			}
+			if got := tt.hookType.ToNotificationTags(); !reflect.DeepEqual(got, tt.expectedTags) {
+				t.Errorf("ToNotificationTags() = %v, expected %v", got, tt.expectedTags)
+			}
+			if got := tt.hookType.IsBlocking(); got != tt.expectedBlocking {
+				t.Errorf("IsBlocking() = %v, expected %v", got, tt.expectedBlocking)
+			}
+		})
+	}
+}
+
+func TestHookType_NotificationMappingUnknown(t *testing.T) {
+	unknown := HookType("Unknown")
+
+	if got := unknown.ToNotificationPriority(); got != PriorityNormal {
+		t.Errorf("Expected normal priority for unknown hook type, got %s", got)
+	}
+	if got := unknown.ToNotificationTags(); !reflect.DeepEqual(got, []string{"claude-code"}) {
+		t.Errorf("Expected only the base tag for unknown hook type, got %v", got)
+	}
+	if unknown.IsBlocking() {
+		t.Error("Expected unknown hook type not to block")
+	}
+}
+
+func TestAllHookTypes_MatchesIsValid(t *testing.T) {
+	tests := []struct {
+		hookType HookType
+		valid    bool
+	}{
+		{HookTypePreToolUse, true},
+		{HookTypePostToolUse, true},
+		{HookTypeNotification, true},
+		{HookTypeUserPromptSubmit, true},
+		{HookTypeStop, true},
+		{HookTypeSubagentStop, true},
+		{HookTypePreCompact, true},
+		{"", false},
+		{"pretooluse", false},
+		{"pre-tool-use", false}, // Legacy spellings are resolved by the webhook handler, not IsValid
+		{"PreToolUse ", false},
+		{"Unknown", false},
+	}
+
+	listed := make(map[HookType]int)
+	for _, hookType := range AllHookTypes() {
+		listed[hookType]++
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.hookType), func(t *testing.T) {
+			if got := tt.hookType.IsValid(); got != tt.valid {
+				t.Errorf("IsValid() = %v, expected %v", got, tt.valid)
+			}
+			expected := 0
+			if tt.valid {
+				expected = 1
+			}
+			if listed[tt.hookType] != expected {
+				t.Errorf("AllHookTypes() lists %q %d times, expected %d", tt.hookType, listed[tt.hookType], expected)
+			}
+		})
+	}
+	if all := AllHookTypes(); len(all) != 7 {
+		t.Errorf("Expected AllHookTypes() to list the 7 hook types above, got %v", all)
+	}
+}
+
+func TestHookData_UnmarshalJSONConcreteTypes(t *testing.T) {
+	for _, hookType := range AllHookTypes() {
+		t.Run(hookType.String(), func(t *testing.T) {
+			original := sampleHookData(hookType, 1)
+			data, err := json.Marshal(original)
+			if err != nil {
+				t.Fatalf("Failed to marshal hook data: %v", err)
+			}
+
+			var decoded HookData
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Failed to unmarshal hook data: %v", err)
+			}
+
+			if !reflect.DeepEqual(&decoded, original) {
+				t.Errorf("Round trip mismatch\ngot:  %#v\nwant: %#v", decoded.Data, original.Data)
+			}
+		})
+	}
+}
+
+func TestHookData_UnmarshalJSONFallbacks(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{
+			name:     "unknown hook type keeps generic data",
+			input:    `{"type":"Custom","data":{"foo":"bar"}}`,
+			expected: map[string]interface{}{"foo": "bar"},
+		},
+		{
+			name:     "mismatched field keeps generic data",
+			input:    `{"type":"Stop","data":{"stop_hook_active":"yes"}}`,
+			expected: map[string]interface{}{"stop_hook_active": "yes"},
+		},
+		{
+			name:     "null data",
+			input:    `{"type":"Stop","data":null}`,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decoded HookData
+			if err := json.Unmarshal([]byte(tt.input), &decoded); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(decoded.Data, tt.expected) {
+				t.Errorf("Expected data %#v, got %#v", tt.expected, decoded.Data)
+			}
+		})
+	}
+}
+
+func FuzzHookDataRoundTrip(f *testing.F) {
+	hookTypes := []HookType{
+		HookTypePreToolUse, HookTypePostToolUse, HookTypeNotification, HookTypeUserPromptSubmit,
+		HookTypeStop, HookTypeSubagentStop, HookTypePreCompact,
+	}
+	for _, hookType := range hookTypes {
+		seed, err := json.Marshal(sampleHookData(hookType, 0))
+		if err != nil {
+			f.Fatalf("Failed to marshal seed: %v", err)
+		}
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		var first HookData
+		if err := json.Unmarshal(input, &first); err != nil {
+			return
+		}
+
+		encoded, err := json.Marshal(&first)
+		if err != nil {
+			t.Fatalf("Failed to marshal decoded hook data: %v", err)
+		}
+
+		var second HookData
+		if err := json.Unmarshal(encoded, &second); err != nil {
+			t.Fatalf("Failed to decode re-encoded hook data %s: %v", encoded, err)
+		}
+
+		reencoded, err := json.Marshal(&second)
+		if err != nil {
+			t.Fatalf("Failed to marshal hook data again: %v", err)
+		}
+		if !bytes.Equal(encoded, reencoded) {
+			t.Errorf("Encoding is not stable\nfirst:  %s\nsecond: %s", encoded, reencoded)
+		}
+	})
+}
+
+func TestHookData_GetCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		hookData *HookData
+		expected string
+	}{
+		{"PreToolUse Bash", &HookData{Type: HookTypePreToolUse, Data: &PreToolUseHookData{ToolName: "Bash", ToolInput: &ToolInput{Command: "make test"}}}, "make test"},
+		{"PreToolUse without input", &HookData{Type: HookTypePreToolUse, Data: &PreToolUseHookData{ToolName: "Read"}}, ""},
+		{"PostToolUse", &HookData{Type: HookTypePostToolUse, Data: &PostToolUseHookData{ToolName: "Bash", ToolInput: &ToolInput{Command: "make test"}}}, ""},
+		{"Untyped data", &HookData{Type: HookTypePreToolUse, Data: map[string]interface{}{"command": "make test"}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hookData.GetCommand(); got != tt.expected {
+				t.Errorf("GetCommand() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHookData_GetTranscriptPath(t *testing.T) {
+	base := BaseHookData{SessionID: "abc123", TranscriptPath: "/home/dev/.claude/projects/app/abc123.jsonl"}
+
+	tests := []struct {
+		name     string
+		hookData *HookData
+		expected string
+	}{
+		{"PreToolUse", &HookData{Type: HookTypePreToolUse, Data: &PreToolUseHookData{BaseHookData: base, ToolName: "Bash"}}, base.TranscriptPath},
+		{"Stop", &HookData{Type: HookTypeStop, Data: &StopHookData{BaseHookData: base}}, base.TranscriptPath},
+		{"Untyped data", &HookData{Type: HookTypeStop, Data: map[string]interface{}{"transcript_path": "/tmp/t.jsonl"}}, "/tmp/t.jsonl"},
+		{"No transcript", &HookData{Type: HookTypeNotification, Data: &NotificationHookData{}}, ""},
+		{"Nil hook data", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hookData.GetTranscriptPath(); got != tt.expected {
+				t.Errorf("GetTranscriptPath() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHookData_GetSessionIDAndToolName(t *testing.T) {
+	base := BaseHookData{SessionID: "abc123"}
+
+	tests := []struct {
+		name              string
+		hookData          *HookData
+		expectedSessionID string
+		expectedToolName  string
+	}{
+		{"PreToolUse", &HookData{Type: HookTypePreToolUse, Data: &PreToolUseHookData{BaseHookData: base, ToolName: "Bash"}}, "abc123", "Bash"},
+		{"PostToolUse", &HookData{Type: HookTypePostToolUse, Data: &PostToolUseHookData{BaseHookData: base, ToolName: "Edit"}}, "abc123", "Edit"},
+		{"Notification", &HookData{Type: HookTypeNotification, Data: &NotificationHookData{BaseHookData: base}}, "abc123", ""},
+		{"PreCompact", &HookData{Type: HookTypePreCompact, Data: &PreCompactHookData{BaseHookData: base}}, "abc123", ""},
+		{"Untyped data", &HookData{Type: HookTypePreToolUse, Data: map[string]interface{}{"session_id": "def456", "tool_name": "Read"}}, "def456", "Read"},
+		{"Nil hook data", nil, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hookData.GetSessionID(); got != tt.expectedSessionID {
+				t.Errorf("GetSessionID() = %q, expected %q", got, tt.expectedSessionID)
+			}
+			if got := tt.hookData.GetToolName(); got != tt.expectedToolName {
+				t.Errorf("GetToolName() = %q, expected %q", got, tt.expectedToolName)
+			}
+		})
+	}
+}
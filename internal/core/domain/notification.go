@@ -17,27 +17,107 @@ const (
 	PriorityUrgent NotificationPriority = "urgent"
 )
 
+// ToPagerDutyUrgency maps the priority to a PagerDuty incident urgency ("high" or "low")
+func (p NotificationPriority) ToPagerDutyUrgency() string {
+	switch p {
+	case PriorityHigh, PriorityUrgent:
+		return "high"
+	default:
+		return "low"
+	}
+}
+
+// ToSeverity maps the priority to a PagerDuty event severity
+func (p NotificationPriority) ToSeverity() string {
+	switch p {
+	case PriorityUrgent:
+		return "critical"
+	case PriorityHigh:
+		return "error"
+	case PriorityNormal:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// ToSlackColor maps the priority to a Slack attachment color
+func (p NotificationPriority) ToSlackColor() string {
+	switch p {
+	case PriorityUrgent:
+		return "danger"
+	case PriorityHigh:
+		return "warning"
+	default:
+		return "good"
+	}
+}
+
+// Discord embed colors, as the decimal RGB values Discord expects
+const (
+	DiscordColorRed    = 0xE74C3C
+	DiscordColorYellow = 0xF1C40F
+	DiscordColorBlue   = 0x3498DB
+	DiscordColorGray   = 0x95A5A6
+)
+
+// ToDiscordColor maps the priority to a Discord embed color: red, yellow, blue or gray from urgent to low
+func (p NotificationPriority) ToDiscordColor() int {
+	switch p {
+	case PriorityUrgent:
+		return DiscordColorRed
+	case PriorityHigh:
+		return DiscordColorYellow
+	case PriorityLow:
+		return DiscordColorGray
+	default:
+		return DiscordColorBlue
+	}
+}
+
+// ToPushoverPriority maps the priority to Pushover's -2 (silent) to 2 (emergency) scale
+// Low priority stays quiet rather than silent so it still shows up on the lock screen.
+func (p NotificationPriority) ToPushoverPriority() int {
+	switch p {
+	case PriorityUrgent:
+		return 2
+	case PriorityHigh:
+		return 1
+	case PriorityLow:
+		return -1
+	default:
+		return 0
+	}
+}
+
 // Notification represents a push notification to be sent to the user
 type Notification struct {
-	ID          uuid.UUID            `json:"id"`
-	TaskID      uuid.UUID            `json:"task_id"`
-	Title       string               `json:"title"`
-	Message     string               `json:"message"`
-	Priority    NotificationPriority `json:"priority"`
-	ActionURL   string               `json:"action_url"`   // URL to task management page
-	Tags        []string             `json:"tags"`
-	CreatedAt   time.Time            `json:"created_at"`
-	SentAt      *time.Time           `json:"sent_at,omitempty"`
-	DeliveredAt *time.Time           `json:"delivered_at,omitempty"`
+	ID              uuid.UUID            `json:"id"`
+	TaskID          uuid.UUID            `json:"task_id"`
+	Title           string               `json:"title"`
+	Message         string               `json:"message"`
+	Priority        NotificationPriority `json:"priority"`
+	ActionURL       string               `json:"action_url"`                  // URL to task management page
+	QuickApproveURL string               `json:"quick_approve_url,omitempty"` // Approves the task with a POST and no login; empty when quick actions are off
+	QuickRejectURL  string               `json:"quick_reject_url,omitempty"`  // Rejects the task the same way
+	Tags            []string             `json:"tags"`
+	SourceCWD       string               `json:"source_cwd,omitempty"` // Working directory of the Claude Code session
+	HookType        HookType             `json:"hook_type,omitempty"`  // Empty for alerts that aren't about one hook
+	SessionID       string               `json:"session_id,omitempty"` // Claude Code session the task belongs to, when known
+	CreatedAt       time.Time            `json:"created_at"`
+	SentAt          *time.Time           `json:"sent_at,omitempty"`
+	DeliveredAt     *time.Time           `json:"delivered_at,omitempty"`
 }
 
 // NewNotification creates a new notification for a task
-func NewNotification(taskID uuid.UUID, hookType HookType, webDomain string) *Notification {
+func NewNotification(taskID uuid.UUID, hookType HookType, webDomain string, sourceCWD string) *Notification {
 	notification := &Notification{
 		ID:        uuid.New(),
 		TaskID:    taskID,
-		Priority:  PriorityNormal,
-		Tags:      []string{"claude-code"},
+		Priority:  hookType.ToNotificationPriority(),
+		Tags:      hookType.ToNotificationTags(),
+		SourceCWD: sourceCWD,
+		HookType:  hookType,
 		CreatedAt: time.Now(),
 		ActionURL: fmt.Sprintf("http://%s/task/%s", webDomain, taskID.String()),
 	}
@@ -47,44 +127,30 @@ func NewNotification(taskID uuid.UUID, hookType HookType, webDomain string) *Not
 	case HookTypePreToolUse:
 		notification.Title = "🔧 Claude Code - Tool Approval"
 		notification.Message = "Claude needs permission to execute a tool"
-		notification.Priority = PriorityHigh
-		notification.Tags = append(notification.Tags, "tool-approval")
 		
 	case HookTypeNotification:
 		notification.Title = "⚠️ Claude Code - Attention Required"
 		notification.Message = "Claude Code needs your attention"
-		notification.Priority = PriorityHigh
-		notification.Tags = append(notification.Tags, "attention")
 		
 	case HookTypeUserPromptSubmit:
 		notification.Title = "📝 Claude Code - Prompt Validation"
 		notification.Message = "New prompt submitted for validation"
-		notification.Priority = PriorityNormal
-		notification.Tags = append(notification.Tags, "prompt")
 		
 	case HookTypePostToolUse:
 		notification.Title = "✅ Claude Code - Tool Completed"
 		notification.Message = "Tool execution completed"
-		notification.Priority = PriorityLow
-		notification.Tags = append(notification.Tags, "completed")
 		
 	case HookTypeStop:
 		notification.Title = "🏁 Claude Code - Session Complete"
 		notification.Message = "Claude Code session has finished"
-		notification.Priority = PriorityLow
-		notification.Tags = append(notification.Tags, "finished")
 		
 	case HookTypeSubagentStop:
 		notification.Title = "🤖 Claude Code - Subagent Complete"
 		notification.Message = "Claude Code subagent has finished"
-		notification.Priority = PriorityLow
-		notification.Tags = append(notification.Tags, "subagent")
 		
 	case HookTypePreCompact:
 		notification.Title = "🗜️ Claude Code - Compacting"
 		notification.Message = "Claude Code is compacting context"
-		notification.Priority = PriorityNormal
-		notification.Tags = append(notification.Tags, "compact")
 		
 	default:
 		notification.Title = "🔔 Claude Code - Event"
@@ -94,6 +160,64 @@ func NewNotification(taskID uuid.UUID, hookType HookType, webDomain string) *Not
 	return notification
 }
 
+// NewConcurrentSessionsNotification creates an urgent alert that too many sessions are waiting on the user at once
+// It isn't about a single task, so TaskID is left nil and the link goes to the dashboard.
+func NewConcurrentSessionsNotification(sessionCount int, webDomain string) *Notification {
+	return &Notification{
+		ID:        uuid.New(),
+		Title:     "🚦 Claude Code - Sessions Waiting",
+		Message:   fmt.Sprintf("⚠️ %d concurrent sessions need attention", sessionCount),
+		Priority:  PriorityUrgent,
+		Tags:      []string{"warning", "busy"},
+		CreatedAt: time.Now(),
+		ActionURL: fmt.Sprintf("http://%s/dashboard", webDomain),
+	}
+}
+
+// SetQuickActions adds approve and reject URLs that act on the task straight from the notification
+// Each URL carries a token signed with secret that expires after ttl, so it stops working once the
+// hook has stopped waiting. baseURL includes the scheme, so the token only travels over https when
+// the server has TLS. Notifications that aren't about a task are left without them.
+func (n *Notification) SetQuickActions(baseURL, secret string, ttl time.Duration) {
+	if secret == "" || n.TaskID == uuid.Nil {
+		return
+	}
+
+	n.QuickApproveURL = quickActionURL(baseURL, n.TaskID, ActionTypeApprove, secret, ttl)
+	n.QuickRejectURL = quickActionURL(baseURL, n.TaskID, ActionTypeReject, secret, ttl)
+}
+
+// quickActionURL returns the URL that takes action on a task with a freshly signed token
+func quickActionURL(baseURL string, taskID uuid.UUID, action ActionType, secret string, ttl time.Duration) string {
+	return fmt.Sprintf("%s/api/tasks/%s/action?action=%s&token=%s",
+		baseURL, taskID, action, GenerateApprovalToken(taskID, action, secret, ttl))
+}
+
+// EscalateForDanger raises the notification to urgent if the task's danger score reaches the escalation threshold
+func (n *Notification) EscalateForDanger(dangerScore float64) {
+	if dangerScore < DangerScoreEscalationThreshold {
+		return
+	}
+
+	n.Priority = PriorityUrgent
+	n.Tags = append(n.Tags, "warning")
+	n.Message = fmt.Sprintf("%s (danger score %.2f)", n.Message, dangerScore)
+}
+
+// ApplyCommandRisk sets the notification's priority from the risk of the command it asks about
+// High and critical commands are also tagged and explained in the message, so they stand out before the task is opened.
+func (n *Notification) ApplyCommandRisk(risk *CommandRisk) {
+	if risk == nil {
+		return
+	}
+
+	n.Priority = risk.Level.ToNotificationPriority()
+	if risk.Level.severity() >= RiskLevelHigh.severity() {
+		n.Tags = append(n.Tags, "warning")
+		n.Message = fmt.Sprintf("%s (%s risk: %s)", n.Message, risk.Level, risk.Explanation)
+	}
+}
+
 // MarkSent records when the notification was sent
 func (n *Notification) MarkSent() {
 	now := time.Now()
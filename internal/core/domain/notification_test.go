@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestNotificationPriority_PagerDutyMapping(t *testing.T) {
+	tests := []struct {
+		priority         NotificationPriority
+		expectedUrgency  string
+		expectedSeverity string
+	}{
+		{PriorityLow, "low", "info"},
+		{PriorityNormal, "low", "warning"},
+		{PriorityHigh, "high", "error"},
+		{PriorityUrgent, "high", "critical"},
+		{NotificationPriority(""), "low", "info"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.priority), func(t *testing.T) {
+			if got := tt.priority.ToPagerDutyUrgency(); got != tt.expectedUrgency {
+				t.Errorf("ToPagerDutyUrgency() = %v, expected %v", got, tt.expectedUrgency)
+			}
+			if got := tt.priority.ToSeverity(); got != tt.expectedSeverity {
+				t.Errorf("ToSeverity() = %v, expected %v", got, tt.expectedSeverity)
+			}
+		})
+	}
+}
+
+func TestNotificationPriority_ToSlackColor(t *testing.T) {
+	tests := []struct {
+		priority NotificationPriority
+		expected string
+	}{
+		{PriorityLow, "good"},
+		{PriorityNormal, "good"},
+		{PriorityHigh, "warning"},
+		{PriorityUrgent, "danger"},
+		{NotificationPriority(""), "good"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.priority), func(t *testing.T) {
+			if got := tt.priority.ToSlackColor(); got != tt.expected {
+				t.Errorf("ToSlackColor() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNotificationPriority_ToDiscordColor(t *testing.T) {
+	tests := []struct {
+		priority NotificationPriority
+		expected int
+	}{
+		{PriorityLow, DiscordColorGray},
+		{PriorityNormal, DiscordColorBlue},
+		{PriorityHigh, DiscordColorYellow},
+		{PriorityUrgent, DiscordColorRed},
+		{NotificationPriority(""), DiscordColorBlue},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.priority), func(t *testing.T) {
+			if got := tt.priority.ToDiscordColor(); got != tt.expected {
+				t.Errorf("ToDiscordColor() = %#x, expected %#x", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNotificationPriority_ToPushoverPriority(t *testing.T) {
+	tests := []struct {
+		priority NotificationPriority
+		expected int
+	}{
+		{PriorityLow, -1},
+		{PriorityNormal, 0},
+		{PriorityHigh, 1},
+		{PriorityUrgent, 2},
+		{NotificationPriority(""), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.priority), func(t *testing.T) {
+			if got := tt.priority.ToPushoverPriority(); got != tt.expected {
+				t.Errorf("ToPushoverPriority() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewConcurrentSessionsNotification(t *testing.T) {
+	notification := NewConcurrentSessionsNotification(7, "claude.example.com/control")
+
+	if notification.Message != "⚠️ 7 concurrent sessions need attention" {
+		t.Errorf("Unexpected message: %q", notification.Message)
+	}
+	if notification.Priority != PriorityUrgent {
+		t.Errorf("Expected urgent priority, got %s", notification.Priority)
+	}
+	if notification.ActionURL != "http://claude.example.com/control/dashboard" {
+		t.Errorf("Expected a dashboard link, got %s", notification.ActionURL)
+	}
+}
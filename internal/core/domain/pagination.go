@@ -0,0 +1,19 @@
+package domain
+
+// Pagination describes where one page of a list sits in the full result set
+type Pagination struct {
+	Total   int64 `json:"total"`    // Items matching the filter across all pages
+	Limit   int   `json:"limit"`    // Page size asked for, 0 when unlimited
+	Offset  int   `json:"offset"`   // Items skipped before this page
+	HasMore bool  `json:"has_more"` // More items follow this page
+}
+
+// NewPagination describes a page of returned items read at offset, out of total matching items
+func NewPagination(total int64, limit, offset, returned int) Pagination {
+	return Pagination{
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: int64(offset+returned) < total,
+	}
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "testing"
+
+func TestNewPagination(t *testing.T) {
+	tests := []struct {
+		name            string
+		total           int64
+		limit           int
+		offset          int
+		returned        int
+		expectedHasMore bool
+	}{
+		{"First of several pages", 120, 50, 0, 50, true},
+		{"Last full page", 100, 50, 50, 50, false},
+		{"Last partial page", 120, 50, 100, 20, false},
+		{"Unlimited", 3, 0, 0, 3, false},
+		{"Offset past the end", 3, 50, 10, 0, false},
+		{"Empty", 0, 50, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pagination := NewPagination(tt.total, tt.limit, tt.offset, tt.returned)
+			if pagination.HasMore != tt.expectedHasMore {
+				t.Errorf("HasMore = %v, expected %v", pagination.HasMore, tt.expectedHasMore)
+			}
+			if pagination.Total != tt.total || pagination.Limit != tt.limit || pagination.Offset != tt.offset {
+				t.Errorf("NewPagination() = %+v, expected total %d, limit %d, offset %d", pagination, tt.total, tt.limit, tt.offset)
+			}
+		})
+	}
+}
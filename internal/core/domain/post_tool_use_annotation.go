@@ -0,0 +1,16 @@
+package domain
+
+// Annotation severities, from least to most serious
+const (
+	AnnotationSeverityInfo    = "info"
+	AnnotationSeverityWarning = "warning"
+	AnnotationSeverityError   = "error"
+)
+
+// PostToolUseAnnotation is a processor's analysis of a finished tool call, stored in the task's history
+type PostToolUseAnnotation struct {
+	Processor string   `json:"processor"`         // Name of the processor that produced the annotation
+	Severity  string   `json:"severity"`          // One of the AnnotationSeverity constants
+	Message   string   `json:"message,omitempty"` // Human-readable summary of the finding
+	Matches   []string `json:"matches,omitempty"` // What triggered the finding, e.g. matched keywords
+}
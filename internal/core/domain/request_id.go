@@ -0,0 +1,17 @@
+package domain
+
+import "context"
+
+// requestIDContextKey stores the ID of the HTTP request a context belongs to
+type requestIDContextKey struct{}
+
+// WithRequestID returns a context carrying requestID, so work done for the request can be traced back to it
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or "" outside a request
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
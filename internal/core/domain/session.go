@@ -4,9 +4,88 @@ import (
 	"time"
 )
 
+// DefaultSessionIdleThreshold is how long a session can go without a hook event before it counts as idle
+const DefaultSessionIdleThreshold = 30 * time.Minute
+
+// SessionState is a session's liveness, derived from its last hook event
+type SessionState string
+
+const (
+	// SessionStateActive means a hook fired within the idle threshold
+	SessionStateActive SessionState = "active"
+
+	// SessionStateIdle means no hook has fired within the idle threshold
+	SessionStateIdle SessionState = "idle"
+
+	// SessionStateStopped means Claude finished responding and is waiting for the user
+	SessionStateStopped SessionState = "stopped"
+)
+
 // Session represents a Claude Code conversation session
 type Session struct {
-	ID        string    `json:"id"`         // Claude Code session ID
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID             string    `json:"id"`                       // Claude Code session ID
+	SubagentIDs    []string  `json:"subagent_ids,omitempty"`   // Subagents (Task tool calls) spawned by this session
+	EventCount     int       `json:"event_count"`              // Number of hook events recorded for this session
+	LastActivityAt time.Time `json:"last_activity_at"`         // When the most recent hook event fired
+	LastHookType   HookType  `json:"last_hook_type,omitempty"` // Type of the most recent hook event
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// IsActive returns true if the session fired a hook within idleThreshold
+func (s *Session) IsActive(idleThreshold time.Duration) bool {
+	return s.isActiveAt(time.Now(), idleThreshold)
+}
+
+// IdleSince returns how long ago the session fired its last hook
+func (s *Session) IdleSince() time.Duration {
+	return s.idleSinceAt(time.Now())
+}
+
+// Classify reports whether the session is active, idle or stopped, using DefaultSessionIdleThreshold
+// A session whose last hook was Stop is stopped however long ago that was.
+func (s *Session) Classify() SessionState {
+	return s.classifyAt(time.Now(), DefaultSessionIdleThreshold)
+}
+
+// isActiveAt returns true if the session fired a hook within idleThreshold of now
+func (s *Session) isActiveAt(now time.Time, idleThreshold time.Duration) bool {
+	return s.LastActivityAt.After(now.Add(-idleThreshold))
+}
+
+// idleSinceAt returns how long before now the session fired its last hook
+func (s *Session) idleSinceAt(now time.Time) time.Duration {
+	return now.Sub(s.LastActivityAt)
+}
+
+// classifyAt derives the session's state as of now
+func (s *Session) classifyAt(now time.Time, idleThreshold time.Duration) SessionState {
+	switch {
+	case s.LastHookType == HookTypeStop:
+		return SessionStateStopped
+	case s.isActiveAt(now, idleThreshold):
+		return SessionStateActive
+	default:
+		return SessionStateIdle
+	}
+}
+
+// AddSubagentID records a subagent as belonging to this session, returning false if it was already known
+func (s *Session) AddSubagentID(subagentID string) bool {
+	if subagentID == "" || s.HasSubagent(subagentID) {
+		return false
+	}
+	s.SubagentIDs = append(s.SubagentIDs, subagentID)
+	s.UpdatedAt = time.Now()
+	return true
+}
+
+// HasSubagent reports whether the subagent was spawned by this session
+func (s *Session) HasSubagent(subagentID string) bool {
+	for _, id := range s.SubagentIDs {
+		if id == subagentID {
+			return true
+		}
+	}
+	return false
 }
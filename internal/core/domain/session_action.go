@@ -12,8 +12,30 @@ const (
 	ActionTypeApprove      ActionType = "approve"
 	ActionTypeReject       ActionType = "reject"
 	ActionTypeSubmitPrompt ActionType = "submit_prompt"
+	ActionTypeCancel       ActionType = "cancel"
+
+	// ActionTypeContinue lets Claude carry on past a Stop hook, usually with further input
+	ActionTypeContinue ActionType = "continue"
+
+	// ActionTypeSkip defers a decision, leaving the task pending
+	ActionTypeSkip ActionType = "skip"
 )
 
+// String returns the string representation of the action type
+func (a ActionType) String() string {
+	return string(a)
+}
+
+// IsTerminal returns true if the action resolves a task so no further actions can be taken
+func (a ActionType) IsTerminal() bool {
+	switch a {
+	case ActionTypeApprove, ActionTypeReject, ActionTypeCancel:
+		return true
+	default:
+		return false
+	}
+}
+
 // SessionAction represents an action taken in response to a session event
 type SessionAction struct {
 	ID             uuid.UUID  `json:"id"`
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestActionType_IsTerminal(t *testing.T) {
+	tests := []struct {
+		action   ActionType
+		expected bool
+	}{
+		{ActionTypeApprove, true},
+		{ActionTypeReject, true},
+		{ActionTypeCancel, true},
+		{ActionTypeSubmitPrompt, false},
+		{ActionTypeSkip, false},
+		{ActionType("retry"), false},
+		{ActionType(""), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.action), func(t *testing.T) {
+			if got := tt.action.IsTerminal(); got != tt.expected {
+				t.Errorf("IsTerminal() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
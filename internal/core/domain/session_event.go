@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,3 +18,36 @@ type SessionEvent struct {
 	EventData      interface{} `json:"event_data"`       // Hook-specific data
 	CreatedAt      time.Time   `json:"created_at"`
 }
+
+// GetSubagentID returns the subagent ID carried by a SubagentStop event, or an empty string
+func (e *SessionEvent) GetSubagentID() string {
+	if e.HookType != HookTypeSubagentStop {
+		return ""
+	}
+
+	switch data := e.EventData.(type) {
+	case *SubagentStopHookData:
+		return data.SubagentID
+	case map[string]interface{}:
+		subagentID, _ := data["subagent_id"].(string)
+		return subagentID
+	}
+	return ""
+}
+
+// HookData decodes the event's webhook data into the typed hook data the task layer works with
+func (e *SessionEvent) HookData() (*HookData, error) {
+	typed := newHookDataStruct(e.HookType)
+	if typed == nil {
+		return nil, fmt.Errorf("unknown hook type: %s", e.HookType)
+	}
+
+	data, err := json.Marshal(e.EventData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event data: %w", e.HookType, err)
+	}
+	if err := json.Unmarshal(data, typed); err != nil {
+		return nil, fmt.Errorf("failed to decode %s event data: %w", e.HookType, err)
+	}
+	return &HookData{Type: e.HookType, Data: typed}, nil
+}
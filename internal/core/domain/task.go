@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskStatus is where a task is in its lifecycle
+type TaskStatus string
+
+const (
+	// TaskStatusPending means the task is waiting for the user's decision
+	TaskStatusPending TaskStatus = "pending"
+
+	// TaskStatusCompleted means the task needed no decision, or was resolved by an action other than approve or reject
+	TaskStatusCompleted TaskStatus = "completed"
+
+	// TaskStatusFailed means the blocking hook gave up waiting before the user decided
+	TaskStatusFailed TaskStatus = "failed"
+
+	TaskStatusApproved TaskStatus = "approved"
+	TaskStatusRejected TaskStatus = "rejected"
+)
+
+// String returns the string representation of the task status
+func (s TaskStatus) String() string {
+	return string(s)
+}
+
+// IsValid checks if the task status is one of the known statuses
+func (s TaskStatus) IsValid() bool {
+	switch s {
+	case TaskStatusPending, TaskStatusCompleted, TaskStatusFailed, TaskStatusApproved, TaskStatusRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// Task is one hook event received from Claude Code, and the user's decision on it
+type Task struct {
+	ID           uuid.UUID              `json:"id"`
+	HookType     HookType               `json:"hook_type"`
+	HookData     *HookData              `json:"hook_data"`
+	Status       TaskStatus             `json:"status"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+	ActionTaken  *ActionType            `json:"action_taken,omitempty"`
+	ResponseData map[string]interface{} `json:"response_data,omitempty"`
+
+	// SnoozedUntil holds back the task's notifications until then; nil when it isn't snoozed
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"`
+
+	// ParentTaskID is the task this one depends on; rejecting the parent cancels it while it is pending
+	ParentTaskID *uuid.UUID `json:"parent_task_id,omitempty"`
+}
+
+// NewTask creates a pending task for a hook event
+func NewTask(hookData *HookData) *Task {
+	now := time.Now()
+	task := &Task{
+		ID:        uuid.New(),
+		HookData:  hookData,
+		Status:    TaskStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if hookData != nil {
+		task.HookType = hookData.Type
+	}
+	return task
+}
+
+// Serialize encodes the task as canonical JSON, with its hook data in the {"type", "data"} form
+// DeserializeTask restores the concrete hook data struct from it, so tasks keep their shape across process boundaries.
+func (t *Task) Serialize() ([]byte, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize task: %w", err)
+	}
+	return data, nil
+}
+
+// DeserializeTask decodes a task encoded by Serialize, checking its hook type and status
+func DeserializeTask(data []byte) (*Task, error) {
+	var task Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return nil, fmt.Errorf("failed to deserialize task: %w", err)
+	}
+
+	hookType, err := ParseHookType(task.HookType.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize task: %w", err)
+	}
+	task.HookType = hookType
+
+	if !task.Status.IsValid() {
+		return nil, fmt.Errorf("failed to deserialize task: invalid status: %s", task.Status)
+	}
+	return &task, nil
+}
+
+// IsActionable returns true if the task is still waiting for a decision
+func (t *Task) IsActionable() bool {
+	return t.Status == TaskStatusPending && !t.IsDecided()
+}
+
+// IsDecided returns true once a terminal action has been taken on the task
+func (t *Task) IsDecided() bool {
+	return t.ActionTaken != nil && t.ActionTaken.IsTerminal()
+}
+
+// TakeAction records the user's action and the data sent with it, and moves the task to the matching status
+// Non-terminal actions such as retry complete the task, except a skip, which leaves it pending.
+func (t *Task) TakeAction(action ActionType, responseData map[string]interface{}) {
+	t.ActionTaken = &action
+	t.ResponseData = responseData
+	t.UpdatedAt = time.Now()
+
+	if !action.IsTerminal() {
+		if action != ActionTypeSkip {
+			t.Status = TaskStatusCompleted
+		}
+		return
+	}
+
+	switch action {
+	case ActionTypeApprove:
+		t.Status = TaskStatusApproved
+	case ActionTypeReject:
+		t.Status = TaskStatusRejected
+	default:
+		t.Status = TaskStatusCompleted
+	}
+}
+
+// History actions recorded by the task service; user actions are recorded under their ActionType
+const (
+	HistoryActionCreated  = "created"
+	HistoryActionNotified = "notified"
+)
+
+// TaskHistory is one entry in a task's audit trail
+type TaskHistory struct {
+	ID        uuid.UUID              `json:"id"`
+	TaskID    uuid.UUID              `json:"task_id"`
+	Action    string                 `json:"action"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// NewTaskHistory creates a history entry for a task
+func NewTaskHistory(taskID uuid.UUID, action string, data map[string]interface{}) *TaskHistory {
+	return &TaskHistory{
+		ID:        uuid.New(),
+		TaskID:    taskID,
+		Action:    action,
+		Data:      data,
+		CreatedAt: time.Now(),
+	}
+}
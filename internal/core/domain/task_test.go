@@ -0,0 +1,196 @@
+package domain
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// allHookTypes lists the seven hook types Claude Code sends
+var allHookTypes = []HookType{
+	HookTypePreToolUse, HookTypePostToolUse, HookTypeNotification, HookTypeUserPromptSubmit,
+	HookTypeStop, HookTypeSubagentStop, HookTypePreCompact,
+}
+
+// sampleTask builds a decided task for a hook type with every optional field set
+func sampleTask(hookType HookType) *Task {
+	created := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	snoozedUntil := created.Add(time.Hour)
+	parentID := uuid.MustParse("6f1c2a7e-9d7b-4c36-9a51-3b8f0e2d4c11")
+	action := ActionTypeApprove
+
+	task := NewTask(sampleHookData(hookType, 1))
+	task.Status = TaskStatusApproved
+	task.CreatedAt = created
+	task.UpdatedAt = created.Add(time.Minute)
+	task.ActionTaken = &action
+	task.ResponseData = map[string]interface{}{"comment": "ok", "decision_time_ms": float64(1500)}
+	task.SnoozedUntil = &snoozedUntil
+	task.ParentTaskID = &parentID
+	return task
+}
+
+func TestNewTask(t *testing.T) {
+	hookData := &HookData{Type: HookTypePreToolUse, Data: &PreToolUseHookData{ToolName: "Bash"}}
+
+	task := NewTask(hookData)
+	if task.HookType != HookTypePreToolUse {
+		t.Errorf("HookType = %s, expected %s", task.HookType, HookTypePreToolUse)
+	}
+	if !task.IsActionable() {
+		t.Errorf("new task has status %s, expected it to be actionable", task.Status)
+	}
+	if task.ActionTaken != nil {
+		t.Errorf("ActionTaken = %v, expected nil", *task.ActionTaken)
+	}
+}
+
+func TestTask_TakeAction(t *testing.T) {
+	tests := []struct {
+		action         ActionType
+		expectedStatus TaskStatus
+	}{
+		{ActionTypeApprove, TaskStatusApproved},
+		{ActionTypeReject, TaskStatusRejected},
+		{ActionTypeContinue, TaskStatusCompleted},
+		{ActionTypeCancel, TaskStatusCompleted},
+		{ActionTypeSkip, TaskStatusPending},
+		{ActionTypeSubmitPrompt, TaskStatusCompleted},
+		{ActionType("retry"), TaskStatusCompleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.action.String(), func(t *testing.T) {
+			task := NewTask(&HookData{Type: HookTypePreToolUse})
+			task.TakeAction(tt.action, map[string]interface{}{"comment": "ok"})
+
+			if task.Status != tt.expectedStatus {
+				t.Errorf("Status = %s, expected %s", task.Status, tt.expectedStatus)
+			}
+			if task.ActionTaken == nil || *task.ActionTaken != tt.action {
+				t.Errorf("ActionTaken = %v, expected %s", task.ActionTaken, tt.action)
+			}
+			if task.ResponseData["comment"] != "ok" {
+				t.Errorf("ResponseData = %v, expected the action's data", task.ResponseData)
+			}
+		})
+	}
+}
+
+func TestTask_IsActionable(t *testing.T) {
+	approve := ActionTypeApprove
+	skip := ActionTypeSkip
+	tests := []struct {
+		name        string
+		status      TaskStatus
+		actionTaken *ActionType
+		expected    bool
+	}{
+		{name: "Pending", status: TaskStatusPending, expected: true},
+		{name: "Pending after skip", status: TaskStatusPending, actionTaken: &skip, expected: true},
+		{name: "Pending after approve", status: TaskStatusPending, actionTaken: &approve},
+		{name: "Approved", status: TaskStatusApproved, actionTaken: &approve},
+		{name: "Completed", status: TaskStatusCompleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{Status: tt.status, ActionTaken: tt.actionTaken}
+			if got := task.IsActionable(); got != tt.expected {
+				t.Errorf("IsActionable() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTaskStatus_IsValid(t *testing.T) {
+	for _, status := range []TaskStatus{TaskStatusPending, TaskStatusCompleted, TaskStatusFailed, TaskStatusApproved, TaskStatusRejected} {
+		if !status.IsValid() {
+			t.Errorf("%s.IsValid() = false, expected true", status)
+		}
+	}
+	if TaskStatus("archived").IsValid() {
+		t.Error("unknown status is valid")
+	}
+}
+
+func TestTask_SerializeRoundTrip(t *testing.T) {
+	for _, hookType := range allHookTypes {
+		t.Run(hookType.String(), func(t *testing.T) {
+			original := sampleTask(hookType)
+			data, err := original.Serialize()
+			if err != nil {
+				t.Fatalf("Failed to serialize task: %v", err)
+			}
+
+			decoded, err := DeserializeTask(data)
+			if err != nil {
+				t.Fatalf("Failed to deserialize task: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, original) {
+				t.Errorf("Round trip mismatch\ngot:  %#v\nwant: %#v", decoded, original)
+			}
+		})
+	}
+}
+
+func TestDeserializeTask_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "Not JSON", input: `{"id":`},
+		{name: "Unknown hook type", input: `{"hook_type":"Custom","status":"pending"}`},
+		{name: "Unknown status", input: `{"hook_type":"Stop","status":"waiting"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DeserializeTask([]byte(tt.input)); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func FuzzDeserializeTask(f *testing.F) {
+	for _, hookType := range allHookTypes {
+		seed, err := sampleTask(hookType).Serialize()
+		if err != nil {
+			f.Fatalf("Failed to serialize seed: %v", err)
+		}
+		f.Add(seed)
+		if seed, err = NewTask(sampleHookData(hookType, 0)).Serialize(); err != nil {
+			f.Fatalf("Failed to serialize seed: %v", err)
+		}
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		first, err := DeserializeTask(input)
+		if err != nil {
+			return
+		}
+
+		encoded, err := first.Serialize()
+		if err != nil {
+			t.Fatalf("Failed to serialize decoded task: %v", err)
+		}
+
+		second, err := DeserializeTask(encoded)
+		if err != nil {
+			t.Fatalf("Failed to deserialize re-encoded task %s: %v", encoded, err)
+		}
+
+		reencoded, err := second.Serialize()
+		if err != nil {
+			t.Fatalf("Failed to serialize task again: %v", err)
+		}
+		if !bytes.Equal(encoded, reencoded) {
+			t.Errorf("Encoding is not stable\nfirst:  %s\nsecond: %s", encoded, reencoded)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// DefaultMaxToolOutputBytes is how much of a tool's stdout and stderr is kept when a hook is stored
+const DefaultMaxToolOutputBytes = 32 * 1024
+
+// ToolOutputTruncation records the original sizes of tool output that was cut short before storage
+type ToolOutputTruncation struct {
+	OriginalStdoutBytes int
+	OriginalStderrBytes int
+}
+
+// TruncateToolOutput limits a PostToolUse hook's stdout and stderr to maxBytes each
+// Returns the original sizes if either was truncated, or nil if nothing changed.
+func (h *HookData) TruncateToolOutput(maxBytes int) *ToolOutputTruncation {
+	if h == nil {
+		return nil
+	}
+	data, ok := h.Data.(*PostToolUseHookData)
+	if !ok || data.ToolResponse == nil {
+		return nil
+	}
+
+	truncation := ToolOutputTruncation{
+		OriginalStdoutBytes: len(data.ToolResponse.Stdout),
+		OriginalStderrBytes: len(data.ToolResponse.Stderr),
+	}
+	stdout, stdoutTruncated := truncateOutput(data.ToolResponse.Stdout, maxBytes)
+	stderr, stderrTruncated := truncateOutput(data.ToolResponse.Stderr, maxBytes)
+	if !stdoutTruncated && !stderrTruncated {
+		return nil
+	}
+
+	data.ToolResponse.Stdout = stdout
+	data.ToolResponse.Stderr = stderr
+	return &truncation
+}
+
+// ToolCallFailed returns true for a PostToolUse hook whose tool was interrupted or wrote to stderr
+func (h *HookData) ToolCallFailed() bool {
+	if h == nil {
+		return false
+	}
+	data, ok := h.Data.(*PostToolUseHookData)
+	if !ok || data.ToolResponse == nil {
+		return false
+	}
+	return data.ToolResponse.Interrupted || data.ToolResponse.Stderr != ""
+}
+
+// truncateOutput keeps the first maxBytes of output and notes how many bytes were dropped
+// The cut never splits a UTF-8 character, so slightly less than maxBytes may be kept.
+func truncateOutput(output string, maxBytes int) (string, bool) {
+	if len(output) <= maxBytes {
+		return output, false
+	}
+
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(output[cut]) {
+		cut--
+	}
+	return output[:cut] + fmt.Sprintf("\n[output truncated: %d bytes omitted]", len(output)-cut), true
+}
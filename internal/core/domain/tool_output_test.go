@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestHookData_TruncateToolOutput(t *testing.T) {
+	stdout := strings.Repeat("a", 100*1024)
+	stderr := strings.Repeat("e", 40*1024)
+	hookData := &HookData{
+		Type: HookTypePostToolUse,
+		Data: &PostToolUseHookData{
+			ToolName:     "Bash",
+			ToolInput:    &ToolInput{Command: "make build"},
+			ToolResponse: &ToolResponse{Stdout: stdout, Stderr: stderr},
+		},
+	}
+
+	truncation := hookData.TruncateToolOutput(DefaultMaxToolOutputBytes)
+	if truncation == nil {
+		t.Fatal("Expected the output to be truncated")
+	}
+	if truncation.OriginalStdoutBytes != 100*1024 || truncation.OriginalStderrBytes != 40*1024 {
+		t.Errorf("Unexpected original sizes: %+v", truncation)
+	}
+
+	// Round trip through JSON the way the task repository stores hook data
+	stored, err := json.Marshal(hookData)
+	if err != nil {
+		t.Fatalf("Failed to marshal hook data: %v", err)
+	}
+	var decoded HookData
+	if err := json.Unmarshal(stored, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal hook data: %v", err)
+	}
+	response := decoded.Data.(*PostToolUseHookData).ToolResponse
+
+	expectedStdout := strings.Repeat("a", 32*1024) + "\n[output truncated: 69632 bytes omitted]"
+	if response.Stdout != expectedStdout {
+		t.Errorf("Expected 32 KB of stdout plus the truncation message, got %d bytes ending %q",
+			len(response.Stdout), response.Stdout[len(response.Stdout)-50:])
+	}
+	expectedStderr := strings.Repeat("e", 32*1024) + "\n[output truncated: 8192 bytes omitted]"
+	if response.Stderr != expectedStderr {
+		t.Errorf("Expected 32 KB of stderr plus the truncation message, got %d bytes", len(response.Stderr))
+	}
+}
+
+func TestHookData_TruncateToolOutputUnchanged(t *testing.T) {
+	tests := []struct {
+		name     string
+		hookData *HookData
+	}{
+		{"nil hook data", nil},
+		{"pre tool use", &HookData{Type: HookTypePreToolUse, Data: &PreToolUseHookData{ToolName: "Bash"}}},
+		{"no tool response", &HookData{Type: HookTypePostToolUse, Data: &PostToolUseHookData{ToolName: "Bash"}}},
+		{"output within limit", &HookData{Type: HookTypePostToolUse, Data: &PostToolUseHookData{
+			ToolName:     "Bash",
+			ToolResponse: &ToolResponse{Stdout: strings.Repeat("a", DefaultMaxToolOutputBytes), Stderr: "warning"},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if truncation := tt.hookData.TruncateToolOutput(DefaultMaxToolOutputBytes); truncation != nil {
+				t.Errorf("Expected nothing to be truncated, got %+v", truncation)
+			}
+		})
+	}
+}
+
+func TestHookData_ToolCallFailed(t *testing.T) {
+	postToolUse := func(response *ToolResponse) *HookData {
+		return &HookData{Type: HookTypePostToolUse, Data: &PostToolUseHookData{ToolName: "Bash", ToolResponse: response}}
+	}
+
+	tests := []struct {
+		name     string
+		hookData *HookData
+		expected bool
+	}{
+		{"nil hook data", nil, false},
+		{"pre tool use", &HookData{Type: HookTypePreToolUse, Data: &PreToolUseHookData{ToolName: "Bash"}}, false},
+		{"no tool response", postToolUse(nil), false},
+		{"success", postToolUse(&ToolResponse{Stdout: "ok", Success: true}), false},
+		{"stderr", postToolUse(&ToolResponse{Stderr: "make: *** [test] Error 1"}), true},
+		{"interrupted", postToolUse(&ToolResponse{Interrupted: true}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hookData.ToolCallFailed(); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTruncateOutputKeepsWholeCharacters(t *testing.T) {
+	// "é" is two bytes, so a 5-byte limit falls in the middle of the third one
+	truncated, ok := truncateOutput("ééééé", 5)
+	if !ok {
+		t.Fatal("Expected the output to be truncated")
+	}
+	if expected := "éé\n[output truncated: 6 bytes omitted]"; truncated != expected {
+		t.Errorf("Expected %q, got %q", expected, truncated)
+	}
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+// HookDecisionService holds blocking webhooks open until the user decides what Claude Code should do
+type HookDecisionService interface {
+	// CreateTaskAndWaitForDecision creates a task for the hook and waits up to timeout for the user's decision
+	CreateTaskAndWaitForDecision(ctx context.Context, hookData *domain.HookData, timeout time.Duration) (*domain.HookResponse, error)
+
+	// GetTimeoutForHookType returns how long a blocking hook of this type waits for a decision
+	GetTimeoutForHookType(hookType domain.HookType) time.Duration
+}
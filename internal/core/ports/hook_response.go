@@ -0,0 +1,44 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+// HookResponseValidator checks a hook response against the rules Claude Code applies to hook output
+type HookResponseValidator interface {
+	// ValidateResponse returns an error describing the first rule the response breaks
+	ValidateResponse(response *domain.HookResponse) error
+
+	// ValidateJSON parses hook output as Claude Code would and validates the response it holds
+	ValidateJSON(data []byte) error
+}
+
+// HookResponseBuilder creates the JSON responses returned to Claude Code's hooks
+type HookResponseBuilder interface {
+	// BuildBlockingResponse creates a response that blocks Claude Code execution
+	BuildBlockingResponse(taskID, reason string) *domain.HookResponse
+
+	// BuildApprovedResponse creates a response that allows Claude Code to continue
+	BuildApprovedResponse(taskID string) *domain.HookResponse
+
+	// BuildRejectedResponse creates a response that blocks Claude Code with user rejection
+	BuildRejectedResponse(taskID, reason string) *domain.HookResponse
+
+	// BuildTimeoutResponse creates a response for when the user's decision times out
+	BuildTimeoutResponse(taskID string, timeout time.Duration) *domain.HookResponse
+
+	// BuildModifiedCommandResponse creates a response that lets Claude Code continue with a substituted command
+	BuildModifiedCommandResponse(taskID, modifiedCommand string) *domain.HookResponse
+
+	// BuildContinueResponse creates a non-blocking response that allows continuation
+	BuildContinueResponse() *domain.HookResponse
+
+	// BuildSuppressedResponse creates a non-blocking response with suppressed output
+	BuildSuppressedResponse() *domain.HookResponse
+
+	// BuildResponseFromDecision creates the response for a user decision
+	// An optional modified command replaces the tool command on approval and is ignored otherwise.
+	BuildResponseFromDecision(taskID string, decision domain.ActionType, modifiedCommand ...string) *domain.HookResponse
+}
@@ -0,0 +1,14 @@
+package ports
+
+import "github.com/dan/claude-control/internal/core/domain"
+
+// IdempotencyCache remembers hook responses by the X-Idempotency-Key they were sent for
+// Claude Code may resend a hook after a server restart; a cached response is replayed instead of
+// processing the event twice.
+type IdempotencyCache interface {
+	// Get returns the response cached for key, if it hasn't expired
+	Get(key string) (*domain.HookResponse, bool)
+
+	// Set caches the response sent for key
+	Set(key string, resp *domain.HookResponse)
+}
@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+// TaskMetrics records task and decision statistics for monitoring
+type TaskMetrics interface {
+	// WebhookReceived counts a hook that created a task
+	WebhookReceived(hookType domain.HookType)
+
+	// DecisionMade counts a user action on a task
+	DecisionMade(hookType domain.HookType, action domain.ActionType)
+
+	// DecisionWaited records how long a blocking hook waited for the user's decision
+	DecisionWaited(hookType domain.HookType, waited time.Duration)
+
+	// DecisionTimedOut counts a blocking hook that gave up waiting for a decision
+	DecisionTimedOut(hookType domain.HookType)
+
+	// ConcurrentSessions records how many sessions have a blocking hook waiting on the user
+	ConcurrentSessions(count int)
+}
@@ -15,6 +15,17 @@ type NotificationSender interface {
 	Verify(ctx context.Context) error
 }
 
+// NotificationDestination describes where a notification was delivered
+type NotificationDestination struct {
+	Channel string `json:"channel"`         // Notification backend, e.g. "ntfy" or "pagerduty"
+	Topic   string `json:"topic,omitempty"` // Backend-specific target, e.g. the NTFY topic
+}
+
+// NotificationRouter is optionally implemented by senders that can report where a notification goes
+type NotificationRouter interface {
+	DestinationFor(notification *domain.Notification) NotificationDestination
+}
+
 // NotificationConfig holds configuration for notification services
 type NotificationConfig struct {
 	ServerURL string `json:"server_url"`
@@ -22,4 +33,13 @@ type NotificationConfig struct {
 	Token     string `json:"token,omitempty"`    // Optional authentication token
 	Username  string `json:"username,omitempty"` // Optional basic auth username
 	Password  string `json:"password,omitempty"` // Optional basic auth password
+
+	// Per-project routing: when TopicFromCWD is set, notifications carrying a source CWD
+	// go to "<TopicPrefix>-<project>" instead of Topic
+	TopicFromCWD bool   `json:"topic_from_cwd,omitempty"`
+	TopicPrefix  string `json:"topic_prefix,omitempty"`
+
+	// NTFYEncryptionKey, when set, encrypts notification titles and messages with AES-GCM
+	// so the NTFY server never sees them; the action URL stays readable
+	NTFYEncryptionKey string `json:"-"`
 }
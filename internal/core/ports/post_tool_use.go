@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+// PostToolUseProcessor analyses a finished tool call, e.g. to flag errors or run a linter on the result
+type PostToolUseProcessor interface {
+	// Process returns an annotation for the tool call, or nil if there is nothing to report
+	Process(ctx context.Context, hookData *domain.PostToolUseHookData) (*domain.PostToolUseAnnotation, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUnknownReceipt is returned for a receipt token that was never issued, has expired or was already used
+var ErrUnknownReceipt = errors.New("unknown receipt token")
+
+// ReceiptRecorder records that Claude Code acknowledged a hook response
+type ReceiptRecorder interface {
+	// AcknowledgeReceipt marks the response carrying token as processed by Claude Code
+	AcknowledgeReceipt(ctx context.Context, token string) error
+}
@@ -0,0 +1,126 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// TaskRepository defines the interface for task persistence
+type TaskRepository interface {
+	// Create stores a new task
+	Create(ctx context.Context, task *domain.Task) error
+
+	// GetByID retrieves a task by its ID
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Task, error)
+
+	// Update persists every field of an existing task
+	Update(ctx context.Context, task *domain.Task) error
+
+	// List retrieves tasks with optional filtering
+	List(ctx context.Context, filter TaskFilter) ([]*domain.Task, error)
+
+	// Count counts the tasks List would return for the filter, ignoring its limit and offset
+	Count(ctx context.Context, filter TaskFilter) (int64, error)
+
+	// Delete removes a task by ID
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// GetChildTasks retrieves the tasks whose parent is taskID, oldest first
+	GetChildTasks(ctx context.Context, taskID uuid.UUID) ([]*domain.Task, error)
+
+	// ListBySession retrieves tasks matching the filter grouped by session, oldest first within each session
+	ListBySession(ctx context.Context, filter TaskFilter) ([]*domain.Task, error)
+
+	// GetPendingTasks retrieves all tasks that require user action, oldest first
+	GetPendingTasks(ctx context.Context) ([]*domain.Task, error)
+
+	// GetPendingTasksForSession retrieves the pending tasks raised by one session, oldest first
+	GetPendingTasksForSession(ctx context.Context, sessionID string) ([]*domain.Task, error)
+
+	// GetSessionSummary counts a session's tasks and reports when it was first and last active
+	GetSessionSummary(ctx context.Context, sessionID string) (*SessionSummary, error)
+
+	// GetExpiredSnoozes retrieves pending tasks whose snooze ended at or before now
+	GetExpiredSnoozes(ctx context.Context, now time.Time) ([]*domain.Task, error)
+
+	// ClearSnooze removes a task's snooze, returning false if another caller cleared it first
+	ClearSnooze(ctx context.Context, id uuid.UUID) (bool, error)
+
+	// GetToolUsageStats aggregates tool calls since the given time by tool name
+	GetToolUsageStats(ctx context.Context, since time.Time) ([]ToolUsageStat, error)
+
+	// GetTaskStats aggregates tasks created since the given time for the dashboard
+	GetTaskStats(ctx context.Context, since time.Time) (*TaskStats, error)
+
+	// GetCompactStats aggregates PreCompact hooks since the given time by session
+	GetCompactStats(ctx context.Context, since time.Time) (*CompactStats, error)
+
+	// CountByStatus counts all tasks by status
+	CountByStatus(ctx context.Context) (map[domain.TaskStatus]int, error)
+
+	// CountByHookType counts all tasks by hook type
+	CountByHookType(ctx context.Context) (map[domain.HookType]int, error)
+
+	// CountConcurrentSessions counts sessions with a pending task created since the given time
+	CountConcurrentSessions(ctx context.Context, since time.Time) (int, error)
+
+	// GetTasksByHookType retrieves all tasks of one hook type
+	GetTasksByHookType(ctx context.Context, hookType domain.HookType) ([]*domain.Task, error)
+
+	// ArchiveCompleted moves resolved tasks untouched for olderThan to the archive, returning how many moved
+	ArchiveCompleted(ctx context.Context, olderThan time.Duration) (int, error)
+
+	// ListArchived retrieves archived tasks with the same filtering as List
+	ListArchived(ctx context.Context, filter TaskFilter) ([]*domain.Task, error)
+}
+
+// TaskHistoryRepository defines the interface for task history persistence
+type TaskHistoryRepository interface {
+	// Create stores a new task history entry
+	Create(ctx context.Context, history *domain.TaskHistory) error
+
+	// GetByTaskID retrieves all history entries for a task
+	GetByTaskID(ctx context.Context, taskID uuid.UUID) ([]*domain.TaskHistory, error)
+
+	// List retrieves history entries with optional filtering
+	List(ctx context.Context, filter TaskHistoryFilter) ([]*domain.TaskHistory, error)
+
+	// DeleteOlderThan removes history entries older than days, returning how many were removed
+	DeleteOlderThan(ctx context.Context, days int) (int64, error)
+
+	// GetHourlyActivity counts created and notified tasks per hour since the given time
+	GetHourlyActivity(ctx context.Context, since time.Time) ([]HourlyBucket, error)
+}
+
+// TaskFilter provides filtering options for task queries
+type TaskFilter struct {
+	Status    *domain.TaskStatus `json:"status,omitempty"`
+	HookType  *domain.HookType   `json:"hook_type,omitempty"`
+	Limit     int                `json:"limit,omitempty"`
+	Offset    int                `json:"offset,omitempty"`
+	SortBy    string             `json:"sort_by,omitempty"`    // created_at, updated_at, status
+	SortOrder string             `json:"sort_order,omitempty"` // asc, desc
+
+	// SearchQuery matches tasks whose hook data contains it anywhere, ignoring case
+	SearchQuery *string `json:"search_query,omitempty"`
+
+	// CreatedAfter and CreatedBefore bound when matching tasks were created, inclusively
+	CreatedAfter  *time.Time `json:"created_after,omitempty"`
+	CreatedBefore *time.Time `json:"created_before,omitempty"`
+
+	// SessionID matches tasks raised by one Claude Code session
+	SessionID *string `json:"session_id,omitempty"`
+}
+
+// TaskHistoryFilter provides filtering options for task history queries
+type TaskHistoryFilter struct {
+	TaskID    *uuid.UUID `json:"task_id,omitempty"`
+	Action    *string    `json:"action,omitempty"`
+	Limit     int        `json:"limit,omitempty"`
+	Offset    int        `json:"offset,omitempty"`
+	SortBy    string     `json:"sort_by,omitempty"`    // created_at
+	SortOrder string     `json:"sort_order,omitempty"` // asc, desc
+}
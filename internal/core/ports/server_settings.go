@@ -0,0 +1,21 @@
+package ports
+
+import "context"
+
+// SettingsRepository persists server-wide settings keyed by setting name
+type SettingsRepository interface {
+	// GetSetting retrieves a setting's value, returning found=false if it has never been set
+	GetSetting(ctx context.Context, name string) (value string, found bool, err error)
+
+	// SetSetting creates or replaces a setting's value
+	SetSetting(ctx context.Context, name string, value string) error
+}
+
+// ServerSettingsService manages runtime server settings that operators can toggle
+type ServerSettingsService interface {
+	// HooksDisabled reports whether hook processing is disabled; it must not touch the database
+	HooksDisabled() bool
+
+	// SetHooksDisabled enables or disables hook processing and persists the new state
+	SetHooksDisabled(ctx context.Context, disabled bool) error
+}
@@ -11,6 +11,15 @@ type SessionRepository interface {
 	// GetSession retrieves a session by its ID, and creates it if it doesn't exist
 	GetSession(ctx context.Context, sessionID string) (*domain.Session, error)
 
+	// UpdateSession persists changes to a session, including its subagent IDs
+	UpdateSession(ctx context.Context, session *domain.Session) error
+
+	// ListSessions retrieves up to limit sessions, most recently active first
+	ListSessions(ctx context.Context, limit int) ([]*domain.Session, error)
+
+	// FindSessionBySubagentID retrieves the parent session of a subagent
+	FindSessionBySubagentID(ctx context.Context, subagentID string) (*domain.Session, error)
+
 	// AddEvent stores a new event for a session
 	AddEvent(ctx context.Context, sessionID string, event *domain.SessionEvent) error
 
@@ -16,6 +16,15 @@ type SessionService interface {
 
 	// GetSessionEvents retrieves events for a session with optional filtering
 	GetSessionEvents(ctx context.Context, sessionID string, filter EventFilter) ([]*domain.SessionEvent, error)
+
+	// RecordSubagent links a subagent to its parent session
+	RecordSubagent(ctx context.Context, sessionID string, subagentID string) error
+
+	// ListSessions retrieves up to limit sessions, most recently active first
+	ListSessions(ctx context.Context, limit int) ([]*domain.Session, error)
+
+	// GetParentSession retrieves the session that spawned the given subagent
+	GetParentSession(ctx context.Context, subagentID string) (*domain.Session, error)
 }
 
 // EventFilter provides filtering options for session event queries
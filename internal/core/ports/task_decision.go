@@ -15,12 +15,25 @@ type TaskDecisionManager interface {
 	// SendDecision sends a decision to the waiting channel
 	SendDecision(taskID string, decision domain.ActionType) bool
 
+	// SendDecisionWithOverwrite replaces any queued decisions so the latest decision always wins
+	SendDecisionWithOverwrite(taskID string, decision domain.ActionType) bool
+
 	// RemoveDecisionChannel removes and closes a decision channel
 	RemoveDecisionChannel(taskID string)
 
 	// WaitForDecision waits for a user decision with timeout
 	WaitForDecision(ctx context.Context, taskID string, timeout time.Duration) (domain.ActionType, error)
 
+	// WaitForSessionDecision waits for a user decision with timeout, recording the Claude Code session that is blocked
+	WaitForSessionDecision(ctx context.Context, taskID, sessionID string, timeout time.Duration) (domain.ActionType, error)
+
+	// OpenSessionDecision registers a task's decision channel ahead of AwaitDecision, so a decision sent in between is kept
+	// timeout is how long AwaitDecision will wait. Every OpenSessionDecision must be followed by AwaitDecision.
+	OpenSessionDecision(ctx context.Context, taskID, sessionID string, timeout time.Duration)
+
+	// AwaitDecision waits with timeout for a decision on the channel opened by OpenSessionDecision, then removes it
+	AwaitDecision(ctx context.Context, taskID string, timeout time.Duration) (domain.ActionType, error)
+
 	// GetActiveDecisions returns the number of active decision channels
 	GetActiveDecisions() int
 
@@ -30,4 +43,17 @@ type TaskDecisionManager interface {
 	// CleanupExpiredChannels removes channels that haven't been used (emergency cleanup)
 	// This should rarely be needed as channels are cleaned up in defer statements
 	CleanupExpiredChannels()
+
+	// GetStuckDecisions returns decision channels that have been open longer than maxAge
+	GetStuckDecisions(maxAge time.Duration) []StuckDecision
+
+	// ForceResolveStuck cancels decisions older than maxAge and returns how many were cancelled
+	ForceResolveStuck(maxAge time.Duration) int
+}
+
+// StuckDecision describes a decision channel that has outlived its expected wait
+type StuckDecision struct {
+	TaskID    string        `json:"task_id"`
+	Age       time.Duration `json:"age"`
+	SessionID string        `json:"session_id,omitempty"`
 }
@@ -0,0 +1,87 @@
+package ports
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+// ToolUsageStat summarises how often a tool was requested and how those requests were decided
+type ToolUsageStat struct {
+	ToolName             string  `json:"tool_name"`
+	CallCount            int     `json:"call_count"`
+	ApprovalCount        int     `json:"approval_count"`
+	RejectionCount       int     `json:"rejection_count"`
+	TimeoutCount         int     `json:"timeout_count"`
+	AvgDecisionLatencyMs float64 `json:"avg_decision_latency_ms"` // Over approved and rejected calls only
+}
+
+// RejectionPercent returns the percentage of calls that were rejected
+func (s ToolUsageStat) RejectionPercent() float64 {
+	if s.CallCount == 0 {
+		return 0
+	}
+	return 100 * float64(s.RejectionCount) / float64(s.CallCount)
+}
+
+// HourlyBucket counts task history events in one hour of the activity timeline
+type HourlyBucket struct {
+	Hour         time.Time `json:"hour"`
+	EventCount   int       `json:"event_count"`
+	ActionsTaken int       `json:"actions_taken"` // Events other than task creation and notification
+}
+
+// TaskStats summarises the tasks created since a point in time and how they were decided
+// ByStatus is keyed by the status name since the task status type lives with the task model.
+type TaskStats struct {
+	TotalTasks            int64                       `json:"total_tasks"`
+	PendingTasks          int64                       `json:"pending_tasks"`
+	ByHookType            map[domain.HookType]int64   `json:"by_hook_type"`
+	ByStatus              map[string]int64            `json:"by_status"`
+	ByAction              map[domain.ActionType]int64 `json:"by_action"`
+	AvgDecisionDurationMs float64                     `json:"avg_decision_duration_ms"` // Over tasks with an action taken
+	P95DecisionDurationMs float64                     `json:"p95_decision_duration_ms"`
+	Compacts              CompactStats                `json:"compacts"`
+}
+
+// CompactStats counts the PreCompact hooks raised since a point in time, split by what triggered them
+// BySession is keyed by session ID, so sessions that keep running out of context stand out.
+type CompactStats struct {
+	TotalCompacts      int64            `json:"total_compacts"`
+	AutoCompacts       int64            `json:"auto_compacts"`   // Claude Code ran out of context
+	ManualCompacts     int64            `json:"manual_compacts"` // The user ran /compact
+	BySession          map[string]int64 `json:"by_session"`
+	AvgTranscriptLines float64          `json:"avg_transcript_lines"` // Over compacts whose transcript was read
+}
+
+// SessionSummary aggregates the tasks raised by one Claude Code session
+type SessionSummary struct {
+	SessionID             string    `json:"session_id"`
+	TotalTasks            int64     `json:"total_tasks"`
+	PendingTasks          int64     `json:"pending_tasks"`
+	ApprovedTasks         int64     `json:"approved_tasks"`
+	RejectedTasks         int64     `json:"rejected_tasks"`
+	FirstSeen             time.Time `json:"first_seen"`
+	LastSeen              time.Time `json:"last_seen"`
+	AvgDecisionDurationMs float64   `json:"avg_decision_duration_ms"` // Over tasks with an action taken
+}
+
+// AvgDecisionDuration returns the average decision time rounded to the second, or 0 if nothing has been decided
+func (s SessionSummary) AvgDecisionDuration() time.Duration {
+	return (time.Duration(s.AvgDecisionDurationMs) * time.Millisecond).Round(time.Second)
+}
+
+// String summarises the session on one line, e.g. "Session abc12345: 5 tasks, 2 pending, avg decision 12s"
+func (s SessionSummary) String() string {
+	sessionID := s.SessionID
+	if len(sessionID) > 8 {
+		sessionID = sessionID[:8]
+	}
+
+	decision := "no decisions yet"
+	if s.ApprovedTasks+s.RejectedTasks > 0 {
+		decision = fmt.Sprintf("avg decision %v", s.AvgDecisionDuration())
+	}
+	return fmt.Sprintf("Session %s: %d tasks, %d pending, %s", sessionID, s.TotalTasks, s.PendingTasks, decision)
+}
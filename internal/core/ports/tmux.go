@@ -0,0 +1,46 @@
+package ports
+
+import (
+	"context"
+)
+
+// TMuxController defines the interface for controlling tmux sessions running Claude Code
+type TMuxController interface {
+	// SendKeys sends keystrokes to a specific tmux session
+	SendKeys(ctx context.Context, sessionName string, keys string) error
+
+	// SendCommand sends a command to a tmux session (equivalent to typing + Enter)
+	SendCommand(ctx context.Context, sessionName string, command string) error
+
+	// ListSessions returns a list of available tmux sessions
+	ListSessions(ctx context.Context) ([]TMuxSession, error)
+
+	// SessionExists checks if a tmux session with the given name exists
+	SessionExists(ctx context.Context, sessionName string) (bool, error)
+
+	// CreateSession creates a new tmux session with the given name
+	CreateSession(ctx context.Context, sessionName string) error
+
+	// KillSession terminates a tmux session
+	KillSession(ctx context.Context, sessionName string) error
+
+	// GetSessionInfo retrieves detailed information about a session
+	GetSessionInfo(ctx context.Context, sessionName string) (*TMuxSession, error)
+
+	// CapturePaneScrollback returns up to maxLines of the session's scrollback plus the visible pane
+	CapturePaneScrollback(ctx context.Context, sessionName string, maxLines int) (string, error)
+}
+
+// TMuxSession represents a running tmux session
+type TMuxSession struct {
+	Name     string `json:"name"`
+	Windows  int    `json:"windows"`
+	Created  string `json:"created"`
+	Attached bool   `json:"attached"`
+	LastUsed string `json:"last_used"`
+}
+
+// TMuxConfig holds configuration for the tmux controller
+type TMuxConfig struct {
+	SocketPath string `json:"socket_path,omitempty"` // Optional tmux server socket (-S)
+}
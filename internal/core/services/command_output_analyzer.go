@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+// CommandOutputAnalyzerName identifies CommandOutputAnalyzer annotations in task history
+const CommandOutputAnalyzerName = "command_output_analyzer"
+
+// stderrErrorKeywords mark stderr output as a failure; matched case-insensitively
+var stderrErrorKeywords = []string{
+	"error",
+	"fatal",
+	"panic",
+	"exception",
+	"traceback",
+	"permission denied",
+	"command not found",
+	"no such file or directory",
+	"segmentation fault",
+}
+
+// stderrWarningKeywords mark stderr output as worth a look; matched case-insensitively
+var stderrWarningKeywords = []string{
+	"warning",
+	"deprecated",
+}
+
+// CommandOutputAnalyzer flags PostToolUse hooks whose stderr contains error or warning keywords
+type CommandOutputAnalyzer struct{}
+
+// NewCommandOutputAnalyzer creates a stderr keyword analyzer
+func NewCommandOutputAnalyzer() *CommandOutputAnalyzer {
+	return &CommandOutputAnalyzer{}
+}
+
+// Process annotates the tool call with error severity if stderr has an error keyword, or warning severity
+// if it only has warning keywords. Tool calls with clean stderr get no annotation.
+func (a *CommandOutputAnalyzer) Process(ctx context.Context, hookData *domain.PostToolUseHookData) (*domain.PostToolUseAnnotation, error) {
+	if hookData == nil || hookData.ToolResponse == nil || hookData.ToolResponse.Stderr == "" {
+		return nil, nil
+	}
+
+	stderr := strings.ToLower(hookData.ToolResponse.Stderr)
+	if matches := matchKeywords(stderr, stderrErrorKeywords); len(matches) > 0 {
+		return &domain.PostToolUseAnnotation{
+			Processor: CommandOutputAnalyzerName,
+			Severity:  domain.AnnotationSeverityError,
+			Message:   fmt.Sprintf("%s wrote errors to stderr", hookData.ToolName),
+			Matches:   matches,
+		}, nil
+	}
+	if matches := matchKeywords(stderr, stderrWarningKeywords); len(matches) > 0 {
+		return &domain.PostToolUseAnnotation{
+			Processor: CommandOutputAnalyzerName,
+			Severity:  domain.AnnotationSeverityWarning,
+			Message:   fmt.Sprintf("%s wrote warnings to stderr", hookData.ToolName),
+			Matches:   matches,
+		}, nil
+	}
+	return nil, nil
+}
+
+// matchKeywords returns the keywords found in text, in keyword order
+func matchKeywords(text string, keywords []string) []string {
+	var matches []string
+	for _, keyword := range keywords {
+		if strings.Contains(text, keyword) {
+			matches = append(matches, keyword)
+		}
+	}
+	return matches
+}
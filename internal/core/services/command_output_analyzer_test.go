@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+func TestCommandOutputAnalyzer_Process(t *testing.T) {
+	tests := []struct {
+		name             string
+		response         *domain.ToolResponse
+		expectedSeverity string // "" means no annotation
+		expectedMatches  []string
+	}{
+		{"No tool response", nil, "", nil},
+		{"Clean stderr", &domain.ToolResponse{Stdout: "ok", Stderr: ""}, "", nil},
+		{"Stderr without keywords", &domain.ToolResponse{Stderr: "Cloning into 'haiper'..."}, "", nil},
+		{
+			"Compile error",
+			&domain.ToolResponse{Stderr: "main.go:12:2: undefined: foo\nError: build failed"},
+			domain.AnnotationSeverityError,
+			[]string{"error"},
+		},
+		{
+			"Several error keywords",
+			&domain.ToolResponse{Stderr: "bash: ./deploy.sh: Permission denied\nFATAL: aborting"},
+			domain.AnnotationSeverityError,
+			[]string{"fatal", "permission denied"},
+		},
+		{
+			"Warning only",
+			&domain.ToolResponse{Stderr: "npm WARN deprecated request@2.88.2"},
+			domain.AnnotationSeverityWarning,
+			[]string{"deprecated"},
+		},
+		{
+			"Errors outrank warnings",
+			&domain.ToolResponse{Stderr: "warning: unused variable\npanic: runtime error"},
+			domain.AnnotationSeverityError,
+			[]string{"error", "panic"},
+		},
+	}
+
+	analyzer := NewCommandOutputAnalyzer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hookData := &domain.PostToolUseHookData{ToolName: "Bash", ToolResponse: tt.response}
+			annotation, err := analyzer.Process(context.Background(), hookData)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if tt.expectedSeverity == "" {
+				if annotation != nil {
+					t.Errorf("Expected no annotation, got %+v", annotation)
+				}
+				return
+			}
+			if annotation == nil {
+				t.Fatalf("Expected a %s annotation, got none", tt.expectedSeverity)
+			}
+			if annotation.Processor != CommandOutputAnalyzerName {
+				t.Errorf("Expected processor %q, got %q", CommandOutputAnalyzerName, annotation.Processor)
+			}
+			if annotation.Severity != tt.expectedSeverity {
+				t.Errorf("Expected severity %q, got %q", tt.expectedSeverity, annotation.Severity)
+			}
+			if !reflect.DeepEqual(annotation.Matches, tt.expectedMatches) {
+				t.Errorf("Expected matches %v, got %v", tt.expectedMatches, annotation.Matches)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// GetCompactStats counts the PreCompact hooks raised since the given time by trigger and session
+func (s *TaskService) GetCompactStats(ctx context.Context, since time.Time) (*ports.CompactStats, error) {
+	stats, err := s.taskRepo.GetCompactStats(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compact stats: %w", err)
+	}
+	return stats, nil
+}
+
+// estimateContextSize records how many lines a PreCompact hook's transcript has as a rough context size
+// Does nothing unless CountTranscriptLines is set; a transcript that can't be read is logged and left uncounted.
+func (s *TaskService) estimateContextSize(hookData *domain.HookData) {
+	if s.config == nil || !s.config.CountTranscriptLines {
+		return
+	}
+	data, ok := hookData.Data.(*domain.PreCompactHookData)
+	if !ok || data.TranscriptPath == "" {
+		return
+	}
+
+	lines, err := countTranscriptLines(data.TranscriptPath)
+	if err != nil {
+		log.Printf("Warning: failed to count transcript lines for session %s: %v", data.SessionID, err)
+		return
+	}
+	data.TranscriptLines = lines
+}
+
+// countTranscriptLines counts the entries in a transcript, one JSON object per line
+// The path comes from the webhook body, so it gets the same checks as a transcript backup. Lines are
+// counted in fixed-size chunks since a single transcript entry can be larger than any scanner buffer.
+func countTranscriptLines(transcriptPath string) (int, error) {
+	path, err := validateTranscriptPath(transcriptPath)
+	if err != nil {
+		return 0, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer file.Close()
+
+	lines := 0
+	endsWithNewline := true
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			lines += bytes.Count(buf[:n], []byte{'\n'})
+			endsWithNewline = buf[n-1] == '\n'
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read transcript: %w", err)
+		}
+	}
+
+	// A last entry still being written has no newline yet
+	if !endsWithNewline {
+		lines++
+	}
+	return lines, nil
+}
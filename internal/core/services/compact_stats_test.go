@@ -0,0 +1,74 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+func TestCountTranscriptLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected int
+	}{
+		{"Empty transcript", "", 0},
+		{"Every entry finished", "{\"type\":\"user\"}\n{\"type\":\"assistant\"}\n", 2},
+		{"Last entry still being written", "{\"type\":\"user\"}\n{\"type\":\"assist", 2},
+		{"Entry larger than one chunk", "{\"text\":\"" + strings.Repeat("a", 100*1024) + "\"}\n{}\n", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transcript := filepath.Join(t.TempDir(), "abc123.jsonl")
+			if err := os.WriteFile(transcript, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("Failed to write transcript: %v", err)
+			}
+
+			lines, err := countTranscriptLines(transcript)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if lines != tt.expected {
+				t.Errorf("Expected %d lines, got %d", tt.expected, lines)
+			}
+		})
+	}
+
+	if _, err := countTranscriptLines("relative/abc123.jsonl"); err == nil {
+		t.Error("Expected a relative transcript path to be rejected")
+	}
+}
+
+func TestEstimateContextSize(t *testing.T) {
+	transcript := filepath.Join(t.TempDir(), "abc123.jsonl")
+	if err := os.WriteFile(transcript, []byte("{}\n{}\n{}\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		config   *TaskServiceConfig
+		path     string
+		expected int
+	}{
+		{"Counted when enabled", &TaskServiceConfig{CountTranscriptLines: true}, transcript, 3},
+		{"Left alone when disabled", &TaskServiceConfig{}, transcript, 0},
+		{"Left alone without config", nil, transcript, 0},
+		{"Unreadable transcript is skipped", &TaskServiceConfig{CountTranscriptLines: true}, transcript + ".missing.jsonl", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hookData := preCompactHook("abc123", tt.path)
+			(&TaskService{config: tt.config}).estimateContextSize(hookData)
+
+			if lines := hookData.Data.(*domain.PreCompactHookData).TranscriptLines; lines != tt.expected {
+				t.Errorf("Expected %d transcript lines, got %d", tt.expected, lines)
+			}
+		})
+	}
+}
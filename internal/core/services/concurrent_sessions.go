@@ -0,0 +1,46 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// ConcurrentSessionWindow is how recent a pending task must be for its session to count as waiting
+const ConcurrentSessionWindow = 5 * time.Minute
+
+// ConcurrentSessionMonitor tracks how many sessions are waiting on the user and decides when to alert
+// It alerts once when the count goes over the threshold and re-arms only after it drops back,
+// so a busy stretch produces one notification rather than one per poll.
+type ConcurrentSessionMonitor struct {
+	threshold int
+	alerting  bool
+	mutex     sync.Mutex
+}
+
+// NewConcurrentSessionMonitor creates a monitor that alerts above threshold sessions; 0 disables alerts
+func NewConcurrentSessionMonitor(threshold int) *ConcurrentSessionMonitor {
+	return &ConcurrentSessionMonitor{threshold: threshold}
+}
+
+// Observe takes the latest session count and returns true if an alert should be sent for it
+func (m *ConcurrentSessionMonitor) Observe(count int) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.threshold <= 0 || count <= m.threshold {
+		m.alerting = false
+		return false
+	}
+	if m.alerting {
+		return false
+	}
+	m.alerting = true
+	return true
+}
+
+// Rearm lets the next Observe over the threshold alert again, e.g. after the alert failed to send
+func (m *ConcurrentSessionMonitor) Rearm() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.alerting = false
+}
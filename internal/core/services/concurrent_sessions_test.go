@@ -0,0 +1,43 @@
+package services
+
+import "testing"
+
+func TestConcurrentSessionMonitor(t *testing.T) {
+	steps := []struct {
+		count       int
+		expectAlert bool
+	}{
+		{count: 2, expectAlert: false},
+		{count: 3, expectAlert: false}, // At the threshold is fine
+		{count: 4, expectAlert: true},
+		{count: 6, expectAlert: false}, // Still over, already alerted
+		{count: 3, expectAlert: false},
+		{count: 5, expectAlert: true}, // Crossed again after dropping back
+	}
+
+	monitor := NewConcurrentSessionMonitor(3)
+	for i, step := range steps {
+		if alert := monitor.Observe(step.count); alert != step.expectAlert {
+			t.Errorf("Step %d (count %d): expected alert=%t, got %t", i, step.count, step.expectAlert, alert)
+		}
+	}
+}
+
+func TestConcurrentSessionMonitor_Rearm(t *testing.T) {
+	monitor := NewConcurrentSessionMonitor(1)
+	if !monitor.Observe(2) {
+		t.Fatal("Expected the first crossing to alert")
+	}
+
+	monitor.Rearm()
+	if !monitor.Observe(2) {
+		t.Error("Expected an alert after re-arming")
+	}
+}
+
+func TestConcurrentSessionMonitor_Disabled(t *testing.T) {
+	monitor := NewConcurrentSessionMonitor(0)
+	if monitor.Observe(50) {
+		t.Error("Expected no alerts with a threshold of 0")
+	}
+}
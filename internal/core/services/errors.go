@@ -9,9 +9,36 @@ var (
 	// ErrTaskNotFound is returned when a task cannot be found
 	ErrTaskNotFound = errors.New("task not found")
 	
+	// ErrSessionNotFound is returned when a session has raised no tasks
+	ErrSessionNotFound = errors.New("session not found")
+	
 	// ErrTaskNotActionable is returned when trying to take action on a non-actionable task
 	ErrTaskNotActionable = errors.New("task is not actionable")
 	
 	// ErrInvalidAction is returned when an invalid action is provided
 	ErrInvalidAction = errors.New("invalid action type")
+
+	// ErrInvalidSnoozeDuration is returned when a snooze duration is not positive or too long
+	ErrInvalidSnoozeDuration = errors.New("invalid snooze duration")
+
+	// ErrInvalidModifiedCommand is returned when a substituted command is too long or contains null bytes
+	ErrInvalidModifiedCommand = errors.New("invalid modified command")
+
+	// ErrCommandNotModifiable is returned when modifying the command of a task that isn't a tool call
+	ErrCommandNotModifiable = errors.New("task has no command to modify")
+
+	// ErrRenotifyRateLimited is returned when a task's notification was re-sent too recently
+	ErrRenotifyRateLimited = errors.New("notification re-sent too recently")
+
+	// ErrTranscriptBackupNotFound is returned when a task has no transcript backup
+	ErrTranscriptBackupNotFound = errors.New("no transcript backup for task")
+
+	// ErrInvalidBulkAction is returned when a bulk action or bulk delete names no tasks or too many
+	ErrInvalidBulkAction = errors.New("invalid bulk action")
+
+	// ErrInvalidComment is returned when a comment on a decision is too long
+	ErrInvalidComment = errors.New("invalid comment")
+
+	// ErrTaskNotDeletable is returned when deleting a task that hasn't finished yet
+	ErrTaskNotDeletable = errors.New("task is not finished and can't be deleted")
 )
\ No newline at end of file
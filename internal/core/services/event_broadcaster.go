@@ -0,0 +1,74 @@
+package services
+
+import (
+	"sync"
+)
+
+// DefaultEventSubscriberBuffer is how many events a subscriber can fall behind before it starts missing them
+const DefaultEventSubscriberBuffer = 16
+
+// EventBroadcaster fans events out to every current subscriber
+// Publishing never blocks: a subscriber whose buffer is full misses the event instead of stalling the publisher.
+type EventBroadcaster[T any] struct {
+	subscribers map[chan T]struct{}
+	bufferSize  int
+	dropped     int
+	mutex       sync.Mutex
+}
+
+// NewEventBroadcaster creates a broadcaster whose subscribers each buffer up to bufferSize events
+func NewEventBroadcaster[T any](bufferSize int) *EventBroadcaster[T] {
+	return &EventBroadcaster[T]{
+		subscribers: make(map[chan T]struct{}),
+		bufferSize:  bufferSize,
+	}
+}
+
+// Subscribe returns a channel receiving every event published from now on, and a function that ends
+// the subscription and closes the channel. The function is safe to call more than once.
+func (b *EventBroadcaster[T]) Subscribe() (<-chan T, func()) {
+	events := make(chan T, b.bufferSize)
+
+	b.mutex.Lock()
+	b.subscribers[events] = struct{}{}
+	b.mutex.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mutex.Lock()
+			delete(b.subscribers, events)
+			b.mutex.Unlock()
+			close(events)
+		})
+	}
+	return events, unsubscribe
+}
+
+// Publish sends an event to every subscriber that has room for it
+func (b *EventBroadcaster[T]) Publish(event T) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for events := range b.subscribers {
+		select {
+		case events <- event:
+		default:
+			b.dropped++
+		}
+	}
+}
+
+// Subscribers returns the number of current subscribers
+func (b *EventBroadcaster[T]) Subscribers() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return len(b.subscribers)
+}
+
+// Dropped returns how many deliveries were skipped because a subscriber's buffer was full
+func (b *EventBroadcaster[T]) Dropped() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.dropped
+}
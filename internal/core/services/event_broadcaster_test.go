@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+)
+
+func TestEventBroadcaster_FanOut(t *testing.T) {
+	broadcaster := NewEventBroadcaster[string](4)
+	first, unsubscribeFirst := broadcaster.Subscribe()
+	second, unsubscribeSecond := broadcaster.Subscribe()
+	defer unsubscribeSecond()
+
+	if got := broadcaster.Subscribers(); got != 2 {
+		t.Fatalf("Expected 2 subscribers, got %d", got)
+	}
+
+	broadcaster.Publish("created")
+	for name, events := range map[string]<-chan string{"first": first, "second": second} {
+		select {
+		case event := <-events:
+			if event != "created" {
+				t.Errorf("%s subscriber: expected %q, got %q", name, "created", event)
+			}
+		default:
+			t.Errorf("%s subscriber: expected an event", name)
+		}
+	}
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+	if _, open := <-first; open {
+		t.Error("Expected the channel to be closed after unsubscribing")
+	}
+	if got := broadcaster.Subscribers(); got != 1 {
+		t.Errorf("Expected 1 subscriber after unsubscribing, got %d", got)
+	}
+
+	broadcaster.Publish("updated")
+	if event := <-second; event != "updated" {
+		t.Errorf("Expected %q, got %q", "updated", event)
+	}
+}
+
+func TestEventBroadcaster_SlowSubscriberDoesNotBlock(t *testing.T) {
+	broadcaster := NewEventBroadcaster[int](2)
+	events, unsubscribe := broadcaster.Subscribe()
+	defer unsubscribe()
+
+	for i := 0; i < 5; i++ {
+		broadcaster.Publish(i)
+	}
+
+	if got := broadcaster.Dropped(); got != 3 {
+		t.Errorf("Expected 3 dropped events, got %d", got)
+	}
+	for _, expected := range []int{0, 1} {
+		if event := <-events; event != expected {
+			t.Errorf("Expected buffered event %d, got %d", expected, event)
+		}
+	}
+}
+
+func TestEventBroadcaster_PublishWithoutSubscribers(t *testing.T) {
+	broadcaster := NewEventBroadcaster[string](DefaultEventSubscriberBuffer)
+	broadcaster.Publish("nobody listening")
+	if got := broadcaster.Dropped(); got != 0 {
+		t.Errorf("Expected no dropped events without subscribers, got %d", got)
+	}
+}
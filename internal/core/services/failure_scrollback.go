@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+const (
+	// DefaultScrollbackLines is how many lines of terminal history a scrollback capture includes by default
+	DefaultScrollbackLines = 100
+
+	// MaxScrollbackLines is the most lines of terminal history a single capture may request
+	MaxScrollbackLines = 10000
+
+	// historyActionScrollbackCaptured is the history action recorded when a failed tool call's terminal is captured
+	historyActionScrollbackCaptured = "scrollback_captured"
+
+	// terminalScrollbackKey holds the captured terminal text in the history entry's data
+	terminalScrollbackKey = "terminal_scrollback"
+)
+
+// FailureScrollbackCapturer captures the terminal of the Claude Code tmux session behind a failed tool call
+type FailureScrollbackCapturer struct {
+	tmuxController     ports.TMuxController
+	sessionNamePattern *regexp.Regexp
+	lines              int
+}
+
+// NewFailureScrollbackCapturer creates a capturer for tmux sessions whose names match namePattern
+func NewFailureScrollbackCapturer(tmuxController ports.TMuxController, namePattern string) (*FailureScrollbackCapturer, error) {
+	pattern, err := regexp.Compile(namePattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Claude session name pattern %q: %w", namePattern, err)
+	}
+
+	return &FailureScrollbackCapturer{
+		tmuxController:     tmuxController,
+		sessionNamePattern: pattern,
+		lines:              DefaultScrollbackLines,
+	}, nil
+}
+
+// Capture returns the tmux session name and scrollback for a hook reporting a failed tool call
+// Hooks don't say which tmux session they came from, so nothing is captured (and sessionName is "")
+// unless exactly one session matches the pattern; the same goes for hooks that didn't fail.
+func (c *FailureScrollbackCapturer) Capture(ctx context.Context, hookData *domain.HookData) (sessionName, scrollback string, err error) {
+	if !hookData.ToolCallFailed() {
+		return "", "", nil
+	}
+
+	sessions, err := c.tmuxController.ListSessions(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list tmux sessions: %w", err)
+	}
+
+	var matches []string
+	for _, session := range sessions {
+		if c.sessionNamePattern.MatchString(session.Name) {
+			matches = append(matches, session.Name)
+		}
+	}
+	if len(matches) != 1 {
+		if len(matches) > 1 {
+			log.Printf("Skipping scrollback capture: %d tmux sessions match %s", len(matches), c.sessionNamePattern)
+		}
+		return "", "", nil
+	}
+
+	scrollback, err = c.tmuxController.CapturePaneScrollback(ctx, matches[0], c.lines)
+	if err != nil {
+		return "", "", err
+	}
+	return matches[0], scrollback, nil
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dan/claude-control/internal/adapters/mock"
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// cannedScrollback is what `tmux capture-pane -p -J` prints for a failed build
+const cannedScrollback = `$ go build ./...
+# github.com/dan/claude-control/internal/core/services
+internal/core/services/task_service.go:17:24: undefined: ports.TaskRepository
+$ `
+
+func failedToolCall(response *domain.ToolResponse) *domain.HookData {
+	return &domain.HookData{
+		Type: domain.HookTypePostToolUse,
+		Data: &domain.PostToolUseHookData{
+			ToolName:     "Bash",
+			ToolInput:    &domain.ToolInput{Command: "go build ./..."},
+			ToolResponse: response,
+		},
+	}
+}
+
+func TestFailureScrollbackCapturer_Capture(t *testing.T) {
+	tests := []struct {
+		name            string
+		hookData        *domain.HookData
+		sessions        []ports.TMuxSession
+		expectedSession string
+		expectCapture   bool
+	}{
+		{
+			name:            "stderr",
+			hookData:        failedToolCall(&domain.ToolResponse{Stderr: "undefined: ports.TaskRepository"}),
+			sessions:        []ports.TMuxSession{{Name: "claude-haiper"}, {Name: "scratch"}},
+			expectedSession: "claude-haiper",
+			expectCapture:   true,
+		},
+		{
+			name:            "interrupted",
+			hookData:        failedToolCall(&domain.ToolResponse{Interrupted: true}),
+			sessions:        []ports.TMuxSession{{Name: "claude-haiper"}},
+			expectedSession: "claude-haiper",
+			expectCapture:   true,
+		},
+		{
+			name:     "successful tool call",
+			hookData: failedToolCall(&domain.ToolResponse{Stdout: "ok", Success: true}),
+			sessions: []ports.TMuxSession{{Name: "claude-haiper"}},
+		},
+		{
+			name:     "no matching session",
+			hookData: failedToolCall(&domain.ToolResponse{Stderr: "boom"}),
+			sessions: []ports.TMuxSession{{Name: "scratch"}},
+		},
+		{
+			name:     "ambiguous sessions",
+			hookData: failedToolCall(&domain.ToolResponse{Stderr: "boom"}),
+			sessions: []ports.TMuxSession{{Name: "claude-haiper"}, {Name: "claude-other"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmuxController := mock.NewMockTMuxController()
+			tmuxController.SetResponse("ListSessions", tt.sessions, nil)
+			tmuxController.SetResponse("CapturePaneScrollback", cannedScrollback, nil)
+
+			capturer, err := NewFailureScrollbackCapturer(tmuxController, "^claude")
+			if err != nil {
+				t.Fatalf("Failed to create capturer: %v", err)
+			}
+
+			sessionName, scrollback, err := capturer.Capture(context.Background(), tt.hookData)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if sessionName != tt.expectedSession {
+				t.Errorf("Expected session %q, got %q", tt.expectedSession, sessionName)
+			}
+
+			var captures []mock.TMuxCall
+			for _, call := range tmuxController.Calls() {
+				if call.Method == "CapturePaneScrollback" {
+					captures = append(captures, call)
+				}
+			}
+			if !tt.expectCapture {
+				if len(captures) != 0 || scrollback != "" {
+					t.Errorf("Expected no capture, got %+v", captures)
+				}
+				return
+			}
+
+			expectedCall := mock.TMuxCall{Method: "CapturePaneScrollback", SessionName: tt.expectedSession, Keys: "100"}
+			if len(captures) != 1 || captures[0] != expectedCall {
+				t.Errorf("Expected one capture %+v, got %+v", expectedCall, captures)
+			}
+			if scrollback != cannedScrollback {
+				t.Errorf("Expected the canned scrollback, got %q", scrollback)
+			}
+		})
+	}
+}
+
+func TestFailureScrollbackCapturer_CaptureError(t *testing.T) {
+	tmuxController := mock.NewMockTMuxController()
+	tmuxController.SetResponse("ListSessions", []ports.TMuxSession{{Name: "claude-haiper"}}, nil)
+	captureErr := errors.New("can't find pane")
+	tmuxController.SetResponse("CapturePaneScrollback", nil, captureErr)
+
+	capturer, err := NewFailureScrollbackCapturer(tmuxController, "^claude")
+	if err != nil {
+		t.Fatalf("Failed to create capturer: %v", err)
+	}
+
+	if _, _, err := capturer.Capture(context.Background(), failedToolCall(&domain.ToolResponse{Stderr: "boom"})); !errors.Is(err, captureErr) {
+		t.Errorf("Expected the capture error, got %v", err)
+	}
+}
+
+func TestNewFailureScrollbackCapturer_InvalidPattern(t *testing.T) {
+	if _, err := NewFailureScrollbackCapturer(mock.NewMockTMuxController(), "("); err == nil {
+		t.Error("Expected an invalid pattern to be rejected")
+	}
+}
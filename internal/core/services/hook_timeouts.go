@@ -0,0 +1,31 @@
+package services
+
+import (
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+// DefaultDecisionTimeout is how long a blocking hook waits for the user when its hook type has no timeout of its own
+const DefaultDecisionTimeout = 5 * time.Minute
+
+// hookTimeout returns the hook type's configured decision timeout, or DefaultDecisionTimeout
+// Zero and negative timeouts count as unset.
+func hookTimeout(timeouts map[domain.HookType]time.Duration, hookType domain.HookType) time.Duration {
+	if timeout, ok := timeouts[hookType]; ok && timeout > 0 {
+		return timeout
+	}
+	return DefaultDecisionTimeout
+}
+
+// LongestHookTimeout returns the longest decision wait any hook type can have
+// Request deadlines for blocking webhooks must outlast it.
+func LongestHookTimeout(timeouts map[domain.HookType]time.Duration) time.Duration {
+	longest := DefaultDecisionTimeout
+	for _, timeout := range timeouts {
+		if timeout > longest {
+			longest = timeout
+		}
+	}
+	return longest
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+func TestHookTimeout(t *testing.T) {
+	timeouts := map[domain.HookType]time.Duration{
+		domain.HookTypePreToolUse:       10 * time.Minute,
+		domain.HookTypeUserPromptSubmit: 2 * time.Minute,
+		domain.HookTypeNotification:     0,
+	}
+
+	tests := []struct {
+		name     string
+		hookType domain.HookType
+		expected time.Duration
+	}{
+		{"Longer than the default", domain.HookTypePreToolUse, 10 * time.Minute},
+		{"Shorter than the default", domain.HookTypeUserPromptSubmit, 2 * time.Minute},
+		{"Zero counts as unset", domain.HookTypeNotification, DefaultDecisionTimeout},
+		{"Not configured", domain.HookTypeStop, DefaultDecisionTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hookTimeout(timeouts, tt.hookType); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if got := hookTimeout(nil, domain.HookTypePreToolUse); got != DefaultDecisionTimeout {
+		t.Errorf("Expected the default without any timeouts, got %v", got)
+	}
+}
+
+func TestLongestHookTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts map[domain.HookType]time.Duration
+		expected time.Duration
+	}{
+		{"None configured", nil, DefaultDecisionTimeout},
+		{"Only shorter timeouts", map[domain.HookType]time.Duration{domain.HookTypeUserPromptSubmit: time.Minute}, DefaultDecisionTimeout},
+		{"Longer timeout", map[domain.HookType]time.Duration{
+			domain.HookTypePreToolUse:       10 * time.Minute,
+			domain.HookTypeUserPromptSubmit: 2 * time.Minute,
+		}, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LongestHookTimeout(tt.timeouts); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
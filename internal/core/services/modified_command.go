@@ -0,0 +1,36 @@
+package services
+
+import (
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+const (
+	// modifiedCommandKey holds the command the user substituted for the original in a task's response data
+	modifiedCommandKey = "modified_command"
+
+	// originalCommandKey records the command Claude Code asked to run alongside an override
+	originalCommandKey = "original_command"
+
+	// historyActionCommandModified is the history action recorded when a command override is set or cleared
+	historyActionCommandModified = "command_modified"
+)
+
+// applyCommandOverride prepares an action's response data for a command override
+// On approval, an override stored beforehand (e.g. via PATCH /api/tasks/{taskId}) is carried over unless the
+// approval brings its own, and the original command is recorded next to it. Other actions drop the override.
+func applyCommandOverride(action domain.ActionType, hookData *domain.HookData, stored, responseData map[string]interface{}) {
+	if action != domain.ActionTypeApprove {
+		delete(responseData, modifiedCommandKey)
+		return
+	}
+
+	if override, _ := responseData[modifiedCommandKey].(string); override == "" {
+		storedOverride, _ := stored[modifiedCommandKey].(string)
+		if storedOverride == "" {
+			return
+		}
+		responseData[modifiedCommandKey] = storedOverride
+	}
+
+	responseData[originalCommandKey] = hookData.GetCommand()
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+func TestApplyCommandOverride(t *testing.T) {
+	hookData := &domain.HookData{
+		Type: domain.HookTypePreToolUse,
+		Data: &domain.PreToolUseHookData{ToolName: "Bash", ToolInput: &domain.ToolInput{Command: "rm -rf build"}},
+	}
+
+	tests := []struct {
+		name             string
+		action           domain.ActionType
+		stored           map[string]interface{}
+		responseData     map[string]interface{}
+		expectedOverride string
+		expectedOriginal string
+	}{
+		{
+			name:             "Override sent with approval",
+			action:           domain.ActionTypeApprove,
+			responseData:     map[string]interface{}{"modified_command": "rm -rf build/tmp"},
+			expectedOverride: "rm -rf build/tmp",
+			expectedOriginal: "rm -rf build",
+		},
+		{
+			name:             "Stored override carried over",
+			action:           domain.ActionTypeApprove,
+			stored:           map[string]interface{}{"modified_command": "rm -rf build/cache"},
+			responseData:     map[string]interface{}{"comment": "ok"},
+			expectedOverride: "rm -rf build/cache",
+			expectedOriginal: "rm -rf build",
+		},
+		{
+			name:             "Approval override wins over stored",
+			action:           domain.ActionTypeApprove,
+			stored:           map[string]interface{}{"modified_command": "rm -rf build/cache"},
+			responseData:     map[string]interface{}{"modified_command": "ls build"},
+			expectedOverride: "ls build",
+			expectedOriginal: "rm -rf build",
+		},
+		{
+			name:         "Approval without override",
+			action:       domain.ActionTypeApprove,
+			responseData: map[string]interface{}{},
+		},
+		{
+			name:         "Rejection drops override",
+			action:       domain.ActionTypeReject,
+			stored:       map[string]interface{}{"modified_command": "rm -rf build/cache"},
+			responseData: map[string]interface{}{"modified_command": "ls build"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applyCommandOverride(tt.action, hookData, tt.stored, tt.responseData)
+
+			override, _ := tt.responseData[modifiedCommandKey].(string)
+			if override != tt.expectedOverride {
+				t.Errorf("Expected override %q, got %q", tt.expectedOverride, override)
+			}
+			original, _ := tt.responseData[originalCommandKey].(string)
+			if original != tt.expectedOriginal {
+				t.Errorf("Expected original %q, got %q", tt.expectedOriginal, original)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// historyActionAnnotated is the history action recorded when PostToolUse processors annotate a task
+const historyActionAnnotated = "annotated"
+
+// RunPostToolUseProcessors runs every processor over a PostToolUse hook and collects their annotations
+// A failing processor is reported in the returned errors and doesn't stop the ones after it.
+func RunPostToolUseProcessors(ctx context.Context, processors []ports.PostToolUseProcessor, hookData *domain.PostToolUseHookData) ([]*domain.PostToolUseAnnotation, []error) {
+	var annotations []*domain.PostToolUseAnnotation
+	var errs []error
+	for i, processor := range processors {
+		annotation, err := processor.Process(ctx, hookData)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("post-tool-use processor %d (%T) failed: %w", i, processor, err))
+			continue
+		}
+		if annotation != nil {
+			annotations = append(annotations, annotation)
+		}
+	}
+	return annotations, errs
+}
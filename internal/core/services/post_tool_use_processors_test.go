@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// fakeProcessor returns a fixed annotation or error and counts its calls
+type fakeProcessor struct {
+	annotation *domain.PostToolUseAnnotation
+	err        error
+	calls      int
+}
+
+func (p *fakeProcessor) Process(ctx context.Context, hookData *domain.PostToolUseHookData) (*domain.PostToolUseAnnotation, error) {
+	p.calls++
+	return p.annotation, p.err
+}
+
+func TestRunPostToolUseProcessors(t *testing.T) {
+	lint := &domain.PostToolUseAnnotation{Processor: "lint", Severity: domain.AnnotationSeverityWarning}
+	scan := &domain.PostToolUseAnnotation{Processor: "scan", Severity: domain.AnnotationSeverityError}
+
+	tests := []struct {
+		name               string
+		processors         []*fakeProcessor
+		expectedProcessors []string
+		expectedErrors     int
+	}{
+		{"No processors", nil, nil, 0},
+		{"Annotations kept in order", []*fakeProcessor{{annotation: lint}, {annotation: scan}}, []string{"lint", "scan"}, 0},
+		{"Nil annotations skipped", []*fakeProcessor{{}, {annotation: scan}, {}}, []string{"scan"}, 0},
+		{
+			"Failure doesn't stop the chain",
+			[]*fakeProcessor{{err: errors.New("linter crashed")}, {annotation: scan}},
+			[]string{"scan"},
+			1,
+		},
+	}
+
+	hookData := &domain.PostToolUseHookData{ToolName: "Bash", ToolResponse: &domain.ToolResponse{Stderr: "boom"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processors := make([]ports.PostToolUseProcessor, len(tt.processors))
+			for i, processor := range tt.processors {
+				processors[i] = processor
+			}
+
+			annotations, errs := RunPostToolUseProcessors(context.Background(), processors, hookData)
+			if len(errs) != tt.expectedErrors {
+				t.Errorf("Expected %d errors, got %v", tt.expectedErrors, errs)
+			}
+			if len(annotations) != len(tt.expectedProcessors) {
+				t.Fatalf("Expected %d annotations, got %d", len(tt.expectedProcessors), len(annotations))
+			}
+			for i, annotation := range annotations {
+				if annotation.Processor != tt.expectedProcessors[i] {
+					t.Errorf("Annotation %d: expected processor %q, got %q", i, tt.expectedProcessors[i], annotation.Processor)
+				}
+			}
+			for i, processor := range tt.processors {
+				if processor.calls != 1 {
+					t.Errorf("Processor %d: expected 1 call, got %d", i, processor.calls)
+				}
+			}
+		})
+	}
+}
+
+func TestRunPostToolUseProcessors_WithAnalyzer(t *testing.T) {
+	processors := []ports.PostToolUseProcessor{NewCommandOutputAnalyzer(), &fakeProcessor{annotation: &domain.PostToolUseAnnotation{Processor: "lint"}}}
+	hookData := &domain.PostToolUseHookData{ToolName: "Bash", ToolResponse: &domain.ToolResponse{Stderr: "fatal: not a git repository"}}
+
+	annotations, errs := RunPostToolUseProcessors(context.Background(), processors, hookData)
+	if len(errs) != 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	if len(annotations) != 2 || annotations[0].Severity != domain.AnnotationSeverityError {
+		t.Errorf("Expected the analyzer's error annotation followed by the linter's, got %+v", annotations)
+	}
+}
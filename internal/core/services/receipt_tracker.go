@@ -0,0 +1,96 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReceiptTimeout is how long Claude Code has to acknowledge a hook response before it counts as unacknowledged
+const ReceiptTimeout = 5 * time.Minute
+
+// historyActionAcknowledged is the history action recorded when Claude Code acknowledges a hook response
+const historyActionAcknowledged = "acknowledged"
+
+// ReceiptStats counts hook response receipts since the server started
+type ReceiptStats struct {
+	ReceiptsTotal       int `json:"claude_control_receipts_total"`
+	UnacknowledgedTotal int `json:"claude_control_unacknowledged_total"`
+	Outstanding         int `json:"outstanding"` // Issued and still within ReceiptTimeout
+}
+
+// issuedReceipt is a receipt token waiting for Claude Code to acknowledge it
+type issuedReceipt struct {
+	taskID   uuid.UUID
+	issuedAt time.Time
+}
+
+// ReceiptTracker issues receipt tokens for hook responses and matches acknowledgements to them
+// Tokens live in memory only; a restart drops outstanding ones without counting them as unacknowledged.
+type ReceiptTracker struct {
+	timeout        time.Duration
+	outstanding    map[string]issuedReceipt
+	acknowledged   int
+	unacknowledged int
+	mutex          sync.Mutex
+}
+
+// NewReceiptTracker creates a new receipt tracker
+func NewReceiptTracker(timeout time.Duration) *ReceiptTracker {
+	return &ReceiptTracker{
+		timeout:     timeout,
+		outstanding: make(map[string]issuedReceipt),
+	}
+}
+
+// Issue creates a receipt token for a response to the task
+func (t *ReceiptTracker) Issue(taskID uuid.UUID, now time.Time) string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.expire(now)
+	token := uuid.New().String()
+	t.outstanding[token] = issuedReceipt{taskID: taskID, issuedAt: now}
+	return token
+}
+
+// Acknowledge consumes a token, returning the task it was issued for and how long the acknowledgement took
+// Each token can be acknowledged once; unknown and expired tokens return false.
+func (t *ReceiptTracker) Acknowledge(token string, now time.Time) (uuid.UUID, time.Duration, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.expire(now)
+	receipt, exists := t.outstanding[token]
+	if !exists {
+		return uuid.Nil, 0, false
+	}
+
+	delete(t.outstanding, token)
+	t.acknowledged++
+	return receipt.taskID, now.Sub(receipt.issuedAt), true
+}
+
+// Stats returns the receipt counts, first expiring tokens older than the timeout
+func (t *ReceiptTracker) Stats(now time.Time) ReceiptStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.expire(now)
+	return ReceiptStats{
+		ReceiptsTotal:       t.acknowledged,
+		UnacknowledgedTotal: t.unacknowledged,
+		Outstanding:         len(t.outstanding),
+	}
+}
+
+// expire drops tokens older than the timeout and counts them as unacknowledged; the mutex must be held
+func (t *ReceiptTracker) expire(now time.Time) {
+	for token, receipt := range t.outstanding {
+		if now.Sub(receipt.issuedAt) >= t.timeout {
+			delete(t.outstanding, token)
+			t.unacknowledged++
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestReceiptTracker(t *testing.T) {
+	tracker := NewReceiptTracker(ReceiptTimeout)
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	taskID := uuid.New()
+
+	token := tracker.Issue(taskID, now)
+	if token == "" {
+		t.Fatal("Expected a receipt token")
+	}
+
+	acknowledgedTask, latency, ok := tracker.Acknowledge(token, now.Add(250*time.Millisecond))
+	if !ok {
+		t.Fatal("Expected the issued token to be acknowledged")
+	}
+	if acknowledgedTask != taskID {
+		t.Errorf("Expected task %s, got %s", taskID, acknowledgedTask)
+	}
+	if latency != 250*time.Millisecond {
+		t.Errorf("Expected latency 250ms, got %v", latency)
+	}
+
+	if _, _, ok := tracker.Acknowledge(token, now.Add(time.Second)); ok {
+		t.Error("Expected a token to be accepted only once")
+	}
+	if _, _, ok := tracker.Acknowledge(uuid.New().String(), now); ok {
+		t.Error("Expected an unknown token to be rejected")
+	}
+
+	expiring := tracker.Issue(uuid.New(), now)
+	tracker.Issue(uuid.New(), now.Add(ReceiptTimeout/2))
+
+	if _, _, ok := tracker.Acknowledge(expiring, now.Add(ReceiptTimeout)); ok {
+		t.Error("Expected a token past the timeout to be rejected")
+	}
+
+	stats := tracker.Stats(now.Add(ReceiptTimeout))
+	expected := ReceiptStats{ReceiptsTotal: 1, UnacknowledgedTotal: 1, Outstanding: 1}
+	if stats != expected {
+		t.Errorf("Expected stats %+v, got %+v", expected, stats)
+	}
+}
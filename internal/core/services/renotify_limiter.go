@@ -0,0 +1,55 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RenotifyInterval is how long a task must wait between manual notification re-sends
+const RenotifyInterval = time.Minute
+
+// historyActionRenotified is the history action recorded when a task's notification is re-sent on request
+const historyActionRenotified = "re-notified"
+
+// RenotifyLimiter allows one notification re-send per task per interval
+type RenotifyLimiter struct {
+	interval time.Duration
+	lastSent map[uuid.UUID]time.Time
+	mutex    sync.Mutex
+}
+
+// NewRenotifyLimiter creates a new per-task re-send limiter
+func NewRenotifyLimiter(interval time.Duration) *RenotifyLimiter {
+	return &RenotifyLimiter{
+		interval: interval,
+		lastSent: make(map[uuid.UUID]time.Time),
+	}
+}
+
+// Allow reserves a re-send for the task, or returns how long until the next one is allowed
+// Entries older than the interval are dropped on each call so the map stays small.
+func (l *RenotifyLimiter) Allow(taskID uuid.UUID, now time.Time) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for id, sentAt := range l.lastSent {
+		if now.Sub(sentAt) >= l.interval {
+			delete(l.lastSent, id)
+		}
+	}
+
+	if sentAt, exists := l.lastSent[taskID]; exists {
+		return false, l.interval - now.Sub(sentAt)
+	}
+	l.lastSent[taskID] = now
+	return true, 0
+}
+
+// Release gives back a reservation, e.g. when the re-send failed and may be retried straight away
+func (l *RenotifyLimiter) Release(taskID uuid.UUID) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.lastSent, taskID)
+}
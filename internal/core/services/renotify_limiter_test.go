@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRenotifyLimiter_Allow(t *testing.T) {
+	limiter := NewRenotifyLimiter(RenotifyInterval)
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	taskID := uuid.New()
+	otherTaskID := uuid.New()
+
+	if allowed, _ := limiter.Allow(taskID, now); !allowed {
+		t.Fatal("Expected the first re-send to be allowed")
+	}
+
+	allowed, retryAfter := limiter.Allow(taskID, now.Add(20*time.Second))
+	if allowed {
+		t.Fatal("Expected a second re-send within the interval to be refused")
+	}
+	if retryAfter != 40*time.Second {
+		t.Errorf("Expected to retry in 40s, got %s", retryAfter)
+	}
+
+	if allowed, _ := limiter.Allow(otherTaskID, now.Add(20*time.Second)); !allowed {
+		t.Error("Expected another task's re-send to be allowed")
+	}
+
+	if allowed, _ := limiter.Allow(taskID, now.Add(RenotifyInterval)); !allowed {
+		t.Error("Expected a re-send to be allowed once the interval has passed")
+	}
+}
+
+func TestRenotifyLimiter_Release(t *testing.T) {
+	limiter := NewRenotifyLimiter(RenotifyInterval)
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	taskID := uuid.New()
+
+	limiter.Allow(taskID, now)
+	limiter.Release(taskID)
+
+	if allowed, _ := limiter.Allow(taskID, now.Add(time.Second)); !allowed {
+		t.Error("Expected a released re-send to be retried straight away")
+	}
+}
+
+func TestRenotifyLimiter_DropsExpiredEntries(t *testing.T) {
+	limiter := NewRenotifyLimiter(RenotifyInterval)
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		limiter.Allow(uuid.New(), now)
+	}
+	limiter.Allow(uuid.New(), now.Add(RenotifyInterval))
+
+	if len(limiter.lastSent) != 1 {
+		t.Errorf("Expected expired entries to be dropped, %d remain", len(limiter.lastSent))
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Default cleanup schedule: once a day, keeping 30 days of history
+const (
+	DefaultCleanupInterval = 24 * time.Hour
+	DefaultRetentionDays   = 30
+)
+
+// TaskCleaner removes task data older than a retention period
+type TaskCleaner interface {
+	CleanupOldTasks(ctx context.Context, retentionDays int) (int64, error)
+}
+
+// Scheduler periodically cleans up old task data in the background
+type Scheduler struct {
+	cleaner       TaskCleaner
+	interval      time.Duration
+	retentionDays int
+}
+
+// NewScheduler creates a scheduler that cleans up entries older than retentionDays every interval
+func NewScheduler(cleaner TaskCleaner, interval time.Duration, retentionDays int) *Scheduler {
+	return &Scheduler{
+		cleaner:       cleaner,
+		interval:      interval,
+		retentionDays: retentionDays,
+	}
+}
+
+// Run cleans up on every tick until ctx is done
+// The first cleanup happens one interval after Run starts, not immediately, so restarts don't trigger deletes.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.cleanup(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// cleanup runs one cleanup and logs the outcome
+func (s *Scheduler) cleanup(ctx context.Context) {
+	deleted, err := s.cleaner.CleanupOldTasks(ctx, s.retentionDays)
+	if err != nil {
+		log.Printf("Warning: Task cleanup failed: %v", err)
+		return
+	}
+	log.Printf("Cleaned up %d entries older than %d days", deleted, s.retentionDays)
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeTaskCleaner reports each cleanup's retention period on a channel
+type fakeTaskCleaner struct {
+	calls chan int
+	err   error
+}
+
+func (c *fakeTaskCleaner) CleanupOldTasks(ctx context.Context, retentionDays int) (int64, error) {
+	c.calls <- retentionDays
+	return 3, c.err
+}
+
+func TestScheduler_Run(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"cleanup succeeds", nil},
+		{"cleanup fails", errors.New("database unavailable")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleaner := &fakeTaskCleaner{calls: make(chan int, 10), err: tt.err}
+			scheduler := NewScheduler(cleaner, 10*time.Millisecond, 45)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			stopped := make(chan struct{})
+			go func() {
+				scheduler.Run(ctx)
+				close(stopped)
+			}()
+
+			// A failed run must not stop later ones
+			for i := 0; i < 2; i++ {
+				select {
+				case days := <-cleaner.calls:
+					if days != 45 {
+						t.Errorf("Expected cleanup with 45 retention days, got %d", days)
+					}
+				case <-time.After(time.Second):
+					t.Fatalf("Expected cleanup run %d to fire", i+1)
+				}
+			}
+
+			cancel()
+			select {
+			case <-stopped:
+			case <-time.After(time.Second):
+				t.Fatal("Expected Run to return once the context is cancelled")
+			}
+		})
+	}
+}
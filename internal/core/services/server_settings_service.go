@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// SettingHooksDisabled is the server_settings name for the hooks-disabled maintenance mode
+const SettingHooksDisabled = "hooks_disabled"
+
+var _ ports.ServerSettingsService = (*ServerSettingsService)(nil)
+
+// ServerSettingsService caches persisted server settings in memory
+// Webhook handlers read settings on every request, so reads never hit the repository.
+type ServerSettingsService struct {
+	repo          ports.SettingsRepository
+	hooksDisabled atomic.Bool
+}
+
+// NewServerSettingsService creates a new server settings service
+func NewServerSettingsService(repo ports.SettingsRepository) *ServerSettingsService {
+	return &ServerSettingsService{repo: repo}
+}
+
+// Load reads the persisted settings into memory
+func (s *ServerSettingsService) Load(ctx context.Context) error {
+	value, found, err := s.repo.GetSetting(ctx, SettingHooksDisabled)
+	if err != nil {
+		return fmt.Errorf("failed to load %s setting: %w", SettingHooksDisabled, err)
+	}
+	if !found {
+		s.hooksDisabled.Store(false)
+		return nil
+	}
+
+	disabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s setting %q: %w", SettingHooksDisabled, value, err)
+	}
+	s.hooksDisabled.Store(disabled)
+	return nil
+}
+
+// HooksDisabled reports whether hook processing is disabled
+func (s *ServerSettingsService) HooksDisabled() bool {
+	return s.hooksDisabled.Load()
+}
+
+// SetHooksDisabled enables or disables hook processing and persists the new state
+// The in-memory state is only changed once the setting has been stored.
+func (s *ServerSettingsService) SetHooksDisabled(ctx context.Context, disabled bool) error {
+	if err := s.repo.SetSetting(ctx, SettingHooksDisabled, strconv.FormatBool(disabled)); err != nil {
+		return fmt.Errorf("failed to save %s setting: %w", SettingHooksDisabled, err)
+	}
+	s.hooksDisabled.Store(disabled)
+	return nil
+}
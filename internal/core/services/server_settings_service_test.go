@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeSettingsRepository stores settings in a map
+type fakeSettingsRepository struct {
+	settings map[string]string
+	err      error
+}
+
+func (f *fakeSettingsRepository) GetSetting(ctx context.Context, name string) (string, bool, error) {
+	if f.err != nil {
+		return "", false, f.err
+	}
+	value, found := f.settings[name]
+	return value, found, nil
+}
+
+func (f *fakeSettingsRepository) SetSetting(ctx context.Context, name string, value string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.settings[name] = value
+	return nil
+}
+
+func TestServerSettingsService_ToggleHooksDisabled(t *testing.T) {
+	repo := &fakeSettingsRepository{settings: map[string]string{}}
+	service := NewServerSettingsService(repo)
+
+	if err := service.Load(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if service.HooksDisabled() {
+		t.Error("Expected hooks to be enabled when the setting has never been stored")
+	}
+
+	if err := service.SetHooksDisabled(context.Background(), true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !service.HooksDisabled() {
+		t.Error("Expected hooks to be disabled")
+	}
+	if repo.settings[SettingHooksDisabled] != "true" {
+		t.Errorf("Expected persisted value true, got %q", repo.settings[SettingHooksDisabled])
+	}
+
+	// A restarted server picks up the persisted state
+	restarted := NewServerSettingsService(repo)
+	if err := restarted.Load(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !restarted.HooksDisabled() {
+		t.Error("Expected hooks-disabled state to survive a reload")
+	}
+
+	if err := restarted.SetHooksDisabled(context.Background(), false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if restarted.HooksDisabled() || repo.settings[SettingHooksDisabled] != "false" {
+		t.Error("Expected hooks to be re-enabled and persisted")
+	}
+}
+
+func TestServerSettingsService_SetHooksDisabledFailure(t *testing.T) {
+	repo := &fakeSettingsRepository{settings: map[string]string{}, err: errors.New("database unavailable")}
+	service := NewServerSettingsService(repo)
+
+	if err := service.SetHooksDisabled(context.Background(), true); err == nil {
+		t.Fatal("Expected error when the setting can't be saved")
+	}
+	if service.HooksDisabled() {
+		t.Error("Expected in-memory state to be unchanged when saving fails")
+	}
+}
+
+func TestServerSettingsService_LoadInvalidValue(t *testing.T) {
+	repo := &fakeSettingsRepository{settings: map[string]string{SettingHooksDisabled: "maybe"}}
+	service := NewServerSettingsService(repo)
+
+	if err := service.Load(context.Background()); err == nil {
+		t.Error("Expected error for an invalid persisted value")
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// SessionSemaphore lets one blocking webhook per session wait on the user at a time
+// Each session has a single-slot channel; holding the slot means the session's decision dialog is the one shown.
+// Slots are kept for the life of the process, one small channel per session seen.
+type SessionSemaphore struct {
+	slots sync.Map // session ID -> chan struct{}
+	held  atomic.Int64
+}
+
+// NewSessionSemaphore creates a new per-session semaphore
+func NewSessionSemaphore() *SessionSemaphore {
+	return &SessionSemaphore{}
+}
+
+// Acquire waits until no other webhook from the session holds its slot, returning a func that releases it
+// Webhooks without a session ID can't be grouped, so they never wait. The error is ctx's if it ends first.
+func (s *SessionSemaphore) Acquire(ctx context.Context, sessionID string) (func(), error) {
+	if sessionID == "" {
+		return func() {}, nil
+	}
+
+	slot, _ := s.slots.LoadOrStore(sessionID, make(chan struct{}, 1))
+	ch := slot.(chan struct{})
+
+	select {
+	case ch <- struct{}{}:
+		s.held.Add(1)
+		var once sync.Once
+		return func() {
+			once.Do(func() {
+				s.held.Add(-1)
+				<-ch
+			})
+		}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Held returns how many sessions hold their slot, i.e. have a blocking webhook waiting on the user
+func (s *SessionSemaphore) Held() int {
+	return int(s.held.Load())
+}
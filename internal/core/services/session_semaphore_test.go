@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/adapters/memory"
+	"github.com/dan/claude-control/internal/adapters/mock"
+	"github.com/dan/claude-control/internal/adapters/response"
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+func TestSessionSemaphore_SerializesSameSession(t *testing.T) {
+	semaphore := NewSessionSemaphore()
+	var waiting, overlaps, handled atomic.Int32
+
+	// Both webhooks run in parallel subtests; the group returns once both have finished
+	t.Run("webhooks", func(t *testing.T) {
+		for i := 1; i <= 2; i++ {
+			t.Run(fmt.Sprintf("PreToolUse %d", i), func(t *testing.T) {
+				t.Parallel()
+
+				release, err := semaphore.Acquire(context.Background(), "session-1")
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				defer release()
+
+				if waiting.Add(1) > 1 {
+					overlaps.Add(1)
+				}
+				time.Sleep(20 * time.Millisecond) // Waiting on the user
+				waiting.Add(-1)
+				handled.Add(1)
+			})
+		}
+	})
+
+	if handled.Load() != 2 {
+		t.Fatalf("Expected both webhooks to be handled, got %d", handled.Load())
+	}
+	if overlaps.Load() != 0 {
+		t.Error("Expected the session's webhooks to wait on the user one at a time")
+	}
+}
+
+func TestSessionSemaphore_Acquire(t *testing.T) {
+	semaphore := NewSessionSemaphore()
+	release, err := semaphore.Acquire(context.Background(), "session-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		sessionID string
+		expectErr bool
+	}{
+		{"Same session waits", "session-1", true},
+		{"Other session goes ahead", "session-2", false},
+		{"No session ID never waits", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			other, err := semaphore.Acquire(ctx, tt.sessionID)
+			if tt.expectErr {
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("Expected the context's deadline error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			other()
+		})
+	}
+
+	// Releasing twice must not free a slot someone else has since taken
+	release()
+	release()
+	if _, err := semaphore.Acquire(context.Background(), "session-1"); err != nil {
+		t.Fatalf("Expected the slot to be free once released, got %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := semaphore.Acquire(ctx, "session-1"); err == nil {
+		t.Error("Expected a second release not to free the slot again")
+	}
+}
+
+func TestSessionSemaphore_Held(t *testing.T) {
+	semaphore := NewSessionSemaphore()
+	first, _ := semaphore.Acquire(context.Background(), "session-1")
+	second, _ := semaphore.Acquire(context.Background(), "session-2")
+	unnamed, _ := semaphore.Acquire(context.Background(), "")
+	if held := semaphore.Held(); held != 2 {
+		t.Errorf("Expected the two named sessions to hold slots, got %d", held)
+	}
+
+	first()
+	first()
+	unnamed()
+	if held := semaphore.Held(); held != 1 {
+		t.Errorf("Expected one slot held after releasing the first twice, got %d", held)
+	}
+	second()
+	if held := semaphore.Held(); held != 0 {
+		t.Errorf("Expected no slots held, got %d", held)
+	}
+}
+
+// sessionMetrics records the concurrent session counts it is given
+type sessionMetrics struct {
+	noopTaskMetrics
+	mutex  sync.Mutex
+	counts []int
+}
+
+func (m *sessionMetrics) ConcurrentSessions(count int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.counts = append(m.counts, count)
+}
+
+func TestTaskService_ReportsConcurrentSessions(t *testing.T) {
+	sender := mock.NewMockNotificationSender("test")
+	service := NewTaskService(memory.NewTaskRepository(), memory.NewTaskHistoryRepository(), sender, response.NewHookResponseBuilder(), &TaskServiceConfig{
+		AutoNotifyHookTypes: []domain.HookType{domain.HookTypePreToolUse},
+		SerializePerSession: true,
+	})
+	metrics := &sessionMetrics{}
+	service.SetMetrics(metrics)
+
+	sender.OnSend(func(notification *domain.Notification) {
+		service.SendDecisionToTask(notification.TaskID, domain.ActionTypeApprove)
+	})
+	if _, err := service.CreateTaskAndWaitForDecision(context.Background(), newBlockingHookData("abc123"), 200*time.Millisecond); err != nil {
+		t.Fatalf("Failed to wait for decision: %v", err)
+	}
+
+	if len(metrics.counts) != 2 || metrics.counts[0] != 1 || metrics.counts[1] != 0 {
+		t.Errorf("Expected the session to be counted while it waited and dropped after, got %v", metrics.counts)
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+var _ ports.SessionService = (*SessionService)(nil)
+
+// SessionService records the hook events of each Claude Code session and links subagents to their parent
+type SessionService struct {
+	repo ports.SessionRepository
+}
+
+// NewSessionService creates a new session service
+func NewSessionService(repo ports.SessionRepository) *SessionService {
+	return &SessionService{repo: repo}
+}
+
+// GetOrCreateSession retrieves an existing session or creates a new one
+func (s *SessionService) GetOrCreateSession(ctx context.Context, sessionID string) (*domain.Session, error) {
+	return s.repo.GetSession(ctx, sessionID)
+}
+
+// AppendEvent adds a new event to a session, creating the session if needed
+func (s *SessionService) AppendEvent(ctx context.Context, sessionID string, event *domain.SessionEvent) error {
+	return s.repo.AddEvent(ctx, sessionID, event)
+}
+
+// GetSessionEvents retrieves events for a session with optional filtering
+func (s *SessionService) GetSessionEvents(ctx context.Context, sessionID string, filter ports.EventFilter) ([]*domain.SessionEvent, error) {
+	return s.repo.GetEvents(ctx, sessionID, filter)
+}
+
+// RecordSubagent links a subagent to its parent session; recording the same subagent again does nothing
+func (s *SessionService) RecordSubagent(ctx context.Context, sessionID string, subagentID string) error {
+	session, err := s.repo.GetSession(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get session %s: %w", sessionID, err)
+	}
+	if !session.AddSubagentID(subagentID) {
+		return nil
+	}
+
+	if err := s.repo.UpdateSession(ctx, session); err != nil {
+		return fmt.Errorf("failed to record subagent %s: %w", subagentID, err)
+	}
+	return nil
+}
+
+// ListSessions retrieves up to limit sessions, most recently active first
+func (s *SessionService) ListSessions(ctx context.Context, limit int) ([]*domain.Session, error) {
+	return s.repo.ListSessions(ctx, limit)
+}
+
+// GetParentSession retrieves the session that spawned the given subagent
+func (s *SessionService) GetParentSession(ctx context.Context, subagentID string) (*domain.Session, error) {
+	return s.repo.FindSessionBySubagentID(ctx, subagentID)
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// fakeSessionRepository keeps one session per ID and counts updates
+type fakeSessionRepository struct {
+	ports.SessionRepository
+	sessions map[string]*domain.Session
+	updates  int
+}
+
+func (r *fakeSessionRepository) GetSession(ctx context.Context, sessionID string) (*domain.Session, error) {
+	session, ok := r.sessions[sessionID]
+	if !ok {
+		session = &domain.Session{ID: sessionID}
+		r.sessions[sessionID] = session
+	}
+	stored := *session
+	return &stored, nil
+}
+
+func (r *fakeSessionRepository) UpdateSession(ctx context.Context, session *domain.Session) error {
+	r.updates++
+	stored := *session
+	r.sessions[session.ID] = &stored
+	return nil
+}
+
+func TestSessionService_RecordSubagent(t *testing.T) {
+	repo := &fakeSessionRepository{sessions: make(map[string]*domain.Session)}
+	service := NewSessionService(repo)
+	ctx := context.Background()
+
+	for _, subagentID := range []string{"agent-1", "agent-2", "agent-1", ""} {
+		if err := service.RecordSubagent(ctx, "session-1", subagentID); err != nil {
+			t.Fatalf("RecordSubagent(%q) failed: %v", subagentID, err)
+		}
+	}
+
+	session := repo.sessions["session-1"]
+	if len(session.SubagentIDs) != 2 || !session.HasSubagent("agent-1") || !session.HasSubagent("agent-2") {
+		t.Errorf("Expected subagents agent-1 and agent-2, got %v", session.SubagentIDs)
+	}
+	if repo.updates != 2 {
+		t.Errorf("Expected only new subagents to update the session, got %d updates", repo.updates)
+	}
+}
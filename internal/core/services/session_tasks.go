@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// GetTasksGroupedBySession returns the tasks matching the filter keyed by the Claude Code session that raised them
+// Tasks within a session are oldest first; tasks whose hook data has no session ID are keyed by "".
+func (s *TaskService) GetTasksGroupedBySession(ctx context.Context, filter ports.TaskFilter) (map[string][]*domain.Task, error) {
+	tasks, err := s.taskRepo.ListBySession(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks by session: %w", err)
+	}
+	return groupTasksBySession(tasks), nil
+}
+
+// groupTasksBySession groups tasks by session ID, keeping their order within each session
+func groupTasksBySession(tasks []*domain.Task) map[string][]*domain.Task {
+	groups := make(map[string][]*domain.Task)
+	for _, task := range tasks {
+		var sessionID string
+		if task.HookData != nil {
+			sessionID = task.HookData.GetSessionID()
+		}
+		groups[sessionID] = append(groups[sessionID], task)
+	}
+	return groups
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+func TestGroupTasksBySession(t *testing.T) {
+	newTask := func(sessionID string) *domain.Task {
+		return domain.NewTask(&domain.HookData{
+			Type: domain.HookTypeStop,
+			Data: &domain.StopHookData{BaseHookData: domain.BaseHookData{HookEventName: "Stop", SessionID: sessionID}},
+		})
+	}
+
+	first := newTask("session-a")
+	second := newTask("session-b")
+	third := newTask("session-a")
+	orphan := &domain.Task{HookType: domain.HookTypeNotification}
+
+	groups := groupTasksBySession([]*domain.Task{first, second, third, orphan})
+
+	if len(groups) != 3 {
+		t.Fatalf("Expected 3 groups, got %d", len(groups))
+	}
+	if got := groups["session-a"]; len(got) != 2 || got[0] != first || got[1] != third {
+		t.Errorf("Expected session-a to hold its two tasks in order, got %v", got)
+	}
+	if got := groups["session-b"]; len(got) != 1 || got[0] != second {
+		t.Errorf("Expected session-b to hold one task, got %v", got)
+	}
+	if got := groups[""]; len(got) != 1 || got[0] != orphan {
+		t.Errorf("Expected the task without hook data under the empty session ID, got %v", got)
+	}
+}
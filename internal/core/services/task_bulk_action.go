@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// MaxBulkActionTasks is the most tasks one bulk action may decide
+const MaxBulkActionTasks = 100
+
+// BulkActionError records why the action failed on one task of a bulk action
+type BulkActionError struct {
+	TaskID uuid.UUID `json:"task_id"`
+	Error  string    `json:"error"`
+}
+
+// BulkTakeAction takes the same action on each task and wakes any blocking webhook waiting on it
+// A task that fails doesn't stop the rest; err is only set when the request itself is invalid.
+func (s *TaskService) BulkTakeAction(ctx context.Context, taskIDs []uuid.UUID, action domain.ActionType, responseData map[string]interface{}) (succeeded []uuid.UUID, failed []BulkActionError, err error) {
+	taskIDs, err = uniqueBulkTaskIDs(taskIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	succeeded = make([]uuid.UUID, 0, len(taskIDs))
+	failed = make([]BulkActionError, 0)
+	for _, taskID := range taskIDs {
+		// TakeAction adds per-task details such as the original command, so each task gets its own copy
+		taskResponseData := make(map[string]interface{}, len(responseData)+1)
+		for key, value := range responseData {
+			taskResponseData[key] = value
+		}
+		taskResponseData["bulk_action"] = true
+
+		if err := s.TakeAction(ctx, taskID, action, taskResponseData); err != nil {
+			log.Printf("Warning: bulk %s failed for task %s: %v", action, taskID, err)
+			failed = append(failed, BulkActionError{TaskID: taskID, Error: err.Error()})
+			continue
+		}
+		succeeded = append(succeeded, taskID)
+
+		if s.HasPendingDecision(taskID) && !s.SendDecisionToTask(taskID, action) {
+			log.Printf("Warning: failed to send bulk decision %s to blocking webhook for task %s", action, taskID.String()[:8])
+		}
+	}
+
+	return succeeded, failed, nil
+}
+
+// uniqueBulkTaskIDs drops repeated task IDs, keeping the first occurrence, and checks the count is within bounds
+func uniqueBulkTaskIDs(taskIDs []uuid.UUID) ([]uuid.UUID, error) {
+	seen := make(map[uuid.UUID]bool, len(taskIDs))
+	unique := make([]uuid.UUID, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		if !seen[taskID] {
+			seen[taskID] = true
+			unique = append(unique, taskID)
+		}
+	}
+
+	if len(unique) == 0 {
+		return nil, fmt.Errorf("%w: no task IDs given", ErrInvalidBulkAction)
+	}
+	if len(unique) > MaxBulkActionTasks {
+		return nil, fmt.Errorf("%w: %d tasks given, at most %d allowed", ErrInvalidBulkAction, len(unique), MaxBulkActionTasks)
+	}
+	return unique, nil
+}
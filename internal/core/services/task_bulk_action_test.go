@@ -0,0 +1,52 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestUniqueBulkTaskIDs(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	tooMany := make([]uuid.UUID, MaxBulkActionTasks+1)
+	for i := range tooMany {
+		tooMany[i] = uuid.New()
+	}
+
+	tests := []struct {
+		name        string
+		taskIDs     []uuid.UUID
+		expected    []uuid.UUID
+		expectError bool
+	}{
+		{name: "Distinct IDs", taskIDs: []uuid.UUID{first, second}, expected: []uuid.UUID{first, second}},
+		{name: "Repeated IDs", taskIDs: []uuid.UUID{second, first, second, first}, expected: []uuid.UUID{second, first}},
+		{name: "Maximum", taskIDs: tooMany[:MaxBulkActionTasks], expected: tooMany[:MaxBulkActionTasks]},
+		{name: "Empty", taskIDs: nil, expectError: true},
+		{name: "Too many", taskIDs: tooMany, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unique, err := uniqueBulkTaskIDs(tt.taskIDs)
+			if tt.expectError {
+				if !errors.Is(err, ErrInvalidBulkAction) {
+					t.Errorf("Expected ErrInvalidBulkAction, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(unique) != len(tt.expected) {
+				t.Fatalf("Expected %d IDs, got %d", len(tt.expected), len(unique))
+			}
+			for i := range unique {
+				if unique[i] != tt.expected[i] {
+					t.Errorf("Expected ID %d to be %s, got %s", i, tt.expected[i], unique[i])
+				}
+			}
+		})
+	}
+}
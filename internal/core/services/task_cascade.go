@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+// historyActionCascadeCancelled is the history action recorded on a child task cancelled because its parent was rejected
+const historyActionCascadeCancelled = "cascade_cancelled"
+
+// linkParentTask makes a SubagentStop task the child of its session's newest pending PreToolUse task,
+// so rejecting that tool call also cancels the subagent's stop
+func (s *TaskService) linkParentTask(ctx context.Context, task *domain.Task) {
+	if task.HookType != domain.HookTypeSubagentStop {
+		return
+	}
+	sessionID := task.HookData.GetSessionID()
+	if sessionID == "" {
+		return
+	}
+
+	pending, err := s.taskRepo.GetPendingTasksForSession(ctx, sessionID)
+	if err != nil {
+		log.Printf("Warning: failed to find parent task for session %s: %v", sessionID, err)
+		return
+	}
+	for i := len(pending) - 1; i >= 0; i-- {
+		if pending[i].HookType == domain.HookTypePreToolUse {
+			parentID := pending[i].ID
+			task.ParentTaskID = &parentID
+			return
+		}
+	}
+}
+
+// cancelChildTasks cancels the pending children of a rejected task, and their pending children in turn
+// Each child is cancelled like a decision from the dashboard: the action is stored first, then any webhook
+// waiting on the child is told. Children that were already decided are left alone, as are their own children.
+func (s *TaskService) cancelChildTasks(ctx context.Context, parent *domain.Task) {
+	children, err := s.taskRepo.GetChildTasks(ctx, parent.ID)
+	if err != nil {
+		log.Printf("Warning: failed to get child tasks of %s: %v", parent.ID, err)
+		return
+	}
+
+	for _, child := range children {
+		if !child.IsActionable() {
+			continue
+		}
+
+		child.TakeAction(domain.ActionTypeCancel, map[string]interface{}{
+			"decision_time":  time.Now(),
+			"parent_task_id": parent.ID.String(),
+		})
+		if err := s.taskRepo.Update(ctx, child); err != nil {
+			log.Printf("Warning: failed to cancel child task %s of %s: %v", child.ID, parent.ID, err)
+			continue
+		}
+		s.notifyTaskUpdated(ctx, child)
+		s.metrics.DecisionMade(child.HookType, domain.ActionTypeCancel)
+		s.SendDecisionToTask(child.ID, domain.ActionTypeCancel)
+
+		history := domain.NewTaskHistory(child.ID, historyActionCascadeCancelled, map[string]interface{}{
+			"parent_task_id": parent.ID.String(),
+			"parent_action":  string(domain.ActionTypeReject),
+		})
+		if err := s.historyRepo.Create(ctx, history); err != nil {
+			log.Printf("Warning: failed to create task history: %v", err)
+		}
+
+		s.cancelChildTasks(ctx, child)
+	}
+}
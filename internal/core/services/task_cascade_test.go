@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/adapters/memory"
+	"github.com/dan/claude-control/internal/adapters/mock"
+	"github.com/dan/claude-control/internal/adapters/response"
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+func newSubagentStopHookData(sessionID string) *domain.HookData {
+	return &domain.HookData{
+		Type: domain.HookTypeSubagentStop,
+		Data: &domain.SubagentStopHookData{
+			BaseHookData: domain.BaseHookData{HookEventName: "SubagentStop", SessionID: sessionID},
+			SubagentID:   "subagent-1",
+		},
+	}
+}
+
+// newChildTask stores a pending task whose parent is parentID
+func newChildTask(t *testing.T, taskRepo ports.TaskRepository, parentID uuid.UUID) *domain.Task {
+	t.Helper()
+	task := domain.NewTask(newSubagentStopHookData("abc123"))
+	task.ParentTaskID = &parentID
+	if err := taskRepo.Create(context.Background(), task); err != nil {
+		t.Fatalf("Failed to create child task: %v", err)
+	}
+	return task
+}
+
+func TestTaskService_RejectCancelsWaitingSubagentStop(t *testing.T) {
+	ctx := context.Background()
+	taskRepo := memory.NewTaskRepository()
+	historyRepo := memory.NewTaskHistoryRepository()
+	service := NewTaskService(taskRepo, historyRepo, mock.NewMockNotificationSender("test"), response.NewHookResponseBuilder(), &TaskServiceConfig{})
+
+	parent, err := service.CreateTaskFromHook(ctx, newBlockingHookData("abc123"))
+	if err != nil {
+		t.Fatalf("Failed to create parent task: %v", err)
+	}
+
+	responses := make(chan *domain.HookResponse, 1)
+	go func() {
+		hookResponse, err := service.CreateTaskAndWaitForDecision(ctx, newSubagentStopHookData("abc123"), 2*time.Second)
+		if err != nil {
+			t.Errorf("Failed to wait for decision: %v", err)
+		}
+		responses <- hookResponse
+	}()
+
+	var child *domain.Task
+	for deadline := time.Now().Add(time.Second); child == nil && time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		children, _ := taskRepo.GetChildTasks(ctx, parent.ID)
+		if len(children) == 1 && service.HasPendingDecision(children[0].ID) {
+			child = children[0]
+		}
+	}
+	if child == nil {
+		t.Fatal("Expected the SubagentStop task to be linked to the session's pending PreToolUse task")
+	}
+
+	if err := service.TakeAction(ctx, parent.ID, domain.ActionTypeReject, nil); err != nil {
+		t.Fatalf("Failed to reject parent: %v", err)
+	}
+
+	select {
+	case hookResponse := <-responses:
+		if hookResponse.Continue || hookResponse.StopReason != "User cancelled this action" {
+			t.Errorf("Expected the waiting SubagentStop hook to be cancelled, got %+v", hookResponse)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the waiting SubagentStop hook to be released")
+	}
+
+	stored, err := taskRepo.GetByID(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("Failed to get child task: %v", err)
+	}
+	if stored.Status != domain.TaskStatusCompleted || stored.ActionTaken == nil || *stored.ActionTaken != domain.ActionTypeCancel {
+		t.Errorf("Expected the child to be cancelled, got %s with %v", stored.Status, stored.ActionTaken)
+	}
+
+	history, err := historyRepo.GetByTaskID(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("Failed to get child history: %v", err)
+	}
+	var cascaded int
+	for _, entry := range history {
+		if entry.Action == historyActionCascadeCancelled {
+			cascaded++
+			if entry.Data["parent_task_id"] != parent.ID.String() {
+				t.Errorf("Expected the history entry to name the parent, got %v", entry.Data)
+			}
+		}
+		if entry.Action == string(domain.ActionTypeCancel) {
+			t.Error("Expected the waiting webhook not to record the cancellation a second time")
+		}
+	}
+	if cascaded != 1 {
+		t.Errorf("Expected one %s history entry, got %d in %v", historyActionCascadeCancelled, cascaded, history)
+	}
+}
+
+func TestTaskService_RejectCascade(t *testing.T) {
+	tests := []struct {
+		name   string
+		action domain.ActionType
+	}{
+		{name: "Reject cascades", action: domain.ActionTypeReject},
+		{name: "Approve leaves children pending", action: domain.ActionTypeApprove},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			taskRepo := memory.NewTaskRepository()
+			historyRepo := memory.NewTaskHistoryRepository()
+			service := NewTaskService(taskRepo, historyRepo, mock.NewMockNotificationSender("test"), response.NewHookResponseBuilder(), &TaskServiceConfig{})
+
+			parent := domain.NewTask(newBlockingHookData("abc123"))
+			if err := taskRepo.Create(ctx, parent); err != nil {
+				t.Fatalf("Failed to create parent task: %v", err)
+			}
+			pendingChild := newChildTask(t, taskRepo, parent.ID)
+			pendingGrandchild := newChildTask(t, taskRepo, pendingChild.ID)
+
+			// An already approved child shields its own pending children from the cascade
+			approvedChild := newChildTask(t, taskRepo, parent.ID)
+			approvedChild.TakeAction(domain.ActionTypeApprove, nil)
+			if err := taskRepo.Update(ctx, approvedChild); err != nil {
+				t.Fatalf("Failed to approve child task: %v", err)
+			}
+			shieldedGrandchild := newChildTask(t, taskRepo, approvedChild.ID)
+
+			if err := service.TakeAction(ctx, parent.ID, tt.action, nil); err != nil {
+				t.Fatalf("Failed to take action: %v", err)
+			}
+
+			cascaded := tt.action == domain.ActionTypeReject
+			expected := map[uuid.UUID]domain.TaskStatus{
+				pendingChild.ID:       domain.TaskStatusPending,
+				pendingGrandchild.ID:  domain.TaskStatusPending,
+				approvedChild.ID:      domain.TaskStatusApproved,
+				shieldedGrandchild.ID: domain.TaskStatusPending,
+			}
+			if cascaded {
+				expected[pendingChild.ID] = domain.TaskStatusCompleted
+				expected[pendingGrandchild.ID] = domain.TaskStatusCompleted
+			}
+			for id, status := range expected {
+				task, err := taskRepo.GetByID(ctx, id)
+				if err != nil {
+					t.Fatalf("Failed to get task: %v", err)
+				}
+				if task.Status != status {
+					t.Errorf("Expected task %s to be %s, got %s", id, status, task.Status)
+				}
+			}
+
+			var entries int
+			for _, action := range historyActions(t, historyRepo) {
+				if action == historyActionCascadeCancelled {
+					entries++
+				}
+			}
+			if want := map[bool]int{true: 2, false: 0}[cascaded]; entries != want {
+				t.Errorf("Expected %d %s history entries, got %d", want, historyActionCascadeCancelled, entries)
+			}
+		})
+	}
+}
+
+func TestTaskService_LinkParentTask(t *testing.T) {
+	ctx := context.Background()
+	taskRepo := memory.NewTaskRepository()
+	service := NewTaskService(taskRepo, memory.NewTaskHistoryRepository(), mock.NewMockNotificationSender("test"), response.NewHookResponseBuilder(), &TaskServiceConfig{})
+
+	older, err := service.CreateTaskFromHook(ctx, newBlockingHookData("abc123"))
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	newer, err := service.CreateTaskFromHook(ctx, newBlockingHookData("abc123"))
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if older.ParentTaskID != nil || newer.ParentTaskID != nil {
+		t.Error("Expected PreToolUse tasks to have no parent")
+	}
+
+	tests := []struct {
+		name      string
+		sessionID string
+		expected  *uuid.UUID
+	}{
+		{name: "Newest pending PreToolUse in the session", sessionID: "abc123", expected: &newer.ID},
+		{name: "Other session", sessionID: "def456"},
+		{name: "No session", sessionID: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := service.CreateTaskFromHook(ctx, newSubagentStopHookData(tt.sessionID))
+			if err != nil {
+				t.Fatalf("Failed to create task: %v", err)
+			}
+			switch {
+			case tt.expected == nil && task.ParentTaskID != nil:
+				t.Errorf("Expected no parent, got %s", task.ParentTaskID)
+			case tt.expected != nil && (task.ParentTaskID == nil || *task.ParentTaskID != *tt.expected):
+				t.Errorf("Expected parent %s, got %v", tt.expected, task.ParentTaskID)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	// taskCommentKey holds the operator's note on a decision in a task's response data and in history
+	taskCommentKey = "comment"
+
+	// historyActionCommented is the history action recorded for a comment on a decision
+	historyActionCommented = "commented"
+
+	// MaxTaskCommentLength is the longest comment, in characters, that can be left on a decision
+	MaxTaskCommentLength = 2000
+)
+
+// TaskComment is a note an operator left when deciding a task
+type TaskComment struct {
+	Comment   string            `json:"comment"`
+	Action    domain.ActionType `json:"action,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// ValidateTaskComment checks a comment fits in MaxTaskCommentLength characters
+func ValidateTaskComment(comment string) error {
+	if length := utf8.RuneCountInString(comment); length > MaxTaskCommentLength {
+		return fmt.Errorf("%w: %d characters, at most %d allowed", ErrInvalidComment, length, MaxTaskCommentLength)
+	}
+	return nil
+}
+
+// GetTaskComments returns the comments left on a task's decisions, oldest first
+func (s *TaskService) GetTaskComments(ctx context.Context, taskID uuid.UUID) ([]TaskComment, error) {
+	history, err := s.historyRepo.GetByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task history: %w", err)
+	}
+	return CommentsFromHistory(history), nil
+}
+
+// recordComment stores the comment in an action's response data as its own history entry
+// The decision's entry leaves the comment out so each comment appears once in the audit trail.
+func (s *TaskService) recordComment(ctx context.Context, taskID uuid.UUID, action domain.ActionType, responseData map[string]interface{}) {
+	comment := taskComment(responseData)
+	if comment == "" {
+		return
+	}
+
+	history := domain.NewTaskHistory(taskID, historyActionCommented, map[string]interface{}{
+		taskCommentKey: comment,
+		"action":       string(action),
+	})
+	if err := s.historyRepo.Create(ctx, history); err != nil {
+		log.Printf("Warning: failed to record comment on task %s: %v", taskID, err)
+	}
+}
+
+// withoutComment copies response data for the decision's history entry, leaving out the comment
+func withoutComment(responseData map[string]interface{}) map[string]interface{} {
+	if _, ok := responseData[taskCommentKey]; !ok {
+		return responseData
+	}
+
+	data := make(map[string]interface{}, len(responseData))
+	for key, value := range responseData {
+		if key != taskCommentKey {
+			data[key] = value
+		}
+	}
+	return data
+}
+
+// taskComment returns the trimmed comment in response data, or "" if there is none
+func taskComment(data map[string]interface{}) string {
+	comment, _ := data[taskCommentKey].(string)
+	return strings.TrimSpace(comment)
+}
+
+// CommentsFromHistory picks the history entries carrying a non-empty comment
+func CommentsFromHistory(history []*domain.TaskHistory) []TaskComment {
+	comments := make([]TaskComment, 0)
+	for _, entry := range history {
+		comment := taskComment(entry.Data)
+		if comment == "" {
+			continue
+		}
+		action, _ := entry.Data["action"].(string)
+		comments = append(comments, TaskComment{
+			Comment:   comment,
+			Action:    domain.ActionType(action),
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+	return comments
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+func TestValidateTaskComment(t *testing.T) {
+	tests := []struct {
+		name        string
+		comment     string
+		expectError bool
+	}{
+		{name: "Empty", comment: ""},
+		{name: "Short", comment: "Only touches the build cache"},
+		{name: "Maximum", comment: strings.Repeat("é", MaxTaskCommentLength)},
+		{name: "Too long", comment: strings.Repeat("a", MaxTaskCommentLength+1), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTaskComment(tt.comment)
+			if tt.expectError != errors.Is(err, ErrInvalidComment) {
+				t.Errorf("Expected error %t, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestWithoutComment(t *testing.T) {
+	responseData := map[string]interface{}{"comment": "Looks fine", "user_agent": "curl"}
+
+	data := withoutComment(responseData)
+	if _, ok := data["comment"]; ok {
+		t.Error("Expected the comment to be left out")
+	}
+	if data["user_agent"] != "curl" {
+		t.Errorf("Expected other keys to be kept, got %v", data)
+	}
+	if responseData["comment"] != "Looks fine" {
+		t.Error("Expected the original response data to keep its comment")
+	}
+}
+
+func TestCommentsFromHistory(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	history := []*domain.TaskHistory{
+		{Action: "created", Data: map[string]interface{}{"hook_type": "PreToolUse"}, CreatedAt: now},
+		{Action: "approve", Data: map[string]interface{}{"comment": ""}, CreatedAt: now.Add(time.Minute)},
+		{Action: historyActionCommented, Data: map[string]interface{}{"comment": "  Only touches the build cache ", "action": "approve"}, CreatedAt: now.Add(time.Minute)},
+		{Action: historyActionCommented, Data: map[string]interface{}{"comment": "Reverted by hand"}, CreatedAt: now.Add(time.Hour)},
+	}
+
+	comments := CommentsFromHistory(history)
+	if len(comments) != 2 {
+		t.Fatalf("Expected 2 comments, got %+v", comments)
+	}
+	if comments[0].Comment != "Only touches the build cache" || comments[0].Action != domain.ActionTypeApprove {
+		t.Errorf("Unexpected first comment: %+v", comments[0])
+	}
+	if comments[1].Comment != "Reverted by hand" || comments[1].Action != "" || !comments[1].CreatedAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("Unexpected second comment: %+v", comments[1])
+	}
+}
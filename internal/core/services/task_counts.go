@@ -0,0 +1,59 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+// DefaultTaskCountsCacheTTL is how long dashboard badge counts are served from memory
+const DefaultTaskCountsCacheTTL = 5 * time.Second
+
+// TaskCounts holds the number of tasks in each status and raised by each hook type
+type TaskCounts struct {
+	ByStatus   map[domain.TaskStatus]int `json:"by_status"`
+	ByHookType map[domain.HookType]int   `json:"by_hook_type"`
+}
+
+// Status returns the number of tasks in a status, or 0 when the counts are unavailable
+func (c *TaskCounts) Status(status domain.TaskStatus) int {
+	if c == nil {
+		return 0
+	}
+	return c.ByStatus[status]
+}
+
+// TaskCountsCache holds the most recent task counts for a short TTL
+// The dashboard polls the counts, so a few seconds of staleness saves a GROUP BY per poll.
+type TaskCountsCache struct {
+	ttl      time.Duration
+	counts   *TaskCounts
+	cachedAt time.Time
+	mutex    sync.Mutex
+}
+
+// NewTaskCountsCache creates a new task counts cache
+func NewTaskCountsCache(ttl time.Duration) *TaskCountsCache {
+	return &TaskCountsCache{ttl: ttl}
+}
+
+// Get returns the cached counts if they are younger than the TTL
+func (c *TaskCountsCache) Get(now time.Time) (*TaskCounts, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.counts == nil || now.Sub(c.cachedAt) >= c.ttl {
+		return nil, false
+	}
+	return c.counts, true
+}
+
+// Set stores the counts
+func (c *TaskCountsCache) Set(counts *TaskCounts, now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.counts = counts
+	c.cachedAt = now
+}
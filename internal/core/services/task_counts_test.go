@@ -0,0 +1,59 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+func TestTaskCountsCache(t *testing.T) {
+	cache := NewTaskCountsCache(DefaultTaskCountsCacheTTL)
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	counts := &TaskCounts{
+		ByStatus:   map[domain.TaskStatus]int{domain.TaskStatusPending: 2, domain.TaskStatusApproved: 5},
+		ByHookType: map[domain.HookType]int{domain.HookTypePreToolUse: 7},
+	}
+
+	if _, ok := cache.Get(now); ok {
+		t.Fatal("Expected a miss on an empty cache")
+	}
+
+	cache.Set(counts, now)
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected bool
+	}{
+		{name: "Fresh entry", at: now.Add(4 * time.Second), expected: true},
+		{name: "Expired entry", at: now.Add(DefaultTaskCountsCacheTTL), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cached, ok := cache.Get(tt.at)
+			if ok != tt.expected {
+				t.Fatalf("Expected hit=%t, got %t", tt.expected, ok)
+			}
+			if ok && cached.ByStatus[domain.TaskStatusApproved] != 5 {
+				t.Errorf("Unexpected cached counts: %+v", cached)
+			}
+		})
+	}
+}
+
+func TestTaskCounts_Status(t *testing.T) {
+	counts := &TaskCounts{ByStatus: map[domain.TaskStatus]int{domain.TaskStatusPending: 3}}
+	if got := counts.Status(domain.TaskStatusPending); got != 3 {
+		t.Errorf("Expected 3 pending, got %d", got)
+	}
+	if got := counts.Status(domain.TaskStatusRejected); got != 0 {
+		t.Errorf("Expected 0 rejected, got %d", got)
+	}
+
+	var missing *TaskCounts
+	if got := missing.Status(domain.TaskStatusPending); got != 0 {
+		t.Errorf("Expected 0 from nil counts, got %d", got)
+	}
+}
@@ -2,38 +2,75 @@ package services
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
 )
 
+// DefaultDecisionChannelBufferSize is how many decisions can be queued per task by default
+const DefaultDecisionChannelBufferSize = 1
+
 // TaskDecisionManager manages real-time decision channels for blocking webhook handlers
 type TaskDecisionManager struct {
 	decisions map[string]chan domain.ActionType
+	waiters   map[string]decisionWaiter
 	mutex     sync.RWMutex
+
+	// ChannelBufferSize is how many decisions can be queued for a task before further sends are dropped
+	// Applies to channels created after it is changed.
+	ChannelBufferSize int
+
+	// LastDecisionWins makes SendDecision replace any queued decisions instead of being dropped when the channel is full
+	LastDecisionWins bool
+}
+
+// decisionWaiter records when and for which session a decision channel was opened
+type decisionWaiter struct {
+	createdAt time.Time
+	sessionID string
 }
 
 // NewTaskDecisionManager creates a new decision manager
 func NewTaskDecisionManager() *TaskDecisionManager {
 	return &TaskDecisionManager{
-		decisions: make(map[string]chan domain.ActionType),
+		decisions:         make(map[string]chan domain.ActionType),
+		waiters:           make(map[string]decisionWaiter),
+		ChannelBufferSize: DefaultDecisionChannelBufferSize,
 	}
 }
 
 // CreateDecisionChannel creates a new decision channel for a task
 func (m *TaskDecisionManager) CreateDecisionChannel(taskID string) chan domain.ActionType {
+	return m.createDecisionChannel(taskID, "")
+}
+
+// createDecisionChannel creates a decision channel and remembers when it was opened
+func (m *TaskDecisionManager) createDecisionChannel(taskID, sessionID string) chan domain.ActionType {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	decisionChan := make(chan domain.ActionType, 1)
+	bufferSize := m.ChannelBufferSize
+	if bufferSize < 1 {
+		bufferSize = DefaultDecisionChannelBufferSize
+	}
+
+	decisionChan := make(chan domain.ActionType, bufferSize)
 	m.decisions[taskID] = decisionChan
+	m.waiters[taskID] = decisionWaiter{createdAt: time.Now(), sessionID: sessionID}
 	return decisionChan
 }
 
 // SendDecision sends a decision to the waiting channel
+// If LastDecisionWins is set this behaves like SendDecisionWithOverwrite.
 func (m *TaskDecisionManager) SendDecision(taskID string, decision domain.ActionType) bool {
 	m.mutex.RLock()
+	if m.LastDecisionWins {
+		m.mutex.RUnlock()
+		return m.SendDecisionWithOverwrite(taskID, decision)
+	}
 	defer m.mutex.RUnlock()
 
 	if decisionChan, exists := m.decisions[taskID]; exists {
@@ -48,6 +85,34 @@ func (m *TaskDecisionManager) SendDecision(taskID string, decision domain.Action
 	return false
 }
 
+// SendDecisionWithOverwrite discards any decisions still queued for the task before sending, so the latest decision always wins
+func (m *TaskDecisionManager) SendDecisionWithOverwrite(taskID string, decision domain.ActionType) bool {
+	// Exclusive lock so concurrent senders can't interleave their drain and send
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	decisionChan, exists := m.decisions[taskID]
+	if !exists {
+		return false
+	}
+
+drain:
+	for {
+		select {
+		case <-decisionChan:
+		default:
+			break drain
+		}
+	}
+
+	select {
+	case decisionChan <- decision:
+		return true
+	default:
+		return false
+	}
+}
+
 // RemoveDecisionChannel removes and closes a decision channel
 func (m *TaskDecisionManager) RemoveDecisionChannel(taskID string) {
 	m.mutex.Lock()
@@ -56,12 +121,35 @@ func (m *TaskDecisionManager) RemoveDecisionChannel(taskID string) {
 	if decisionChan, exists := m.decisions[taskID]; exists {
 		close(decisionChan)
 		delete(m.decisions, taskID)
+		delete(m.waiters, taskID)
 	}
 }
 
 // WaitForDecision waits for a user decision with timeout
 func (m *TaskDecisionManager) WaitForDecision(ctx context.Context, taskID string, timeout time.Duration) (domain.ActionType, error) {
-	decisionChan := m.CreateDecisionChannel(taskID)
+	return m.WaitForSessionDecision(ctx, taskID, "", timeout)
+}
+
+// WaitForSessionDecision waits for a user decision with timeout, recording the Claude Code session that is blocked
+func (m *TaskDecisionManager) WaitForSessionDecision(ctx context.Context, taskID, sessionID string, timeout time.Duration) (domain.ActionType, error) {
+	m.OpenSessionDecision(ctx, taskID, sessionID, timeout)
+	return m.AwaitDecision(ctx, taskID, timeout)
+}
+
+// OpenSessionDecision registers a task's decision channel ahead of AwaitDecision, so a decision sent in between is kept
+func (m *TaskDecisionManager) OpenSessionDecision(ctx context.Context, taskID, sessionID string, timeout time.Duration) {
+	m.createDecisionChannel(taskID, sessionID)
+}
+
+// AwaitDecision waits with timeout for a decision on the channel opened by OpenSessionDecision, then removes it
+// A channel that was never opened is opened now, so the wait behaves like WaitForDecision.
+func (m *TaskDecisionManager) AwaitDecision(ctx context.Context, taskID string, timeout time.Duration) (domain.ActionType, error) {
+	m.mutex.RLock()
+	decisionChan, exists := m.decisions[taskID]
+	m.mutex.RUnlock()
+	if !exists {
+		decisionChan = m.createDecisionChannel(taskID, "")
+	}
 	defer m.RemoveDecisionChannel(taskID)
 
 	select {
@@ -99,5 +187,38 @@ func (m *TaskDecisionManager) CleanupExpiredChannels() {
 	for taskID, decisionChan := range m.decisions {
 		close(decisionChan)
 		delete(m.decisions, taskID)
+		delete(m.waiters, taskID)
+	}
+}
+
+// GetStuckDecisions returns decision channels that have been open longer than maxAge, oldest first
+func (m *TaskDecisionManager) GetStuckDecisions(maxAge time.Duration) []ports.StuckDecision {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	now := time.Now()
+	stuck := []ports.StuckDecision{}
+	for taskID, waiter := range m.waiters {
+		age := now.Sub(waiter.createdAt)
+		if age > maxAge {
+			stuck = append(stuck, ports.StuckDecision{TaskID: taskID, Age: age, SessionID: waiter.sessionID})
+		}
 	}
-}
\ No newline at end of file
+
+	sort.Slice(stuck, func(i, j int) bool {
+		return stuck[i].Age > stuck[j].Age
+	})
+	return stuck
+}
+
+// ForceResolveStuck cancels decisions that have been open longer than maxAge, returning how many were cancelled
+// The waiting handler receives ActionTypeCancel and cleans up its channel as usual.
+func (m *TaskDecisionManager) ForceResolveStuck(maxAge time.Duration) int {
+	resolved := 0
+	for _, stuck := range m.GetStuckDecisions(maxAge) {
+		if m.SendDecision(stuck.TaskID, domain.ActionTypeCancel) {
+			resolved++
+		}
+	}
+	return resolved
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+// waitInBackground starts a blocking wait and returns a channel delivering its decision
+func waitInBackground(m *TaskDecisionManager, taskID, sessionID string) <-chan domain.ActionType {
+	result := make(chan domain.ActionType, 1)
+	go func() {
+		decision, _ := m.WaitForSessionDecision(context.Background(), taskID, sessionID, time.Minute)
+		result <- decision
+	}()
+
+	// Wait until the channel is registered
+	for !m.HasPendingDecision(taskID) {
+		time.Sleep(time.Millisecond)
+	}
+	return result
+}
+
+func TestTaskDecisionManager_GetStuckDecisions(t *testing.T) {
+	m := NewTaskDecisionManager()
+	oldWait := waitInBackground(m, "old-task", "session-1")
+	time.Sleep(50 * time.Millisecond)
+	newWait := waitInBackground(m, "new-task", "session-2")
+
+	stuck := m.GetStuckDecisions(25 * time.Millisecond)
+	if len(stuck) != 1 {
+		t.Fatalf("Expected 1 stuck decision, got %v", stuck)
+	}
+	if stuck[0].TaskID != "old-task" || stuck[0].SessionID != "session-1" {
+		t.Errorf("Unexpected stuck decision: %+v", stuck[0])
+	}
+	if stuck[0].Age < 50*time.Millisecond {
+		t.Errorf("Expected age of at least 50ms, got %v", stuck[0].Age)
+	}
+
+	if all := m.GetStuckDecisions(0); len(all) != 2 || all[0].TaskID != "old-task" {
+		t.Errorf("Expected both decisions oldest first, got %v", all)
+	}
+
+	m.SendDecision("old-task", domain.ActionTypeApprove)
+	m.SendDecision("new-task", domain.ActionTypeApprove)
+	<-oldWait
+	<-newWait
+}
+
+func TestTaskDecisionManager_ForceResolveStuck(t *testing.T) {
+	m := NewTaskDecisionManager()
+	oldWait := waitInBackground(m, "old-task", "session-1")
+	time.Sleep(50 * time.Millisecond)
+	newWait := waitInBackground(m, "new-task", "session-2")
+
+	if resolved := m.ForceResolveStuck(25 * time.Millisecond); resolved != 1 {
+		t.Fatalf("Expected 1 resolved decision, got %d", resolved)
+	}
+
+	select {
+	case decision := <-oldWait:
+		if decision != domain.ActionTypeCancel {
+			t.Errorf("Expected cancel decision, got %s", decision)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stuck waiter was not resolved")
+	}
+
+	select {
+	case decision := <-newWait:
+		t.Fatalf("Recent waiter should not be resolved, got %s", decision)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	m.SendDecision("new-task", domain.ActionTypeApprove)
+	<-newWait
+
+	if active := m.GetActiveDecisions(); active != 0 {
+		t.Errorf("Expected no active decisions, got %d", active)
+	}
+}
+
+func TestTaskDecisionManager_ChannelBufferSize(t *testing.T) {
+	tests := []struct {
+		name             string
+		bufferSize       int
+		lastDecisionWins bool
+		expectedSent     []bool
+		expectedQueued   []domain.ActionType
+	}{
+		{
+			name:           "Default buffer drops the second decision",
+			bufferSize:     DefaultDecisionChannelBufferSize,
+			expectedSent:   []bool{true, false},
+			expectedQueued: []domain.ActionType{domain.ActionTypeApprove},
+		},
+		{
+			name:           "Larger buffer queues both decisions",
+			bufferSize:     2,
+			expectedSent:   []bool{true, true},
+			expectedQueued: []domain.ActionType{domain.ActionTypeApprove, domain.ActionTypeReject},
+		},
+		{
+			name:             "Last decision wins",
+			bufferSize:       DefaultDecisionChannelBufferSize,
+			lastDecisionWins: true,
+			expectedSent:     []bool{true, true},
+			expectedQueued:   []domain.ActionType{domain.ActionTypeReject},
+		},
+		{
+			name:             "Last decision wins with a larger buffer",
+			bufferSize:       3,
+			lastDecisionWins: true,
+			expectedSent:     []bool{true, true},
+			expectedQueued:   []domain.ActionType{domain.ActionTypeReject},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewTaskDecisionManager()
+			m.ChannelBufferSize = tt.bufferSize
+			m.LastDecisionWins = tt.lastDecisionWins
+
+			decisionChan := m.CreateDecisionChannel("task-1")
+			for i, decision := range []domain.ActionType{domain.ActionTypeApprove, domain.ActionTypeReject} {
+				if sent := m.SendDecision("task-1", decision); sent != tt.expectedSent[i] {
+					t.Errorf("Send %s: expected sent=%t, got %t", decision, tt.expectedSent[i], sent)
+				}
+			}
+
+			if len(decisionChan) != len(tt.expectedQueued) {
+				t.Fatalf("Expected %d queued decisions, got %d", len(tt.expectedQueued), len(decisionChan))
+			}
+			for _, expected := range tt.expectedQueued {
+				if decision := <-decisionChan; decision != expected {
+					t.Errorf("Expected %s, got %s", expected, decision)
+				}
+			}
+		})
+	}
+}
+
+func TestTaskDecisionManager_SendDecisionWithOverwrite(t *testing.T) {
+	m := NewTaskDecisionManager()
+
+	if m.SendDecisionWithOverwrite("missing-task", domain.ActionTypeApprove) {
+		t.Error("Expected no send without a decision channel")
+	}
+
+	wait := waitInBackground(m, "task-1", "session-1")
+	if !m.SendDecisionWithOverwrite("task-1", domain.ActionTypeApprove) {
+		t.Fatal("Expected decision to be sent")
+	}
+
+	select {
+	case decision := <-wait:
+		if decision != domain.ActionTypeApprove {
+			t.Errorf("Expected approve, got %s", decision)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for decision")
+	}
+}
+
+func TestTaskDecisionManager_DecisionBeforeAwait(t *testing.T) {
+	m := NewTaskDecisionManager()
+	ctx := context.Background()
+
+	m.OpenSessionDecision(ctx, "task-1", "session-1", time.Minute)
+	if !m.SendDecision("task-1", domain.ActionTypeReject) {
+		t.Fatal("Expected a decision sent between open and await to be accepted")
+	}
+
+	decision, err := m.AwaitDecision(ctx, "task-1", time.Second)
+	if err != nil || decision != domain.ActionTypeReject {
+		t.Errorf("Expected the early rejection, got %q, %v", decision, err)
+	}
+	if m.HasPendingDecision("task-1") {
+		t.Error("Expected the channel to be removed once the wait ended")
+	}
+}
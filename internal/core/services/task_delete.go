@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// deletableTaskStatuses are the finished states a task can be deleted in
+// Pending tasks may still have a webhook waiting on them, so they are never deleted.
+var deletableTaskStatuses = map[domain.TaskStatus]bool{
+	domain.TaskStatusApproved:  true,
+	domain.TaskStatusRejected:  true,
+	domain.TaskStatusCompleted: true,
+	domain.TaskStatusFailed:    true,
+}
+
+// isDeletableStatus returns true if a task in the status is finished and may be deleted
+func isDeletableStatus(status domain.TaskStatus) bool {
+	return deletableTaskStatuses[status]
+}
+
+// DeleteTask deletes a finished task; its history goes with it through the task_history foreign key
+func (s *TaskService) DeleteTask(ctx context.Context, taskID uuid.UUID) error {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrTaskNotFound, taskID, err)
+	}
+
+	if !isDeletableStatus(task.Status) {
+		return fmt.Errorf("%w: %s (status: %s)", ErrTaskNotDeletable, taskID, task.Status.String())
+	}
+
+	if err := s.taskRepo.Delete(ctx, taskID); err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	s.publishTaskEvent(ctx, TaskEventDeleted, task)
+	return nil
+}
+
+// BulkDeleteTasks deletes each finished task in the list
+// A task that can't be deleted doesn't stop the rest; err is only set when the request itself is invalid.
+func (s *TaskService) BulkDeleteTasks(ctx context.Context, taskIDs []uuid.UUID) (deleted []uuid.UUID, failed []BulkActionError, err error) {
+	taskIDs, err = uniqueBulkTaskIDs(taskIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deleted = make([]uuid.UUID, 0, len(taskIDs))
+	failed = make([]BulkActionError, 0)
+	for _, taskID := range taskIDs {
+		if err := s.DeleteTask(ctx, taskID); err != nil {
+			log.Printf("Warning: bulk delete failed for task %s: %v", taskID, err)
+			failed = append(failed, BulkActionError{TaskID: taskID, Error: err.Error()})
+			continue
+		}
+		deleted = append(deleted, taskID)
+	}
+
+	return deleted, failed, nil
+}
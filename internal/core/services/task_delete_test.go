@@ -0,0 +1,29 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+func TestIsDeletableStatus(t *testing.T) {
+	tests := []struct {
+		status   domain.TaskStatus
+		expected bool
+	}{
+		{domain.TaskStatusPending, false},
+		{domain.TaskStatusApproved, true},
+		{domain.TaskStatusRejected, true},
+		{domain.TaskStatusCompleted, true},
+		{domain.TaskStatusFailed, true},
+		{domain.TaskStatus("unknown"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status.String(), func(t *testing.T) {
+			if got := isDeletableStatus(tt.status); got != tt.expected {
+				t.Errorf("isDeletableStatus(%s) = %v, expected %v", tt.status, got, tt.expected)
+			}
+		})
+	}
+}
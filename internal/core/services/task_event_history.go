@@ -0,0 +1,60 @@
+package services
+
+// DefaultTaskEventHistorySize is how many recent task events are kept for clients resuming a stream
+const DefaultTaskEventHistorySize = 100
+
+// TaskEventHistory numbers task events and keeps the most recent ones in a circular buffer
+// It isn't safe for concurrent use; TaskService guards it together with its event broadcaster.
+type TaskEventHistory struct {
+	events []TaskEvent
+	next   int
+	full   bool
+	lastID uint64
+}
+
+// NewTaskEventHistory creates a history remembering up to size events
+func NewTaskEventHistory(size int) *TaskEventHistory {
+	return &TaskEventHistory{events: make([]TaskEvent, size)}
+}
+
+// Append gives the event the next sequence number, stores it and returns the numbered event
+// Sequence numbers start at 1, so 0 can mean "nothing seen yet".
+func (h *TaskEventHistory) Append(event TaskEvent) TaskEvent {
+	h.lastID++
+	event.ID = h.lastID
+	if len(h.events) == 0 {
+		return event
+	}
+
+	h.events[h.next] = event
+	h.next = (h.next + 1) % len(h.events)
+	if h.next == 0 {
+		h.full = true
+	}
+	return event
+}
+
+// Since returns the remembered events numbered after lastID, oldest first
+// If some of those events have already been overwritten, only the ones still held are returned.
+func (h *TaskEventHistory) Since(lastID uint64) []TaskEvent {
+	var events []TaskEvent
+	for _, event := range h.ordered() {
+		if event.ID > lastID {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// LastID returns the sequence number of the most recent event, or 0 if there have been none
+func (h *TaskEventHistory) LastID() uint64 {
+	return h.lastID
+}
+
+// ordered returns the held events oldest first
+func (h *TaskEventHistory) ordered() []TaskEvent {
+	if !h.full {
+		return h.events[:h.next]
+	}
+	return append(append([]TaskEvent{}, h.events[h.next:]...), h.events[:h.next]...)
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+)
+
+func TestTaskEventHistory_Since(t *testing.T) {
+	history := NewTaskEventHistory(3)
+	for i := 0; i < 5; i++ {
+		event := history.Append(TaskEvent{Type: TaskEventUpdated})
+		if event.ID != uint64(i+1) {
+			t.Fatalf("Expected event %d to get ID %d, got %d", i, i+1, event.ID)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		lastID   uint64
+		expected []uint64
+	}{
+		{"From the start", 0, []uint64{3, 4, 5}},
+		{"Gap beyond the buffer", 1, []uint64{3, 4, 5}},
+		{"Oldest held event seen", 3, []uint64{4, 5}},
+		{"Up to date", 5, nil},
+		{"ID from the future", 9, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := history.Since(tt.lastID)
+			if len(events) != len(tt.expected) {
+				t.Fatalf("Expected %d events, got %d", len(tt.expected), len(events))
+			}
+			for i, event := range events {
+				if event.ID != tt.expected[i] {
+					t.Errorf("Expected event %d to have ID %d, got %d", i, tt.expected[i], event.ID)
+				}
+			}
+		})
+	}
+
+	if history.LastID() != 5 {
+		t.Errorf("Expected last ID 5, got %d", history.LastID())
+	}
+}
+
+func TestTaskEventHistory_NotFull(t *testing.T) {
+	history := NewTaskEventHistory(DefaultTaskEventHistorySize)
+	if events := history.Since(0); len(events) != 0 {
+		t.Errorf("Expected no events in an empty history, got %d", len(events))
+	}
+
+	history.Append(TaskEvent{Type: TaskEventCreated})
+	history.Append(TaskEvent{Type: TaskEventUpdated})
+
+	events := history.Since(0)
+	if len(events) != 2 || events[0].Type != TaskEventCreated || events[1].Type != TaskEventUpdated {
+		t.Errorf("Expected created then updated, got %+v", events)
+	}
+}
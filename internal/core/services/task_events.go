@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// TaskEventType says what happened to the task in a TaskEvent
+type TaskEventType string
+
+const (
+	// TaskEventCreated is published when a hook creates a task
+	TaskEventCreated TaskEventType = "created"
+
+	// TaskEventUpdated is published when a task changes, e.g. it is approved, rejected, times out or is snoozed
+	TaskEventUpdated TaskEventType = "updated"
+
+	// TaskEventDeleted is published when a finished task is deleted
+	TaskEventDeleted TaskEventType = "deleted"
+)
+
+// TaskEvent is published to dashboard subscribers whenever a task is created or changes
+// ID is its sequence number, increasing by one per event, so a client can tell what it missed.
+// UUID identifies the event across servers, and RequestID names the request that caused it, if any.
+type TaskEvent struct {
+	ID        uint64        `json:"id"`
+	UUID      uuid.UUID     `json:"uuid"`
+	Type      TaskEventType `json:"type"`
+	Task      *domain.Task  `json:"task"`
+	RequestID string        `json:"request_id,omitempty"`
+}
+
+// newTaskEvent snapshots the task so subscribers can encode it while the service keeps working on the original
+func newTaskEvent(ctx context.Context, eventType TaskEventType, task *domain.Task) TaskEvent {
+	snapshot := *task
+	return TaskEvent{UUID: uuid.New(), Type: eventType, Task: &snapshot, RequestID: domain.RequestIDFromContext(ctx)}
+}
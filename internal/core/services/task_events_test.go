@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+func TestNewTaskEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		requestID string
+	}{
+		{"Inside a request", domain.WithRequestID(context.Background(), "req-123"), "req-123"},
+		{"Background work", context.Background(), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &domain.Task{ID: uuid.New(), Status: domain.TaskStatusPending}
+			event := newTaskEvent(tt.ctx, TaskEventCreated, task)
+
+			if event.RequestID != tt.requestID {
+				t.Errorf("Expected request ID %q, got %q", tt.requestID, event.RequestID)
+			}
+			if event.UUID == uuid.Nil {
+				t.Error("Expected the event to get a UUID")
+			}
+			if event.Task == task || event.Task.ID != task.ID {
+				t.Error("Expected the event to carry a snapshot of the task")
+			}
+		})
+	}
+
+	first := newTaskEvent(context.Background(), TaskEventUpdated, &domain.Task{})
+	second := newTaskEvent(context.Background(), TaskEventUpdated, &domain.Task{})
+	if first.UUID == second.UUID {
+		t.Error("Expected each event to get its own UUID")
+	}
+}
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// noopTaskMetrics discards everything; it stands in until SetMetrics is called
+type noopTaskMetrics struct{}
+
+var _ ports.TaskMetrics = noopTaskMetrics{}
+
+func (noopTaskMetrics) WebhookReceived(hookType domain.HookType)                        {}
+func (noopTaskMetrics) DecisionMade(hookType domain.HookType, action domain.ActionType) {}
+func (noopTaskMetrics) DecisionWaited(hookType domain.HookType, waited time.Duration)   {}
+func (noopTaskMetrics) DecisionTimedOut(hookType domain.HookType)                       {}
+func (noopTaskMetrics) ConcurrentSessions(count int)                                    {}
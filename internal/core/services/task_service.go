@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/dan/claude-control/internal/core/domain"
@@ -11,6 +12,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// Ensure TaskService can hold blocking webhooks open
+var _ ports.HookDecisionService = (*TaskService)(nil)
+
 // TaskService handles the core business logic for task management
 type TaskService struct {
 	taskRepo        ports.TaskRepository
@@ -19,12 +23,78 @@ type TaskService struct {
 	responseBuilder ports.HookResponseBuilder
 	decisionManager ports.TaskDecisionManager
 	config          *TaskServiceConfig
+	failureCapture  *FailureScrollbackCapturer // Optional - failed tool calls get no scrollback when nil
+	metrics         ports.TaskMetrics          // Discards everything until SetMetrics is called
+
+	watcher        *TaskWatcher
+	statsCache     *ToolStatsCache
+	countsCache    *TaskCountsCache
+	resendLimiter  *RenotifyLimiter
+	receipts       *ReceiptTracker
+	sessionMonitor *ConcurrentSessionMonitor
+	sessionSlots   *SessionSemaphore
+
+	events       *EventBroadcaster[TaskEvent]
+	eventHistory *TaskEventHistory
+	eventsMutex  sync.Mutex // Numbers, records and publishes each event in one step
 }
 
 // TaskServiceConfig holds configuration for the task service
 type TaskServiceConfig struct {
 	WebDomain          string `json:"web_domain"`
+	BasePath           string `json:"base_path"` // Path prefix the web interface is served under, "" for the root
 	AutoNotifyHookTypes []domain.HookType `json:"auto_notify_hook_types"`
+	MaxOutputBytes     int    `json:"max_output_bytes"` // Stdout/stderr kept per PostToolUse hook, DefaultMaxToolOutputBytes when 0
+
+	// PreCompact hooks copy the session transcript into TranscriptBackupDir when enabled
+	PreCompactBackupEnabled bool   `json:"precompact_backup_enabled"`
+	TranscriptBackupDir     string `json:"transcript_backup_dir"`
+	MaxBackupSize           int64  `json:"max_backup_size"` // Bytes copied per transcript, DefaultMaxTranscriptBackupBytes when 0
+
+	// CountTranscriptLines stores a PreCompact hook's transcript line count with the task as a context size estimate
+	CountTranscriptLines bool `json:"count_transcript_lines"`
+
+	// MaxConcurrentSessions is how many sessions may wait on the user at once before an urgent alert; 0 disables it
+	MaxConcurrentSessions int `json:"max_concurrent_sessions"`
+
+	// SerializePerSession makes a session's blocking webhooks wait on the user one at a time
+	SerializePerSession bool `json:"serialize_per_session"`
+
+	// HookTimeouts sets how long blocking hooks of each type wait for a decision; others use DefaultDecisionTimeout
+	HookTimeouts map[domain.HookType]time.Duration `json:"hook_timeouts"`
+
+	// QuickActionSecret signs approve and reject links in notifications, valid while the hook waits; empty leaves them out
+	QuickActionSecret string `json:"-"`
+
+	// UseTLS makes quick action links https, so their signed tokens aren't sent in the clear
+	UseTLS bool `json:"use_tls"`
+
+	// PostToolUseProcessors analyse each finished tool call; their annotations are stored in the task's history
+	PostToolUseProcessors []ports.PostToolUseProcessor `json:"-"`
+}
+
+// NewTaskService creates a task service whose blocking webhooks wait on an in-memory decision manager
+func NewTaskService(taskRepo ports.TaskRepository, historyRepo ports.TaskHistoryRepository, notificationSvc ports.NotificationSender, responseBuilder ports.HookResponseBuilder, config *TaskServiceConfig) *TaskService {
+	return &TaskService{
+		taskRepo:        taskRepo,
+		historyRepo:     historyRepo,
+		notificationSvc: notificationSvc,
+		responseBuilder: responseBuilder,
+		decisionManager: NewTaskDecisionManager(),
+		config:          config,
+		metrics:         noopTaskMetrics{},
+
+		watcher:        NewTaskWatcher(),
+		statsCache:     NewToolStatsCache(DefaultToolStatsCacheTTL),
+		countsCache:    NewTaskCountsCache(DefaultTaskCountsCacheTTL),
+		resendLimiter:  NewRenotifyLimiter(RenotifyInterval),
+		receipts:       NewReceiptTracker(ReceiptTimeout),
+		sessionMonitor: NewConcurrentSessionMonitor(config.MaxConcurrentSessions),
+		sessionSlots:   NewSessionSemaphore(),
+
+		events:       NewEventBroadcaster[TaskEvent](DefaultEventSubscriberBuffer),
+		eventHistory: NewTaskEventHistory(DefaultTaskEventHistorySize),
+	}
 }
 
 // CreateTask creates a new task with structured hook data
@@ -33,6 +103,8 @@ func (s *TaskService) CreateTask(ctx context.Context, task *domain.Task) error {
 	if err := s.taskRepo.Create(ctx, task); err != nil {
 		return fmt.Errorf("failed to create task: %w", err)
 	}
+	s.publishTaskEvent(ctx, TaskEventCreated, task)
+	s.metrics.WebhookReceived(task.HookType)
 
 	// Create history entry
 	history := domain.NewTaskHistory(task.ID, domain.HistoryActionCreated, map[string]interface{}{
@@ -53,6 +125,11 @@ func (s *TaskService) CreateTask(ctx context.Context, task *domain.Task) error {
 
 // sendNotificationIfRequired sends a notification if the hook type requires it
 func (s *TaskService) sendNotificationIfRequired(ctx context.Context, task *domain.Task) {
+	if isSnoozed(task.SnoozedUntil, time.Now()) {
+		log.Printf("Skipping notification for task %s (%s): snoozed until %s", task.ID, task.Summary(), task.SnoozedUntil.Format(time.RFC3339))
+		return
+	}
+
 	if s.shouldNotify(task.HookType) {
 		if err := s.sendNotification(ctx, task); err != nil {
 			log.Printf("Warning: failed to send notification for task %s: %v", task.ID, err)
@@ -60,15 +137,138 @@ func (s *TaskService) sendNotificationIfRequired(ctx context.Context, task *doma
 	}
 }
 
+// SetMetrics records webhook, decision and timeout statistics with metrics
+func (s *TaskService) SetMetrics(metrics ports.TaskMetrics) {
+	s.metrics = metrics
+}
+
+// SetDecisionManager replaces the manager blocking webhooks wait on, e.g. with one that persists pending decisions
+// Call it before webhooks are served; decisions already being waited on stay with the old manager.
+func (s *TaskService) SetDecisionManager(decisionManager ports.TaskDecisionManager) {
+	s.decisionManager = decisionManager
+}
+
+// SetFailureCapture attaches the terminal scrollback to the history of tasks for failed tool calls
+func (s *TaskService) SetFailureCapture(capturer *FailureScrollbackCapturer) {
+	s.failureCapture = capturer
+}
+
+// recordFailureScrollback attaches the terminal scrollback of a failed tool call to the task's history
+// Does nothing unless failure capture was enabled with SetFailureCapture.
+func (s *TaskService) recordFailureScrollback(ctx context.Context, taskID uuid.UUID, hookData *domain.HookData) {
+	if s.failureCapture == nil {
+		return
+	}
+
+	sessionName, scrollback, err := s.failureCapture.Capture(ctx, hookData)
+	if err != nil {
+		log.Printf("Warning: failed to capture terminal scrollback for task %s: %v", taskID, err)
+		return
+	}
+	if sessionName == "" {
+		return
+	}
+
+	history := domain.NewTaskHistory(taskID, historyActionScrollbackCaptured, map[string]interface{}{
+		"tmux_session":        sessionName,
+		terminalScrollbackKey: scrollback,
+	})
+	if err := s.historyRepo.Create(ctx, history); err != nil {
+		log.Printf("Warning: failed to create task history: %v", err)
+	}
+}
+
+// recordTranscriptBackup copies a PreCompact hook's transcript and records the backup in the task's history
+// Does nothing unless PreCompactBackupEnabled is set; failures are logged since the hook must still continue.
+func (s *TaskService) recordTranscriptBackup(ctx context.Context, taskID uuid.UUID, hookData *domain.HookData) {
+	if s.config == nil || !s.config.PreCompactBackupEnabled {
+		return
+	}
+
+	backup := NewTranscriptBackup(s.config.TranscriptBackupDir, s.config.MaxBackupSize)
+	result, err := backup.Backup(hookData, time.Now())
+	if err != nil {
+		log.Printf("Warning: failed to back up transcript for task %s: %v", taskID, err)
+		return
+	}
+	if result == nil {
+		return
+	}
+
+	history := domain.NewTaskHistory(taskID, historyActionTranscriptBackedUp, map[string]interface{}{
+		"transcript_path":       result.SourcePath,
+		transcriptBackupPathKey: result.BackupPath,
+		"bytes":                 result.Bytes,
+		"truncated":             result.Truncated,
+	})
+	if err := s.historyRepo.Create(ctx, history); err != nil {
+		log.Printf("Warning: failed to create task history: %v", err)
+	}
+}
+
+// recordPostToolUseAnnotations runs the configured PostToolUse processors and records their annotations
+// in the task's history. Processor failures are logged; the hook is non-blocking so it continues regardless.
+func (s *TaskService) recordPostToolUseAnnotations(ctx context.Context, taskID uuid.UUID, hookData *domain.HookData) {
+	if s.config == nil || len(s.config.PostToolUseProcessors) == 0 {
+		return
+	}
+	postToolUse, ok := hookData.Data.(*domain.PostToolUseHookData)
+	if !ok {
+		return
+	}
+
+	annotations, errs := RunPostToolUseProcessors(ctx, s.config.PostToolUseProcessors, postToolUse)
+	for _, err := range errs {
+		log.Printf("Warning: task %s: %v", taskID, err)
+	}
+	if len(annotations) == 0 {
+		return
+	}
+
+	history := domain.NewTaskHistory(taskID, historyActionAnnotated, map[string]interface{}{
+		"annotations": annotations,
+	})
+	if err := s.historyRepo.Create(ctx, history); err != nil {
+		log.Printf("Warning: failed to create task history: %v", err)
+	}
+}
+
+// GetTranscriptBackupPath returns where a PreCompact task's transcript was backed up
+func (s *TaskService) GetTranscriptBackupPath(ctx context.Context, taskID uuid.UUID) (string, error) {
+	history, err := s.historyRepo.GetByTaskID(ctx, taskID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get task history: %w", err)
+	}
+
+	for i := len(history) - 1; i >= 0; i-- {
+		if string(history[i].Action) != historyActionTranscriptBackedUp {
+			continue
+		}
+		if backupPath, ok := history[i].Data[transcriptBackupPathKey].(string); ok {
+			return backupPath, nil
+		}
+	}
+	return "", ErrTranscriptBackupNotFound
+}
+
 // CreateTaskFromHook processes an incoming Claude Code hook and creates a task
 func (s *TaskService) CreateTaskFromHook(ctx context.Context, hookData *domain.HookData) (*domain.Task, error) {
 	// Create new task with structured data
+	hookData.UpdateDangerScore()
+	hookData.UpdateCommandRisk()
+	truncation := s.truncateToolOutput(hookData)
+	s.estimateContextSize(hookData)
 	task := domain.NewTask(hookData)
+	s.linkParentTask(ctx, task)
 
 	// Store task using the new CreateTask method
 	if err := s.CreateTask(ctx, task); err != nil {
 		return nil, err
 	}
+	s.recordOutputTruncation(ctx, task.ID, truncation)
+	s.recordFailureScrollback(ctx, task.ID, hookData)
+	s.recordTranscriptBackup(ctx, task.ID, hookData)
+	s.recordPostToolUseAnnotations(ctx, task.ID, hookData)
 
 	return task, nil
 }
@@ -98,23 +298,138 @@ func (s *TaskService) ListTasks(ctx context.Context, filter ports.TaskFilter) ([
 	return s.taskRepo.List(ctx, filter)
 }
 
+// CountTasks counts the tasks ListTasks would return for the filter, ignoring its limit and offset
+func (s *TaskService) CountTasks(ctx context.Context, filter ports.TaskFilter) (int64, error) {
+	return s.taskRepo.Count(ctx, filter)
+}
+
 // GetPendingTasks retrieves all tasks that require user action
 func (s *TaskService) GetPendingTasks(ctx context.Context) ([]*domain.Task, error) {
 	return s.taskRepo.GetPendingTasks(ctx)
 }
 
+// GetPendingTasksForSession retrieves the tasks awaiting user action for one Claude Code session
+func (s *TaskService) GetPendingTasksForSession(ctx context.Context, sessionID string) ([]*domain.Task, error) {
+	return s.taskRepo.GetPendingTasksForSession(ctx, sessionID)
+}
+
+// GetSessionSummary aggregates the tasks raised by one Claude Code session
+func (s *TaskService) GetSessionSummary(ctx context.Context, sessionID string) (*ports.SessionSummary, error) {
+	summary, err := s.taskRepo.GetSessionSummary(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session summary: %w", err)
+	}
+	if summary.TotalTasks == 0 {
+		return nil, ErrSessionNotFound
+	}
+	return summary, nil
+}
+
+// GetToolUsageStats returns per-tool call and decision counts for tasks created within the window
+// Results are cached per window for DefaultToolStatsCacheTTL since the aggregation scans every task.
+func (s *TaskService) GetToolUsageStats(ctx context.Context, window time.Duration) ([]ports.ToolUsageStat, error) {
+	now := time.Now()
+	if stats, ok := s.statsCache.Get(window, now); ok {
+		return stats, nil
+	}
+
+	stats, err := s.taskRepo.GetToolUsageStats(ctx, now.Add(-window))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tool usage stats: %w", err)
+	}
+
+	s.statsCache.Set(window, stats, now)
+	return stats, nil
+}
+
+// GetStats returns task totals, per hook type, status and action counts, decision durations
+// and compaction counts for tasks created since the given time
+func (s *TaskService) GetStats(ctx context.Context, since time.Time) (*ports.TaskStats, error) {
+	stats, err := s.taskRepo.GetTaskStats(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task stats: %w", err)
+	}
+
+	compacts, err := s.GetCompactStats(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	stats.Compacts = *compacts
+	return stats, nil
+}
+
+// GetTaskCounts returns how many tasks are in each status and were raised by each hook type
+// Results are cached for DefaultTaskCountsCacheTTL so dashboard badges don't query on every page load.
+func (s *TaskService) GetTaskCounts(ctx context.Context) (*TaskCounts, error) {
+	now := time.Now()
+	if counts, ok := s.countsCache.Get(now); ok {
+		return counts, nil
+	}
+
+	byStatus, err := s.taskRepo.CountByStatus(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks by status: %w", err)
+	}
+
+	byHookType, err := s.taskRepo.CountByHookType(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks by hook type: %w", err)
+	}
+
+	counts := &TaskCounts{ByStatus: byStatus, ByHookType: byHookType}
+	s.countsCache.Set(counts, now)
+	return counts, nil
+}
+
+// GetConcurrentSessionCount returns how many sessions raised a still-pending task within ConcurrentSessionWindow
+func (s *TaskService) GetConcurrentSessionCount(ctx context.Context) (int, error) {
+	count, err := s.taskRepo.CountConcurrentSessions(ctx, time.Now().Add(-ConcurrentSessionWindow))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count concurrent sessions: %w", err)
+	}
+	return count, nil
+}
+
+// CheckConcurrentSessions counts waiting sessions and sends an urgent alert when there are more than
+// MaxConcurrentSessions. It alerts once per busy stretch and is meant to be polled every minute.
+func (s *TaskService) CheckConcurrentSessions(ctx context.Context) (int, error) {
+	count, err := s.GetConcurrentSessionCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if !s.sessionMonitor.Observe(count) {
+		return count, nil
+	}
+
+	notification := domain.NewConcurrentSessionsNotification(count, s.config.WebDomain+s.config.BasePath)
+	if err := s.notificationSvc.Send(ctx, notification); err != nil {
+		s.sessionMonitor.Rearm()
+		return count, fmt.Errorf("failed to send concurrent sessions alert: %w", err)
+	}
+
+	log.Printf("Sent concurrent sessions alert: %d sessions waiting (max %d)", count, s.config.MaxConcurrentSessions)
+	return count, nil
+}
+
 // TakeAction processes a user action on a task
 func (s *TaskService) TakeAction(ctx context.Context, taskID uuid.UUID, action domain.ActionType, responseData map[string]interface{}) error {
 	// Get the task
 	task, err := s.taskRepo.GetByID(ctx, taskID)
 	if err != nil {
-		return fmt.Errorf("failed to get task: %w", err)
+		return fmt.Errorf("%w: %s: %v", ErrTaskNotFound, taskID, err)
 	}
 
-	// Check if task is actionable
+	// Only a pending task without a terminal decision can be acted on; a skipped task can still be decided
 	if !task.IsActionable() {
-		return fmt.Errorf("task %s is not actionable (status: %s)", taskID, task.Status.String())
+		return fmt.Errorf("%w: %s (status: %s)", ErrTaskNotActionable, taskID, task.Status.String())
+	}
+
+	// Apply any command override set before the decision and record the original command next to it
+	if responseData == nil {
+		responseData = make(map[string]interface{})
 	}
+	applyCommandOverride(action, task.HookData, task.ResponseData, responseData)
 
 	// Take the action on the task
 	task.TakeAction(action, responseData)
@@ -123,12 +438,20 @@ func (s *TaskService) TakeAction(ctx context.Context, taskID uuid.UUID, action d
 	if err := s.taskRepo.Update(ctx, task); err != nil {
 		return fmt.Errorf("failed to update task: %w", err)
 	}
+	s.notifyTaskUpdated(ctx, task)
+	s.metrics.DecisionMade(task.HookType, action)
 
 	// Create history entry
-	history := domain.NewTaskHistory(task.ID, string(action), responseData)
+	history := domain.NewTaskHistory(task.ID, string(action), withoutComment(responseData))
 	if err := s.historyRepo.Create(ctx, history); err != nil {
 		log.Printf("Warning: failed to create task history: %v", err)
 	}
+	s.recordComment(ctx, task.ID, action, responseData)
+
+	// A rejected parent takes its pending children with it
+	if action == domain.ActionTypeReject {
+		s.cancelChildTasks(ctx, task)
+	}
 
 	// Note: In JSON-based architecture, responses are handled via webhook returns
 	// No need to send TMux commands as Claude Code receives JSON responses directly
@@ -136,12 +459,155 @@ func (s *TaskService) TakeAction(ctx context.Context, taskID uuid.UUID, action d
 	return nil
 }
 
+// SetModifiedCommand stores a command to run instead of the original once a pending PreToolUse task is approved
+// An empty command clears the override.
+func (s *TaskService) SetModifiedCommand(ctx context.Context, taskID uuid.UUID, command string) (*domain.Task, error) {
+	if err := domain.ValidateModifiedCommand(command); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidModifiedCommand, err)
+	}
+
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	if !task.IsActionable() {
+		return nil, fmt.Errorf("%w: %s (status: %s)", ErrTaskNotActionable, taskID, task.Status.String())
+	}
+	if task.HookType != domain.HookTypePreToolUse {
+		return nil, fmt.Errorf("%w: %s is a %s task", ErrCommandNotModifiable, taskID, task.HookType.String())
+	}
+
+	if task.ResponseData == nil {
+		task.ResponseData = make(map[string]interface{})
+	}
+	if command == "" {
+		delete(task.ResponseData, modifiedCommandKey)
+	} else {
+		task.ResponseData[modifiedCommandKey] = command
+	}
+	task.UpdatedAt = time.Now()
+
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return nil, fmt.Errorf("failed to update task: %w", err)
+	}
+	s.notifyTaskUpdated(ctx, task)
+
+	history := domain.NewTaskHistory(task.ID, historyActionCommandModified, map[string]interface{}{
+		originalCommandKey: task.HookData.GetCommand(),
+		modifiedCommandKey: command,
+	})
+	if err := s.historyRepo.Create(ctx, history); err != nil {
+		log.Printf("Warning: failed to create task history: %v", err)
+	}
+
+	return task, nil
+}
+
+// SnoozeTask silences notifications for a pending task for the given duration
+func (s *TaskService) SnoozeTask(ctx context.Context, taskID uuid.UUID, duration time.Duration) (*domain.Task, error) {
+	until, err := snoozeDeadline(time.Now(), duration)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.setSnoozedUntil(ctx, taskID, &until)
+}
+
+// UnsnoozeTask clears a task's snooze so notifications resume
+func (s *TaskService) UnsnoozeTask(ctx context.Context, taskID uuid.UUID) (*domain.Task, error) {
+	return s.setSnoozedUntil(ctx, taskID, nil)
+}
+
+// setSnoozedUntil stores a new snooze deadline on a pending task
+func (s *TaskService) setSnoozedUntil(ctx context.Context, taskID uuid.UUID, until *time.Time) (*domain.Task, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	if !task.IsActionable() {
+		return nil, fmt.Errorf("%w: %s (status: %s)", ErrTaskNotActionable, taskID, task.Status.String())
+	}
+
+	task.SnoozedUntil = until
+	task.UpdatedAt = time.Now()
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return nil, fmt.Errorf("failed to update task: %w", err)
+	}
+	s.notifyTaskUpdated(ctx, task)
+
+	return task, nil
+}
+
+// ResendExpiredSnoozes clears snoozes that have ended and re-sends their tasks' notifications
+// Returns how many tasks were woken up.
+func (s *TaskService) ResendExpiredSnoozes(ctx context.Context) (int, error) {
+	tasks, err := s.taskRepo.GetExpiredSnoozes(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get expired snoozes: %w", err)
+	}
+
+	woken := 0
+	for _, task := range tasks {
+		cleared, err := s.taskRepo.ClearSnooze(ctx, task.ID)
+		if err != nil {
+			log.Printf("Warning: failed to clear snooze for task %s: %v", task.ID, err)
+			continue
+		}
+		if !cleared {
+			continue // Resolved or unsnoozed since it was loaded
+		}
+		task.SnoozedUntil = nil
+		woken++
+
+		s.sendNotificationIfRequired(ctx, task)
+	}
+
+	return woken, nil
+}
+
+// RenotifyTask re-sends the notification for a pending task, e.g. when the phone was offline the first time
+// Each task can be re-sent once per RenotifyInterval. Returns where the notification was delivered.
+func (s *TaskService) RenotifyTask(ctx context.Context, taskID uuid.UUID) (ports.NotificationDestination, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return ports.NotificationDestination{}, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	if task.Status != domain.TaskStatusPending {
+		return ports.NotificationDestination{}, fmt.Errorf("%w: %s (status: %s)", ErrTaskNotActionable, taskID, task.Status.String())
+	}
+
+	if allowed, retryAfter := s.resendLimiter.Allow(taskID, time.Now()); !allowed {
+		return ports.NotificationDestination{}, fmt.Errorf("%w: task %s, retry in %s", ErrRenotifyRateLimited, taskID, retryAfter.Round(time.Second))
+	}
+
+	notification, err := s.deliverNotification(ctx, task)
+	if err != nil {
+		s.resendLimiter.Release(taskID)
+		return ports.NotificationDestination{}, err
+	}
+
+	history := domain.NewTaskHistory(task.ID, historyActionRenotified, map[string]interface{}{
+		"notification_id": notification.ID.String(),
+		"title":           notification.Title,
+	})
+	if err := s.historyRepo.Create(ctx, history); err != nil {
+		log.Printf("Warning: failed to create notification history: %v", err)
+	}
+
+	if router, ok := s.notificationSvc.(ports.NotificationRouter); ok {
+		return router.DestinationFor(notification), nil
+	}
+	return ports.NotificationDestination{}, nil
+}
+
 // sendNotification creates and sends a notification for a task
 func (s *TaskService) sendNotification(ctx context.Context, task *domain.Task) error {
-	notification := domain.NewNotification(task.ID, task.HookType, s.config.WebDomain)
-
-	if err := s.notificationSvc.Send(ctx, notification); err != nil {
-		return fmt.Errorf("failed to send notification: %w", err)
+	notification, err := s.deliverNotification(ctx, task)
+	if err != nil {
+		return err
 	}
 
 	// Create history entry for notification
@@ -156,6 +622,37 @@ func (s *TaskService) sendNotification(ctx context.Context, task *domain.Task) e
 	return nil
 }
 
+// deliverNotification builds a task's notification and sends it without recording history
+func (s *TaskService) deliverNotification(ctx context.Context, task *domain.Task) (*domain.Notification, error) {
+	var sourceCWD string
+	if task.HookData != nil {
+		sourceCWD = task.HookData.GetCWD()
+	}
+	notification := domain.NewNotification(task.ID, task.HookType, s.config.WebDomain+s.config.BasePath, sourceCWD)
+	if task.HookData != nil {
+		notification.SessionID = task.HookData.GetSessionID()
+		notification.Message = task.Summary()
+		notification.ApplyCommandRisk(task.HookData.CommandRisk)
+		notification.EscalateForDanger(task.HookData.DangerScore)
+	}
+	notification.SetQuickActions(s.quickActionBaseURL(), s.config.QuickActionSecret, s.GetTimeoutForHookType(task.HookType))
+
+	if err := s.notificationSvc.Send(ctx, notification); err != nil {
+		return nil, fmt.Errorf("failed to send notification: %w", err)
+	}
+
+	return notification, nil
+}
+
+// quickActionBaseURL returns the scheme, domain and base path quick action links are built on
+func (s *TaskService) quickActionBaseURL() string {
+	scheme := "http"
+	if s.config.UseTLS {
+		scheme = "https"
+	}
+	return scheme + "://" + s.config.WebDomain + s.config.BasePath
+}
+
 
 // shouldNotify determines if a hook type should trigger a notification
 func (s *TaskService) shouldNotify(hookType domain.HookType) bool {
@@ -167,16 +664,47 @@ func (s *TaskService) shouldNotify(hookType domain.HookType) bool {
 	return false
 }
 
+// GetTimeoutForHookType returns how long a blocking hook of this type should wait for the user's decision
+func (s *TaskService) GetTimeoutForHookType(hookType domain.HookType) time.Duration {
+	return hookTimeout(s.config.HookTimeouts, hookType)
+}
+
 // CreateTaskAndWaitForDecision creates a task and waits for user decision, returning hook response
 func (s *TaskService) CreateTaskAndWaitForDecision(ctx context.Context, hookData *domain.HookData, timeout time.Duration) (*domain.HookResponse, error) {
+	// Queue behind the session's earlier webhook before creating the task, so only one of its dialogs is shown at a time
+	if s.config.SerializePerSession {
+		release, err := s.sessionSlots.Acquire(ctx, hookData.GetSessionID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to wait for session %s's earlier decision: %w", hookData.GetSessionID(), err)
+		}
+		s.metrics.ConcurrentSessions(s.sessionSlots.Held())
+		defer func() {
+			release()
+			s.metrics.ConcurrentSessions(s.sessionSlots.Held())
+		}()
+	}
+
 	// Create new task with structured data
+	hookData.UpdateDangerScore()
+	hookData.UpdateCommandRisk()
+	truncation := s.truncateToolOutput(hookData)
+	s.estimateContextSize(hookData)
 	task := domain.NewTask(hookData)
+	s.linkParentTask(ctx, task)
 
 	// Store task
 	if err := s.taskRepo.Create(ctx, task); err != nil {
 		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
 
+	// Open the decision channel before anyone hears of the task, so a decision made straight away,
+	// e.g. from a notification button, reaches the wait below instead of being dropped
+	s.decisionManager.OpenSessionDecision(ctx, task.ID.String(), hookData.GetSessionID(), timeout)
+
+	s.publishTaskEvent(ctx, TaskEventCreated, task)
+	s.metrics.WebhookReceived(task.HookType)
+	s.recordOutputTruncation(ctx, task.ID, truncation)
+
 	// Create history entry
 	history := domain.NewTaskHistory(task.ID, domain.HistoryActionCreated, map[string]interface{}{
 		"hook_type":  hookData.Type.String(),
@@ -196,35 +724,81 @@ func (s *TaskService) CreateTaskAndWaitForDecision(ctx context.Context, hookData
 	}
 
 	// Wait for user decision
-	decision, err := s.decisionManager.WaitForDecision(ctx, task.ID.String(), timeout)
+	waitStarted := time.Now()
+	decision, err := s.decisionManager.AwaitDecision(ctx, task.ID.String(), timeout)
+
+	// Decisions made from the dashboard or a notification have already been taken and stored along with
+	// any comment or substituted command, so work from the stored task rather than the one created above
+	task = s.reloadTask(ctx, task)
 	if err != nil {
-		// On timeout or error, update task status and return timeout response
-		task.Status = domain.TaskStatusFailed
-		s.taskRepo.Update(ctx, task)
-		
-		return s.responseBuilder.BuildTimeoutResponse(task.ID.String(), timeout), nil
+		// On timeout or error, fail the task unless a decision landed after the wait gave up
+		s.metrics.DecisionTimedOut(task.HookType)
+		if task.IsActionable() {
+			task.Status = domain.TaskStatusFailed
+			task.UpdatedAt = time.Now()
+			if err := s.taskRepo.Update(ctx, task); err != nil {
+				log.Printf("Warning: failed to mark task %s as timed out: %v", task.ID, err)
+			}
+			s.notifyTaskUpdated(ctx, task)
+		}
+
+		return s.attachReceipt(s.responseBuilder.BuildTimeoutResponse(task.ID.String(), timeout), task.ID), nil
 	}
 
-	// Update task with decision
-	task.TakeAction(decision, map[string]interface{}{
-		"decision_time": time.Now(),
-		"blocking_call": true,
-	})
-	s.taskRepo.Update(ctx, task)
+	s.metrics.DecisionWaited(task.HookType, time.Since(waitStarted))
 
-	// Create history entry for decision
-	history = domain.NewTaskHistory(task.ID, string(decision), map[string]interface{}{
-		"blocking_decision": true,
-	})
-	s.historyRepo.Create(ctx, history)
+	// A decision sent without taking the action, e.g. cancelling a stuck wait, is recorded here
+	if task.IsActionable() {
+		decisionData := map[string]interface{}{
+			"decision_time": time.Now(),
+			"blocking_call": true,
+		}
+		applyCommandOverride(decision, task.HookData, task.ResponseData, decisionData)
+		task.TakeAction(decision, decisionData)
+		if err := s.taskRepo.Update(ctx, task); err != nil {
+			log.Printf("Warning: failed to record decision for task %s: %v", task.ID, err)
+		}
+		s.notifyTaskUpdated(ctx, task)
+
+		history = domain.NewTaskHistory(task.ID, string(decision), map[string]interface{}{
+			"blocking_decision": true,
+		})
+		if err := s.historyRepo.Create(ctx, history); err != nil {
+			log.Printf("Warning: failed to create task history: %v", err)
+		}
+	}
+
+	var modifiedCommand string
+	if decision == domain.ActionTypeApprove {
+		modifiedCommand, _ = task.ResponseData[modifiedCommandKey].(string)
+	}
 
 	// Return appropriate hook response based on user decision
-	return s.responseBuilder.BuildResponseFromDecision(task.ID.String(), decision), nil
+	hookResponse := s.responseBuilder.BuildResponseFromDecision(task.ID.String(), decision, modifiedCommand)
+	if err := hookResponse.Validate(); err != nil {
+		log.Printf("Warning: dropping modified command for task %s: %v", task.ID, err)
+		hookResponse.ModifiedCommand = ""
+	}
+	return s.attachReceipt(hookResponse, task.ID), nil
+}
+
+// reloadTask returns the stored copy of task, or task itself when it can't be loaded
+func (s *TaskService) reloadTask(ctx context.Context, task *domain.Task) *domain.Task {
+	storedTask, err := s.taskRepo.GetByID(ctx, task.ID)
+	if err != nil {
+		log.Printf("Warning: failed to reload task %s: %v", task.ID, err)
+		return task
+	}
+	return storedTask
 }
 
 // CreateNonBlockingResponse creates a hook response for non-blocking hooks
 func (s *TaskService) CreateNonBlockingResponse(ctx context.Context, hookData *domain.HookData, suppressOutput bool) (*domain.HookResponse, error) {
 	// Create new task with structured data
+	hookData.UpdateDangerScore()
+	hookData.UpdateCommandRisk()
+	truncation := s.truncateToolOutput(hookData)
+	s.estimateContextSize(hookData)
 	task := domain.NewTask(hookData)
 	task.Status = domain.TaskStatusCompleted // Non-blocking tasks are immediately completed
 
@@ -232,6 +806,12 @@ func (s *TaskService) CreateNonBlockingResponse(ctx context.Context, hookData *d
 	if err := s.taskRepo.Create(ctx, task); err != nil {
 		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
+	s.publishTaskEvent(ctx, TaskEventCreated, task)
+	s.metrics.WebhookReceived(task.HookType)
+	s.recordOutputTruncation(ctx, task.ID, truncation)
+	s.recordFailureScrollback(ctx, task.ID, hookData)
+	s.recordTranscriptBackup(ctx, task.ID, hookData)
+	s.recordPostToolUseAnnotations(ctx, task.ID, hookData)
 
 	// Create history entry
 	history := domain.NewTaskHistory(task.ID, domain.HistoryActionCreated, map[string]interface{}{
@@ -253,9 +833,37 @@ func (s *TaskService) CreateNonBlockingResponse(ctx context.Context, hookData *d
 
 	// Return appropriate non-blocking response
 	if suppressOutput {
-		return s.responseBuilder.BuildSuppressedResponse(), nil
+		return s.attachReceipt(s.responseBuilder.BuildSuppressedResponse(), task.ID), nil
+	}
+	return s.attachReceipt(s.responseBuilder.BuildContinueResponse(), task.ID), nil
+}
+
+// attachReceipt gives a hook response a receipt token so Claude Code can acknowledge processing it
+func (s *TaskService) attachReceipt(response *domain.HookResponse, taskID uuid.UUID) *domain.HookResponse {
+	response.ReceiptToken = s.receipts.Issue(taskID, time.Now())
+	return response
+}
+
+// AcknowledgeReceipt records that Claude Code processed the hook response carrying token
+// Receipts are optional, so a response that is never acknowledged only shows up in GetReceiptStats.
+func (s *TaskService) AcknowledgeReceipt(ctx context.Context, token string) error {
+	taskID, latency, ok := s.receipts.Acknowledge(token, time.Now())
+	if !ok {
+		return ports.ErrUnknownReceipt
+	}
+
+	history := domain.NewTaskHistory(taskID, historyActionAcknowledged, map[string]interface{}{
+		"latency_ms": latency.Milliseconds(),
+	})
+	if err := s.historyRepo.Create(ctx, history); err != nil {
+		log.Printf("Warning: failed to record receipt for task %s: %v", taskID, err)
 	}
-	return s.responseBuilder.BuildContinueResponse(), nil
+	return nil
+}
+
+// GetReceiptStats returns how many hook responses were and weren't acknowledged since startup
+func (s *TaskService) GetReceiptStats() ReceiptStats {
+	return s.receipts.Stats(time.Now())
 }
 
 // SendDecisionToTask sends a user decision to a waiting task
@@ -273,9 +881,88 @@ func (s *TaskService) GetActiveDecisions() int {
 	return s.decisionManager.GetActiveDecisions()
 }
 
-// CleanupOldTasks removes old completed tasks and their history
-func (s *TaskService) CleanupOldTasks(ctx context.Context, retentionDays int) error {
-	// This would typically be implemented with a database query
-	// For now, we'll just clean up old history entries
-	return s.historyRepo.DeleteOlderThan(ctx, retentionDays)
+// WatchTask returns a channel that is closed the next time the task is updated
+func (s *TaskService) WatchTask(taskID uuid.UUID) <-chan struct{} {
+	return s.watcher.Watch(taskID)
+}
+
+// SubscribeTaskEvents returns a channel of task creations and updates, and a function to unsubscribe
+// Subscribers that fall DefaultEventSubscriberBuffer events behind miss events rather than slow hooks down.
+func (s *TaskService) SubscribeTaskEvents() (<-chan TaskEvent, func()) {
+	return s.events.Subscribe()
+}
+
+// ResumeTaskEvents subscribes like SubscribeTaskEvents and also returns the remembered events numbered
+// after lastEventID, so a reconnecting client sees nothing twice and misses nothing still in the
+// last DefaultTaskEventHistorySize events
+func (s *TaskService) ResumeTaskEvents(lastEventID uint64) ([]TaskEvent, <-chan TaskEvent, func()) {
+	s.eventsMutex.Lock()
+	defer s.eventsMutex.Unlock()
+	subscription, unsubscribe := s.events.Subscribe()
+	return s.eventHistory.Since(lastEventID), subscription, unsubscribe
+}
+
+// notifyTaskUpdated wakes long-polling watchers of the task and publishes the update to event subscribers
+func (s *TaskService) notifyTaskUpdated(ctx context.Context, task *domain.Task) {
+	s.watcher.Notify(task.ID)
+	s.publishTaskEvent(ctx, TaskEventUpdated, task)
+}
+
+// publishTaskEvent numbers the event, records it in the event history and sends it to subscribers
+func (s *TaskService) publishTaskEvent(ctx context.Context, eventType TaskEventType, task *domain.Task) {
+	s.eventsMutex.Lock()
+	defer s.eventsMutex.Unlock()
+	s.events.Publish(s.eventHistory.Append(newTaskEvent(ctx, eventType, task)))
+}
+
+// GetStuckDecisions returns blocking waits that have been open longer than maxAge
+func (s *TaskService) GetStuckDecisions(maxAge time.Duration) []ports.StuckDecision {
+	return s.decisionManager.GetStuckDecisions(maxAge)
+}
+
+// ForceResolveStuckDecisions cancels blocking waits older than maxAge, returning how many were cancelled
+func (s *TaskService) ForceResolveStuckDecisions(maxAge time.Duration) int {
+	resolved := s.decisionManager.ForceResolveStuck(maxAge)
+	if resolved > 0 {
+		log.Printf("Warning: Cancelled %d stuck decision waits older than %v", resolved, maxAge)
+	}
+	return resolved
+}
+
+// ArchiveCompletedTasks moves tasks that were resolved more than olderThan ago to the archive
+func (s *TaskService) ArchiveCompletedTasks(ctx context.Context, olderThan time.Duration) (int, error) {
+	archived, err := s.taskRepo.ArchiveCompleted(ctx, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive completed tasks: %w", err)
+	}
+	return archived, nil
+}
+
+// ListArchivedTasks retrieves archived tasks with optional filtering
+func (s *TaskService) ListArchivedTasks(ctx context.Context, filter ports.TaskFilter) ([]*domain.Task, error) {
+	return s.taskRepo.ListArchived(ctx, filter)
+}
+
+// GetHourlyActivity returns per-hour history event counts for the window ending now
+func (s *TaskService) GetHourlyActivity(ctx context.Context, window time.Duration) ([]ports.HourlyBucket, error) {
+	buckets, err := s.historyRepo.GetHourlyActivity(ctx, time.Now().Add(-window))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hourly activity: %w", err)
+	}
+	return buckets, nil
+}
+
+// CleanupOldTasks archives tasks resolved more than retentionDays ago and deletes history entries older than that,
+// returning how many tasks and entries were cleaned up
+func (s *TaskService) CleanupOldTasks(ctx context.Context, retentionDays int) (int64, error) {
+	archived, err := s.ArchiveCompletedTasks(ctx, time.Duration(retentionDays)*24*time.Hour)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := s.historyRepo.DeleteOlderThan(ctx, retentionDays)
+	if err != nil {
+		return int64(archived), fmt.Errorf("failed to delete old task history: %w", err)
+	}
+	return int64(archived) + deleted, nil
 }
\ No newline at end of file
@@ -0,0 +1,329 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/adapters/memory"
+	"github.com/dan/claude-control/internal/adapters/mock"
+	"github.com/dan/claude-control/internal/adapters/response"
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// historyActions returns the recorded history actions, oldest first
+func historyActions(t *testing.T, historyRepo ports.TaskHistoryRepository) []string {
+	t.Helper()
+	entries, err := historyRepo.List(context.Background(), ports.TaskHistoryFilter{SortBy: "created_at", SortOrder: "asc"})
+	if err != nil {
+		t.Fatalf("Failed to list history: %v", err)
+	}
+	actions := make([]string, len(entries))
+	for i, entry := range entries {
+		actions[i] = entry.Action
+	}
+	return actions
+}
+
+// storedTasks returns every task in the repository
+func storedTasks(t *testing.T, taskRepo ports.TaskRepository) []*domain.Task {
+	t.Helper()
+	tasks, err := taskRepo.List(context.Background(), ports.TaskFilter{})
+	if err != nil {
+		t.Fatalf("Failed to list tasks: %v", err)
+	}
+	return tasks
+}
+
+func newBlockingHookData(sessionID string) *domain.HookData {
+	return &domain.HookData{
+		Type: domain.HookTypePreToolUse,
+		Data: &domain.PreToolUseHookData{
+			BaseHookData: domain.BaseHookData{HookEventName: "PreToolUse", SessionID: sessionID},
+			ToolName:     "Bash",
+			ToolInput:    &domain.ToolInput{Command: "make deploy"},
+		},
+	}
+}
+
+func TestCreateTaskAndWaitForDecision_DecisionFromNotification(t *testing.T) {
+	taskRepo := memory.NewTaskRepository()
+	sender := mock.NewMockNotificationSender("test")
+	service := NewTaskService(taskRepo, memory.NewTaskHistoryRepository(), sender, response.NewHookResponseBuilder(), &TaskServiceConfig{
+		AutoNotifyHookTypes: []domain.HookType{domain.HookTypePreToolUse},
+	})
+
+	// The quick action handler persists the decision, then signals the waiting webhook
+	sender.OnSend(func(notification *domain.Notification) {
+		if err := service.TakeAction(context.Background(), notification.TaskID, domain.ActionTypeApprove, nil); err != nil {
+			t.Errorf("Failed to take action: %v", err)
+		}
+		if !service.SendDecisionToTask(notification.TaskID, domain.ActionTypeApprove) {
+			t.Error("Expected the decision to reach the waiting webhook")
+		}
+	})
+
+	hookResponse, err := service.CreateTaskAndWaitForDecision(context.Background(), newBlockingHookData("abc123"), 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to wait for decision: %v", err)
+	}
+	if hookResponse.Decision != domain.ActionTypeApprove {
+		t.Errorf("Expected the tapped approval, got %+v", hookResponse)
+	}
+	for _, task := range storedTasks(t, taskRepo) {
+		if task.Status != domain.TaskStatusApproved {
+			t.Errorf("Expected the task to stay approved, got %s", task.Status)
+		}
+	}
+}
+
+func TestCreateTaskAndWaitForDecision_KeepsStoredDecision(t *testing.T) {
+	taskRepo := memory.NewTaskRepository()
+	historyRepo := memory.NewTaskHistoryRepository()
+	sender := mock.NewMockNotificationSender("test")
+	service := NewTaskService(taskRepo, historyRepo, sender, response.NewHookResponseBuilder(), &TaskServiceConfig{
+		AutoNotifyHookTypes: []domain.HookType{domain.HookTypePreToolUse},
+	})
+	snoozedUntil := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	// The dashboard substitutes the command, something else snoozes the task, then the user approves with a comment
+	sender.OnSend(func(notification *domain.Notification) {
+		ctx := context.Background()
+		if _, err := service.SetModifiedCommand(ctx, notification.TaskID, "make deploy-staging"); err != nil {
+			t.Errorf("Failed to set modified command: %v", err)
+		}
+		task, _ := taskRepo.GetByID(ctx, notification.TaskID)
+		task.SnoozedUntil = &snoozedUntil
+		taskRepo.Update(ctx, task)
+		if err := service.TakeAction(ctx, notification.TaskID, domain.ActionTypeApprove, map[string]interface{}{"comment": "staging first"}); err != nil {
+			t.Errorf("Failed to take action: %v", err)
+		}
+		service.SendDecisionToTask(notification.TaskID, domain.ActionTypeApprove)
+	})
+
+	hookResponse, err := service.CreateTaskAndWaitForDecision(context.Background(), newBlockingHookData("abc123"), 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Failed to wait for decision: %v", err)
+	}
+	if hookResponse.ModifiedCommand != "make deploy-staging" {
+		t.Errorf("Expected the substituted command in the response, got %q", hookResponse.ModifiedCommand)
+	}
+
+	for _, task := range storedTasks(t, taskRepo) {
+		if task.ResponseData["comment"] != "staging first" || task.ResponseData[modifiedCommandKey] != "make deploy-staging" {
+			t.Errorf("Expected the handler's response data to be kept, got %v", task.ResponseData)
+		}
+		if task.SnoozedUntil == nil || !task.SnoozedUntil.Equal(snoozedUntil) {
+			t.Errorf("Expected the snooze to be kept, got %v", task.SnoozedUntil)
+		}
+	}
+	approvals := 0
+	for _, action := range historyActions(t, historyRepo) {
+		if action == string(domain.ActionTypeApprove) {
+			approvals++
+		}
+	}
+	if approvals != 1 {
+		t.Errorf("Expected the approval to be recorded once, got %v", historyActions(t, historyRepo))
+	}
+}
+
+func TestCreateTaskAndWaitForDecision_RecordsSignalledDecision(t *testing.T) {
+	taskRepo := memory.NewTaskRepository()
+	historyRepo := memory.NewTaskHistoryRepository()
+	sender := mock.NewMockNotificationSender("test")
+	service := NewTaskService(taskRepo, historyRepo, sender, response.NewHookResponseBuilder(), &TaskServiceConfig{
+		AutoNotifyHookTypes: []domain.HookType{domain.HookTypePreToolUse},
+	})
+
+	// Cancelling a stuck wait signals the webhook without taking the action first
+	sender.OnSend(func(notification *domain.Notification) {
+		service.SendDecisionToTask(notification.TaskID, domain.ActionTypeCancel)
+	})
+
+	if _, err := service.CreateTaskAndWaitForDecision(context.Background(), newBlockingHookData("abc123"), 200*time.Millisecond); err != nil {
+		t.Fatalf("Failed to wait for decision: %v", err)
+	}
+	for _, task := range storedTasks(t, taskRepo) {
+		if task.IsActionable() || task.ActionTaken == nil || *task.ActionTaken != domain.ActionTypeCancel {
+			t.Errorf("Expected the waiter to record the cancellation, got status %s", task.Status)
+		}
+	}
+	if actions := historyActions(t, historyRepo); actions[len(actions)-1] != string(domain.ActionTypeCancel) {
+		t.Errorf("Expected the cancellation in the history, got %v", actions)
+	}
+}
+
+func TestTaskService_NotificationTitlePerHookType(t *testing.T) {
+	tests := []struct {
+		hookType      domain.HookType
+		expectedTitle string
+	}{
+		{domain.HookTypePreToolUse, "🔧 Claude Code - Tool Approval"},
+		{domain.HookTypePostToolUse, "✅ Claude Code - Tool Completed"},
+		{domain.HookTypeNotification, "⚠️ Claude Code - Attention Required"},
+		{domain.HookTypeUserPromptSubmit, "📝 Claude Code - Prompt Validation"},
+		{domain.HookTypeStop, "🏁 Claude Code - Session Complete"},
+		{domain.HookTypeSubagentStop, "🤖 Claude Code - Subagent Complete"},
+		{domain.HookTypePreCompact, "🗜️ Claude Code - Compacting"},
+	}
+
+	hookTypes := make([]domain.HookType, len(tests))
+	for i, tt := range tests {
+		hookTypes[i] = tt.hookType
+	}
+	sender := mock.NewMockNotificationSender("claude-notifications")
+	service := NewTaskService(memory.NewTaskRepository(), memory.NewTaskHistoryRepository(), sender, response.NewHookResponseBuilder(), &TaskServiceConfig{
+		AutoNotifyHookTypes: hookTypes,
+	})
+
+	for _, tt := range tests {
+		if err := service.CreateTask(context.Background(), domain.NewTask(&domain.HookData{Type: tt.hookType})); err != nil {
+			t.Fatalf("Failed to create %s task: %v", tt.hookType, err)
+		}
+	}
+
+	sender.AssertSentCount(t, len(tests))
+	sender.AssertSentToTopic(t, "claude-notifications")
+	for i, call := range sender.Calls() {
+		if call.Title != tests[i].expectedTitle {
+			t.Errorf("%s: expected title %q, got %q", tests[i].hookType, tests[i].expectedTitle, call.Title)
+		}
+	}
+}
+
+func TestTaskService_NotificationFailureKeepsTask(t *testing.T) {
+	taskRepo := memory.NewTaskRepository()
+	historyRepo := memory.NewTaskHistoryRepository()
+	sender := mock.NewMockNotificationSender("claude-notifications")
+	sender.SetError(errors.New("ntfy unavailable"))
+	service := NewTaskService(taskRepo, historyRepo, sender, response.NewHookResponseBuilder(), &TaskServiceConfig{
+		AutoNotifyHookTypes: []domain.HookType{domain.HookTypePreToolUse},
+	})
+
+	if _, err := service.CreateTaskFromHook(context.Background(), newBlockingHookData("abc123")); err != nil {
+		t.Fatalf("Expected the task to be created despite the failed notification, got %v", err)
+	}
+	sender.AssertSentCount(t, 0)
+	if tasks := storedTasks(t, taskRepo); len(tasks) != 1 {
+		t.Errorf("Expected one stored task, got %d", len(tasks))
+	}
+	for _, action := range historyActions(t, historyRepo) {
+		if action == domain.HistoryActionNotified {
+			t.Error("Expected no notified history entry for a failed send")
+		}
+	}
+}
+
+func TestTaskService_TakeActionTransitions(t *testing.T) {
+	tests := []struct {
+		name           string
+		first          domain.ActionType
+		second         domain.ActionType
+		expectError    bool
+		expectedStatus domain.TaskStatus
+	}{
+		{name: "Skip then approve", first: domain.ActionTypeSkip, second: domain.ActionTypeApprove, expectedStatus: domain.TaskStatusApproved},
+		{name: "Skip twice", first: domain.ActionTypeSkip, second: domain.ActionTypeSkip, expectedStatus: domain.TaskStatusPending},
+		{name: "Approve then reject", first: domain.ActionTypeApprove, second: domain.ActionTypeReject, expectError: true, expectedStatus: domain.TaskStatusApproved},
+		{name: "Cancel then approve", first: domain.ActionTypeCancel, second: domain.ActionTypeApprove, expectError: true, expectedStatus: domain.TaskStatusCompleted},
+		{name: "Retry then approve", first: "retry", second: domain.ActionTypeApprove, expectError: true, expectedStatus: domain.TaskStatusCompleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			taskRepo := memory.NewTaskRepository()
+			service := NewTaskService(taskRepo, memory.NewTaskHistoryRepository(), mock.NewMockNotificationSender("test"), response.NewHookResponseBuilder(), &TaskServiceConfig{})
+			task := domain.NewTask(newBlockingHookData("abc123"))
+			if err := taskRepo.Create(ctx, task); err != nil {
+				t.Fatalf("Failed to create task: %v", err)
+			}
+
+			if err := service.TakeAction(ctx, task.ID, tt.first, nil); err != nil {
+				t.Fatalf("Failed to take first action: %v", err)
+			}
+			err := service.TakeAction(ctx, task.ID, tt.second, nil)
+			if tt.expectError != errors.Is(err, ErrTaskNotActionable) {
+				t.Errorf("Expected ErrTaskNotActionable %t, got %v", tt.expectError, err)
+			}
+
+			stored, err := taskRepo.GetByID(ctx, task.ID)
+			if err != nil {
+				t.Fatalf("Failed to get task: %v", err)
+			}
+			if stored.Status != tt.expectedStatus {
+				t.Errorf("Expected status %s, got %s", tt.expectedStatus, stored.Status)
+			}
+		})
+	}
+}
+
+func TestTaskService_TakeActionOnDecidedPendingTask(t *testing.T) {
+	ctx := context.Background()
+	taskRepo := memory.NewTaskRepository()
+	service := NewTaskService(taskRepo, memory.NewTaskHistoryRepository(), mock.NewMockNotificationSender("test"), response.NewHookResponseBuilder(), &TaskServiceConfig{})
+
+	// A row left pending alongside a terminal decision, e.g. by a status reset, is still decided
+	task := domain.NewTask(newBlockingHookData("abc123"))
+	approve := domain.ActionTypeApprove
+	task.ActionTaken = &approve
+	if err := taskRepo.Create(ctx, task); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if err := taskRepo.Update(ctx, task); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+
+	if err := service.TakeAction(ctx, task.ID, domain.ActionTypeReject, nil); !errors.Is(err, ErrTaskNotActionable) {
+		t.Errorf("Expected ErrTaskNotActionable, got %v", err)
+	}
+}
+
+func TestTaskService_CleanupOldTasks(t *testing.T) {
+	ctx := context.Background()
+	old := time.Now().AddDate(0, 0, -10)
+
+	resolved := domain.NewTask(newBlockingHookData("abc123"))
+	resolved.TakeAction(domain.ActionTypeApprove, nil)
+	resolved.UpdatedAt = old
+	pending := domain.NewTask(newBlockingHookData("abc123"))
+	pending.UpdatedAt = old
+	recent := domain.NewTask(newBlockingHookData("abc123"))
+	recent.TakeAction(domain.ActionTypeReject, nil)
+	taskRepo := memory.NewTaskRepository(resolved, pending, recent)
+
+	historyRepo := memory.NewTaskHistoryRepository()
+	oldEntry := domain.NewTaskHistory(resolved.ID, domain.HistoryActionCreated, nil)
+	oldEntry.CreatedAt = old
+	for _, entry := range []*domain.TaskHistory{oldEntry, domain.NewTaskHistory(recent.ID, domain.HistoryActionCreated, nil)} {
+		if err := historyRepo.Create(ctx, entry); err != nil {
+			t.Fatalf("Failed to create history: %v", err)
+		}
+	}
+	service := NewTaskService(taskRepo, historyRepo, mock.NewMockNotificationSender("test"), response.NewHookResponseBuilder(), &TaskServiceConfig{})
+
+	cleaned, err := service.CleanupOldTasks(ctx, 7)
+	if err != nil {
+		t.Fatalf("Failed to clean up: %v", err)
+	}
+	if cleaned != 2 {
+		t.Errorf("Expected the old resolved task and the old history entry to be cleaned up, got %d", cleaned)
+	}
+
+	archived, err := taskRepo.ListArchived(ctx, ports.TaskFilter{})
+	if err != nil {
+		t.Fatalf("Failed to list archived tasks: %v", err)
+	}
+	if len(archived) != 1 || archived[0].ID != resolved.ID {
+		t.Errorf("Expected only the old resolved task to be archived, got %v", archived)
+	}
+	if tasks := storedTasks(t, taskRepo); len(tasks) != 2 {
+		t.Errorf("Expected the pending and recent tasks to stay, got %d tasks", len(tasks))
+	}
+	if actions := historyActions(t, historyRepo); len(actions) != 1 {
+		t.Errorf("Expected only the recent history entry to stay, got %v", actions)
+	}
+}
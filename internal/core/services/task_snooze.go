@@ -0,0 +1,27 @@
+package services
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultSnoozeDuration is used when a snooze request doesn't specify a duration
+	DefaultSnoozeDuration = 10 * time.Minute
+
+	// MaxSnoozeDuration is the longest a task's notifications can be silenced
+	MaxSnoozeDuration = 24 * time.Hour
+)
+
+// snoozeDeadline returns when a snooze of the given duration starting at now ends
+func snoozeDeadline(now time.Time, duration time.Duration) (time.Time, error) {
+	if duration <= 0 || duration > MaxSnoozeDuration {
+		return time.Time{}, fmt.Errorf("%w: %s (must be between 0 and %s)", ErrInvalidSnoozeDuration, duration, MaxSnoozeDuration)
+	}
+	return now.Add(duration), nil
+}
+
+// isSnoozed returns true if notifications are silenced until a time after now
+func isSnoozed(snoozedUntil *time.Time, now time.Time) bool {
+	return snoozedUntil != nil && snoozedUntil.After(now)
+}
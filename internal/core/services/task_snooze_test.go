@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSnoozeDeadline(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		duration    time.Duration
+		expected    time.Time
+		expectError bool
+	}{
+		{name: "Default duration", duration: DefaultSnoozeDuration, expected: now.Add(10 * time.Minute)},
+		{name: "Maximum duration", duration: MaxSnoozeDuration, expected: now.Add(24 * time.Hour)},
+		{name: "Zero duration", duration: 0, expectError: true},
+		{name: "Negative duration", duration: -time.Minute, expectError: true},
+		{name: "Too long", duration: MaxSnoozeDuration + time.Second, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deadline, err := snoozeDeadline(now, tt.duration)
+			if tt.expectError {
+				if !errors.Is(err, ErrInvalidSnoozeDuration) {
+					t.Errorf("Expected ErrInvalidSnoozeDuration, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !deadline.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, deadline)
+			}
+		})
+	}
+}
+
+func TestIsSnoozed(t *testing.T) {
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Minute)
+	earlier := now.Add(-time.Minute)
+
+	tests := []struct {
+		name         string
+		snoozedUntil *time.Time
+		expected     bool
+	}{
+		{name: "Not snoozed", snoozedUntil: nil, expected: false},
+		{name: "Snoozed", snoozedUntil: &later, expected: true},
+		{name: "Snooze expired", snoozedUntil: &earlier, expected: false},
+		{name: "Snooze ends now", snoozedUntil: &now, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSnoozed(tt.snoozedUntil, now); got != tt.expected {
+				t.Errorf("Expected %t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
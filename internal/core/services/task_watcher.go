@@ -0,0 +1,52 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// TaskWatcher lets long-polling clients wait for a task to be updated
+// Every watcher of a task shares one channel, which is closed on the task's next update.
+type TaskWatcher struct {
+	watchers map[uuid.UUID]chan struct{}
+	mutex    sync.Mutex
+}
+
+// NewTaskWatcher creates a new task watcher
+func NewTaskWatcher() *TaskWatcher {
+	return &TaskWatcher{
+		watchers: make(map[uuid.UUID]chan struct{}),
+	}
+}
+
+// Watch returns a channel that is closed the next time the task is updated
+func (w *TaskWatcher) Watch(taskID uuid.UUID) <-chan struct{} {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	updated, exists := w.watchers[taskID]
+	if !exists {
+		updated = make(chan struct{})
+		w.watchers[taskID] = updated
+	}
+	return updated
+}
+
+// Notify wakes everyone watching the task
+func (w *TaskWatcher) Notify(taskID uuid.UUID) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if updated, exists := w.watchers[taskID]; exists {
+		close(updated)
+		delete(w.watchers, taskID)
+	}
+}
+
+// ActiveWatches returns the number of tasks currently being watched
+func (w *TaskWatcher) ActiveWatches() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return len(w.watchers)
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTaskWatcher_NotifyDeliversWithin100ms(t *testing.T) {
+	watcher := NewTaskWatcher()
+	taskID := uuid.New()
+
+	const watchers = 10
+	var wg sync.WaitGroup
+	latencies := make(chan time.Duration, watchers)
+	var notifiedAt time.Time
+	var notifiedMutex sync.Mutex
+
+	for i := 0; i < watchers; i++ {
+		updated := watcher.Watch(taskID)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case <-updated:
+				notifiedMutex.Lock()
+				latencies <- time.Since(notifiedAt)
+				notifiedMutex.Unlock()
+			case <-time.After(time.Second):
+				t.Error("Watcher was not notified")
+			}
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	notifiedMutex.Lock()
+	notifiedAt = time.Now()
+	notifiedMutex.Unlock()
+	watcher.Notify(taskID)
+
+	wg.Wait()
+	close(latencies)
+	for latency := range latencies {
+		if latency > 100*time.Millisecond {
+			t.Errorf("Expected resolution within 100ms, took %v", latency)
+		}
+	}
+
+	if active := watcher.ActiveWatches(); active != 0 {
+		t.Errorf("Expected no active watches after notify, got %d", active)
+	}
+}
+
+func TestTaskWatcher_NotifyOnlyAffectsWatchedTask(t *testing.T) {
+	watcher := NewTaskWatcher()
+	watched := watcher.Watch(uuid.New())
+
+	watcher.Notify(uuid.New())
+
+	select {
+	case <-watched:
+		t.Fatal("Watcher for a different task should not be notified")
+	default:
+	}
+
+	// Watching again after a notify returns a fresh channel
+	taskID := uuid.New()
+	first := watcher.Watch(taskID)
+	watcher.Notify(taskID)
+	second := watcher.Watch(taskID)
+	if first == second {
+		t.Error("Expected a new channel after notify")
+	}
+}
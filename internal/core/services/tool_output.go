@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/dan/claude-control/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// historyActionOutputTruncated is the history action recorded when a hook's tool output was cut short before storage
+const historyActionOutputTruncated = "output_truncated"
+
+// truncateToolOutput limits a hook's tool output to the configured size before it is stored
+func (s *TaskService) truncateToolOutput(hookData *domain.HookData) *domain.ToolOutputTruncation {
+	maxBytes := s.config.MaxOutputBytes
+	if maxBytes <= 0 {
+		maxBytes = domain.DefaultMaxToolOutputBytes
+	}
+	return hookData.TruncateToolOutput(maxBytes)
+}
+
+// recordOutputTruncation stores the original output sizes of a truncated hook in the task's history
+func (s *TaskService) recordOutputTruncation(ctx context.Context, taskID uuid.UUID, truncation *domain.ToolOutputTruncation) {
+	if truncation == nil {
+		return
+	}
+
+	history := domain.NewTaskHistory(taskID, historyActionOutputTruncated, map[string]interface{}{
+		"original_stdout_bytes": truncation.OriginalStdoutBytes,
+		"original_stderr_bytes": truncation.OriginalStderrBytes,
+	})
+	if err := s.historyRepo.Create(ctx, history); err != nil {
+		log.Printf("Warning: failed to create task history: %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+// DefaultToolStatsCacheTTL is how long tool usage stats are served from memory
+const DefaultToolStatsCacheTTL = 60 * time.Second
+
+// ToolStatsCache caches tool usage stats per reporting window
+// Stats are keyed by window (e.g. 7 days) rather than start time, since the start time moves on every request.
+type ToolStatsCache struct {
+	ttl     time.Duration
+	entries map[time.Duration]toolStatsCacheEntry
+	mutex   sync.Mutex
+}
+
+// toolStatsCacheEntry is one cached stats result
+type toolStatsCacheEntry struct {
+	stats    []ports.ToolUsageStat
+	cachedAt time.Time
+}
+
+// NewToolStatsCache creates a new tool usage stats cache
+func NewToolStatsCache(ttl time.Duration) *ToolStatsCache {
+	return &ToolStatsCache{
+		ttl:     ttl,
+		entries: make(map[time.Duration]toolStatsCacheEntry),
+	}
+}
+
+// Get returns the cached stats for a window if they are younger than the TTL
+func (c *ToolStatsCache) Get(window time.Duration, now time.Time) ([]ports.ToolUsageStat, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, exists := c.entries[window]
+	if !exists || now.Sub(entry.cachedAt) >= c.ttl {
+		return nil, false
+	}
+	return entry.stats, true
+}
+
+// Set stores the stats for a window
+func (c *ToolStatsCache) Set(window time.Duration, stats []ports.ToolUsageStat, now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[window] = toolStatsCacheEntry{stats: stats, cachedAt: now}
+}
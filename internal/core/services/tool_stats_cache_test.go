@@ -0,0 +1,44 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/ports"
+)
+
+func TestToolStatsCache(t *testing.T) {
+	cache := NewToolStatsCache(DefaultToolStatsCacheTTL)
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+	stats := []ports.ToolUsageStat{{ToolName: "Bash", CallCount: 3}}
+
+	if _, ok := cache.Get(week, now); ok {
+		t.Fatal("Expected a miss on an empty cache")
+	}
+
+	cache.Set(week, stats, now)
+
+	tests := []struct {
+		name     string
+		window   time.Duration
+		at       time.Time
+		expected bool
+	}{
+		{name: "Fresh entry", window: week, at: now.Add(59 * time.Second), expected: true},
+		{name: "Expired entry", window: week, at: now.Add(DefaultToolStatsCacheTTL), expected: false},
+		{name: "Different window", window: 24 * time.Hour, at: now, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cached, ok := cache.Get(tt.window, tt.at)
+			if ok != tt.expected {
+				t.Fatalf("Expected hit=%t, got %t", tt.expected, ok)
+			}
+			if ok && (len(cached) != 1 || cached[0].ToolName != "Bash") {
+				t.Errorf("Unexpected cached stats: %+v", cached)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+const (
+	// DefaultMaxTranscriptBackupBytes is the most of a transcript copied into a backup by default
+	DefaultMaxTranscriptBackupBytes = 50 * 1024 * 1024
+
+	// historyActionTranscriptBackedUp is the history action recorded when a transcript is backed up before compaction
+	historyActionTranscriptBackedUp = "transcript_backed_up"
+
+	// transcriptBackupPathKey holds the backup file path in the history entry's data
+	transcriptBackupPathKey = "backup_path"
+
+	// transcriptFileExtension is the extension of Claude Code transcripts; nothing else is backed up
+	transcriptFileExtension = ".jsonl"
+)
+
+// unsafeFileNameChars matches anything that shouldn't appear in a backup file name
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// TranscriptBackup copies Claude Code transcripts into a backup directory before they are compacted
+type TranscriptBackup struct {
+	dir      string
+	maxBytes int64
+}
+
+// TranscriptBackupResult describes a transcript copy
+type TranscriptBackupResult struct {
+	SourcePath string
+	BackupPath string
+	Bytes      int64
+	Truncated  bool // The transcript was larger than the size limit and only its start was kept
+}
+
+// NewTranscriptBackup creates a backup that writes into dir, copying at most maxBytes per transcript
+func NewTranscriptBackup(dir string, maxBytes int64) *TranscriptBackup {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxTranscriptBackupBytes
+	}
+	return &TranscriptBackup{dir: dir, maxBytes: maxBytes}
+}
+
+// Backup copies the transcript named by a PreCompact hook, returning nil for any other hook
+// The transcript path comes from the webhook body, so only absolute paths to regular .jsonl files are
+// read, and the backup name is built from a sanitised session ID so it always lands inside the directory.
+func (b *TranscriptBackup) Backup(hookData *domain.HookData, now time.Time) (*TranscriptBackupResult, error) {
+	data, ok := hookData.Data.(*domain.PreCompactHookData)
+	if !ok || data.TranscriptPath == "" {
+		return nil, nil
+	}
+
+	sourcePath, err := validateTranscriptPath(data.TranscriptPath)
+	if err != nil {
+		return nil, err
+	}
+
+	backupPath, err := b.backupPath(data.SessionID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript: %w", err)
+	}
+	defer source.Close()
+
+	if err := os.MkdirAll(b.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create transcript backup directory: %w", err)
+	}
+
+	backup, err := os.OpenFile(backupPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transcript backup: %w", err)
+	}
+	defer backup.Close()
+
+	// Copy one byte past the limit so an oversized transcript can be detected, then trim it
+	copied, err := io.Copy(backup, io.LimitReader(source, b.maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy transcript: %w", err)
+	}
+
+	truncated := copied > b.maxBytes
+	if truncated {
+		if err := backup.Truncate(b.maxBytes); err != nil {
+			return nil, fmt.Errorf("failed to truncate transcript backup: %w", err)
+		}
+		copied = b.maxBytes
+	}
+
+	return &TranscriptBackupResult{
+		SourcePath: sourcePath,
+		BackupPath: backupPath,
+		Bytes:      copied,
+		Truncated:  truncated,
+	}, nil
+}
+
+// backupPath names the backup <session>-<timestamp>.jsonl inside the backup directory
+func (b *TranscriptBackup) backupPath(sessionID string, now time.Time) (string, error) {
+	name := unsafeFileNameChars.ReplaceAllString(sessionID, "_")
+	if strings.Trim(name, "_") == "" {
+		name = "session"
+	}
+	fileName := fmt.Sprintf("%s-%s%s", name, now.UTC().Format("20060102T150405.000000000Z"), transcriptFileExtension)
+
+	dir, err := filepath.Abs(b.dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve transcript backup directory: %w", err)
+	}
+	backupPath := filepath.Join(dir, fileName)
+	if filepath.Dir(backupPath) != dir {
+		return "", fmt.Errorf("transcript backup path %q escapes the backup directory", backupPath)
+	}
+	return backupPath, nil
+}
+
+// validateTranscriptPath checks that a hook-supplied transcript path names a regular .jsonl file
+func validateTranscriptPath(transcriptPath string) (string, error) {
+	if !filepath.IsAbs(transcriptPath) {
+		return "", fmt.Errorf("transcript path %q is not absolute", transcriptPath)
+	}
+
+	cleaned := filepath.Clean(transcriptPath)
+	if filepath.Ext(cleaned) != transcriptFileExtension {
+		return "", fmt.Errorf("transcript path %q is not a %s file", transcriptPath, transcriptFileExtension)
+	}
+
+	// Lstat so a symlink can't point the copy at a file outside the transcript
+	info, err := os.Lstat(cleaned)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat transcript: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("transcript path %q is not a regular file", transcriptPath)
+	}
+	return cleaned, nil
+}
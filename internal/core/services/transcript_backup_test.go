@@ -0,0 +1,156 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dan/claude-control/internal/core/domain"
+)
+
+// preCompactHook builds PreCompact hook data for a session and transcript path
+func preCompactHook(sessionID, transcriptPath string) *domain.HookData {
+	return &domain.HookData{
+		Type: domain.HookTypePreCompact,
+		Data: &domain.PreCompactHookData{
+			BaseHookData: domain.BaseHookData{
+				HookEventName:  "PreCompact",
+				SessionID:      sessionID,
+				TranscriptPath: transcriptPath,
+			},
+			Trigger: "auto",
+		},
+	}
+}
+
+func TestTranscriptBackup_Backup(t *testing.T) {
+	sourceDir := t.TempDir()
+	backupDir := filepath.Join(t.TempDir(), "backups")
+	transcript := filepath.Join(sourceDir, "abc123.jsonl")
+	content := `{"type":"user","message":"hello"}` + "\n"
+	if err := os.WriteFile(transcript, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+
+	now := time.Date(2025, 8, 1, 9, 30, 0, 0, time.UTC)
+	result, err := NewTranscriptBackup(backupDir, 0).Backup(preCompactHook("abc123", transcript), now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if filepath.Dir(result.BackupPath) != backupDir {
+		t.Errorf("Expected backup in %s, got %s", backupDir, result.BackupPath)
+	}
+	if !strings.HasPrefix(filepath.Base(result.BackupPath), "abc123-20250801T093000") {
+		t.Errorf("Expected a session and timestamp file name, got %s", filepath.Base(result.BackupPath))
+	}
+	backedUp, err := os.ReadFile(result.BackupPath)
+	if err != nil {
+		t.Fatalf("Failed to read backup: %v", err)
+	}
+	if string(backedUp) != content || result.Bytes != int64(len(content)) || result.Truncated {
+		t.Errorf("Expected an exact copy, got %q (%d bytes, truncated=%t)", backedUp, result.Bytes, result.Truncated)
+	}
+
+	// A second compaction in the same session gets its own file
+	second, err := NewTranscriptBackup(backupDir, 0).Backup(preCompactHook("abc123", transcript), now.Add(time.Millisecond))
+	if err != nil {
+		t.Fatalf("Unexpected error on second backup: %v", err)
+	}
+	if second.BackupPath == result.BackupPath {
+		t.Error("Expected a new backup file for the second compaction")
+	}
+}
+
+func TestTranscriptBackup_SizeLimit(t *testing.T) {
+	transcript := filepath.Join(t.TempDir(), "large.jsonl")
+	if err := os.WriteFile(transcript, []byte(strings.Repeat("x", 100)), 0o600); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+
+	result, err := NewTranscriptBackup(t.TempDir(), 40).Backup(preCompactHook("abc123", transcript), time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	info, err := os.Stat(result.BackupPath)
+	if err != nil {
+		t.Fatalf("Failed to stat backup: %v", err)
+	}
+	if !result.Truncated || result.Bytes != 40 || info.Size() != 40 {
+		t.Errorf("Expected the backup cut to 40 bytes, got %d bytes on disk (result %+v)", info.Size(), result)
+	}
+}
+
+func TestTranscriptBackup_PathSafety(t *testing.T) {
+	sourceDir := t.TempDir()
+	transcript := filepath.Join(sourceDir, "abc123.jsonl")
+	if err := os.WriteFile(transcript, []byte("{}\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+	secret := filepath.Join(sourceDir, "secret.txt")
+	if err := os.WriteFile(secret, []byte("hunter2"), 0o600); err != nil {
+		t.Fatalf("Failed to write secret: %v", err)
+	}
+	dir := filepath.Join(sourceDir, "dir.jsonl")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	link := filepath.Join(sourceDir, "link.jsonl")
+	if err := os.Symlink(secret, link); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	rejected := []struct {
+		name           string
+		transcriptPath string
+	}{
+		{"Relative path", "../" + filepath.Base(sourceDir) + "/abc123.jsonl"},
+		{"Not a transcript", secret},
+		{"Traversal to a non-transcript", sourceDir + "/../" + filepath.Base(sourceDir) + "/secret.txt"},
+		{"Symlink", link},
+		{"Directory", dir},
+		{"Missing file", filepath.Join(sourceDir, "missing.jsonl")},
+	}
+
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			backupDir := t.TempDir()
+			result, err := NewTranscriptBackup(backupDir, 0).Backup(preCompactHook("abc123", tt.transcriptPath), time.Now())
+			if err == nil {
+				t.Fatalf("Expected %q to be rejected, got backup %+v", tt.transcriptPath, result)
+			}
+			if entries, _ := os.ReadDir(backupDir); len(entries) != 0 {
+				t.Errorf("Expected no backup files, found %d", len(entries))
+			}
+		})
+	}
+
+	// Session IDs end up in the file name, so traversal in them must not leave the backup directory
+	for _, sessionID := range []string{"../../etc/cron.d/evil", "..", "/tmp/abs", `..\..\windows`} {
+		t.Run("Session "+sessionID, func(t *testing.T) {
+			backupDir := t.TempDir()
+			result, err := NewTranscriptBackup(backupDir, 0).Backup(preCompactHook(sessionID, transcript), time.Now())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if filepath.Dir(result.BackupPath) != backupDir {
+				t.Errorf("Expected backup inside %s, got %s", backupDir, result.BackupPath)
+			}
+		})
+	}
+}
+
+func TestTranscriptBackup_IgnoresOtherHooks(t *testing.T) {
+	hookData := &domain.HookData{
+		Type: domain.HookTypeStop,
+		Data: &domain.StopHookData{BaseHookData: domain.BaseHookData{TranscriptPath: "/tmp/abc123.jsonl"}},
+	}
+
+	result, err := NewTranscriptBackup(t.TempDir(), 0).Backup(hookData, time.Now())
+	if err != nil || result != nil {
+		t.Errorf("Expected no backup for a Stop hook, got %+v, %v", result, err)
+	}
+}
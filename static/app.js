@@ -0,0 +1,51 @@
+// Shared dashboard behaviour
+
+// Reload whenever the server reports a task change, falling back to polling without WebSocket support
+(() => {
+    const fallbackReloadDelay = 30000;
+    const maxReconnectDelay = 30000;
+    const reloadDebounce = 250;
+
+    if (!('WebSocket' in window)) {
+        setTimeout(() => window.location.reload(), fallbackReloadDelay);
+        return;
+    }
+
+    // The script is served from {basePath}/static/app.js, so its URL gives the base path
+    const scriptPath = document.currentScript ? new URL(document.currentScript.src).pathname : '/static/app.js';
+    const basePath = scriptPath.replace(/\/static\/app\.js$/, '');
+    const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+    const url = protocol + '//' + window.location.host + basePath + '/ws/tasks';
+
+    let reconnectDelay = 1000;
+    let reloadTimer = null;
+
+    function connect() {
+        const socket = new WebSocket(url);
+
+        socket.onopen = () => {
+            reconnectDelay = 1000;
+        };
+
+        socket.onmessage = (message) => {
+            let event;
+            try {
+                event = JSON.parse(message.data);
+            } catch (e) {
+                return;
+            }
+            if (event.type === 'ping' || reloadTimer) {
+                return;
+            }
+            // Several tasks often change together; reload once for the batch
+            reloadTimer = setTimeout(() => window.location.reload(), reloadDebounce);
+        };
+
+        socket.onclose = () => {
+            setTimeout(connect, reconnectDelay);
+            reconnectDelay = Math.min(reconnectDelay * 2, maxReconnectDelay);
+        };
+    }
+
+    connect();
+})();
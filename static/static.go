@@ -0,0 +1,9 @@
+// Package static embeds the web dashboard's static assets
+package static
+
+import "embed"
+
+// Files holds the static assets served under /static/
+//
+//go:embed *.js
+var Files embed.FS
@@ -0,0 +1,9 @@
+// Package templates embeds the web dashboard's HTML templates
+package templates
+
+import "embed"
+
+// Files holds the dashboard page templates
+//
+//go:embed *.html
+var Files embed.FS